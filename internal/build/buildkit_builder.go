@@ -0,0 +1,289 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/pkg/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Builds images from a Dockerfile.
+//
+// Implemented by the DockerBuilder, which builds with the Docker daemon,
+// and the BuildkitBuilder, which builds with a standalone Buildkit daemon.
+type DockerfileBuilder interface {
+	DockerKubeConnection
+
+	BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet,
+		spec v1alpha1.DockerImageSpec,
+		cluster *v1alpha1.Cluster,
+		imageMaps map[ktypes.NamespacedName]*v1alpha1.ImageMap,
+		filter model.PathMatcher) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error)
+	PushImage(ctx context.Context, ref reference.NamedTagged) error
+	ImageExists(ctx context.Context, ref reference.NamedTagged) (bool, error)
+}
+
+var _ DockerfileBuilder = &DockerBuilder{}
+var _ DockerfileBuilder = &BuildkitBuilder{}
+
+// Builds images with a standalone Buildkit daemon, for clusters
+// that don't have a Docker daemon (e.g., clusters that run containerd).
+//
+// Buildkit can't re-tag an image after it's built. So unlike the DockerBuilder,
+// we tag images with the build time (like custom_build does) rather than the
+// image digest.
+type BuildkitBuilder struct {
+	clock Clock
+	conn  v1alpha1.BuildkitClusterConnection
+	state *buildkitBuilderState
+}
+
+type buildkitBuilderState struct {
+	mu sync.Mutex
+
+	// The last image we built for each repository, keyed by repository name.
+	//
+	// Builds replace each other, and we forget an image when its DockerImage
+	// is deleted, so this doesn't grow with every build in the session.
+	images map[string]buildkitImage
+
+	// Checks the registry for images we didn't build in this session.
+	registryHasImage func(ctx context.Context, ref reference.Named) bool
+}
+
+type buildkitImage struct {
+	ref    string
+	pushed bool
+}
+
+// The tag prefix for images built by Buildkit.
+const buildkitTagPrefix = "tilt-build-"
+
+func NewBuildkitBuilder(clock Clock) *BuildkitBuilder {
+	return &BuildkitBuilder{
+		clock: clock,
+		state: &buildkitBuilderState{
+			images:           make(map[string]buildkitImage),
+			registryHasImage: registryHasImage,
+		},
+	}
+}
+
+// Returns a builder that builds with the cluster's Buildkit daemon,
+// or nil if the cluster builds with Docker.
+func (b *BuildkitBuilder) ForCluster(cluster *v1alpha1.Cluster) *BuildkitBuilder {
+	if b == nil || cluster == nil || cluster.Spec.Buildkit == nil {
+		return nil
+	}
+	return &BuildkitBuilder{
+		clock: b.clock,
+		conn:  *cluster.Spec.Buildkit,
+		state: b.state,
+	}
+}
+
+// If Buildkit loads images into the cluster's containerd, they're already
+// on the cluster's container runtime.
+func (b *BuildkitBuilder) WillBuildToKubeContext(kctx k8s.KubeContext) bool {
+	return b.conn.LoadToContainerd
+}
+
+// Returns true if we built the image in this session, or if we pushed
+// it to its registry in a previous session.
+//
+// Buildkit doesn't have a way to list images, so after a restart we ask the
+// registry. Images that were only loaded into containerd get rebuilt, which
+// is quick if Buildkit still has them in its cache.
+func (b *BuildkitBuilder) ImageExists(ctx context.Context, ref reference.NamedTagged) (bool, error) {
+	b.state.mu.Lock()
+	image, ok := b.state.images[ref.Name()]
+	b.state.mu.Unlock()
+	if ok && image.ref == ref.String() {
+		return true, nil
+	}
+
+	if !strings.HasPrefix(ref.Tag(), buildkitTagPrefix) {
+		return false, nil
+	}
+	return b.state.registryHasImage(ctx, ref), nil
+}
+
+// Forget an image that's no longer used.
+func (b *BuildkitBuilder) ForgetImage(ref reference.Named) {
+	if b == nil {
+		return
+	}
+
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+	image, ok := b.state.images[ref.Name()]
+	if ok && image.ref == ref.String() {
+		delete(b.state.images, ref.Name())
+	}
+}
+
+func (b *BuildkitBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet,
+	spec v1alpha1.DockerImageSpec,
+	cluster *v1alpha1.Cluster,
+	imageMaps map[ktypes.NamespacedName]*v1alpha1.ImageMap,
+	filter model.PathMatcher) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error) {
+	spec = InjectClusterPlatform(spec, cluster)
+	spec, err := InjectImageDependencies(spec, imageMaps)
+	if err != nil {
+		return container.TaggedRefs{}, nil, err
+	}
+
+	platforms := strings.Join(specPlatforms(spec), ",")
+	platformSuffix := ""
+	if platforms != "" {
+		platformSuffix = fmt.Sprintf(" for platform %s", platforms)
+	}
+	logger.Get(ctx).Infof("Building Dockerfile%s with Buildkit at %s:\n%s\n",
+		platformSuffix, b.conn.Host, indent(spec.DockerfileContents, "  "))
+
+	// Builds that finish in the same second must not share a tag,
+	// so tag with the nanoseconds.
+	tagged, err := refs.AddTagSuffix(fmt.Sprintf("%s%d", buildkitTagPrefix, b.clock.Now().UnixNano()))
+	if err != nil {
+		return container.TaggedRefs{}, nil, errors.Wrap(err, "TagImage")
+	}
+
+	// Buildkit exports the image once, so if the cluster pulls
+	// from a registry, push it as part of the build.
+	push := !b.conn.LoadToContainerd && spec.ClusterNeeds == v1alpha1.ClusterImageNeedsPush
+
+	ps.StartBuildStep(ctx, "Building image")
+	ctx = ps.AttachLogger(ctx)
+	stages, err := b.solve(ctx, spec, filter, tagged, push)
+	if err != nil {
+		return container.TaggedRefs{}, stages, explainPlatformError(err, platforms)
+	}
+
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+	b.state.images[tagged.LocalRef.Name()] = buildkitImage{ref: tagged.LocalRef.String(), pushed: push}
+	return tagged, stages, nil
+}
+
+// Buildkit pushes images in the same solve that builds them,
+// so this only checks that the build pushed the image.
+func (b *BuildkitBuilder) PushImage(ctx context.Context, ref reference.NamedTagged) error {
+	b.state.mu.Lock()
+	defer b.state.mu.Unlock()
+	image, ok := b.state.images[ref.Name()]
+	if !ok || image.ref != ref.String() {
+		return fmt.Errorf("pushing image %q: not built with Buildkit", ref.String())
+	}
+	if !image.pushed {
+		return fmt.Errorf("pushing image %q: not pushed when it was built", ref.String())
+	}
+	return nil
+}
+
+func (b *BuildkitBuilder) solve(ctx context.Context, spec v1alpha1.DockerImageSpec, filter model.PathMatcher,
+	refs container.TaggedRefs, push bool) ([]v1alpha1.DockerImageStageStatus, error) {
+	options := Options(nil, spec)
+	dockerfileDir, err := writeTempDockerfileSyncdir(spec.DockerfileContents)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(dockerfileDir)
+	}()
+	options.SyncedDirs = toSyncedDirs(spec.Context, dockerfileDir, filter)
+	options.Dockerfile = DockerfileName
+
+	opt, err := docker.ToSolveOpt(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "ImageBuild")
+	}
+	opt.Session, err = docker.BuildkitAttachables(ctx, options)
+	if err != nil {
+		return nil, errors.Wrap(err, "ImageBuild")
+	}
+	opt.Exports = []bkclient.ExportEntry{
+		{Type: bkclient.ExporterImage, Attrs: buildkitExportAttrs(refs, push, b.conn)},
+	}
+
+	bk, err := bkclient.New(ctx, b.conn.Host, bkclient.WithFailFast())
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to buildkitd at %s", b.conn.Host)
+	}
+
+	body := docker.StreamSolve(ctx, bk, opt)
+	defer func() {
+		_ = body.Close()
+	}()
+
+	_, stages, err := readDockerOutput(ctx, body)
+	if err != nil {
+		return stages, errors.Wrap(err, "ImageBuild")
+	}
+	return stages, nil
+}
+
+// Attributes for Buildkit's image exporter.
+func buildkitExportAttrs(refs container.TaggedRefs, push bool, conn v1alpha1.BuildkitClusterConnection) map[string]string {
+	attrs := map[string]string{
+		"name": strings.Join(buildkitExportNames(refs, push, conn), ","),
+	}
+	if push {
+		attrs["push"] = "true"
+
+		// Local registries (e.g., for KIND) usually don't have TLS.
+		// The cluster may know a local registry by a name other than localhost,
+		// so check both refs.
+		if isLocalRegistry(refs.LocalRef.String()) || isLocalRegistry(refs.ClusterRef.String()) {
+			attrs["registry.insecure"] = "true"
+		}
+	}
+	if conn.LoadToContainerd {
+		// Unpack the layers into the snapshotter, so that
+		// containerd can run the image without pulling it.
+		attrs["unpack"] = "true"
+	}
+	return attrs
+}
+
+// The names to export the image as.
+//
+// Buildkit runs next to the cluster, so it pushes to the registry
+// by the host that the cluster uses, which may not be reachable
+// by the host that Tilt uses. If we're loading into containerd,
+// the cluster will look for the image by its cluster ref.
+func buildkitExportNames(refs container.TaggedRefs, push bool, conn v1alpha1.BuildkitClusterConnection) []string {
+	local := refs.LocalRef.String()
+	cluster := refs.ClusterRef.String()
+	if cluster == local {
+		return []string{local}
+	}
+	if conn.LoadToContainerd {
+		return []string{local, cluster}
+	}
+	if push {
+		return []string{cluster}
+	}
+	return []string{local}
+}
+
+func isLocalRegistry(name string) bool {
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return false
+	}
+	host := reference.Domain(named)
+	return strings.HasPrefix(host, "localhost:") || strings.HasPrefix(host, "127.0.0.1:")
+}
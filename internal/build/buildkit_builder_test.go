@@ -0,0 +1,215 @@
+package build
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func buildkitCluster(conn *v1alpha1.BuildkitClusterConnection) *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{Spec: v1alpha1.ClusterSpec{Buildkit: conn}}
+}
+
+func TestBuildkitBuilderForCluster(t *testing.T) {
+	bkb := NewBuildkitBuilder(ProvideClock())
+	assert.Nil(t, bkb.ForCluster(nil))
+	assert.Nil(t, bkb.ForCluster(buildkitCluster(nil)))
+
+	var nilBuilder *BuildkitBuilder
+	assert.Nil(t, nilBuilder.ForCluster(buildkitCluster(&v1alpha1.BuildkitClusterConnection{Host: "tcp://buildkitd:1234"})))
+
+	b := bkb.ForCluster(buildkitCluster(&v1alpha1.BuildkitClusterConnection{Host: "tcp://buildkitd:1234", LoadToContainerd: true}))
+	require.NotNil(t, b)
+	assert.Equal(t, "tcp://buildkitd:1234", b.conn.Host)
+	assert.True(t, b.WillBuildToKubeContext(k8s.KubeContext("kind-kind")))
+}
+
+func TestImageBuilderSelectsBuildkitForCluster(t *testing.T) {
+	db := NewDockerBuilder(docker.NewFakeClient(), nil)
+	ib := NewImageBuilder(db, NewBuildkitBuilder(ProvideClock()), nil, nil, nil)
+
+	assert.Equal(t, db, ib.dockerfileBuilder(nil))
+	assert.Equal(t, db, ib.dockerfileBuilder(buildkitCluster(nil)))
+
+	builder := ib.dockerfileBuilder(buildkitCluster(&v1alpha1.BuildkitClusterConnection{Host: "unix:///run/buildkit/buildkitd.sock"}))
+	if assert.IsType(t, &BuildkitBuilder{}, builder) {
+		assert.Equal(t, "unix:///run/buildkit/buildkitd.sock", builder.(*BuildkitBuilder).conn.Host)
+	}
+}
+
+func TestBuildkitBuilderSharesStateAcrossClusters(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	bkb := NewBuildkitBuilder(ProvideClock())
+	bkb.state.registryHasImage = func(ctx context.Context, ref reference.Named) bool { return false }
+	a := bkb.ForCluster(buildkitCluster(&v1alpha1.BuildkitClusterConnection{Host: "tcp://a:1234"}))
+	b := bkb.ForCluster(buildkitCluster(&v1alpha1.BuildkitClusterConnection{Host: "tcp://b:1234"}))
+
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	exists, err := b.ImageExists(ctx, ref)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	a.state.images[ref.Name()] = buildkitImage{ref: ref.String(), pushed: true}
+	exists, err = b.ImageExists(ctx, ref)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, b.PushImage(ctx, ref))
+}
+
+func TestBuildkitBuilderForgetImage(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	bkb := NewBuildkitBuilder(ProvideClock())
+	bkb.state.registryHasImage = func(ctx context.Context, ref reference.Named) bool { return false }
+
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-2")
+	bkb.state.images[ref.Name()] = buildkitImage{ref: ref.String()}
+
+	// Forgetting an older build of the same repo keeps the newer one.
+	bkb.ForgetImage(container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1"))
+	exists, err := bkb.ImageExists(ctx, ref)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	bkb.ForgetImage(ref)
+	exists, err = bkb.ImageExists(ctx, ref)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Empty(t, bkb.state.images)
+
+	var nilBuilder *BuildkitBuilder
+	nilBuilder.ForgetImage(ref)
+}
+
+func TestBuildkitBuilderImageExistsInRegistry(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/foo/bar/manifests/tilt-build-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+		w.Header().Set("Docker-Content-Digest", "sha256:"+strings.Repeat("a", 64))
+		w.Header().Set("Content-Length", "2")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer registry.Close()
+
+	host := strings.TrimPrefix(registry.URL, "http://")
+	bkb := NewBuildkitBuilder(ProvideClock())
+
+	exists, err := bkb.ImageExists(ctx, container.MustParseNamedTagged(host+"/foo/bar:tilt-build-1"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = bkb.ImageExists(ctx, container.MustParseNamedTagged(host+"/foo/bar:tilt-build-2"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Images that Buildkit didn't tag aren't looked up.
+	bkb.state.registryHasImage = func(ctx context.Context, ref reference.Named) bool {
+		t.Errorf("unexpected registry lookup of %s", ref)
+		return false
+	}
+	exists, err = bkb.ImageExists(ctx, container.MustParseNamedTagged(host+"/foo/bar:tilt-1234"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestBuildkitBuilderPushUnbuiltImage(t *testing.T) {
+	bkb := NewBuildkitBuilder(ProvideClock()).
+		ForCluster(buildkitCluster(&v1alpha1.BuildkitClusterConnection{Host: "tcp://buildkitd:1234"}))
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	err := bkb.PushImage(context.Background(), ref)
+	assert.EqualError(t, err, `pushing image "gcr.io/foo/bar:tilt-build-1": not built with Buildkit`)
+
+	bkb.state.images[ref.Name()] = buildkitImage{ref: ref.String()}
+	err = bkb.PushImage(context.Background(), ref)
+	assert.EqualError(t, err, `pushing image "gcr.io/foo/bar:tilt-build-1": not pushed when it was built`)
+}
+
+func TestBuildkitExportAttrs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		local    string
+		cluster  string
+		push     bool
+		conn     v1alpha1.BuildkitClusterConnection
+		expected map[string]string
+	}{
+		{
+			name:     "build",
+			local:    "gcr.io/foo/bar:tilt-build-1",
+			expected: map[string]string{"name": "gcr.io/foo/bar:tilt-build-1"},
+		},
+		{
+			name:     "push",
+			local:    "gcr.io/foo/bar:tilt-build-1",
+			push:     true,
+			expected: map[string]string{"name": "gcr.io/foo/bar:tilt-build-1", "push": "true"},
+		},
+		{
+			name:  "push to local registry",
+			local: "localhost:5000/bar:tilt-build-1",
+			push:  true,
+			expected: map[string]string{
+				"name":              "localhost:5000/bar:tilt-build-1",
+				"push":              "true",
+				"registry.insecure": "true",
+			},
+		},
+		{
+			name:    "push to local registry by its cluster host",
+			local:   "localhost:5000/bar:tilt-build-1",
+			cluster: "registry:5000/bar:tilt-build-1",
+			push:    true,
+			expected: map[string]string{
+				"name":              "registry:5000/bar:tilt-build-1",
+				"push":              "true",
+				"registry.insecure": "true",
+			},
+		},
+		{
+			name:    "build for local registry by its cluster host",
+			local:   "localhost:5000/bar:tilt-build-1",
+			cluster: "registry:5000/bar:tilt-build-1",
+			expected: map[string]string{
+				"name": "localhost:5000/bar:tilt-build-1",
+			},
+		},
+		{
+			name:    "load to containerd",
+			local:   "localhost:5000/bar:tilt-build-1",
+			cluster: "registry:5000/bar:tilt-build-1",
+			conn:    v1alpha1.BuildkitClusterConnection{LoadToContainerd: true},
+			expected: map[string]string{
+				"name":   "localhost:5000/bar:tilt-build-1,registry:5000/bar:tilt-build-1",
+				"unpack": "true",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			refs := container.TaggedRefs{
+				LocalRef:   container.MustParseNamedTagged(tc.local),
+				ClusterRef: container.MustParseNamedTagged(tc.local),
+			}
+			if tc.cluster != "" {
+				refs.ClusterRef = container.MustParseNamedTagged(tc.cluster)
+			}
+			assert.Equal(t, tc.expected, buildkitExportAttrs(refs, tc.push, tc.conn))
+		})
+	}
+}
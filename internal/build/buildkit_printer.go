@@ -8,6 +8,7 @@ import (
 
 	digest "github.com/opencontainers/go-digest"
 	"github.com/tonistiigi/units"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -133,8 +134,9 @@ func (b *buildkitPrinter) toStageStatuses() []v1alpha1.DockerImageStageStatus {
 		if vl, ok := b.vData[digest]; ok {
 			v := vl.vertex
 			status := v1alpha1.DockerImageStageStatus{
-				Name:   v.name,
-				Cached: v.cached,
+				Name:             v.name,
+				Cached:           v.cached,
+				TransferredBytes: vl.statuses.combined().current,
 			}
 			if v.startedTime != nil {
 				st := apis.NewMicroTime(*v.startedTime)
@@ -144,6 +146,9 @@ func (b *buildkitPrinter) toStageStatuses() []v1alpha1.DockerImageStageStatus {
 				ct := apis.NewMicroTime(*v.completedTime)
 				status.FinishedAt = &ct
 			}
+			if v.startedTime != nil && v.completedTime != nil {
+				status.Duration = &metav1.Duration{Duration: v.duration}
+			}
 			if v.isError() {
 				status.Error = v.error
 			}
@@ -158,10 +163,16 @@ func (b *buildkitPrinter) parseAndPrint(vertexes []*vertex, logs []*vertexLog, s
 		if vl, ok := b.vData[v.digest]; ok {
 			vl.vertex.started = v.started
 			vl.vertex.completed = v.completed
-			vl.vertex.startedTime = v.startedTime
-			vl.vertex.completedTime = v.completedTime
 			vl.vertex.cached = v.cached
 
+			// Keep the timestamps from earlier updates if this update doesn't have them.
+			if v.startedTime != nil {
+				vl.vertex.startedTime = v.startedTime
+			}
+			if v.completedTime != nil {
+				vl.vertex.completedTime = v.completedTime
+			}
+
 			// NOTE(nick): Fun fact! The buildkit protocol sends down multiple completion timestamps.
 			// We need to take the last one.
 			if v.duration > vl.vertex.duration {
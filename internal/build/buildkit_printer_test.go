@@ -9,10 +9,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	controlapi "github.com/moby/buildkit/api/services/control"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -104,3 +106,44 @@ func TestBuildkitPrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildkitPrinterStageStatuses(t *testing.T) {
+	f, err := os.Open("testdata/TestBuildkitPrinter/add-success.response.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	responses, err := buildkitTestCase{}.readResponse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := newBuildkitPrinter(logger.NewLogger(logger.InfoLvl, &strings.Builder{}))
+	for _, resp := range responses {
+		err := p.parseAndPrint(toVertexes(resp))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stages := make(map[string]v1alpha1.DockerImageStageStatus)
+	for _, s := range p.toStageStatuses() {
+		stages[s.Name] = s
+	}
+
+	context := stages["[internal] load build context"]
+	assert.Equal(t, int64(362), context.TransferredBytes)
+	if assert.NotNil(t, context.Duration) {
+		assert.Equal(t, 38*time.Millisecond, context.Duration.Truncate(time.Millisecond))
+	}
+
+	add := stages["[2/3] ADD hi.txt hi.txt"]
+	assert.False(t, add.Cached)
+	assert.Equal(t, int64(0), add.TransferredBytes)
+	if assert.NotNil(t, add.Duration) {
+		assert.Equal(t, 113*time.Millisecond, add.Duration.Truncate(time.Millisecond))
+	}
+}
@@ -0,0 +1,57 @@
+package build
+
+import (
+	"context"
+	"io/ioutil"
+
+	dockerremote "github.com/containerd/containerd/remotes/docker"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/distribution/reference"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// The Docker config file stores Docker Hub credentials under its v1 index URL.
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// Returns true if the image is in the registry in its name.
+//
+// Buildkit doesn't have a way to list the images it's built, so after
+// a restart, this is how we find out if an image we pushed is still usable.
+// Any error (e.g., the registry is down, or we don't have credentials)
+// is treated as "not found", so that we rebuild the image.
+func registryHasImage(ctx context.Context, ref reference.Named) bool {
+	resolver := dockerremote.NewResolver(dockerremote.ResolverOptions{
+		Hosts: dockerremote.ConfigureDefaultRegistries(
+			// Local registries (e.g., for KIND) usually don't have TLS.
+			dockerremote.WithPlainHTTP(dockerremote.MatchLocalhost),
+			dockerremote.WithAuthorizer(dockerremote.NewDockerAuthorizer(
+				dockerremote.WithAuthCreds(registryCreds))),
+		),
+	})
+
+	_, _, err := resolver.Resolve(ctx, ref.String())
+	if err != nil {
+		logger.Get(ctx).Debugf("Looking up image %s in registry: %v", ref.String(), err)
+		return false
+	}
+	return true
+}
+
+// Reads registry credentials from the Docker config file.
+func registryCreds(host string) (string, string, error) {
+	key := host
+	if host == "registry-1.docker.io" {
+		key = dockerHubAuthKey
+	}
+
+	configFile := config.LoadDefaultConfigFile(ioutil.Discard)
+	auth, err := configFile.GetAuthConfig(key)
+	if err != nil {
+		return "", "", err
+	}
+	if auth.IdentityToken != "" {
+		return "", auth.IdentityToken, nil
+	}
+	return auth.Username, auth.Password, nil
+}
@@ -155,9 +155,16 @@ func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs
 		return container.TaggedRefs{}, nil, err
 	}
 
+	platforms := strings.Join(specPlatforms(spec), ",")
+	clusterPlatform := ClusterPlatform(cluster)
+	if clusterPlatform != "" && !platformsContain(specPlatforms(spec), clusterPlatform) {
+		logger.Get(ctx).Warnf("Building for platform %s, but the cluster runs %s. "+
+			"Containers may fail to start with an exec format error.", platforms, clusterPlatform)
+	}
+
 	platformSuffix := ""
-	if spec.Platform != "" {
-		platformSuffix = fmt.Sprintf(" for platform %s", spec.Platform)
+	if platforms != "" {
+		platformSuffix = fmt.Sprintf(" for platform %s", platforms)
 	}
 	logger.Get(ctx).Infof("Building Dockerfile%s:\n%s\n", platformSuffix, indent(spec.DockerfileContents, "  "))
 
@@ -189,7 +196,7 @@ func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs
 		}
 
 		if err != nil {
-			return container.TaggedRefs{}, stages, err
+			return container.TaggedRefs{}, stages, explainPlatformError(err, platforms)
 		}
 	}
 
@@ -201,6 +208,36 @@ func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs
 	return tagged, stages, nil
 }
 
+// Returns true if the list of platforms includes the given platform.
+func platformsContain(platforms []string, platform string) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// The errors Buildkit emits when it can't build for the target platform are
+// pretty cryptic, so tell people what to do about them.
+func explainPlatformError(err error, platform string) error {
+	msg := err.Error()
+	if strings.Contains(msg, "exec format error") {
+		target := "the target platform"
+		if platform != "" {
+			target = fmt.Sprintf("platform %s", platform)
+		}
+		return fmt.Errorf("%v\nThe builder can't run binaries for %s. "+
+			"To build for other architectures, install QEMU emulators with:\n  %s",
+			err, target, docker.BinfmtInstallCommand)
+	}
+	if strings.Contains(msg, "does not currently support exporting manifest lists") {
+		return fmt.Errorf("%v\nThe Docker image store can't hold multi-platform images. "+
+			"Enable the containerd image store in Docker, or build for a single platform.", err)
+	}
+	return err
+}
+
 // A helper function that builds the paths to the given docker image,
 // then returns the output digest.
 func (d *DockerBuilder) buildToDigest(ctx context.Context, spec v1alpha1.DockerImageSpec, filter model.PathMatcher, allowBuildkit bool) (digest.Digest, []v1alpha1.DockerImageStageStatus, error) {
@@ -304,6 +341,7 @@ func cleanupDockerBuildError(err string) string {
 	// this is pretty much always the same, and meaningless noise to most users
 	ret := strings.TrimPrefix(err, "failed to solve with frontend dockerfile.v0: ")
 	ret = strings.TrimPrefix(ret, "failed to solve with frontend gateway.v0: ")
+	ret = strings.TrimPrefix(ret, "failed to solve: ")
 	ret = strings.TrimPrefix(ret, "rpc error: code = Unknown desc = ")
 	ret = strings.TrimPrefix(ret, "failed to build LLB: ")
 	for _, re := range dockerBuildCleanupRexes {
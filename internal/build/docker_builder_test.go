@@ -173,3 +173,22 @@ func TestCleanUpBuildKitErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestExplainPlatformError(t *testing.T) {
+	err := explainPlatformError(fmt.Errorf("process \"/bin/sh -c make\" did not complete: exec format error"), "linux/arm64")
+	assert.Contains(t, err.Error(), "can't run binaries for platform linux/arm64")
+	assert.Contains(t, err.Error(), docker.BinfmtInstallCommand)
+
+	err = explainPlatformError(fmt.Errorf("docker exporter does not currently support exporting manifest lists"), "linux/amd64,linux/arm64")
+	assert.Contains(t, err.Error(), "Enable the containerd image store")
+
+	err = explainPlatformError(fmt.Errorf("some made up explosion"), "linux/amd64")
+	assert.Equal(t, "some made up explosion", err.Error())
+}
+
+func TestPlatformsContain(t *testing.T) {
+	assert.True(t, platformsContain([]string{"linux/amd64"}, "linux/amd64"))
+	assert.True(t, platformsContain([]string{"linux/amd64", "linux/arm64"}, "linux/arm64"))
+	assert.False(t, platformsContain([]string{"linux/amd64"}, "linux/arm64"))
+	assert.False(t, platformsContain(nil, "linux/arm64"))
+}
@@ -4,36 +4,59 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"k8s.io/apimachinery/pkg/types"
 
-	"github.com/tilt-dev/clusterid"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/ignore"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type ImageBuilder struct {
 	db    *DockerBuilder
+	bkb   *BuildkitBuilder
 	custb *CustomBuilder
-	kl    KINDLoader
+	il    ImageLoader
+	cache *ImageCache
 }
 
-func NewImageBuilder(db *DockerBuilder, custb *CustomBuilder, kl KINDLoader) *ImageBuilder {
+func NewImageBuilder(db *DockerBuilder, bkb *BuildkitBuilder, custb *CustomBuilder, il ImageLoader, cache *ImageCache) *ImageBuilder {
 	return &ImageBuilder{
 		db:    db,
+		bkb:   bkb,
 		custb: custb,
-		kl:    kl,
+		il:    il,
+		cache: cache,
 	}
 }
 
+// Returns the builder for Dockerfiles: the cluster's Buildkit daemon
+// if it has one, or the Docker daemon otherwise.
+func (ib *ImageBuilder) dockerfileBuilder(cluster *v1alpha1.Cluster) DockerfileBuilder {
+	bkb := ib.bkb.ForCluster(cluster)
+	if bkb != nil {
+		return bkb
+	}
+	return ib.db
+}
+
 func (ib *ImageBuilder) CanReuseRef(ctx context.Context, iTarget model.ImageTarget, ref reference.NamedTagged) (bool, error) {
 	switch iTarget.BuildDetails.(type) {
 	case model.DockerBuild:
+		// Images built by Buildkit never make it to the Docker daemon,
+		// so check those first.
+		if ib.bkb != nil {
+			exists, err := ib.bkb.ImageExists(ctx, ref)
+			if err != nil || exists {
+				return exists, err
+			}
+		}
 		return ib.db.ImageExists(ctx, ref)
 	case model.CustomBuild:
 		// Custom build doesn't have a good way to check if the ref still exists in the image
@@ -44,6 +67,19 @@ func (ib *ImageBuilder) CanReuseRef(ctx context.Context, iTarget model.ImageTarg
 		"DockerBuild nor CustomBuild)", iTarget.ImageMapSpec.Selector)
 }
 
+// Forget an image that's no longer used, so that builders
+// don't hang onto it.
+func (ib *ImageBuilder) ForgetImage(ref string) {
+	if ref == "" {
+		return
+	}
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return
+	}
+	ib.bkb.ForgetImage(named)
+}
+
 // Build the image, and push it if necessary.
 //
 // Note that this function can return partial results on an error.
@@ -54,21 +90,86 @@ func (ib *ImageBuilder) Build(ctx context.Context,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	ps *PipelineState) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error) {
-	refs, stages, err := ib.buildOnly(ctx, iTarget, cluster, imageMaps, ps)
-	if err != nil {
-		return refs, stages, err
+	digest := ib.digest(ctx, iTarget, cluster, imageMaps)
+	refs, stages, ok := ib.loadFromCache(ctx, iTarget, cluster, digest, ps)
+	if !ok {
+		var err error
+		refs, stages, err = ib.buildOnly(ctx, iTarget, cluster, imageMaps, ps)
+		if err != nil {
+			return refs, stages, err
+		}
+
+		err = ib.cache.Put(digest, refs, time.Now())
+		if err != nil {
+			logger.Get(ctx).Debugf("Error writing image cache: %v", err)
+		}
 	}
 
+	// Even if we found the image in the cache, the cluster may not have it yet.
 	pushStage := ib.push(ctx, refs, ps, iTarget, cluster)
 	if pushStage != nil {
 		stages = append(stages, *pushStage)
 	}
 
 	if pushStage != nil && pushStage.Error != "" {
-		err = errors.New(pushStage.Error)
+		return refs, stages, errors.New(pushStage.Error)
 	}
 
-	return refs, stages, err
+	return refs, stages, nil
+}
+
+// Computes the digest of the image's inputs for the ImageCache,
+// or the empty string if the image can't be cached.
+//
+// We only cache images built by the local Docker daemon, because that's
+// the only image store we can check. The push stage takes care of getting
+// a cached image to the registry or cluster.
+func (ib *ImageBuilder) digest(ctx context.Context,
+	iTarget model.ImageTarget,
+	cluster *v1alpha1.Cluster,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) string {
+	if ib.cache == nil || !iTarget.IsDockerBuild() || ib.bkb.ForCluster(cluster) != nil {
+		return ""
+	}
+
+	digest, err := ImageTargetDigest(iTarget, cluster, imageMaps)
+	if err != nil {
+		logger.Get(ctx).Debugf("Skipping image cache: %v", err)
+		return ""
+	}
+	return digest
+}
+
+// Looks for an image from a previous build with the same inputs
+// that still exists in the local Docker daemon.
+func (ib *ImageBuilder) loadFromCache(ctx context.Context,
+	iTarget model.ImageTarget,
+	cluster *v1alpha1.Cluster,
+	digest string,
+	ps *PipelineState) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, bool) {
+	refs, ok := ib.cache.Get(digest)
+	if !ok {
+		return container.TaggedRefs{}, nil, false
+	}
+
+	exists, err := ib.db.ImageExists(ctx, refs.LocalRef)
+	if err != nil || !exists {
+		return container.TaggedRefs{}, nil, false
+	}
+
+	startTime := apis.NowMicro()
+	ps.StartPipelineStep(ctx, "Loading cached image: [%s]", iTarget.ImageMapSpec.Selector)
+	ps.Printf(ctx, "Inputs unchanged since last build. Reusing %s", container.FamiliarString(refs.LocalRef))
+	ps.EndPipelineStep(ctx)
+	endTime := apis.NowMicro()
+
+	stage := v1alpha1.DockerImageStageStatus{
+		Name:       "cached",
+		Cached:     true,
+		StartedAt:  &startTime,
+		FinishedAt: &endTime,
+	}
+	return refs, []v1alpha1.DockerImageStageStatus{stage}, true
 }
 
 // Build the image, but don't do any push.
@@ -91,7 +192,7 @@ func (ib *ImageBuilder) buildOnly(ctx context.Context,
 		defer ps.EndPipelineStep(ctx)
 
 		filter := ignore.CreateBuildContextFilter(bd.DockerImageSpec.ContextIgnores)
-		return ib.db.BuildImage(ctx, ps, refs, bd.DockerImageSpec,
+		return ib.dockerfileBuilder(cluster).BuildImage(ctx, ps, refs, bd.DockerImageSpec,
 			cluster,
 			imageMaps,
 			filter)
@@ -141,24 +242,45 @@ func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps
 		return nil
 	}
 
-	if ib.db.WillBuildToKubeContext(k8s.KubeContext(k8sConnStatus(cluster).Context)) {
+	builder := DockerfileBuilder(ib.db)
+	if iTarget.IsDockerBuild() {
+		builder = ib.dockerfileBuilder(cluster)
+	}
+
+	if builder.WillBuildToKubeContext(k8s.KubeContext(k8sConnStatus(cluster).Context)) {
 		ps.Printf(ctx, "Skipping push: building on cluster's container runtime")
 		return nil
 	}
 
 	startTime := apis.NowMicro()
 	var err error
-	if ib.shouldUseKINDLoad(refs, cluster) {
-		ps.Printf(ctx, "Loading image to KIND")
-		err := ib.kl.LoadToKIND(ps.AttachLogger(ctx), cluster, refs.LocalRef)
+	if bkb, ok := builder.(*BuildkitBuilder); ok {
+		err = bkb.PushImage(ps.AttachLogger(ctx), refs.LocalRef)
+		if err != nil {
+			endTime := apis.NowMicro()
+			return &v1alpha1.DockerImageStageStatus{
+				Name:       "buildkit push",
+				StartedAt:  &startTime,
+				FinishedAt: &endTime,
+				Error:      fmt.Sprintf("buildkit push: %v", err),
+			}
+		}
+		ps.Printf(ctx, "Skipping push: Buildkit at %s pushed the image during the build", bkb.conn.Host)
+		return nil
+	}
+
+	if ib.shouldUseImageLoader(refs, cluster) {
+		product := k8sConnStatus(cluster).Product
+		ps.Printf(ctx, "Loading image into %s nodes", product)
+		err := ib.il.LoadImage(ps.AttachLogger(ctx), cluster, refs.LocalRef)
 		endTime := apis.NowMicro()
 		stage := &v1alpha1.DockerImageStageStatus{
-			Name:       "kind load",
+			Name:       "image load",
 			StartedAt:  &startTime,
 			FinishedAt: &endTime,
 		}
 		if err != nil {
-			stage.Error = fmt.Sprintf("Error loading image to KIND: %v", err)
+			stage.Error = fmt.Sprintf("Error loading image into %s: %v", product, err)
 		}
 		return stage
 	}
@@ -178,25 +300,15 @@ func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps
 	return stage
 }
 
-func (ib *ImageBuilder) shouldUseKINDLoad(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
-	isKIND := k8sConnStatus(cluster).Product == string(clusterid.ProductKIND)
-	if !isKIND {
-		return false
-	}
-
-	// if we're using KIND and the image has a separate ref by which it's referred to
-	// in the cluster, that implies that we have a local registry in place, and should
-	// push to that instead of using KIND load.
-	if refs.LocalRef.String() != refs.ClusterRef.String() {
-		return false
-	}
-
-	hasRegistry := cluster.Status.Registry != nil && cluster.Status.Registry.Host != ""
-	if hasRegistry {
+func (ib *ImageBuilder) shouldUseImageLoader(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
+	if cluster == nil || cluster.Status.ImageLoader != v1alpha1.ImageLoaderContainerd {
 		return false
 	}
 
-	return true
+	// If the image has a separate ref by which it's referred to in the cluster,
+	// that implies that we have a registry in place, and should push to that
+	// instead of loading the image directly.
+	return refs.LocalRef.String() == refs.ClusterRef.String()
 }
 
 func k8sConnStatus(cluster *v1alpha1.Cluster) *v1alpha1.KubernetesClusterConnectionStatus {
@@ -0,0 +1,273 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/pkg/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/ignore"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+)
+
+const imageCacheFile = "image-cache.json"
+
+// Don't let the cache grow forever.
+const imageCacheMaxEntries = 500
+
+// ImageCache remembers the images that Tilt built in previous sessions,
+// keyed by a digest of everything that went into the build.
+//
+// When Tilt starts up, it can skip building an image whose inputs
+// haven't changed since the last session, as long as that image still exists.
+//
+// A nil ImageCache is valid, and never has any images.
+type ImageCache struct {
+	path string
+
+	mu sync.Mutex
+}
+
+type imageCacheEntry struct {
+	LocalRef   string    `json:"localRef"`
+	ClusterRef string    `json:"clusterRef"`
+	BuiltAt    time.Time `json:"builtAt"`
+}
+
+func NewImageCache(path string) *ImageCache {
+	return &ImageCache{path: path}
+}
+
+func ProvideImageCache(dir *dirs.TiltDevDir) *ImageCache {
+	return NewImageCache(filepath.Join(dir.Root(), imageCacheFile))
+}
+
+// Get the refs of the image last built with the given digest.
+func (c *ImageCache) Get(digest string) (container.TaggedRefs, bool) {
+	if c == nil || digest == "" {
+		return container.TaggedRefs{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.read()[digest]
+	if !ok {
+		return container.TaggedRefs{}, false
+	}
+	localRef, err := container.ParseNamedTagged(entry.LocalRef)
+	if err != nil {
+		return container.TaggedRefs{}, false
+	}
+	clusterRef, err := container.ParseNamedTagged(entry.ClusterRef)
+	if err != nil {
+		return container.TaggedRefs{}, false
+	}
+	return container.TaggedRefs{LocalRef: localRef, ClusterRef: clusterRef}, true
+}
+
+// Record that the image with the given digest was built as refs.
+func (c *ImageCache) Put(digest string, refs container.TaggedRefs, now time.Time) error {
+	if c == nil || digest == "" || refs.LocalRef == nil || refs.ClusterRef == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	// Other Tilt processes share the cache, so hold a file lock
+	// while we read, update, and write it.
+	lock := flock.New(c.path + ".lock")
+	err = lock.Lock()
+	if err != nil {
+		return errors.Wrap(err, "locking image cache")
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	entries := c.read()
+	entries[digest] = imageCacheEntry{
+		LocalRef:   refs.LocalRef.String(),
+		ClusterRef: refs.ClusterRef.String(),
+		BuiltAt:    now,
+	}
+
+	if len(entries) > imageCacheMaxEntries {
+		digests := make([]string, 0, len(entries))
+		for d := range entries {
+			digests = append(digests, d)
+		}
+		sort.Slice(digests, func(i, j int) bool {
+			return entries[digests[i]].BuiltAt.Before(entries[digests[j]].BuiltAt)
+		})
+		for _, d := range digests[:len(digests)-imageCacheMaxEntries] {
+			delete(entries, d)
+		}
+	}
+
+	contents, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename, so that readers
+	// never see a partially-written cache.
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), imageCacheFile+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(contents)
+	closeErr := tmp.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Reads the cache from disk. A missing or corrupt cache is treated as empty.
+func (c *ImageCache) read() map[string]imageCacheEntry {
+	entries := make(map[string]imageCacheEntry)
+	contents, err := os.ReadFile(c.path)
+	if err != nil {
+		return entries
+	}
+	err = json.Unmarshal(contents, &entries)
+	if err != nil {
+		return make(map[string]imageCacheEntry)
+	}
+	return entries
+}
+
+// ImageTargetDigest computes a digest of everything that goes into a Docker build:
+// the Dockerfile, build args, target, and other options; the images it depends on;
+// the refs it will be tagged as; and the files in the build context, minus ignores.
+//
+// Build context files are digested by their contents, so that a checkout or
+// a `touch` that leaves the files the same still hits the cache.
+//
+// Returns an empty digest for images that we don't know how to digest
+// (e.g., custom builds).
+func ImageTargetDigest(iTarget model.ImageTarget, cluster *v1alpha1.Cluster, imageMaps map[ktypes.NamespacedName]*v1alpha1.ImageMap) (string, error) {
+	db, ok := iTarget.BuildDetails.(model.DockerBuild)
+	if !ok {
+		return "", nil
+	}
+
+	refs, err := iTarget.Refs(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	spec := InjectClusterPlatform(db.DockerImageSpec, cluster)
+	spec, err = InjectImageDependencies(spec, imageMaps)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	_, _ = fmt.Fprintf(h, "spec %s\n", specJSON)
+	_, _ = fmt.Fprintf(h, "local %s\ncluster %s\n", refs.LocalRef(), refs.ClusterRef())
+
+	filter := ignore.CreateBuildContextFilter(spec.ContextIgnores)
+	err = digestContext(h, spec.Context, filter)
+	if err != nil {
+		return "", errors.Wrap(err, "digesting build context")
+	}
+
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// Writes the path, mode, and contents of every file
+// in the build context to the hash.
+func digestContext(h hash.Hash, root string, filter model.PathMatcher) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != root {
+			matches, err := filter.Matches(path)
+			if err != nil {
+				return err
+			}
+			if matches {
+				if info.IsDir() {
+					skip, err := filter.MatchesEntireDir(path)
+					if err != nil {
+						return err
+					}
+					if skip {
+						return filepath.SkipDir
+					}
+				}
+				return nil
+			}
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%s %o", filepath.ToSlash(rel), info.Mode())
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, " -> %s", target)
+		case info.Mode().IsRegular():
+			_, _ = fmt.Fprintf(h, " %d ", info.Size())
+			err := digestFile(h, path)
+			if err != nil {
+				return err
+			}
+		}
+		_, _ = fmt.Fprintf(h, "\n")
+		return nil
+	})
+}
+
+func digestFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = io.Copy(h, f)
+	return err
+}
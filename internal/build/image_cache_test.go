@@ -0,0 +1,215 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestImageCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image-cache.json")
+	refs := taggedRefsFromString(t, "gcr.io/foo:tilt-1234")
+
+	c := NewImageCache(path)
+	_, ok := c.Get("sha256:abc")
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put("sha256:abc", refs, time.Now()))
+
+	// A new cache (i.e., a new session) should see the old entry.
+	actual, ok := NewImageCache(path).Get("sha256:abc")
+	require.True(t, ok)
+	assert.Equal(t, refs.LocalRef.String(), actual.LocalRef.String())
+	assert.Equal(t, refs.ClusterRef.String(), actual.ClusterRef.String())
+}
+
+func TestImageCachePrunesOldest(t *testing.T) {
+	c := NewImageCache(filepath.Join(t.TempDir(), "image-cache.json"))
+	refs := taggedRefsFromString(t, "gcr.io/foo:tilt-1234")
+
+	start := time.Now()
+	for i := 0; i <= imageCacheMaxEntries; i++ {
+		require.NoError(t, c.Put(fmt.Sprintf("sha256:%d", i), refs, start.Add(time.Duration(i)*time.Second)))
+	}
+
+	_, ok := c.Get("sha256:0")
+	assert.False(t, ok)
+	_, ok = c.Get(fmt.Sprintf("sha256:%d", imageCacheMaxEntries))
+	assert.True(t, ok)
+}
+
+func TestImageCacheConcurrentPuts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image-cache.json")
+	refs := taggedRefsFromString(t, "gcr.io/foo:tilt-1234")
+
+	// Each cache stands in for a separate Tilt process.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, NewImageCache(path).Put(fmt.Sprintf("sha256:%d", i), refs, time.Now()))
+		}(i)
+	}
+	wg.Wait()
+
+	c := NewImageCache(path)
+	for i := 0; i < 10; i++ {
+		_, ok := c.Get(fmt.Sprintf("sha256:%d", i))
+		assert.True(t, ok, "missing entry %d", i)
+	}
+
+	tmps, err := filepath.Glob(path + ".*.tmp")
+	require.NoError(t, err)
+	assert.Empty(t, tmps)
+}
+
+func TestImageCacheNil(t *testing.T) {
+	var c *ImageCache
+	require.NoError(t, c.Put("sha256:abc", taggedRefsFromString(t, "gcr.io/foo:tilt-1234"), time.Now()))
+	_, ok := c.Get("sha256:abc")
+	assert.False(t, ok)
+}
+
+func TestImageTargetDigest(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.WriteFile("main.go", "package main")
+	f.WriteFile("tmp/scratch.txt", "hello")
+
+	iTarget := f.imageTarget(v1alpha1.DockerImageSpec{
+		DockerfileContents: "FROM alpine\nCOPY . /",
+		Context:            f.Path(),
+		ContextIgnores: []v1alpha1.IgnoreDef{
+			{BasePath: f.JoinPath("tmp")},
+		},
+	})
+
+	digest := f.digest(iTarget)
+	assert.Contains(t, digest, "sha256:")
+	assert.Equal(t, digest, f.digest(iTarget))
+
+	// Ignored files don't change the digest.
+	f.WriteFile("tmp/scratch.txt", "goodbye")
+	assert.Equal(t, digest, f.digest(iTarget))
+
+	// Context files do.
+	f.WriteFile("main.go", "package main\n\nfunc main() {}")
+	digest2 := f.digest(iTarget)
+	assert.NotEqual(t, digest, digest2)
+
+	// Touching a file doesn't.
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(f.JoinPath("main.go"), later, later))
+	assert.Equal(t, digest2, f.digest(iTarget))
+
+	// Changing a file does, even if its size and modification time stay the same.
+	f.WriteFile("main.go", "package mian\n\nfunc main() {}")
+	require.NoError(t, os.Chtimes(f.JoinPath("main.go"), later, later))
+	digest3 := f.digest(iTarget)
+	assert.NotEqual(t, digest2, digest3)
+
+	// So do build args.
+	spec := iTarget.DockerBuildInfo().DockerImageSpec
+	spec.Args = []string{"FOO=bar"}
+	assert.NotEqual(t, digest3, f.digest(iTarget.WithDockerImage(spec)))
+}
+
+func TestImageTargetDigestCustomBuild(t *testing.T) {
+	iTarget := model.MustNewImageTarget(container.MustParseSelector("gcr.io/foo")).
+		WithBuildDetails(model.CustomBuild{})
+	digest, err := ImageTargetDigest(iTarget, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", digest)
+}
+
+func TestImageBuilderReusesCachedImage(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.WriteFile("main.go", "package main")
+	iTarget := f.imageTarget(v1alpha1.DockerImageSpec{
+		DockerfileContents: "FROM alpine\nCOPY . /",
+		Context:            f.Path(),
+	})
+
+	cache := NewImageCache(filepath.Join(t.TempDir(), "image-cache.json"))
+	cluster := &v1alpha1.Cluster{
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{Docker: &v1alpha1.DockerClusterConnection{}},
+		},
+	}
+
+	ib := NewImageBuilder(f.b, nil, nil, nil, cache)
+	refs, _, err := ib.Build(f.ctx, iTarget, cluster, nil, f.ps)
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.fakeDocker.BuildCount)
+
+	// If the image is gone, we have to rebuild.
+	_, _, err = ib.Build(f.ctx, iTarget, cluster, nil, f.ps)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.fakeDocker.BuildCount)
+
+	// If the image still exists, we can skip the build.
+	f.fakeDocker.Images[refs.LocalRef.String()] = types.ImageInspect{}
+	cachedRefs, stages, err := ib.Build(f.ctx, iTarget, cluster, nil, f.ps)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.fakeDocker.BuildCount)
+	assert.Equal(t, refs.LocalRef.String(), cachedRefs.LocalRef.String())
+	require.Len(t, stages, 1)
+	assert.True(t, stages[0].Cached)
+
+	// Changing the inputs invalidates the cache.
+	f.WriteFile("main.go", "package main\n\nfunc main() {}")
+	_, _, err = ib.Build(f.ctx, iTarget, cluster, nil, f.ps)
+	require.NoError(t, err)
+	assert.Equal(t, 3, f.fakeDocker.BuildCount)
+}
+
+func TestImageBuilderSkipsCacheForBuildkit(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.WriteFile("main.go", "package main")
+	iTarget := f.imageTarget(v1alpha1.DockerImageSpec{
+		DockerfileContents: "FROM alpine\nCOPY . /",
+		Context:            f.Path(),
+	})
+
+	cache := NewImageCache(filepath.Join(t.TempDir(), "image-cache.json"))
+	ib := NewImageBuilder(f.b, NewBuildkitBuilder(realClock{}), nil, nil, cache)
+
+	// We can't check whether Buildkit still has an image, so we don't cache it.
+	cluster := &v1alpha1.Cluster{
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{Kubernetes: &v1alpha1.KubernetesClusterConnection{}},
+			Buildkit:   &v1alpha1.BuildkitClusterConnection{Host: "tcp://buildkitd:1234"},
+		},
+	}
+	assert.Equal(t, "", ib.digest(f.ctx, iTarget, cluster, nil))
+
+	cluster.Spec.Buildkit = nil
+	assert.Contains(t, ib.digest(f.ctx, iTarget, cluster, nil), "sha256:")
+}
+
+func (f *dockerBuildFixture) imageTarget(spec v1alpha1.DockerImageSpec) model.ImageTarget {
+	return model.MustNewImageTarget(container.MustParseSelector("gcr.io/foo")).WithDockerImage(spec)
+}
+
+func (f *dockerBuildFixture) digest(iTarget model.ImageTarget) string {
+	digest, err := ImageTargetDigest(iTarget, nil, nil)
+	require.NoError(f.t, err)
+	return digest
+}
+
+func taggedRefsFromString(t *testing.T, s string) container.TaggedRefs {
+	ref, err := container.ParseNamedTagged(s)
+	require.NoError(t, err)
+	return container.TaggedRefs{LocalRef: ref, ClusterRef: ref}
+}
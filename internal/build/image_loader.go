@@ -0,0 +1,253 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Loads images directly into the container runtime of a local cluster,
+// for clusters that don't have a registry.
+type ImageLoader interface {
+	LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error
+}
+
+// How to find the node containers of a local cluster that runs in Docker.
+type containerdNodes struct {
+	// The Docker label that identifies the cluster's containers.
+	clusterLabel string
+
+	// The prefix that the product adds to the cluster name in the kubeconfig.
+	kubeconfigPrefix string
+
+	// The Docker label for a container's role in the cluster, and the roles
+	// that are Kubernetes nodes (rather than, e.g., load balancers).
+	roleLabel string
+	nodeRoles map[string]bool
+}
+
+// Local cluster products that we know how to load images into, keyed by product.
+var containerdNodesByProduct = map[clusterid.Product]containerdNodes{
+	clusterid.ProductKIND: {
+		clusterLabel:     "io.x-k8s.kind.cluster",
+		kubeconfigPrefix: "kind-",
+	},
+	clusterid.ProductK3D: {
+		clusterLabel:     "k3d.cluster",
+		kubeconfigPrefix: "k3d-",
+		roleLabel:        "k3d.role",
+		nodeRoles:        map[string]bool{"server": true, "agent": true},
+	},
+	clusterid.ProductMinikube: {
+		// Only minikube's Docker driver runs nodes in containers.
+		clusterLabel: "name.minikube.sigs.k8s.io",
+	},
+}
+
+// Decides how to get locally-built images onto a Kubernetes cluster.
+//
+// If the cluster has a registry, we always push to it. Otherwise, we load
+// images directly into the containerd of local clusters that we know how to
+// find the nodes of.
+func ChooseImageLoader(product clusterid.Product, runtime container.Runtime, registry *v1alpha1.RegistryHosting) string {
+	if !container.IsEmptyRegistry(registry) {
+		return v1alpha1.ImageLoaderRegistry
+	}
+	if _, ok := containerdNodesByProduct[product]; !ok {
+		return v1alpha1.ImageLoaderRegistry
+	}
+
+	// KIND and k3d always run containerd, but minikube can run Docker.
+	if product == clusterid.ProductMinikube && runtime != container.RuntimeContainerd {
+		return v1alpha1.ImageLoaderRegistry
+	}
+	return v1alpha1.ImageLoaderContainerd
+}
+
+// Streams images from the Docker daemon into the containerd of each node
+// of the cluster, like `kind load docker-image` does.
+type containerdImageLoader struct {
+	dCli docker.Client
+}
+
+func NewImageLoader(dCli docker.Client) ImageLoader {
+	return &containerdImageLoader{dCli: dCli}
+}
+
+func (l *containerdImageLoader) LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	k8sConn := k8sConnStatus(cluster)
+	product := clusterid.Product(k8sConn.Product)
+	nodes, ok := containerdNodesByProduct[product]
+	if !ok {
+		return fmt.Errorf("loading images into %s clusters is not supported", product)
+	}
+
+	nodeContainers, err := l.nodeContainers(ctx, nodes, k8sConn.Cluster)
+	if err != nil {
+		return err
+	}
+	if len(nodeContainers) == 0 {
+		return fmt.Errorf("no node containers found for %s cluster %q. "+
+			"To load images without a registry, the cluster's nodes must run in Docker",
+			product, k8sConn.Cluster)
+	}
+
+	return l.loadIntoNodes(ctx, nodeContainers, ref)
+}
+
+// Returns the node containers, sorted by name.
+func (l *containerdImageLoader) nodeContainers(ctx context.Context, nodes containerdNodes, kubeconfigCluster string) ([]types.Container, error) {
+	name := strings.TrimPrefix(kubeconfigCluster, nodes.kubeconfigPrefix)
+	containers, err := l.dCli.ContainerList(ctx, types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", nodes.clusterLabel, name))),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing cluster nodes")
+	}
+
+	var result []types.Container
+	for _, c := range containers {
+		if nodes.roleLabel != "" && !nodes.nodeRoles[c.Labels[nodes.roleLabel]] {
+			continue
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return nodeName(result[i]) < nodeName(result[j])
+	})
+	return result, nil
+}
+
+// Exports the image from Docker once, and streams it to every node in parallel.
+func (l *containerdImageLoader) loadIntoNodes(ctx context.Context, nodes []types.Container, ref reference.NamedTagged) error {
+	tarball, err := l.dCli.ImageSave(ctx, []string{ref.String()})
+	if err != nil {
+		return errors.Wrapf(err, "exporting image %s", container.FamiliarString(ref))
+	}
+	defer func() {
+		_ = tarball.Close()
+	}()
+
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, nodeName(node))
+	}
+	pw := newProgressWriter(ctx, ioutil.Discard, "tilt-image-load",
+		fmt.Sprintf("Loading image into %s", strings.Join(names, ", ")))
+	pw.Init()
+	defer pw.Close()
+
+	g, ctx := errgroup.WithContext(ctx)
+	writers := []io.Writer{pw}
+	pipes := make([]*io.PipeWriter, 0, len(nodes))
+	for _, node := range nodes {
+		node := node
+		r, w := io.Pipe()
+		writers = append(writers, w)
+		pipes = append(pipes, w)
+		g.Go(func() error {
+			err := l.loadIntoNode(ctx, node, r)
+
+			// Unblock the tee if the import stopped reading early.
+			if err != nil {
+				_ = r.CloseWithError(err)
+			} else {
+				_ = r.Close()
+			}
+			return err
+		})
+	}
+
+	g.Go(func() error {
+		_, err := io.Copy(io.MultiWriter(writers...), tarball)
+		for _, w := range pipes {
+			_ = w.CloseWithError(err)
+		}
+		return err
+	})
+	return g.Wait()
+}
+
+func (l *containerdImageLoader) loadIntoNode(ctx context.Context, node types.Container, tarball io.Reader) error {
+	// Import the image the same way `kind load` does: all platforms
+	// (so that multi-platform images keep their index), unpacked into
+	// the snapshotter that the CRI plugin runs containers with.
+	argv := []string{"ctr", "--namespace=k8s.io", "images", "import", "--all-platforms", "--digests"}
+	snapshotter := l.nodeSnapshotter(ctx, node)
+	if snapshotter != "" {
+		argv = append(argv, "--snapshotter="+snapshotter)
+	}
+	argv = append(argv, "-")
+
+	// The import command's output isn't useful unless something goes wrong.
+	out := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.DebugLvl))
+	err := l.dCli.ExecInContainer(ctx, container.ID(node.ID), model.Cmd{Argv: argv}, tarball, out)
+	if err != nil {
+		return errors.Wrapf(err, "importing image into node %s", nodeName(node))
+	}
+	return nil
+}
+
+// Returns the snapshotter that the node's containerd runs containers with,
+// or "" if we can't tell (in which case ctr uses containerd's default).
+func (l *containerdImageLoader) nodeSnapshotter(ctx context.Context, node types.Container) string {
+	out := &strings.Builder{}
+	cmd := model.Cmd{Argv: []string{"containerd", "config", "dump"}}
+	err := l.dCli.ExecInContainer(ctx, container.ID(node.ID), cmd, nil, out)
+	if err != nil {
+		logger.Get(ctx).Debugf("Reading containerd config of node %s: %v", nodeName(node), err)
+		return ""
+	}
+	return parseSnapshotter(out.String())
+}
+
+// The config tables that set the CRI plugin's snapshotter, in containerd's
+// version 2 and version 3 config formats.
+var snapshotterTables = map[string]bool{
+	`[plugins."io.containerd.grpc.v1.cri".containerd]`: true,
+	`[plugins."io.containerd.cri.v1.images"]`:          true,
+}
+
+// Finds the CRI plugin's snapshotter in the output of `containerd config dump`.
+func parseSnapshotter(config string) string {
+	inTable := false
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inTable = snapshotterTables[line]
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "snapshotter" {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return ""
+}
+
+// Docker prefixes container names with a slash.
+func nodeName(c types.Container) string {
+	if len(c.Names) == 0 {
+		return container.ID(c.ID).ShortStr()
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
@@ -0,0 +1,179 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestChooseImageLoader(t *testing.T) {
+	registry := &v1alpha1.RegistryHosting{Host: "localhost:5000"}
+	for _, tc := range []struct {
+		name     string
+		product  clusterid.Product
+		runtime  container.Runtime
+		registry *v1alpha1.RegistryHosting
+		expected string
+	}{
+		{"kind", clusterid.ProductKIND, container.RuntimeContainerd, nil, v1alpha1.ImageLoaderContainerd},
+		{"kind with registry", clusterid.ProductKIND, container.RuntimeContainerd, registry, v1alpha1.ImageLoaderRegistry},
+		{"k3d", clusterid.ProductK3D, container.RuntimeUnknown, nil, v1alpha1.ImageLoaderContainerd},
+		{"minikube containerd", clusterid.ProductMinikube, container.RuntimeContainerd, nil, v1alpha1.ImageLoaderContainerd},
+		{"minikube docker", clusterid.ProductMinikube, container.RuntimeDocker, nil, v1alpha1.ImageLoaderRegistry},
+		{"gke", clusterid.ProductGKE, container.RuntimeContainerd, nil, v1alpha1.ImageLoaderRegistry},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ChooseImageLoader(tc.product, tc.runtime, tc.registry))
+		})
+	}
+}
+
+func loaderCluster(product clusterid.Product, name string) *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{
+		Status: v1alpha1.ClusterStatus{
+			Connection: &v1alpha1.ClusterConnectionStatus{
+				Kubernetes: &v1alpha1.KubernetesClusterConnectionStatus{
+					Product: string(product),
+					Cluster: name,
+				},
+			},
+			ImageLoader: v1alpha1.ImageLoaderContainerd,
+		},
+	}
+}
+
+const kindContainerdConfig = `version = 2
+
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+    sandbox_image = "registry.k8s.io/pause:3.7"
+
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      default_runtime_name = "runc"
+      snapshotter = "overlayfs"
+
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+        snapshotter = ""
+`
+
+func TestImageLoaderKIND(t *testing.T) {
+	dCli := docker.NewFakeClient()
+	dCli.SetContainerListOutput(map[string][]types.Container{
+		"io.x-k8s.kind.cluster=kind": {
+			{ID: "worker-id", Names: []string{"/kind-worker"}},
+			{ID: "control-plane-id", Names: []string{"/kind-control-plane"}},
+		},
+	})
+	dCli.ExecOutputs = map[string]string{"containerd config dump": kindContainerdConfig}
+
+	out := bufsync.NewThreadSafeBuffer()
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(out))
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	err := NewImageLoader(dCli).LoadImage(ctx, loaderCluster(clusterid.ProductKIND, "kind-kind"), ref)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Loading image into kind-control-plane, kind-worker: 45B")
+
+	// The image is exported once, and streamed to every node.
+	assert.Equal(t, []string{ref.String()}, dCli.SavedImages)
+
+	imports := make(map[string]docker.ExecCall)
+	for _, call := range dCli.ExecCalls {
+		if call.Cmd.Argv[0] == "ctr" {
+			imports[call.Container] = call
+		}
+	}
+	assert.Len(t, dCli.ExecCalls, 4)
+	for _, id := range []string{"control-plane-id", "worker-id"} {
+		call, ok := imports[id]
+		if assert.True(t, ok, "no import into %s", id) {
+			assert.Equal(t, []string{"ctr", "--namespace=k8s.io", "images", "import",
+				"--all-platforms", "--digests", "--snapshotter=overlayfs", "-"}, call.Cmd.Argv)
+			assert.Equal(t, "fake tarball of [gcr.io/foo/bar:tilt-build-1]", string(call.Stdin))
+		}
+	}
+}
+
+func TestImageLoaderImportError(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dCli := docker.NewFakeClient()
+	dCli.SetContainerListOutput(map[string][]types.Container{
+		"io.x-k8s.kind.cluster=kind": {{ID: "control-plane-id", Names: []string{"/kind-control-plane"}}},
+	})
+
+	// The snapshotter lookup fails, so ctr falls back to containerd's default.
+	// Then the import fails.
+	dCli.ExecErrorsToThrow = []error{fmt.Errorf("no containerd"), fmt.Errorf("exit status 1")}
+
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	err := NewImageLoader(dCli).LoadImage(ctx, loaderCluster(clusterid.ProductKIND, "kind-kind"), ref)
+	assert.EqualError(t, err, "importing image into node kind-control-plane: exit status 1")
+	if assert.Len(t, dCli.ExecCalls, 2) {
+		assert.Equal(t, []string{"ctr", "--namespace=k8s.io", "images", "import",
+			"--all-platforms", "--digests", "-"}, dCli.ExecCalls[1].Cmd.Argv)
+	}
+}
+
+func TestParseSnapshotter(t *testing.T) {
+	assert.Equal(t, "overlayfs", parseSnapshotter(kindContainerdConfig))
+	assert.Equal(t, "native", parseSnapshotter(`version = 3
+
+[plugins]
+  [plugins."io.containerd.cri.v1.images"]
+    snapshotter = "native"
+`))
+	assert.Equal(t, "", parseSnapshotter("version = 2\n"))
+}
+
+func TestImageLoaderK3DSkipsLoadBalancer(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dCli := docker.NewFakeClient()
+	dCli.SetContainerListOutput(map[string][]types.Container{
+		"k3d.cluster=dev": {
+			{ID: "server-id", Names: []string{"/k3d-dev-server-0"}, Labels: map[string]string{"k3d.role": "server"}},
+			{ID: "lb-id", Names: []string{"/k3d-dev-serverlb"}, Labels: map[string]string{"k3d.role": "loadbalancer"}},
+		},
+	})
+
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	err := NewImageLoader(dCli).LoadImage(ctx, loaderCluster(clusterid.ProductK3D, "k3d-dev"), ref)
+	require.NoError(t, err)
+
+	// One call to look up the snapshotter, and one to import.
+	if assert.Len(t, dCli.ExecCalls, 2) {
+		assert.Equal(t, "server-id", dCli.ExecCalls[0].Container)
+		assert.Equal(t, "server-id", dCli.ExecCalls[1].Container)
+	}
+}
+
+func TestImageLoaderNoNodes(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dCli := docker.NewFakeClient()
+	dCli.SetContainerListOutput(map[string][]types.Container{
+		"name.minikube.sigs.k8s.io=other": {{ID: "other-id"}},
+	})
+
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	err := NewImageLoader(dCli).LoadImage(ctx, loaderCluster(clusterid.ProductMinikube, "minikube"), ref)
+	assert.EqualError(t, err, `no node containers found for minikube cluster "minikube". `+
+		`To load images without a registry, the cluster's nodes must run in Docker`)
+}
+
+func TestImageLoaderUnsupportedProduct(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ref := container.MustParseNamedTagged("gcr.io/foo/bar:tilt-build-1")
+	err := NewImageLoader(docker.NewFakeClient()).
+		LoadImage(ctx, loaderCluster(clusterid.ProductGKE, "gke"), ref)
+	assert.EqualError(t, err, "loading images into gke clusters is not supported")
+}
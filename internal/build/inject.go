@@ -27,16 +27,39 @@ var validBuildkitArchSet = map[string]bool{
 
 // Create a new ImageTarget with the platform OS/Arch from the target cluster.
 func InjectClusterPlatform(spec v1alpha1.DockerImageSpec, cluster *v1alpha1.Cluster) v1alpha1.DockerImageSpec {
-	if spec.Platform != "" || cluster == nil {
+	if spec.Platform != "" || len(spec.Platforms) > 0 {
 		return spec
 	}
 
+	spec.Platform = ClusterPlatform(cluster)
+	return spec
+}
+
+// Returns the platforms that the image will be built for,
+// or nil if we're building for the builder's default platform.
+func specPlatforms(spec v1alpha1.DockerImageSpec) []string {
+	if len(spec.Platforms) > 0 {
+		return spec.Platforms
+	}
+	if spec.Platform != "" {
+		return []string{spec.Platform}
+	}
+	return nil
+}
+
+// Returns the OS/Arch of the target cluster in the form Buildkit expects
+// (e.g., linux/amd64), or "" if we don't know how to build for it.
+func ClusterPlatform(cluster *v1alpha1.Cluster) string {
+	if cluster == nil {
+		return ""
+	}
+
 	// Eventually, it might make sense to read the supported platforms
 	// off the buildkit server and negotiate the right one, but for
 	// now we hard-code a whitelist.
 	targetArch := cluster.Status.Arch
 	if !validBuildkitArchSet[targetArch] {
-		return spec
+		return ""
 	}
 
 	if targetArch == "arm" {
@@ -47,8 +70,7 @@ func InjectClusterPlatform(spec v1alpha1.DockerImageSpec, cluster *v1alpha1.Clus
 
 	// Currently Tilt only supports linux containers.
 	// We don't even build windows-compatible docker contexts.
-	spec.Platform = fmt.Sprintf("linux/%s", targetArch)
-	return spec
+	return fmt.Sprintf("linux/%s", targetArch)
 }
 
 // Create a new ImageTarget with the Dockerfiles rewritten with the injected images.
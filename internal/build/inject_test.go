@@ -0,0 +1,36 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestInjectClusterPlatform(t *testing.T) {
+	cluster := func(arch string) *v1alpha1.Cluster {
+		return &v1alpha1.Cluster{Status: v1alpha1.ClusterStatus{Arch: arch}}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		platform  string
+		platforms []string
+		cluster   *v1alpha1.Cluster
+		expected  string
+	}{
+		{"no cluster", "", nil, nil, ""},
+		{"cluster arch", "", nil, cluster("arm64"), "linux/arm64"},
+		{"arm", "", nil, cluster("arm"), "linux/arm/v7"},
+		{"unknown arch", "", nil, cluster("sparc"), ""},
+		{"explicit platform", "linux/amd64", nil, cluster("arm64"), "linux/amd64"},
+		{"multiple platforms", "", []string{"linux/amd64", "linux/arm64"}, cluster("arm64"), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := InjectClusterPlatform(v1alpha1.DockerImageSpec{Platform: tc.platform, Platforms: tc.platforms}, tc.cluster)
+			assert.Equal(t, tc.expected, spec.Platform)
+			assert.Equal(t, tc.platforms, spec.Platforms)
+		})
+	}
+}
@@ -22,8 +22,10 @@ func Options(archive io.Reader, spec v1alpha1.DockerImageSpec) docker.BuildOptio
 		ExtraTags:   spec.ExtraTags,
 		SecretSpecs: spec.Secrets,
 		CacheFrom:   spec.CacheFrom,
+		CacheTo:     spec.CacheTo,
 		PullParent:  spec.Pull,
 		Platform:    spec.Platform,
+		Platforms:   spec.Platforms,
 	}
 }
 
@@ -15,6 +15,8 @@ import (
 type ProgressWriter struct {
 	ctx                context.Context
 	delegate           io.Writer
+	progressID         string
+	label              string
 	createTime         time.Time
 	byteCount          int
 	lastPrintTime      time.Time
@@ -22,9 +24,15 @@ type ProgressWriter struct {
 }
 
 func NewProgressWriter(ctx context.Context, w io.Writer) *ProgressWriter {
+	return newProgressWriter(ctx, w, "tilt-context-upload", "Sending Docker build context")
+}
+
+func newProgressWriter(ctx context.Context, w io.Writer, progressID string, label string) *ProgressWriter {
 	return &ProgressWriter{
 		ctx:        ctx,
 		delegate:   w,
+		progressID: progressID,
+		label:      label,
 		createTime: time.Now(),
 	}
 }
@@ -61,9 +69,9 @@ func (w *ProgressWriter) Close() {
 }
 
 func (w *ProgressWriter) info(fields logger.Fields) {
-	fields[logger.FieldNameProgressID] = "tilt-context-upload"
+	fields[logger.FieldNameProgressID] = w.progressID
 	logger.Get(w.ctx).WithFields(fields).
-		Infof("Sending Docker build context: %s (%s)",
+		Infof("%s: %s (%s)", w.label,
 			units.HumanSize(float64(w.byteCount)),
 			time.Since(w.createTime).Truncate(time.Millisecond))
 }
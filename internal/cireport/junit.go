@@ -0,0 +1,133 @@
+package cireport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+// The JUnit schema isn't formally specified, but this is the subset
+// that GitLab, Jenkins, and most other CI systems agree on.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// Terminal escape sequences aren't valid XML, and CI systems won't render them anyway.
+var ansiRE = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+func stripANSI(s string) string {
+	return ansiRE.ReplaceAllString(s, "")
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// Each resource is a test suite, and each of its builds is a test case.
+//
+// A resource that crashed after a successful build gets an additional
+// failing "runtime" test case, so that the failure is attributed to it.
+func toJUnit(r Report) junitTestSuites {
+	result := junitTestSuites{Name: "tilt ci"}
+	total := 0.0
+	for _, res := range r.Resources {
+		suite := junitTestSuite{Name: string(res.Name)}
+		suiteTime := 0.0
+		for i, b := range res.Builds {
+			tc := junitTestCase{
+				Name:      fmt.Sprintf("build #%d: %s", i+1, b.Reason),
+				Classname: string(res.Name),
+				Time:      junitTime(b.DurationSeconds),
+				SystemOut: stripANSI(b.Log),
+			}
+			if b.Error != "" {
+				tc.Failure = &junitMessage{Message: b.Error, Type: "BuildError", Body: b.Error}
+				suite.Failures++
+			}
+			if suite.Timestamp == "" && !b.StartTime.IsZero() {
+				suite.Timestamp = b.StartTime.UTC().Format(time.RFC3339)
+			}
+			suiteTime += b.DurationSeconds
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		if len(res.Builds) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "build",
+				Classname: string(res.Name),
+				Time:      junitTime(0),
+				Skipped:   &junitMessage{Message: "Resource never finished building"},
+			})
+			suite.Skipped++
+		}
+
+		if res.RuntimeError != "" {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "runtime",
+				Classname: string(res.Name),
+				Time:      junitTime(0),
+				Failure:   &junitMessage{Message: res.RuntimeError, Type: "RuntimeError", Body: res.RuntimeError},
+			})
+			suite.Failures++
+		}
+
+		suite.Tests = len(suite.Cases)
+		suite.Time = junitTime(suiteTime)
+		total += suiteTime
+
+		result.Tests += suite.Tests
+		result.Failures += suite.Failures
+		result.Skipped += suite.Skipped
+		result.Suites = append(result.Suites, suite)
+	}
+	result.Time = junitTime(total)
+	return result
+}
+
+func WriteJUnit(w io.Writer, r Report) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(toJUnit(r))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
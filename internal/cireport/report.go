@@ -0,0 +1,92 @@
+// Package cireport summarizes the results of a `tilt ci` run in formats
+// that CI systems understand (JUnit XML and JSON).
+package cireport
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type Report struct {
+	Resources []Resource `json:"resources"`
+}
+
+type Resource struct {
+	Name          model.ManifestName     `json:"name"`
+	UpdateStatus  v1alpha1.UpdateStatus  `json:"updateStatus"`
+	RuntimeStatus v1alpha1.RuntimeStatus `json:"runtimeStatus"`
+	RuntimeError  string                 `json:"runtimeError,omitempty"`
+
+	// Completed builds, oldest first.
+	Builds []Build `json:"builds"`
+}
+
+type Build struct {
+	StartTime       time.Time `json:"startTime"`
+	FinishTime      time.Time `json:"finishTime"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Reason          string    `json:"reason"`
+	Error           string    `json:"error,omitempty"`
+	WarningCount    int       `json:"warningCount,omitempty"`
+	Log             string    `json:"log"`
+}
+
+// NewReport builds a report from the engine state.
+//
+// The caller must hold the state lock, because we read logs from the LogStore.
+func NewReport(state store.EngineState) Report {
+	report := Report{}
+	for _, ms := range state.GetTiltfileStates() {
+		report.Resources = append(report.Resources, newResource(state, ms, model.TriggerModeAuto))
+	}
+	for _, mt := range state.Targets() {
+		report.Resources = append(report.Resources, newResource(state, mt.State, mt.Manifest.TriggerMode))
+	}
+	return report
+}
+
+func newResource(state store.EngineState, ms *store.ManifestState, triggerMode model.TriggerMode) Resource {
+	r := Resource{
+		Name:          ms.Name,
+		UpdateStatus:  ms.UpdateStatus(triggerMode),
+		RuntimeStatus: ms.RuntimeStatus(triggerMode),
+		Builds:        []Build{},
+	}
+
+	if r.RuntimeStatus == v1alpha1.RuntimeStatusError && ms.RuntimeState != nil {
+		if err := ms.RuntimeState.RuntimeStatusError(); err != nil {
+			r.RuntimeError = err.Error()
+		}
+	}
+
+	// BuildHistory is sorted most recent first.
+	for i := len(ms.BuildHistory) - 1; i >= 0; i-- {
+		br := ms.BuildHistory[i]
+		b := Build{
+			StartTime:       br.StartTime,
+			FinishTime:      br.FinishTime,
+			DurationSeconds: br.Duration().Seconds(),
+			Reason:          br.Reason.String(),
+			WarningCount:    br.WarningCount,
+		}
+		if br.Error != nil {
+			b.Error = br.Error.Error()
+		}
+		if state.LogStore != nil {
+			b.Log = state.LogStore.SpanLog(br.SpanID)
+		}
+		r.Builds = append(r.Builds, b)
+	}
+	return r
+}
+
+func WriteJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,169 @@
+package cireport
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+var start = time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestReport(t *testing.T) {
+	f := newFixture(t)
+	f.addBuild("(Tiltfile)", "tiltfile:1", 2*time.Second, nil, "Loading Tiltfile\n")
+	f.addManifest("frontend")
+	f.addBuild("frontend", "frontend:1", 3*time.Second, nil, "Building frontend\n")
+	f.addManifest("backend")
+	f.addBuild("backend", "backend:1", 1500*time.Millisecond, fmt.Errorf("exit status 1"), "\x1b[31mcompile error\x1b[0m\n")
+	f.addManifest("db")
+
+	report := NewReport(*f.state)
+	require.Len(t, report.Resources, 4)
+
+	assert.Equal(t, model.ManifestName("(Tiltfile)"), report.Resources[0].Name)
+
+	frontend := report.Resources[1]
+	assert.Equal(t, model.ManifestName("frontend"), frontend.Name)
+	assert.Equal(t, v1alpha1.UpdateStatusOK, frontend.UpdateStatus)
+	require.Len(t, frontend.Builds, 1)
+	assert.Equal(t, 3.0, frontend.Builds[0].DurationSeconds)
+	assert.Equal(t, "Initial Build", frontend.Builds[0].Reason)
+	assert.Equal(t, "Building frontend\n", frontend.Builds[0].Log)
+
+	backend := report.Resources[2]
+	assert.Equal(t, v1alpha1.UpdateStatusError, backend.UpdateStatus)
+	require.Len(t, backend.Builds, 1)
+	assert.Equal(t, "exit status 1", backend.Builds[0].Error)
+
+	db := report.Resources[3]
+	assert.Empty(t, db.Builds)
+}
+
+func TestReportBuildHistoryOldestFirst(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("frontend")
+	f.addBuild("frontend", "frontend:1", time.Second, fmt.Errorf("oops"), "first\n")
+	f.addBuild("frontend", "frontend:2", time.Second, nil, "second\n")
+
+	report := NewReport(*f.state)
+	builds := report.Resources[1].Builds
+	require.Len(t, builds, 2)
+	assert.Equal(t, "oops", builds[0].Error)
+	assert.Equal(t, "first\n", builds[0].Log)
+	assert.Equal(t, "", builds[1].Error)
+	assert.Equal(t, "second\n", builds[1].Log)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("frontend")
+	f.addBuild("frontend", "frontend:1", 3*time.Second, nil, "Building frontend\n")
+	f.addManifest("backend")
+	f.addBuild("backend", "backend:1", 1500*time.Millisecond, fmt.Errorf("exit status 1"), "\x1b[31mcompile error\x1b[0m\n")
+	f.addManifest("db")
+
+	out := bytes.NewBuffer(nil)
+	err := WriteJUnit(out, NewReport(*f.state))
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &suites))
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 2, suites.Skipped)
+	assert.Equal(t, "4.500", suites.Time)
+	require.Len(t, suites.Suites, 4)
+
+	frontend := suites.Suites[1]
+	assert.Equal(t, "frontend", frontend.Name)
+	assert.Equal(t, "2022-05-01T12:00:00Z", frontend.Timestamp)
+	require.Len(t, frontend.Cases, 1)
+	assert.Equal(t, "build #1: Initial Build", frontend.Cases[0].Name)
+	assert.Equal(t, "3.000", frontend.Cases[0].Time)
+	assert.Nil(t, frontend.Cases[0].Failure)
+
+	backend := suites.Suites[2]
+	require.Len(t, backend.Cases, 1)
+	require.NotNil(t, backend.Cases[0].Failure)
+	assert.Equal(t, "exit status 1", backend.Cases[0].Failure.Message)
+	assert.Equal(t, "compile error\n", backend.Cases[0].SystemOut)
+
+	db := suites.Suites[3]
+	require.Len(t, db.Cases, 1)
+	assert.NotNil(t, db.Cases[0].Skipped)
+}
+
+func TestWriteJSON(t *testing.T) {
+	f := newFixture(t)
+	f.addManifest("backend")
+	f.addBuild("backend", "backend:1", time.Second, fmt.Errorf("exit status 1"), "compile error\n")
+
+	out := bytes.NewBuffer(nil)
+	err := WriteJSON(out, NewReport(*f.state))
+	require.NoError(t, err)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report.Resources, 2)
+	assert.Equal(t, "exit status 1", report.Resources[1].Builds[0].Error)
+	assert.Equal(t, "compile error\n", report.Resources[1].Builds[0].Log)
+	assert.Contains(t, out.String(), `"updateStatus": "error"`)
+}
+
+type fixture struct {
+	t     *testing.T
+	state *store.EngineState
+	now   time.Time
+}
+
+func newFixture(t *testing.T) *fixture {
+	return &fixture{
+		t:     t,
+		state: store.NewState(),
+		now:   start,
+	}
+}
+
+func (f *fixture) addManifest(name model.ManifestName) {
+	m := model.Manifest{Name: name}.WithDeployTarget(model.LocalTarget{})
+	f.state.UpsertManifestTarget(store.NewManifestTarget(m))
+}
+
+func (f *fixture) manifestState(name model.ManifestName) *store.ManifestState {
+	if ms, ok := f.state.TiltfileStates[name]; ok {
+		return ms
+	}
+	ms, ok := f.state.ManifestState(name)
+	require.True(f.t, ok)
+	return ms
+}
+
+func (f *fixture) addBuild(name model.ManifestName, spanID logstore.SpanID, dur time.Duration, err error, log string) {
+	f.state.LogStore.Append(store.NewLogAction(name, spanID, logger.InfoLvl, nil, []byte(log)), nil)
+
+	reason := model.BuildReasonFlagInit
+	ms := f.manifestState(name)
+	if len(ms.BuildHistory) > 0 {
+		reason = model.BuildReasonFlagChangedFiles
+	}
+	ms.AddCompletedBuild(model.BuildRecord{
+		StartTime:  f.now,
+		FinishTime: f.now.Add(dur),
+		Reason:     reason,
+		Error:      err,
+		SpanID:     spanID,
+	})
+	f.now = f.now.Add(dur)
+}
@@ -0,0 +1,56 @@
+package cireport
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+type Reporter struct {
+	st store.RStore
+}
+
+func NewReporter(st store.RStore) *Reporter {
+	return &Reporter{st: st}
+}
+
+// WriteReports writes a JUnit and/or JSON report of the current engine state.
+// Empty paths are skipped.
+//
+// Errors are logged rather than returned, so that a bad report path
+// doesn't mask the result of the CI run.
+func (r *Reporter) WriteReports(ctx context.Context, junitPath string, jsonPath string) {
+	if junitPath == "" && jsonPath == "" {
+		return
+	}
+
+	state := r.st.RLockState()
+	report := NewReport(state)
+	r.st.RUnlockState()
+
+	if junitPath != "" {
+		writeReportFile(ctx, junitPath, report, WriteJUnit)
+	}
+	if jsonPath != "" {
+		writeReportFile(ctx, jsonPath, report, WriteJSON)
+	}
+}
+
+func writeReportFile(ctx context.Context, path string, report Report, write func(w io.Writer, r Report) error) {
+	f, err := os.Create(path)
+	if err != nil {
+		logger.Get(ctx).Errorf("Writing report to file: %v", err)
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	err = write(f, report)
+	if err != nil {
+		logger.Get(ctx).Errorf("Writing report to file: %v", err)
+	}
+}
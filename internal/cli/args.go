@@ -16,16 +16,20 @@ import (
 	"k8s.io/kubectl/pkg/cmd/util/editor"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
+	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type argsCmd struct {
-	streams genericclioptions.IOStreams
-	clear   bool
+	streams  genericclioptions.IOStreams
+	clear    bool
+	fileName string
 }
 
 func newArgsCmd(streams genericclioptions.IOStreams) *argsCmd {
@@ -52,7 +56,10 @@ the editor defined by your TILT_EDITOR or EDITOR environment variables, or fall
 an OS-appropriate default.
 
 Note that Tiltfile arguments do not affect built-in Tilt args (i.e., the things that show up in "tilt up --help", such as "--legacy", "--port"), and they
-are defined after built-in args, following a "--".`,
+are defined after built-in args, following a "--".
+
+"tilt args --help" also lists the args that the Tiltfile defines. It executes the
+Tiltfile up to its "config.parse()" call to find them.`,
 		Example: `# Set new args
 tilt args frontend_service backend_service -- --debug on
 
@@ -65,10 +72,70 @@ EDITOR=nano tilt args`,
 
 	addConnectServerFlags(cmd)
 	cmd.Flags().BoolVar(&c.clear, "clear", false, "Clear the Tiltfile args, as if you'd run tilt with no args")
+	cmd.Flags().StringVarP(&c.fileName, "file", "f", tiltfile.FileName, "Path to the Tiltfile whose args are listed by --help")
+
+	defaultHelp := cmd.HelpFunc()
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		defaultHelp(cmd, args)
+		c.printTiltfileArgs(preCommand(cmd.Context(), c.name()))
+	})
 
 	return cmd
 }
 
+type cmdArgsHelpDeps struct {
+	tfl    tiltfile.TiltfileLoader
+	config *config.Plugin
+}
+
+func newArgsHelpDeps(tfl tiltfile.TiltfileLoader, config *config.Plugin) cmdArgsHelpDeps {
+	return cmdArgsHelpDeps{
+		tfl:    tfl,
+		config: config,
+	}
+}
+
+// Prints the args that the Tiltfile defines with config.define_*().
+func (c *argsCmd) printTiltfileArgs(ctx context.Context) {
+	usage, err := c.tiltfileArgsUsage(ctx)
+	if err != nil {
+		fmt.Fprintf(c.streams.ErrOut, "\nUnable to read Tiltfile args from %s: %v\n", c.fileName, err)
+		return
+	}
+
+	fmt.Fprintf(c.streams.Out, "\nTiltfile Args (defined in %s):\n", c.fileName)
+	if strings.TrimSpace(usage) == "" {
+		fmt.Fprintln(c.streams.Out, "  (none)")
+		return
+	}
+	fmt.Fprint(c.streams.Out, usage)
+}
+
+func (c *argsCmd) tiltfileArgsUsage(ctx context.Context) (string, error) {
+	// Only show Tiltfile logs if something goes wrong.
+	l := logger.NewDeferredLogger(ctx)
+	ctx = logger.WithLogger(ctx, l)
+
+	deps, err := wireArgsHelp(ctx, analytics.Get(ctx), "args")
+	if err != nil {
+		return "", err
+	}
+
+	deps.config.SetDescribeOnly(true)
+	tlr := deps.tfl.Load(ctx, ctrltiltfile.MainTiltfile(c.fileName, nil), nil)
+	usage, ok := deps.config.DescribedUsage()
+	if ok {
+		return usage, nil
+	}
+	if tlr.Error != nil {
+		l.SetOutput(logger.NewLogger(l.Level(), c.streams.ErrOut))
+		return "", tlr.Error
+	}
+
+	// The Tiltfile never called config.parse(), so it doesn't take any args.
+	return "", nil
+}
+
 func parseEditResult(b []byte) ([]string, error) {
 	sc := bufio.NewScanner(bytes.NewReader(b))
 	var argsLine *string
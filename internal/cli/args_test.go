@@ -13,6 +13,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
@@ -196,3 +198,46 @@ func getTiltfile(f *serverFixture) *v1alpha1.Tiltfile {
 	require.NoError(f.T(), err)
 	return &tf
 }
+
+func TestArgsHelpListsTiltfileArgs(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("Tiltfile", `
+config.define_enum('env', choices=['dev', 'prod'], default='dev', usage='where to deploy')
+config.define_string_list('to-run', args=True)
+cfg = config.parse()
+local('exit 1')
+`)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newArgsCmd(streams)
+	cmd.fileName = "Tiltfile"
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	cmd.printTiltfileArgs(ctx)
+
+	require.Equal(t, `
+Tiltfile Args (defined in Tiltfile):
+      --env dev|prod   where to deploy (default dev)
+
+Positional args:
+  [to-run...] list[string]
+`, out.String())
+}
+
+func TestArgsHelpNoTiltfileArgs(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("Tiltfile", `print('hi')`)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newArgsCmd(streams)
+	cmd.fileName = "Tiltfile"
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	cmd.printTiltfileArgs(ctx)
+
+	require.Equal(t, "\nTiltfile Args (defined in Tiltfile):\n  (none)\n", out.String())
+}
@@ -20,6 +20,11 @@ import (
 type ciCmd struct {
 	fileName             string
 	outputSnapshotOnExit string
+	gateResources        []string
+	reportJUnit          string
+	reportJSON           string
+
+	logArchive logArchiveFlags
 }
 
 func (c *ciCmd) name() model.TiltSubcommand { return "ci" }
@@ -37,6 +42,13 @@ Exits with failure if any build fails or any server crashes.
 Exits with success if all tasks have completed successfully
 and all servers are healthy.
 
+Use --gate (or ci_settings(gate=[...]) in the Tiltfile) to only wait on
+a subset of resources. Tilt exits as soon as those resources succeed,
+and ignores failures in all other resources.
+
+Use --report-junit and --report-json to write a per-resource report of
+each build (status, duration, reason, error, and logs) for your CI system.
+
 While Tilt is running, you can view the UI at %s:%d
 (configurable with --host and --port).
 
@@ -53,6 +65,13 @@ See blog post for additional information: https://blog.tilt.dev/2020/04/16/how-t
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "",
 		"If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().StringSliceVar(&c.gateResources, "gate", nil,
+		"Resources that determine when Tilt exits. If specified, overrides ci_settings(gate=...) in the Tiltfile")
+	cmd.Flags().StringVar(&c.reportJUnit, "report-junit", "",
+		"If specified, Tilt will write a JUnit XML report of each resource's builds to the specified path when it exits")
+	cmd.Flags().StringVar(&c.reportJSON, "report-json", "",
+		"If specified, Tilt will write a JSON report of each resource's builds to the specified path when it exits")
+	c.logArchive.register(cmd)
 
 	return cmd
 }
@@ -62,6 +81,12 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 	a.Incr("cmd.ci", nil)
 	defer a.Flush(time.Second)
 
+	logArchive, err := c.logArchive.archive(time.Now())
+	if err != nil {
+		return err
+	}
+	defer logArchive.Close()
+
 	deferred := logger.NewDeferredLogger(ctx)
 	ctx = redirectLogs(ctx, deferred)
 
@@ -91,13 +116,22 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 	if c.outputSnapshotOnExit != "" {
 		defer cmdCIDeps.Snapshotter.WriteSnapshot(ctx, c.outputSnapshotOnExit)
 	}
+	defer cmdCIDeps.Reporter.WriteReports(ctx, c.reportJUnit, c.reportJSON)
 
 	err = upper.Start(ctx, args, cmdCIDeps.TiltBuild,
 		c.fileName, store.TerminalModeStream, a.UserOpt(), cmdCIDeps.Token,
-		string(cmdCIDeps.CloudAddress))
+		string(cmdCIDeps.CloudAddress), c.gateManifestNames(), logArchive)
 	if err == nil {
 		_, _ = fmt.Fprintln(colorable.NewColorableStdout(),
 			color.GreenString("SUCCESS. All workloads are healthy."))
 	}
 	return err
 }
+
+func (c *ciCmd) gateManifestNames() []model.ManifestName {
+	var result []model.ManifestName
+	for _, name := range c.gateResources {
+		result = append(result, model.ManifestName(name))
+	}
+	return result
+}
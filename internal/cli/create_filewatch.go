@@ -24,6 +24,7 @@ type createFileWatchCmd struct {
 	cmd    *cobra.Command
 
 	ignoreValues []string
+	contentHash  bool
 }
 
 var _ tiltCmd = &createFileWatchCmd{}
@@ -62,6 +63,8 @@ trigger events when a file changes.
 
 	cmd.Flags().StringSliceVar(&c.ignoreValues, "ignore", nil,
 		"Patterns to ignore. Supports same syntax as .dockerignore. Paths are relative to the current directory.")
+	cmd.Flags().BoolVar(&c.contentHash, "content-hash", false,
+		"Ignore file changes that don't change the file contents.")
 
 	c.helper.addFlags(cmd)
 	c.cmd = cmd
@@ -110,6 +113,7 @@ func (c *createFileWatchCmd) object(args []string) (*v1alpha1.FileWatch, error)
 		Spec: v1alpha1.FileWatchSpec{
 			WatchedPaths: paths,
 			Ignores:      ignores,
+			ContentHash:  c.contentHash,
 		},
 	}
 	return &fw, nil
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -19,6 +20,10 @@ import (
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+// How long to wait for a local_resource teardown_cmd if the Tiltfile
+// doesn't specify a teardown_timeout.
+const defaultLocalTeardownTimeout = 30 * time.Second
+
 type downCmd struct {
 	fileName         string
 	deleteNamespaces bool
@@ -51,6 +56,11 @@ Namespaces are not deleted by default. Use --delete-namespaces to change that.
 
 Kubernetes resources with the annotation 'tilt.dev/down-policy: keep' are not deleted.
 
+Resources are torn down in reverse dependency order (i.e., a resource is torn down
+before the resources it depends on), whether they're Kubernetes, Docker Compose,
+or local resources. Local resources with a teardown_cmd run that command.
+Docker Compose services are removed in order, then the project is brought down.
+
 For more complex cases, the Tiltfile has APIs to add additional flags and arguments to the Tilt CLI.
 These arguments can be scripted to define custom subsets of resources to delete.
 See https://docs.tilt.dev/tiltfile_config.html for examples.
@@ -84,28 +94,7 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 	}
 
 	sortedManifests := sortManifestsForDeletion(tlr.Manifests)
-
-	if err := deleteK8sEntities(ctx, sortedManifests, tlr.UpdateSettings, downDeps, c.deleteNamespaces); err != nil {
-		return err
-	}
-
-	var dcProject v1alpha1.DockerComposeProject
-	for _, m := range sortedManifests {
-		if m.IsDC() {
-			dcProject = m.DockerComposeTarget().Spec.Project
-			break
-		}
-	}
-
-	if !model.IsEmptyDockerComposeProject(dcProject) {
-		dcc := downDeps.dcClient
-		err = dcc.Down(ctx, dcProject, logger.Get(ctx).Writer(logger.InfoLvl), logger.Get(ctx).Writer(logger.InfoLvl))
-		if err != nil {
-			return errors.Wrap(err, "Running `docker-compose down`")
-		}
-	}
-
-	return nil
+	return teardownManifests(ctx, sortedManifests, tlr.UpdateSettings, downDeps, c.deleteNamespaces)
 }
 
 func sortManifestsForDeletion(manifests []model.Manifest) []model.Manifest {
@@ -156,6 +145,118 @@ func manifestsForNode(node *dependencyNode) []model.Manifest {
 	return append(manifests, node.manifest)
 }
 
+// Tears down each resource in the order given, whatever its type.
+//
+// Expects manifests sorted by sortManifestsForDeletion, so that dependents
+// are torn down before their dependencies. Consecutive Kubernetes resources
+// are deleted together, as are consecutive Docker Compose services.
+//
+// A failure doesn't abort the rest of the teardown, so that one misbehaving
+// resource doesn't leave the others behind.
+func teardownManifests(ctx context.Context, manifests []model.Manifest, updateSettings model.UpdateSettings, downDeps DownDeps, deleteNamespaces bool) error {
+	var errs []error
+	var localFailures []teardownFailure
+	var pendingK8s []model.Manifest
+	var pendingDC []v1alpha1.DockerComposeServiceSpec
+	var dcProject v1alpha1.DockerComposeProject
+
+	flush := func() {
+		if len(pendingK8s) > 0 {
+			err := deleteK8sEntities(ctx, pendingK8s, updateSettings, downDeps, deleteNamespaces)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			pendingK8s = nil
+		}
+		if len(pendingDC) > 0 {
+			out := logger.Get(ctx).Writer(logger.InfoLvl)
+			err := downDeps.dcClient.Rm(ctx, pendingDC, out, out)
+			if err != nil {
+				errs = append(errs, errors.Wrap(err, "Running `docker-compose rm`"))
+			}
+			pendingDC = nil
+		}
+	}
+
+	for _, m := range manifests {
+		switch {
+		case m.IsK8s():
+			if len(pendingDC) > 0 {
+				flush()
+			}
+			pendingK8s = append(pendingK8s, m)
+		case m.IsDC():
+			if len(pendingK8s) > 0 {
+				flush()
+			}
+			spec := m.DockerComposeTarget().Spec
+			if model.IsEmptyDockerComposeProject(dcProject) {
+				dcProject = spec.Project
+			}
+			pendingDC = append(pendingDC, spec)
+		case m.IsLocal():
+			lt := m.LocalTarget()
+			if lt.TeardownCmd.Empty() {
+				continue
+			}
+			flush()
+			err := teardownLocalResource(ctx, m.Name, lt, downDeps)
+			if err != nil {
+				localFailures = append(localFailures, teardownFailure{name: m.Name, err: err})
+			}
+		}
+	}
+	flush()
+
+	// Removing the services leaves the project's networks behind.
+	if !model.IsEmptyDockerComposeProject(dcProject) {
+		out := logger.Get(ctx).Writer(logger.InfoLvl)
+		err := downDeps.dcClient.Down(ctx, dcProject, out, out)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "Running `docker-compose down`"))
+		}
+	}
+
+	if len(localFailures) > 0 {
+		errs = append(errs, localTeardownError(ctx, localFailures))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+type teardownFailure struct {
+	name model.ManifestName
+	err  error
+}
+
+// Runs the teardown_cmd of a local resource.
+func teardownLocalResource(ctx context.Context, name model.ManifestName, lt model.LocalTarget, downDeps DownDeps) error {
+	timeout := lt.TeardownTimeout
+	if timeout == 0 {
+		timeout = defaultLocalTeardownTimeout
+	}
+
+	logger.Get(ctx).Infof("Tearing down %s", name)
+	tCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := localexec.OneShotToLogger(tCtx, downDeps.execer, lt.TeardownCmd)
+	if err != nil && tCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// Logs a summary of the local resources that failed to tear down.
+func localTeardownError(ctx context.Context, failures []teardownFailure) error {
+	l := logger.Get(ctx)
+	var names []string
+	l.Infof("Failed to tear down %d local resource(s):", len(failures))
+	for _, f := range failures {
+		l.Infof("  %s: %v", f.name, f.err)
+		names = append(names, string(f.name))
+	}
+	return fmt.Errorf("Tearing down local resources: %s", strings.Join(names, ", "))
+}
+
 func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, updateSettings model.UpdateSettings, downDeps DownDeps, deleteNamespaces bool) error {
 	entities, deleteCmds, err := k8sToDelete(manifests...)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -276,6 +277,84 @@ func TestDownDCFails(t *testing.T) {
 	}
 }
 
+func TestDownLocalTeardownInDependentOrder(t *testing.T) {
+	f := newDownFixture(t)
+
+	db := newLocalTeardownManifest("db", "stop-db")
+	tunnel := newLocalTeardownManifest("tunnel", "stop-tunnel")
+	tunnel.ResourceDependencies = []model.ManifestName{"db"}
+	noTeardown := model.Manifest{Name: "lint"}.WithDeployTarget(
+		model.NewLocalTarget("lint", model.ToHostCmd("make lint"), model.Cmd{}, nil))
+
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: []model.Manifest{db, noTeardown, tunnel}}
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	var argvs []string
+	for _, c := range f.execer.Calls() {
+		argvs = append(argvs, c.Cmd.String())
+	}
+	assert.Equal(t, []string{"stop-tunnel", "stop-db"}, argvs)
+}
+
+func TestDownTearsDownAcrossResourceTypesInDependentOrder(t *testing.T) {
+	f := newDownFixture(t)
+
+	// tunnel (local) -> fe (k8s) -> db (docker compose) -> setup (local)
+	setup := newLocalTeardownManifest("setup", "stop-setup")
+	db := newDCManifest()[0]
+	db.Name = "db"
+	db.ResourceDependencies = []model.ManifestName{"setup"}
+	fe := newK8sDeleteCmdManifest(t, "fe", "delete-fe")
+	fe.ResourceDependencies = []model.ManifestName{"db"}
+	tunnel := newLocalTeardownManifest("tunnel", "stop-tunnel")
+	tunnel.ResourceDependencies = []model.ManifestName{"fe"}
+
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: []model.Manifest{setup, db, fe, tunnel}}
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+
+	var argvs []string
+	for _, c := range f.execer.Calls() {
+		argvs = append(argvs, c.Cmd.String())
+	}
+	assert.Equal(t, []string{"stop-tunnel", "delete-fe", "stop-setup"}, argvs)
+
+	rmCalls := f.dcc.RmCalls()
+	if assert.Len(t, rmCalls, 1) {
+		assert.Equal(t, "fe", rmCalls[0].Specs[0].Service)
+	}
+	assert.Len(t, f.dcc.DownCalls(), 1)
+}
+
+func TestDownLocalTeardownFailureContinues(t *testing.T) {
+	f := newDownFixture(t)
+
+	f.execer.RegisterCommand("stop-db", 1, "", "no such container")
+
+	manifests := append([]model.Manifest{newLocalTeardownManifest("db", "stop-db")}, newK8sManifest()...)
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: manifests}
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Tearing down local resources: db")
+	}
+	assert.Contains(t, f.kCli.DeletedYaml, "sancho")
+}
+
+func TestDownLocalTeardownTimeout(t *testing.T) {
+	f := newDownFixture(t)
+
+	m := newLocalTeardownManifest("db", "stop-db")
+	lt := m.LocalTarget()
+	m = m.WithDeployTarget(lt.WithTeardownCmd(lt.TeardownCmd, time.Nanosecond))
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: []model.Manifest{m}}
+
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Tearing down local resources: db")
+	}
+}
+
 func TestDownArgs(t *testing.T) {
 	f := newDownFixture(t)
 
@@ -374,6 +453,22 @@ data:
 
 }
 
+func newK8sDeleteCmdManifest(t *testing.T, name string, deleteCmd string) model.Manifest {
+	kaSpec := v1alpha1.KubernetesApplySpec{
+		ApplyCmd:  &v1alpha1.KubernetesApplyCmd{Args: []string{"apply-" + name}},
+		DeleteCmd: &v1alpha1.KubernetesApplyCmd{Args: []string{deleteCmd}},
+	}
+	kt, err := k8s.NewTarget(model.TargetName(name), kaSpec, model.PodReadinessIgnore, nil)
+	require.NoError(t, err, "Failed to make KubernetesTarget")
+	return model.Manifest{Name: model.ManifestName(name)}.WithDeployTarget(kt)
+}
+
+func newLocalTeardownManifest(name string, teardownCmd string) model.Manifest {
+	lt := model.NewLocalTarget(model.TargetName(name), model.Cmd{}, model.ToHostCmd("serve-"+name), nil).
+		WithTeardownCmd(model.Cmd{Argv: []string{teardownCmd}, Dir: "."}, 0)
+	return model.Manifest{Name: model.ManifestName(name)}.WithDeployTarget(lt)
+}
+
 func newDCManifest() []model.Manifest {
 	return []model.Manifest{model.Manifest{Name: "fe"}.WithDeployTarget(model.DockerComposeTarget{
 		Name: "fe",
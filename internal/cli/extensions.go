@@ -0,0 +1,449 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newExtensionsCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	result := &cobra.Command{
+		Use:   "extensions",
+		Short: "Manage the extensions that a Tiltfile loads",
+		Long: `Manage the extensions that a Tiltfile loads with load("ext://...").
+
+Extensions are pinned in a tilt_extensions.lock file next to the Tiltfile.
+When the lockfile exists, Tilt checks out the pinned commit of each extension
+repo, and fails to load the Tiltfile if an extension's contents don't match.
+
+Extensions can be vendored into a tilt_extensions directory next to the Tiltfile.
+Vendored extensions are loaded from that directory, without network access.
+`,
+	}
+
+	addCommand(result, newExtensionsListCmd(streams))
+	addCommand(result, newExtensionsFetchCmd(streams))
+	addCommand(result, newExtensionsVendorCmd(streams))
+	addCommand(result, newExtensionsPruneCmd(streams))
+	addCommand(result, newExtensionsUpdateCmd(streams))
+
+	return result
+}
+
+type cmdExtensionsDeps struct {
+	tfl tiltfile.TiltfileLoader
+	ext *tiltextension.Plugin
+}
+
+func newExtensionsDeps(tfl tiltfile.TiltfileLoader, ext *tiltextension.Plugin) cmdExtensionsDeps {
+	return cmdExtensionsDeps{
+		tfl: tfl,
+		ext: ext,
+	}
+}
+
+// Flags and helpers shared by the extensions subcommands.
+type extensionsBaseCmd struct {
+	streams  genericclioptions.IOStreams
+	fileName string
+}
+
+func (c *extensionsBaseCmd) addFlags(cmd *cobra.Command) {
+	addTiltfileFlag(cmd, &c.fileName)
+	addKubeContextFlag(cmd)
+}
+
+// Executes the Tiltfile, and returns the extensions that it loaded.
+//
+// The configure callback sets up the extension plugin before the load.
+func (c *extensionsBaseCmd) load(ctx context.Context, subcommand model.TiltSubcommand, args []string,
+	configure func(ext *tiltextension.Plugin)) (*v1alpha1.Tiltfile, *tiltextension.Plugin, error) {
+	a := analytics.Get(ctx)
+	a.Incr(fmt.Sprintf("cmd.%s", subcommand), map[string]string{})
+	defer a.Flush(time.Second)
+
+	// Only show Tiltfile logs if something goes wrong.
+	l := logger.NewDeferredLogger(ctx)
+	ctx = logger.WithLogger(ctx, l)
+
+	deps, err := wireExtensions(ctx, a, subcommand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tf := ctrltiltfile.MainTiltfile(c.fileName, args)
+	configure(deps.ext)
+	tlr := deps.tfl.Load(ctx, tf, nil)
+	if tlr.Error != nil {
+		l.SetOutput(logger.NewLogger(l.Level(), c.streams.ErrOut))
+		return nil, nil, tlr.Error
+	}
+	return tf, deps.ext, nil
+}
+
+type extensionsUpdateCmd struct {
+	extensionsBaseCmd
+}
+
+var _ tiltCmd = &extensionsUpdateCmd{}
+
+func newExtensionsUpdateCmd(streams genericclioptions.IOStreams) *extensionsUpdateCmd {
+	return &extensionsUpdateCmd{extensionsBaseCmd{streams: streams}}
+}
+
+func (c *extensionsUpdateCmd) name() model.TiltSubcommand { return "extensions-update" }
+
+func (c *extensionsUpdateCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update [-- <Tiltfile args>]",
+		Short: "Pin the extensions that the Tiltfile loads to their latest versions",
+		Long: `Executes the Tiltfile, fetching the latest version of every extension it loads,
+and writes the resolved commit and a content hash for each extension
+to tilt_extensions.lock.
+
+Check the lockfile into version control so that everyone on your team
+loads the same extension code.`,
+		Example: "tilt alpha extensions update",
+	}
+
+	c.addFlags(cmd)
+
+	return cmd
+}
+
+func (c *extensionsUpdateCmd) run(ctx context.Context, args []string) error {
+	tf, ext, err := c.load(ctx, c.name(), args, func(ext *tiltextension.Plugin) {
+		ext.SetLockMode(tiltextension.LockModeUpdate)
+	})
+	if err != nil {
+		return err
+	}
+
+	path := tiltextension.LockFilePath(tf.Spec.Path)
+	oldLock, err := tiltextension.ReadLockFile(path)
+	if err != nil {
+		return err
+	}
+	if oldLock == nil {
+		oldLock = tiltextension.NewLockFile()
+	}
+
+	newLock := ext.UpdatedLockFile()
+	if len(newLock.Extensions) == 0 && len(oldLock.Extensions) == 0 {
+		fmt.Fprintf(c.streams.Out, "%s doesn't load any extensions\n", tf.Spec.Path)
+		return nil
+	}
+
+	err = tiltextension.WriteLockFile(path, newLock)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range newLock.Names() {
+		locked := newLock.Extensions[name]
+		old, ok := oldLock.Extensions[name]
+		switch {
+		case !ok:
+			fmt.Fprintf(c.streams.Out, "+ %s %s\n", name, lockVersion(locked))
+		case old != locked:
+			fmt.Fprintf(c.streams.Out, "~ %s %s -> %s\n", name, lockVersion(old), lockVersion(locked))
+		default:
+			fmt.Fprintf(c.streams.Out, "  %s %s\n", name, lockVersion(locked))
+		}
+	}
+	for _, name := range oldLock.Names() {
+		if _, ok := newLock.Extensions[name]; !ok {
+			fmt.Fprintf(c.streams.Out, "- %s\n", name)
+		}
+	}
+	fmt.Fprintf(c.streams.Out, "Wrote %s\n", path)
+	return nil
+}
+
+type extensionsListCmd struct {
+	extensionsBaseCmd
+}
+
+var _ tiltCmd = &extensionsListCmd{}
+
+func newExtensionsListCmd(streams genericclioptions.IOStreams) *extensionsListCmd {
+	return &extensionsListCmd{extensionsBaseCmd{streams: streams}}
+}
+
+func (c *extensionsListCmd) name() model.TiltSubcommand { return "extensions-list" }
+
+func (c *extensionsListCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [-- <Tiltfile args>]",
+		Short: "List the extensions that the Tiltfile loads",
+		Long: `Executes the Tiltfile, and lists every extension it loads,
+the repo it comes from, and where it was loaded from:
+
+- vendored: a copy in the tilt_extensions directory next to the Tiltfile
+- cached: a repo that Tilt downloaded
+- local: a repo with a file:// URL`,
+		Example: "tilt alpha extensions list",
+	}
+
+	c.addFlags(cmd)
+
+	return cmd
+}
+
+func (c *extensionsListCmd) run(ctx context.Context, args []string) error {
+	_, ext, err := c.load(ctx, c.name(), args, func(ext *tiltextension.Plugin) {})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.streams.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tREPO\tCOMMIT\tSOURCE")
+	for _, r := range ext.Resolved() {
+		commit := r.Commit
+		if commit == "" {
+			commit = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, r.Repo, commit, extensionSource(r))
+	}
+	return w.Flush()
+}
+
+type extensionsFetchCmd struct {
+	extensionsBaseCmd
+}
+
+var _ tiltCmd = &extensionsFetchCmd{}
+
+func newExtensionsFetchCmd(streams genericclioptions.IOStreams) *extensionsFetchCmd {
+	return &extensionsFetchCmd{extensionsBaseCmd{streams: streams}}
+}
+
+func (c *extensionsFetchCmd) name() model.TiltSubcommand { return "extensions-fetch" }
+
+func (c *extensionsFetchCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fetch [-- <Tiltfile args>]",
+		Short: "Download the extensions that the Tiltfile loads",
+		Long: `Executes the Tiltfile, and downloads every extension repo it loads
+into Tilt's cache, at the versions pinned in tilt_extensions.lock (if any).
+
+Vendored extensions are ignored, so that they're fetched from their repos too.
+Useful for warming the cache before going offline.`,
+		Example: "tilt alpha extensions fetch",
+	}
+
+	c.addFlags(cmd)
+
+	return cmd
+}
+
+func (c *extensionsFetchCmd) run(ctx context.Context, args []string) error {
+	tf, ext, err := c.load(ctx, c.name(), args, func(ext *tiltextension.Plugin) {
+		ext.SetIgnoreVendorDir(true)
+	})
+	if err != nil {
+		return err
+	}
+
+	resolved := ext.Resolved()
+	if len(resolved) == 0 {
+		fmt.Fprintf(c.streams.Out, "%s doesn't load any extensions\n", tf.Spec.Path)
+		return nil
+	}
+	for _, r := range resolved {
+		fmt.Fprintf(c.streams.Out, "Fetched %s %s\n", r.Name, lockVersion(r.LockedExtension))
+	}
+	return nil
+}
+
+type extensionsVendorCmd struct {
+	extensionsBaseCmd
+	prune bool
+}
+
+var _ tiltCmd = &extensionsVendorCmd{}
+
+func newExtensionsVendorCmd(streams genericclioptions.IOStreams) *extensionsVendorCmd {
+	return &extensionsVendorCmd{extensionsBaseCmd: extensionsBaseCmd{streams: streams}}
+}
+
+func (c *extensionsVendorCmd) name() model.TiltSubcommand { return "extensions-vendor" }
+
+func (c *extensionsVendorCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vendor [-- <Tiltfile args>]",
+		Short: "Copy the extensions that the Tiltfile loads into the tilt_extensions directory",
+		Long: `Executes the Tiltfile, and copies every extension it loads into the
+tilt_extensions directory next to the Tiltfile, at the versions pinned in
+tilt_extensions.lock (if any).
+
+When an extension has been vendored, Tilt loads the local copy instead of
+fetching the extension repo. Check the tilt_extensions directory into
+version control to load the Tiltfile without network access (e.g., in CI).
+
+With --prune, also deletes the vendored extensions that the Tiltfile didn't load.
+A Tiltfile may load different extensions with different Tiltfile args,
+so only prune with the args that load every extension you use.`,
+		Example: "tilt alpha extensions vendor --prune",
+	}
+
+	c.addFlags(cmd)
+	cmd.Flags().BoolVar(&c.prune, "prune", false,
+		"Delete vendored extensions that the Tiltfile didn't load")
+
+	return cmd
+}
+
+func (c *extensionsVendorCmd) run(ctx context.Context, args []string) error {
+	tf, ext, err := c.load(ctx, c.name(), args, func(ext *tiltextension.Plugin) {
+		ext.SetIgnoreVendorDir(true)
+	})
+	if err != nil {
+		return err
+	}
+
+	vendorDir := tiltextension.VendorDirPath(tf.Spec.Path)
+	resolved := ext.Resolved()
+	if len(resolved) == 0 {
+		fmt.Fprintf(c.streams.Out, "%s doesn't load any extensions\n", tf.Spec.Path)
+	}
+	for _, r := range resolved {
+		err := tiltextension.VendorExtension(vendorDir, r.Name, r.Dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.streams.Out, "Vendored %s %s\n", r.Name, lockVersion(r.LockedExtension))
+	}
+
+	if c.prune {
+		unused, err := unusedVendoredExtensions(vendorDir, ext)
+		if err != nil {
+			return err
+		}
+		for _, name := range unused {
+			err := tiltextension.RemoveVendoredExtension(vendorDir, name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(c.streams.Out, "Removed %s\n", name)
+		}
+	}
+
+	if len(resolved) > 0 {
+		fmt.Fprintf(c.streams.Out, "Wrote %s\n", vendorDir)
+	}
+	return nil
+}
+
+type extensionsPruneCmd struct {
+	extensionsBaseCmd
+}
+
+var _ tiltCmd = &extensionsPruneCmd{}
+
+func newExtensionsPruneCmd(streams genericclioptions.IOStreams) *extensionsPruneCmd {
+	return &extensionsPruneCmd{extensionsBaseCmd{streams: streams}}
+}
+
+func (c *extensionsPruneCmd) name() model.TiltSubcommand { return "extensions-prune" }
+
+func (c *extensionsPruneCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune [-- <Tiltfile args>]",
+		Short: "List vendored extensions that the Tiltfile no longer loads",
+		Long: `Executes the Tiltfile, and lists every extension in the tilt_extensions
+directory that the Tiltfile didn't load.
+
+A Tiltfile may load different extensions with different Tiltfile args,
+so this doesn't delete anything. To delete the unused extensions,
+run 'tilt alpha extensions vendor --prune'.`,
+		Example: "tilt alpha extensions prune",
+	}
+
+	c.addFlags(cmd)
+
+	return cmd
+}
+
+func (c *extensionsPruneCmd) run(ctx context.Context, args []string) error {
+	tf, ext, err := c.load(ctx, c.name(), args, func(ext *tiltextension.Plugin) {})
+	if err != nil {
+		return err
+	}
+
+	unused, err := unusedVendoredExtensions(tiltextension.VendorDirPath(tf.Spec.Path), ext)
+	if err != nil {
+		return err
+	}
+	if len(unused) == 0 {
+		fmt.Fprintln(c.streams.Out, "No unused vendored extensions")
+		return nil
+	}
+	for _, name := range unused {
+		fmt.Fprintf(c.streams.Out, "Unused %s\n", name)
+	}
+	fmt.Fprintln(c.streams.Out, "Run 'tilt alpha extensions vendor --prune' to delete them")
+	return nil
+}
+
+// Returns the vendored extensions that the Tiltfile didn't load.
+func unusedVendoredExtensions(vendorDir string, ext *tiltextension.Plugin) ([]string, error) {
+	loaded := make(map[string]bool)
+	for _, r := range ext.Resolved() {
+		loaded[r.Name] = true
+	}
+
+	vendored, err := tiltextension.VendoredExtensions(vendorDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, name := range vendored {
+		if !loaded[name] {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// Where a loaded extension came from, for display.
+func extensionSource(r tiltextension.ResolvedExtension) string {
+	switch {
+	case r.Vendored:
+		return "vendored"
+	case strings.HasPrefix(r.Repo, "file://"):
+		return "local"
+	default:
+		return "cached"
+	}
+}
+
+// A short, human-readable version of a locked extension.
+func lockVersion(locked tiltextension.LockedExtension) string {
+	if locked.Commit != "" {
+		commit := locked.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		return fmt.Sprintf("(%s)", commit)
+	}
+	hash := locked.Hash
+	if len(hash) > 19 {
+		hash = hash[:19]
+	}
+	return fmt.Sprintf("(%s)", hash)
+}
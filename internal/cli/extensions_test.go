@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+)
+
+func TestExtensionsUpdate(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("my-repo/hello/Tiltfile", `
+def hi():
+  print("hi")
+`)
+	f.WriteFile("Tiltfile", fmt.Sprintf(`
+v1alpha1.extension_repo(name='default', url='file://%s')
+load('ext://hello', 'hi')
+hi()
+`, f.JoinPath("my-repo")))
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newExtensionsUpdateCmd(streams)
+	cmd.fileName = "Tiltfile"
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	err := cmd.run(ctx, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "+ hello (sha256:")
+	assert.Contains(t, out.String(), "Wrote "+f.JoinPath(tiltextension.LockFileName))
+
+	lock, err := tiltextension.ReadLockFile(f.JoinPath(tiltextension.LockFileName))
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, []string{"hello"}, lock.Names())
+	assert.Equal(t, "file://"+f.JoinPath("my-repo"), lock.Extensions["hello"].Repo)
+}
+
+func TestExtensionsVendorListPrune(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("my-repo/hello/Tiltfile", `
+def hi():
+  print("hi")
+`)
+	f.WriteFile("Tiltfile", fmt.Sprintf(`
+v1alpha1.extension_repo(name='default', url='file://%s')
+load('ext://hello', 'hi')
+hi()
+`, f.JoinPath("my-repo")))
+	f.WriteFile("tilt_extensions/unused/Tiltfile", "")
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	vendorCmd := newExtensionsVendorCmd(streams)
+	vendorCmd.fileName = "Tiltfile"
+	require.NoError(t, vendorCmd.run(ctx, nil))
+	assert.Contains(t, out.String(), "Vendored hello")
+	assert.Contains(t, f.ReadFile("tilt_extensions/hello/Tiltfile"), `print("hi")`)
+
+	// Make sure the vendored copy is loaded instead of the repo.
+	f.Rm("my-repo")
+
+	streams, _, out, _ = genericclioptions.NewTestIOStreams()
+	listCmd := newExtensionsListCmd(streams)
+	listCmd.fileName = "Tiltfile"
+	require.NoError(t, listCmd.run(ctx, nil))
+	assert.Contains(t, out.String(), "NAME   REPO")
+	assert.Regexp(t, `hello +file://.*my-repo +- +vendored`, out.String())
+
+	// Prune only reports the unused extensions.
+	streams, _, out, _ = genericclioptions.NewTestIOStreams()
+	pruneCmd := newExtensionsPruneCmd(streams)
+	pruneCmd.fileName = "Tiltfile"
+	require.NoError(t, pruneCmd.run(ctx, nil))
+	assert.Equal(t, "Unused unused\nRun 'tilt alpha extensions vendor --prune' to delete them\n", out.String())
+	assert.FileExists(t, f.JoinPath("tilt_extensions/unused/Tiltfile"))
+}
+
+func TestExtensionsVendorPrune(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("my-repo/hello/Tiltfile", `
+def hi():
+  print("hi")
+`)
+	f.WriteFile("Tiltfile", fmt.Sprintf(`
+v1alpha1.extension_repo(name='default', url='file://%s')
+load('ext://hello', 'hi')
+hi()
+`, f.JoinPath("my-repo")))
+	f.WriteFile("tilt_extensions/unused/Tiltfile", "")
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	// Vendoring doesn't delete anything without --prune.
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	vendorCmd := newExtensionsVendorCmd(streams)
+	vendorCmd.fileName = "Tiltfile"
+	require.NoError(t, vendorCmd.run(ctx, nil))
+	assert.NotContains(t, out.String(), "Removed")
+	assert.FileExists(t, f.JoinPath("tilt_extensions/unused/Tiltfile"))
+
+	streams, _, out, _ = genericclioptions.NewTestIOStreams()
+	vendorCmd = newExtensionsVendorCmd(streams)
+	vendorCmd.fileName = "Tiltfile"
+	vendorCmd.prune = true
+	require.NoError(t, vendorCmd.run(ctx, nil))
+	assert.Contains(t, out.String(), "Removed unused\n")
+	assert.NoDirExists(t, f.JoinPath("tilt_extensions/unused"))
+	assert.FileExists(t, f.JoinPath("tilt_extensions/hello/Tiltfile"))
+}
+
+func TestExtensionsUpdateNoExtensions(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.Chdir()
+
+	f.WriteFile("Tiltfile", `print("hi")`)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newExtensionsUpdateCmd(streams)
+	cmd.fileName = "Tiltfile"
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	err := cmd.run(ctx, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "doesn't load any extensions")
+	lock, err := tiltextension.ReadLockFile(f.JoinPath(tiltextension.LockFileName))
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Flags for archiving logs that Tilt truncates from memory.
+type logArchiveFlags struct {
+	enabled bool
+	dir     string
+	maxSize string
+	maxAge  time.Duration
+}
+
+func (f *logArchiveFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.enabled, "log-archive", false,
+		"If true, Tilt will write logs that it truncates from memory to disk, so that you can page back through them with tilt logs --archived")
+	cmd.Flags().StringVar(&f.dir, "log-archive-dir", "",
+		"Directory for the log archive. Defaults to a logs directory under the Tilt state dir")
+	cmd.Flags().StringVar(&f.maxSize, "log-archive-max-size", "500MB",
+		"Delete the oldest archived logs when the archive is bigger than this. Set to 0 for no limit")
+	cmd.Flags().DurationVar(&f.maxAge, "log-archive-max-age", 7*24*time.Hour,
+		"Delete archived logs older than this. Set to 0 for no limit")
+}
+
+// Creates the log archive for this session, or nil if archiving is disabled.
+func (f *logArchiveFlags) archive(startTime time.Time) (*logstore.Archive, error) {
+	if !f.enabled {
+		return nil, nil
+	}
+
+	maxBytes, err := units.FromHumanSize(f.maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --log-archive-max-size: %v", err)
+	}
+
+	dir := f.dir
+	if dir == "" {
+		dir, err = xdg.NewTiltDevBase().StateFile("logs")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	session := fmt.Sprintf("%s-%d", startTime.Format("20060102-150405"), os.Getpid())
+	return logstore.NewArchive(dir, session, logstore.ArchiveOptions{
+		MaxBytes: maxBytes,
+		MaxAge:   f.maxAge,
+	})
+}
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"regexp"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,6 +17,15 @@ import (
 
 type logsCmd struct {
 	follow bool // if true, follow logs (otherwise print current logs and exit)
+	since  time.Duration
+	tail   int
+	level  string
+	source string
+	grep   string
+	json   bool
+	fields []string
+
+	archived bool
 }
 
 func (c *logsCmd) name() model.TiltSubcommand { return "logs" }
@@ -28,12 +39,32 @@ func (c *logsCmd) register() *cobra.Command {
 
 By default, looks for a running Tilt instance on localhost:10350
 (this is configurable with the --port and --host flags).
+
+Examples:
+  # Errors from the last 10 minutes
+  tilt logs --since 10m --level error
+
+  # Build logs for the frontend, as JSON
+  tilt logs frontend --source build --json
+
+  # Structured logs with a level field of "error" (requires k8s_resource(log_format=...))
+  tilt logs --field level=error
+
+  # All logs, including those Tilt has archived to disk (requires tilt up --log-archive)
+  tilt logs --archived
 `,
 	}
 
 	cmd.Flags().BoolVarP(&c.follow, "follow", "f", false, "If true, stream the requested logs; otherwise, print the requested logs at the current moment in time, then exit.")
+	cmd.Flags().DurationVar(&c.since, "since", 0, "Only print logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs.")
+	cmd.Flags().IntVar(&c.tail, "tail", -1, "Lines of recent logs to print. Defaults to -1, showing all logs. With --follow, new logs are always printed.")
+	cmd.Flags().StringVar(&c.level, "level", "", "Only print logs at least this severe: one of info, warn, error. Defaults to all logs.")
+	cmd.Flags().StringVar(&c.source, "source", string(server.LogSourceAll), "Only print logs from this source: one of all, build, runtime.")
+	cmd.Flags().StringVar(&c.grep, "grep", "", "Only print logs matching this regular expression.")
+	cmd.Flags().BoolVar(&c.json, "json", false, "Print each log segment as a JSON object, with its span ID, resource name, timestamp, level, and fields.")
+	cmd.Flags().StringArrayVar(&c.fields, "field", nil, "Only print logs with this field, as key=value. May be specified multiple times.")
+	cmd.Flags().BoolVar(&c.archived, "archived", false, "Also print older logs that Tilt has truncated from memory and archived to disk. Requires Tilt to be running with --log-archive.")
 
-	// TODO: log level flags
 	addConnectServerFlags(cmd)
 	return cmd
 }
@@ -48,10 +79,62 @@ func (c *logsCmd) run(ctx context.Context, args []string) error {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
+	options, err := c.streamOptions(time.Now())
+	if err != nil {
+		return err
+	}
+
 	logDeps, err := wireLogsDeps(ctx, a, "logs")
 	if err != nil {
 		return err
 	}
 
-	return server.StreamLogs(ctx, c.follow, logDeps.url, args, logDeps.printer)
+	return server.StreamLogs(ctx, c.follow, logDeps.url, args, options, logDeps.printer)
+}
+
+func (c *logsCmd) streamOptions(now time.Time) (server.LogStreamOptions, error) {
+	options := server.LogStreamOptions{
+		Tail:     c.tail,
+		JSON:     c.json,
+		Archived: c.archived,
+	}
+
+	if c.since < 0 {
+		return options, fmt.Errorf("--since must be positive: %s", c.since)
+	}
+	if c.since > 0 {
+		options.Since = now.Add(-c.since)
+	}
+
+	level, err := server.ParseLogLevel(c.level)
+	if err != nil {
+		return options, fmt.Errorf("--level: %v", err)
+	}
+	options.Level = level
+
+	source, err := server.ParseLogSource(c.source)
+	if err != nil {
+		return options, fmt.Errorf("--source: %v", err)
+	}
+	options.Source = source
+
+	if c.grep != "" {
+		re, err := regexp.Compile(c.grep)
+		if err != nil {
+			return options, fmt.Errorf("--grep: %v", err)
+		}
+		options.Grep = re
+	}
+
+	for _, f := range c.fields {
+		key, value, err := server.ParseLogField(f)
+		if err != nil {
+			return options, fmt.Errorf("--field: %v", err)
+		}
+		if options.Fields == nil {
+			options.Fields = make(map[string]string)
+		}
+		options.Fields[key] = value
+	}
+	return options, nil
 }
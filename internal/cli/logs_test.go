@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestLogsFlags(t *testing.T) {
+	c := &logsCmd{}
+	cmd := c.register()
+	err := cmd.Flags().Parse([]string{
+		"--since", "10m", "--tail", "20", "--level", "warn", "--source", "build", "--grep", "^ERR", "--json", "--archived",
+		"--field", "level=error", "--field", "trace_id=abc"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	options, err := c.streamOptions(now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-10*time.Minute), options.Since)
+	assert.Equal(t, 20, options.Tail)
+	assert.Equal(t, logger.WarnLvl, options.Level)
+	assert.Equal(t, server.LogSourceBuild, options.Source)
+	assert.Equal(t, "^ERR", options.Grep.String())
+	assert.True(t, options.JSON)
+	assert.True(t, options.Archived)
+	assert.Equal(t, map[string]string{"level": "error", "trace_id": "abc"}, options.Fields)
+}
+
+func TestLogsFlagsDefaults(t *testing.T) {
+	c := &logsCmd{}
+	cmd := c.register()
+	require.NoError(t, cmd.Flags().Parse(nil))
+
+	options, err := c.streamOptions(time.Now())
+	require.NoError(t, err)
+	assert.True(t, options.Since.IsZero())
+	assert.Equal(t, -1, options.Tail)
+	assert.Equal(t, logger.NoneLvl, options.Level)
+	assert.Equal(t, server.LogSourceAll, options.Source)
+	assert.Nil(t, options.Grep)
+}
+
+func TestLogsFlagsInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"--level", "loud"},
+		{"--source", "pods"},
+		{"--grep", "("},
+		{"--field", "level"},
+	} {
+		c := &logsCmd{}
+		cmd := c.register()
+		require.NoError(t, cmd.Flags().Parse(args))
+
+		_, err := c.streamOptions(time.Now())
+		assert.Error(t, err, "args: %v", args)
+	}
+}
@@ -45,6 +45,8 @@ type upCmd struct {
 
 	legacy bool
 	stream bool
+
+	logArchive logArchiveFlags
 }
 
 func (c *upCmd) name() model.TiltSubcommand { return "up" }
@@ -86,6 +88,7 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	addNamespaceFlag(cmd)
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "", "If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	c.logArchive.register(cmd)
 
 	return cmd
 }
@@ -132,6 +135,12 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 		return err
 	}
 
+	logArchive, err := c.logArchive.archive(time.Now())
+	if err != nil {
+		return err
+	}
+	defer logArchive.Close()
+
 	deferred := logger.NewDeferredLogger(ctx)
 	ctx = redirectLogs(ctx, deferred)
 
@@ -166,7 +175,7 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 	}
 
 	err = upper.Start(ctx, args, cmdUpDeps.TiltBuild,
-		c.fileName, termMode, a.UserOpt(), cmdUpDeps.Token, string(cmdUpDeps.CloudAddress))
+		c.fileName, termMode, a.UserOpt(), cmdUpDeps.Token, string(cmdUpDeps.CloudAddress), nil, logArchive)
 	if err != context.Canceled {
 		return err
 	} else {
@@ -24,6 +24,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/cireport"
 	"github.com/tilt-dev/tilt/internal/cloud"
 	"github.com/tilt-dev/tilt/internal/cloud/cloudurl"
 	"github.com/tilt-dev/tilt/internal/container"
@@ -95,6 +96,7 @@ var BaseWireSet = wire.NewSet(
 	local.NewServerController,
 	kubernetesdiscovery.NewContainerRestartDetector,
 	k8swatch.NewServiceWatcher,
+	k8swatch.NewRolloutWatcher,
 	k8swatch.NewEventWatchManager,
 	uisession.NewSubscriber,
 	uiresource.NewSubscriber,
@@ -147,7 +149,7 @@ var BaseWireSet = wire.NewSet(
 	xdg.NewTiltDevBase,
 	token.GetOrCreateToken,
 
-	build.NewKINDLoader,
+	build.NewImageLoader,
 
 	wire.Value(feature.MainDefaults),
 )
@@ -167,6 +169,16 @@ func wireTiltfileResult(ctx context.Context, analytics *analytics.TiltAnalytics,
 	return cmdTiltfileResultDeps{}, nil
 }
 
+func wireArgsHelp(ctx context.Context, analytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (cmdArgsHelpDeps, error) {
+	wire.Build(UpWireSet, newArgsHelpDeps)
+	return cmdArgsHelpDeps{}, nil
+}
+
+func wireExtensions(ctx context.Context, analytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (cmdExtensionsDeps, error) {
+	wire.Build(UpWireSet, newExtensionsDeps)
+	return cmdExtensionsDeps{}, nil
+}
+
 func wireDockerPrune(ctx context.Context, analytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (dpDeps, error) {
 	wire.Build(UpWireSet, newDPDeps)
 	return dpDeps{}, nil
@@ -192,6 +204,7 @@ type CmdUpDeps struct {
 func wireCmdCI(ctx context.Context, analytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (CmdCIDeps, error) {
 	wire.Build(UpWireSet,
 		cloud.NewSnapshotter,
+		cireport.NewReporter,
 		wire.Value(store.EngineModeCI),
 		wire.Value(engineanalytics.CmdTags(map[string]string{})),
 		wire.Struct(new(CmdCIDeps), "*"),
@@ -205,6 +218,7 @@ type CmdCIDeps struct {
 	Token        token.Token
 	CloudAddress cloudurl.Address
 	Snapshotter  *cloud.Snapshotter
+	Reporter     *cireport.Reporter
 }
 
 func wireCmdUpdog(ctx context.Context,
@@ -0,0 +1,344 @@
+package containerupdate
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// DeltaBlockSize is the size of the blocks that we compare when sending
+// only the changed parts of a file.
+const DeltaBlockSize = 128 * 1024
+
+// DeltaMinFileSize is the smallest file that we send by delta.
+//
+// For smaller files, the extra round-trip to the container costs more
+// than re-sending the whole file.
+const DeltaMinFileSize = 1024 * 1024
+
+// ErrDeltaUnavailable means the container can't run the delta helper
+// (e.g., because it doesn't have a shell, dd, or md5sum).
+var ErrDeltaUnavailable = errors.New("delta transfer unavailable in container")
+
+// A ContainerUpdater that can send only the changed blocks of a file.
+//
+// The blocks are compared with a small shell helper that runs in the container,
+// so this only works in containers with a POSIX shell, dd, and md5sum.
+type DeltaUpdater interface {
+	ContainerUpdater
+
+	// Checksums of each block of the given files in the container.
+	//
+	// Files that don't exist in the container are omitted from the result.
+	// Returns ErrDeltaUnavailable if the container can't run the helper.
+	BlockChecksums(ctx context.Context, cInfo liveupdates.Container, blockSize int64, paths []string) (map[string]RemoteFile, error)
+
+	// Patch the changed blocks into the files in the container.
+	ApplyDeltas(ctx context.Context, cInfo liveupdates.Container, blockSize int64, deltas []FileDelta) error
+}
+
+// The state of a file in the container.
+type RemoteFile struct {
+	Size int64
+
+	// The hex-encoded md5 of each block.
+	Blocks []string
+}
+
+// The blocks that need to be written to a container file to make it match
+// the local file.
+type FileDelta struct {
+	ContainerPath string
+
+	// The size of the local file. The container file is truncated
+	// (or extended) to this size.
+	Size int64
+
+	// The contents of each changed block, keyed by block index.
+	Blocks map[int64][]byte
+}
+
+// The number of bytes that the delta will send.
+func (d FileDelta) TransferSize() int64 {
+	var result int64
+	for _, b := range d.Blocks {
+		result += int64(len(b))
+	}
+	return result
+}
+
+// ComputeDelta compares a local file against the block checksums of the
+// container file.
+func ComputeDelta(localPath string, containerPath string, remote RemoteFile, blockSize int64) (FileDelta, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return FileDelta{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	delta := FileDelta{
+		ContainerPath: containerPath,
+		Blocks:        make(map[int64][]byte),
+	}
+	for i := int64(0); ; i++ {
+		buf := make([]byte, blockSize)
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			buf = buf[:n]
+			delta.Size += int64(n)
+			if i >= int64(len(remote.Blocks)) || fmt.Sprintf("%x", md5.Sum(buf)) != remote.Blocks[i] {
+				delta.Blocks[i] = buf
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return FileDelta{}, err
+		}
+	}
+	return delta, nil
+}
+
+// SyncDeltas sends the changed blocks of each file to the container.
+//
+// Returns the path mappings that couldn't be sent by delta (e.g., because
+// the file doesn't exist in the container yet). The caller should copy these
+// whole. If the container can't run the delta helper at all, every path
+// mapping is returned.
+func SyncDeltas(ctx context.Context, du DeltaUpdater, cInfo liveupdates.Container, mappings []build.PathMapping) ([]build.PathMapping, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(mappings))
+	for _, pm := range mappings {
+		paths = append(paths, pm.ContainerPath)
+	}
+
+	remote, err := du.BlockChecksums(ctx, cInfo, DeltaBlockSize, paths)
+	if err != nil {
+		return mappings, err
+	}
+
+	var deltas []FileDelta
+	var rest []build.PathMapping
+	var total, sent int64
+	for _, pm := range mappings {
+		rf, ok := remote[pm.ContainerPath]
+		if !ok {
+			rest = append(rest, pm)
+			continue
+		}
+
+		delta, err := ComputeDelta(pm.LocalPath, pm.ContainerPath, rf, DeltaBlockSize)
+		if err != nil {
+			rest = append(rest, pm)
+			continue
+		}
+		deltas = append(deltas, delta)
+		total += delta.Size
+		sent += delta.TransferSize()
+	}
+
+	if len(deltas) == 0 {
+		return rest, nil
+	}
+
+	err = du.ApplyDeltas(ctx, cInfo, DeltaBlockSize, deltas)
+	if err != nil {
+		return mappings, err
+	}
+
+	logger.Get(ctx).Infof("Sent %d file(s) by delta (%d of %d bytes changed)", len(deltas), sent, total)
+	return rest, nil
+}
+
+// Runs a command in a container.
+type containerExecFunc func(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout io.Writer) error
+
+// Prints the size and the md5 of each block of every file argument.
+//
+// Each line of output is prefixed, so that we can ignore any
+// noise that the exec API adds.
+const blockChecksumsScript = `bs="$1"; shift
+if ! command -v dd >/dev/null 2>&1 || ! command -v md5sum >/dev/null 2>&1; then
+  echo "tilt-delta unavailable"
+  exit 0
+fi
+for f in "$@"; do
+  if [ ! -f "$f" ]; then
+    echo "tilt-delta missing $f"
+    continue
+  fi
+  size=$(wc -c < "$f" | tr -d ' ')
+  echo "tilt-delta file $size $f"
+  i=0
+  while [ $((i * bs)) -lt "$size" ]; do
+    sum=$(dd if="$f" bs="$bs" skip="$i" count=1 2>/dev/null | md5sum)
+    echo "tilt-delta block ${sum%% *}"
+    i=$((i + 1))
+  done
+done
+echo "tilt-delta done"
+`
+
+// Reads a tar from stdin with a manifest and the changed blocks of
+// each file, then writes each block into place and truncates the file
+// to its new size.
+const applyDeltasScript = `bs="$1"
+dir=$(mktemp -d)
+trap 'rm -rf "$dir"' EXIT
+tar -C "$dir" -x -f - || exit 1
+n=0
+while IFS= read -r line; do
+  size="${line%% *}"
+  path="${line#* }"
+  for b in "$dir/$n"/*; do
+    [ -e "$b" ] || continue
+    dd if="$b" of="$path" bs="$bs" seek="${b##*/}" count=1 conv=notrunc 2>/dev/null || exit 1
+  done
+  dd if=/dev/null of="$path" bs=1 seek="$size" 2>/dev/null || exit 1
+  n=$((n + 1))
+done < "$dir/manifest"
+`
+
+func blockChecksums(ctx context.Context, exec containerExecFunc, blockSize int64, paths []string) (map[string]RemoteFile, error) {
+	argv := append([]string{"sh", "-c", blockChecksumsScript, "tilt-delta", strconv.FormatInt(blockSize, 10)}, paths...)
+	out := bytes.NewBuffer(nil)
+	err := exec(ctx, model.Cmd{Argv: argv}, nil, out)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeltaUnavailable, err)
+	}
+	return parseBlockChecksums(out.String())
+}
+
+func parseBlockChecksums(out string) (map[string]RemoteFile, error) {
+	result := make(map[string]RemoteFile)
+	current := ""
+	done := false
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if !strings.HasPrefix(line, "tilt-delta ") {
+			continue
+		}
+
+		fields := strings.SplitN(strings.TrimPrefix(line, "tilt-delta "), " ", 3)
+		switch fields[0] {
+		case "unavailable":
+			return nil, ErrDeltaUnavailable
+		case "done":
+			done = true
+		case "missing":
+			current = ""
+		case "file":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("malformed delta helper output: %q", line)
+			}
+			size, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("malformed delta helper output: %q", line)
+			}
+			current = fields[2]
+			result[current] = RemoteFile{Size: size}
+		case "block":
+			if current == "" || len(fields) != 2 {
+				return nil, fmt.Errorf("malformed delta helper output: %q", line)
+			}
+			rf := result[current]
+			rf.Blocks = append(rf.Blocks, fields[1])
+			result[current] = rf
+		}
+	}
+
+	if !done {
+		return nil, fmt.Errorf("%w: delta helper exited early", ErrDeltaUnavailable)
+	}
+	return result, nil
+}
+
+func applyDeltas(ctx context.Context, exec containerExecFunc, blockSize int64, deltas []FileDelta) error {
+	archive, err := deltaArchive(deltas)
+	if err != nil {
+		return err
+	}
+
+	argv := []string{"sh", "-c", applyDeltasScript, "tilt-delta", strconv.FormatInt(blockSize, 10)}
+	out := bytes.NewBuffer(nil)
+	err = exec(ctx, model.Cmd{Argv: argv}, archive, out)
+	if err != nil {
+		return fmt.Errorf("applying deltas: %v\n%s", err, out.String())
+	}
+	return nil
+}
+
+// Packs the deltas into a tar, with the changed blocks of the n-th file
+// at "n/<block index>", and a manifest of "<size> <path>" lines.
+func deltaArchive(deltas []FileDelta) (io.Reader, error) {
+	buf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(buf)
+
+	var manifest strings.Builder
+	for n, d := range deltas {
+		if strings.ContainsAny(d.ContainerPath, "\n\r") {
+			return nil, fmt.Errorf("cannot send %q by delta", d.ContainerPath)
+		}
+		fmt.Fprintf(&manifest, "%d %s\n", d.Size, d.ContainerPath)
+
+		if len(d.Blocks) == 0 {
+			continue
+		}
+		err := tw.WriteHeader(&tar.Header{
+			Name:     fmt.Sprintf("%d/", n),
+			Mode:     0700,
+			Typeflag: tar.TypeDir,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i, block := range d.Blocks {
+			err := writeTarFile(tw, fmt.Sprintf("%d/%d", n, i), block)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := writeTarFile(tw, "manifest", []byte(manifest.String()))
+	if err != nil {
+		return nil, err
+	}
+	err = tw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, contents []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(contents)),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(contents)
+	return err
+}
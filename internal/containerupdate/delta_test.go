@@ -0,0 +1,168 @@
+package containerupdate
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestComputeDelta(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+
+	old := bytes.Repeat([]byte("a"), 10)
+	updated := append(append([]byte{}, old...), []byte("bbb")...)
+	updated[5] = 'x'
+	f.WriteFile("local.bin", string(updated))
+
+	fake := &FakeContainerUpdater{ContainerFiles: map[string][]byte{"/app/local.bin": old}}
+	remote, err := fake.BlockChecksums(context.Background(), TestContainerInfo, 4, []string{"/app/local.bin"})
+	require.NoError(t, err)
+
+	delta, err := ComputeDelta(f.JoinPath("local.bin"), "/app/local.bin", remote["/app/local.bin"], 4)
+	require.NoError(t, err)
+
+	// Block 1 changed, block 2 grew, and block 3 is new.
+	assert.Equal(t, int64(13), delta.Size)
+	assert.Equal(t, map[int64][]byte{
+		1: []byte("axaa"),
+		2: []byte("aabb"),
+		3: []byte("b"),
+	}, delta.Blocks)
+}
+
+func TestSyncDeltas(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	old := bytes.Repeat([]byte("01234567"), DeltaBlockSize/4)
+	updated := append([]byte{}, old...)
+	copy(updated[DeltaBlockSize+10:], "changed")
+	f.WriteFile("big.bin", string(updated))
+	f.WriteFile("new.bin", "new")
+
+	fake := &FakeContainerUpdater{ContainerFiles: map[string][]byte{"/app/big.bin": old}}
+	mappings := []build.PathMapping{
+		{LocalPath: f.JoinPath("big.bin"), ContainerPath: "/app/big.bin"},
+		{LocalPath: f.JoinPath("new.bin"), ContainerPath: "/app/new.bin"},
+	}
+
+	rest, err := SyncDeltas(ctx, fake, TestContainerInfo, mappings)
+	require.NoError(t, err)
+
+	// The new file isn't in the container yet, so it needs to be copied whole.
+	assert.Equal(t, mappings[1:], rest)
+	assert.Equal(t, updated, fake.ContainerFiles["/app/big.bin"])
+	if assert.Len(t, fake.DeltaCalls, 1) && assert.Len(t, fake.DeltaCalls[0], 1) {
+		assert.Equal(t, int64(DeltaBlockSize), fake.DeltaCalls[0][0].TransferSize())
+	}
+}
+
+func TestSyncDeltasUnavailable(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	f.WriteFile("big.bin", "contents")
+	fake := &FakeContainerUpdater{
+		ContainerFiles:   map[string][]byte{"/app/big.bin": []byte("old")},
+		DeltaUnavailable: true,
+	}
+	mappings := []build.PathMapping{
+		{LocalPath: f.JoinPath("big.bin"), ContainerPath: "/app/big.bin"},
+	}
+
+	rest, err := SyncDeltas(ctx, fake, TestContainerInfo, mappings)
+	assert.ErrorIs(t, err, ErrDeltaUnavailable)
+	assert.Equal(t, mappings, rest)
+	assert.Empty(t, fake.DeltaCalls)
+}
+
+func TestParseBlockChecksums(t *testing.T) {
+	out := "RUNNING: sh -c ...\r\n" +
+		"tilt-delta missing /app/gone.bin\r\n" +
+		"tilt-delta file 6 /app/my file.bin\r\n" +
+		"tilt-delta block abc\r\n" +
+		"tilt-delta block def\r\n" +
+		"tilt-delta done\r\n"
+
+	result, err := parseBlockChecksums(out)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]RemoteFile{
+		"/app/my file.bin": {Size: 6, Blocks: []string{"abc", "def"}},
+	}, result)
+
+	_, err = parseBlockChecksums("tilt-delta unavailable\n")
+	assert.ErrorIs(t, err, ErrDeltaUnavailable)
+
+	_, err = parseBlockChecksums("sh: not found\n")
+	assert.ErrorIs(t, err, ErrDeltaUnavailable)
+}
+
+// Runs the delta helper scripts against the local filesystem,
+// as if it were the container.
+func TestDeltaHelperScripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("delta helper requires a POSIX shell")
+	}
+	for _, bin := range []string{"sh", "dd", "md5sum", "tar"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("delta helper requires %s", bin)
+		}
+	}
+
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	localExec := func(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout io.Writer) error {
+		c := exec.CommandContext(ctx, cmd.Argv[0], cmd.Argv[1:]...)
+		c.Stdin = stdin
+		c.Stdout = stdout
+		c.Stderr = stdout
+		return c.Run()
+	}
+
+	blockSize := int64(16)
+	for _, tc := range []struct {
+		name     string
+		old      string
+		updated  string
+		expected int
+	}{
+		{"edit", "0123456789abcdef0123456789abcdef", "0123456789abcdef01234567XXabcdef", 1},
+		{"grow", "0123456789abcdef", "0123456789abcdef0123", 1},
+		{"shrink", "0123456789abcdef0123456789abcdef", "0123456789abcdef0123", 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			containerPath := f.JoinPath(tc.name, "container file.bin")
+			f.WriteFile(filepath.Join(tc.name, "container file.bin"), tc.old)
+			f.WriteFile(filepath.Join(tc.name, "local.bin"), tc.updated)
+
+			remote, err := blockChecksums(ctx, localExec, blockSize, []string{containerPath, f.JoinPath(tc.name, "missing.bin")})
+			require.NoError(t, err)
+			require.Contains(t, remote, containerPath)
+			assert.NotContains(t, remote, f.JoinPath(tc.name, "missing.bin"))
+
+			delta, err := ComputeDelta(f.JoinPath(tc.name, "local.bin"), containerPath, remote[containerPath], blockSize)
+			require.NoError(t, err)
+			assert.Len(t, delta.Blocks, tc.expected)
+
+			err = applyDeltas(ctx, localExec, blockSize, []FileDelta{delta})
+			require.NoError(t, err)
+
+			actual, err := os.ReadFile(containerPath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.updated, string(actual))
+		})
+	}
+}
@@ -22,6 +22,8 @@ type DockerUpdater struct {
 }
 
 var _ ContainerUpdater = &DockerUpdater{}
+var _ DeltaUpdater = &DockerUpdater{}
+var _ SyncBackUpdater = &DockerUpdater{}
 
 func NewDockerUpdater(dCli docker.Client) *DockerUpdater {
 	return &DockerUpdater{dCli: dCli}
@@ -46,13 +48,18 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 	// (whereas the Exec API is part of the CRI and much more battle-tested).
 	// Discussion:
 	// https://github.com/tilt-dev/tilt/issues/3708
-	tarCmd := tarCmd()
-	err = cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, tarCmd, archiveToCopy, l.Writer(logger.InfoLvl))
-	if err != nil {
-		if exitCode, ok := ExtractExitCode(err); ok {
-			return wrapTarExecErr(err, tarCmd, exitCode)
+	//
+	// A nil archive means there's nothing to copy (e.g., because the files
+	// are bind-mounted into the container).
+	if archiveToCopy != nil {
+		tarCmd := tarCmd()
+		err = cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, tarCmd, archiveToCopy, l.Writer(logger.InfoLvl))
+		if err != nil {
+			if exitCode, ok := ExtractExitCode(err); ok {
+				return wrapTarExecErr(err, tarCmd, exitCode)
+			}
+			return fmt.Errorf("copying changed files: %w", err)
 		}
-		return fmt.Errorf("copying changed files: %w", err)
 	}
 
 	// Exec run's on container
@@ -82,6 +89,24 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 	return nil
 }
 
+func (cu *DockerUpdater) BlockChecksums(ctx context.Context, cInfo liveupdates.Container, blockSize int64, paths []string) (map[string]RemoteFile, error) {
+	return blockChecksums(ctx, cu.execFunc(cInfo), blockSize, paths)
+}
+
+func (cu *DockerUpdater) ApplyDeltas(ctx context.Context, cInfo liveupdates.Container, blockSize int64, deltas []FileDelta) error {
+	return applyDeltas(ctx, cu.execFunc(cInfo), blockSize, deltas)
+}
+
+func (cu *DockerUpdater) ArchiveFromContainer(ctx context.Context, cInfo liveupdates.Container, containerPath string) (io.ReadCloser, error) {
+	return cu.dCli.ContainerArchive(ctx, cInfo.ContainerID, containerPath)
+}
+
+func (cu *DockerUpdater) execFunc(cInfo liveupdates.Container) containerExecFunc {
+	return func(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout io.Writer) error {
+		return cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, cmd, stdin, stdout)
+	}
+}
+
 func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cID container.ID, paths []string) error {
 	if len(paths) == 0 {
 		return nil
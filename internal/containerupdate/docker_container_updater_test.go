@@ -44,6 +44,18 @@ func TestUpdateInContainerCopiesAndRmsFiles(t *testing.T) {
 	}
 }
 
+func TestUpdateContainerNilArchiveSkipsCopy(t *testing.T) {
+	f := newDCUFixture(t)
+
+	err := f.dcu.UpdateContainer(f.ctx, TestContainerInfo, nil, nil, nil, true)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	assert.Equal(f.t, 0, f.dCli.CopyCount, "calls to CopyToContainer")
+	assert.Equal(f.t, 0, len(f.dCli.ExecCalls), "calls to ExecInContainer")
+}
+
 func TestUpdateContainerExecsRuns(t *testing.T) {
 	f := newDCUFixture(t)
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path"
 	"strings"
 
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -18,6 +19,8 @@ type ExecUpdater struct {
 }
 
 var _ ContainerUpdater = &ExecUpdater{}
+var _ DeltaUpdater = &ExecUpdater{}
+var _ SyncBackUpdater = &ExecUpdater{}
 
 func NewExecUpdater(kCli k8s.Client) *ExecUpdater {
 	return &ExecUpdater{kCli: kCli}
@@ -75,6 +78,38 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 	return nil
 }
 
+func (cu *ExecUpdater) BlockChecksums(ctx context.Context, cInfo liveupdates.Container, blockSize int64, paths []string) (map[string]RemoteFile, error) {
+	return blockChecksums(ctx, cu.execFunc(cInfo), blockSize, paths)
+}
+
+func (cu *ExecUpdater) ApplyDeltas(ctx context.Context, cInfo liveupdates.Container, blockSize int64, deltas []FileDelta) error {
+	return applyDeltas(ctx, cu.execFunc(cInfo), blockSize, deltas)
+}
+
+func (cu *ExecUpdater) ArchiveFromContainer(ctx context.Context, cInfo liveupdates.Container, containerPath string) (io.ReadCloser, error) {
+	dir, base := path.Split(path.Clean(containerPath))
+	if dir == "" {
+		dir = "/"
+	}
+	cmd := model.Cmd{Argv: []string{"tar", "-c", "-f", "-", "-C", dir, base}}
+
+	out := bytes.NewBuffer(nil)
+	errOut := bytes.NewBuffer(nil)
+	err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+		cmd.Argv, nil, out, errOut)
+	if err != nil {
+		return nil, wrapK8sTarErr(errOut, err, cmd, "copying files from container")
+	}
+	return io.NopCloser(out), nil
+}
+
+func (cu *ExecUpdater) execFunc(cInfo liveupdates.Container) containerExecFunc {
+	return func(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout io.Writer) error {
+		return cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+			cmd.Argv, stdin, stdout, stdout)
+	}
+}
+
 // wrapK8sTarErr provides user-friendly diagnostics for common failures when
 // running `tar` as part of a Live Update.
 func wrapK8sTarErr(out *bytes.Buffer, err error, cmd model.Cmd, action string) error {
@@ -1,10 +1,15 @@
 package containerupdate
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/md5"
 	"fmt"
 	"io"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -14,8 +19,22 @@ type FakeContainerUpdater struct {
 	UpdateErrs []error
 
 	Calls []UpdateContainerCall
+
+	// The files in the fake container, keyed by path, for delta transfers.
+	ContainerFiles map[string][]byte
+
+	// Simulates a container that can't run the delta helper.
+	DeltaUnavailable bool
+
+	DeltaCalls [][]FileDelta
+
+	// The container paths that were archived to sync back.
+	ArchiveCalls []string
 }
 
+var _ DeltaUpdater = &FakeContainerUpdater{}
+var _ SyncBackUpdater = &FakeContainerUpdater{}
+
 type UpdateContainerCall struct {
 	ContainerInfo liveupdates.Container
 	Archive       io.Reader
@@ -31,13 +50,17 @@ func (cu *FakeContainerUpdater) SetUpdateErr(err error) {
 func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
 	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
 
-	var archive bytes.Buffer
-	if _, err := io.Copy(&archive, archiveToCopy); err != nil {
-		return fmt.Errorf("FakeContainerUpdater failed to read archive: %v", err)
+	var archive io.Reader
+	if archiveToCopy != nil {
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, archiveToCopy); err != nil {
+			return fmt.Errorf("FakeContainerUpdater failed to read archive: %v", err)
+		}
+		archive = &buf
 	}
 	cu.Calls = append(cu.Calls, UpdateContainerCall{
 		ContainerInfo: cInfo,
-		Archive:       &archive,
+		Archive:       archive,
 		ToDelete:      filesToDelete,
 		Cmds:          cmds,
 		HotReload:     hotReload,
@@ -52,3 +75,89 @@ func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo liveu
 	}
 	return err
 }
+
+func (cu *FakeContainerUpdater) ArchiveFromContainer(ctx context.Context, cInfo liveupdates.Container, containerPath string) (io.ReadCloser, error) {
+	cu.ArchiveCalls = append(cu.ArchiveCalls, containerPath)
+	archive, ok, err := fakeContainerArchive(cu.ContainerFiles, containerPath)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("tar: %s: Cannot stat: No such file or directory", containerPath)
+	}
+	return archive, nil
+}
+
+func (cu *FakeContainerUpdater) BlockChecksums(ctx context.Context, cInfo liveupdates.Container, blockSize int64, paths []string) (map[string]RemoteFile, error) {
+	if cu.DeltaUnavailable {
+		return nil, ErrDeltaUnavailable
+	}
+
+	result := make(map[string]RemoteFile)
+	for _, p := range paths {
+		contents, ok := cu.ContainerFiles[p]
+		if !ok {
+			continue
+		}
+
+		rf := RemoteFile{Size: int64(len(contents))}
+		for start := int64(0); start < int64(len(contents)); start += blockSize {
+			end := start + blockSize
+			if end > int64(len(contents)) {
+				end = int64(len(contents))
+			}
+			rf.Blocks = append(rf.Blocks, fmt.Sprintf("%x", md5.Sum(contents[start:end])))
+		}
+		result[p] = rf
+	}
+	return result, nil
+}
+
+func (cu *FakeContainerUpdater) ApplyDeltas(ctx context.Context, cInfo liveupdates.Container, blockSize int64, deltas []FileDelta) error {
+	cu.DeltaCalls = append(cu.DeltaCalls, deltas)
+	for _, d := range deltas {
+		contents := cu.ContainerFiles[d.ContainerPath]
+		if int64(len(contents)) < d.Size {
+			contents = append(contents, make([]byte, d.Size-int64(len(contents)))...)
+		}
+		contents = contents[:d.Size]
+		for i, block := range d.Blocks {
+			copy(contents[i*blockSize:], block)
+		}
+		cu.ContainerFiles[d.ContainerPath] = contents
+	}
+	return nil
+}
+
+// Archives the fake container files at or under containerPath,
+// in the same layout as a real container archive.
+func fakeContainerArchive(files map[string][]byte, containerPath string) (io.ReadCloser, bool, error) {
+	containerPath = path.Clean(containerPath)
+	parent := path.Dir(containerPath)
+
+	var names []string
+	for p := range files {
+		if p == containerPath || strings.HasPrefix(p, containerPath+"/") {
+			names = append(names, p)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false, nil
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(buf)
+	for _, p := range names {
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, parent), "/")
+		err := writeTarFile(tw, rel, files[p])
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	err := tw.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	return io.NopCloser(buf), true, nil
+}
@@ -0,0 +1,20 @@
+package containerupdate
+
+import (
+	"context"
+	"io"
+
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+)
+
+// A ContainerUpdater that can copy files out of the container,
+// for syncing them back to the local filesystem.
+type SyncBackUpdater interface {
+	ContainerUpdater
+
+	// Returns a tar archive of the file or directory at containerPath.
+	//
+	// Entries are named relative to the parent directory of containerPath
+	// (i.e., the same layout as `docker cp`).
+	ArchiveFromContainer(ctx context.Context, cInfo liveupdates.Container, containerPath string) (io.ReadCloser, error)
+}
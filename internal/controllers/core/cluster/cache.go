@@ -55,6 +55,10 @@ type connection struct {
 	serverVersion string
 	registry      *v1alpha1.RegistryHosting
 	connStatus    *v1alpha1.ClusterConnectionStatus
+	imageLoader   string
+
+	// The registry that the image loader was chosen for.
+	imageLoaderRegistry *v1alpha1.RegistryHosting
 }
 
 func (k *ConnectionManager) GetK8sClient(clusterKey types.NamespacedName) (k8s.Client, metav1.MicroTime, error) {
@@ -22,7 +22,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/tilt-dev/clusterid"
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
@@ -372,6 +374,21 @@ func (r *Reconciler) populateK8sMetadata(ctx context.Context, clusterNN types.Na
 		}
 	}
 
+	// The registry isn't always detected on the first try (e.g., if the
+	// cluster is still starting), so choose again when it changes.
+	registry := conn.registry
+	if container.IsEmptyRegistry(registry) {
+		registry = conn.spec.DefaultRegistry
+	}
+	if conn.imageLoader == "" || !apicmp.DeepEqual(conn.imageLoaderRegistry, registry) {
+		conn.imageLoaderRegistry = registry
+		product := clusterid.Product(conn.connStatus.Kubernetes.Product)
+		conn.imageLoader = build.ChooseImageLoader(product, conn.k8sClient.ContainerRuntime(ctx), registry)
+		if conn.imageLoader == v1alpha1.ImageLoaderContainerd {
+			logger.Get(ctx).Debugf("Loading images directly into the nodes of %s cluster %q", product, clusterNN.Name)
+		}
+	}
+
 	if conn.serverVersion == "" {
 		versionInfo, err := conn.k8sClient.CheckConnected(ctx)
 		if err == nil {
@@ -460,5 +477,6 @@ func (c *connection) toStatus(statusErr string) v1alpha1.ClusterStatus {
 		ConnectedAt: connectedAt,
 		Registry:    c.registry,
 		Connection:  c.connStatus,
+		ImageLoader: c.imageLoader,
 	}
 }
@@ -13,6 +13,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
@@ -193,6 +194,78 @@ users: null
 `, string(contents))
 }
 
+func TestKubernetesImageLoader(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		context  string
+		registry *v1alpha1.RegistryHosting
+		expected string
+	}{
+		{"kind", "kind-kind", nil, v1alpha1.ImageLoaderContainerd},
+		{"kind with registry", "kind-kind", &v1alpha1.RegistryHosting{Host: "localhost:5000"}, v1alpha1.ImageLoaderRegistry},
+		{"k3d", "k3d-dev", nil, v1alpha1.ImageLoaderContainerd},
+		{"remote", "gke_my-project_us-east1_dev", nil, v1alpha1.ImageLoaderRegistry},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			f.k8sClient.Registry = tc.registry
+			f.k8sClient.FakeAPIConfig = &api.Config{
+				CurrentContext: tc.context,
+				Contexts: map[string]*api.Context{
+					tc.context: &api.Context{Cluster: tc.context},
+				},
+				Clusters: map[string]*api.Cluster{
+					tc.context: &api.Cluster{},
+				},
+			}
+			cluster := &v1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: v1alpha1.ClusterSpec{
+					Connection: &v1alpha1.ClusterConnection{
+						Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+					},
+				},
+			}
+
+			f.Create(cluster)
+			f.MustGet(types.NamespacedName{Name: "default"}, cluster)
+			assert.Equal(t, tc.expected, cluster.Status.ImageLoader)
+		})
+	}
+}
+
+func TestKubernetesImageLoaderRegistryDetectedLater(t *testing.T) {
+	f := newFixture(t)
+	f.k8sClient.FakeAPIConfig = &api.Config{
+		CurrentContext: "kind-kind",
+		Contexts: map[string]*api.Context{
+			"kind-kind": &api.Context{Cluster: "kind-kind"},
+		},
+		Clusters: map[string]*api.Cluster{
+			"kind-kind": &api.Cluster{},
+		},
+	}
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	}
+
+	nn := types.NamespacedName{Name: "default"}
+	f.Create(cluster)
+	f.MustGet(nn, cluster)
+	assert.Equal(t, v1alpha1.ImageLoaderContainerd, cluster.Status.ImageLoader)
+
+	f.k8sClient.Registry = &v1alpha1.RegistryHosting{Host: "localhost:5000"}
+	f.MustReconcile(nn)
+	f.MustGet(nn, cluster)
+	assert.Equal(t, "localhost:5000", cluster.Status.Registry.Host)
+	assert.Equal(t, v1alpha1.ImageLoaderRegistry, cluster.Status.ImageLoader)
+}
+
 func TestKubernetesMonitor(t *testing.T) {
 	f := newFixture(t)
 	cluster := &v1alpha1.Cluster{
@@ -48,8 +48,10 @@ func newFixture(t testing.TB) *fixture {
 	dockerCli := docker.NewFakeClient()
 	ib := build.NewImageBuilder(
 		build.NewDockerBuilder(dockerCli, nil),
+		build.NewBuildkitBuilder(clock),
 		build.NewCustomBuilder(dockerCli, clock),
-		build.NewKINDLoader())
+		build.NewImageLoader(dockerCli),
+		nil)
 
 	r := NewReconciler(cfb.Client, cfb.Store, cfb.Scheme(), docker.NewFakeClient(), ib)
 	return &fixture{
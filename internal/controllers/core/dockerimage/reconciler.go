@@ -69,6 +69,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	r.indexer.OnReconcile(nn, obj)
 
 	if apierrors.IsNotFound(err) || obj.ObjectMeta.DeletionTimestamp != nil {
+		if res, ok := r.results[nn]; ok {
+			r.ib.ForgetImage(res.image.Ref)
+		}
 		delete(r.results, nn)
 		r.st.Dispatch(dockerimages.NewDockerImageDeleteAction(nn.Name))
 		return ctrl.Result{}, nil
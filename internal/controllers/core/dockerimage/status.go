@@ -29,7 +29,7 @@ func ToCompletedFailStatus(iTarget model.ImageTarget, startTime metav1.MicroTime
 			stage.FinishedAt = &finishTime
 			stage.Error = err.Error()
 		}
-		stages[i] = stage
+		stages[i] = withDuration(stage)
 	}
 
 	return v1alpha1.DockerImageStatus{
@@ -52,7 +52,7 @@ func ToCompletedSuccessStatus(iTarget model.ImageTarget, startTime metav1.MicroT
 		if stage.StartedAt != nil && stage.FinishedAt == nil {
 			stage.FinishedAt = &finishTime
 		}
-		stages[i] = stage
+		stages[i] = withDuration(stage)
 	}
 
 	return v1alpha1.DockerImageStatus{
@@ -64,3 +64,11 @@ func ToCompletedSuccessStatus(iTarget model.ImageTarget, startTime metav1.MicroT
 		StageStatuses: stages,
 	}
 }
+
+// Fill in the duration of a finished stage, if the builder didn't report one.
+func withDuration(stage v1alpha1.DockerImageStageStatus) v1alpha1.DockerImageStageStatus {
+	if stage.Duration == nil && stage.StartedAt != nil && stage.FinishedAt != nil {
+		stage.Duration = &metav1.Duration{Duration: stage.FinishedAt.Sub(stage.StartedAt.Time)}
+	}
+	return stage
+}
@@ -110,6 +110,48 @@ func (r *Reconciler) ForceApply(ctx context.Context, repo *v1alpha1.ExtensionRep
 	return state.status
 }
 
+// Reconciles the extension repo like ForceApply, then checks out the given ref
+// without changing the repo's spec.
+//
+// Several extensions in the same repo may be pinned to different commits,
+// so the ref is resolved per extension rather than stored on the shared repo.
+// An empty ref leaves the repo at the ref in its spec.
+func (r *Reconciler) ForceApplyAtRef(ctx context.Context, repo *v1alpha1.ExtensionRepo, ref string) v1alpha1.ExtensionRepoStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	nn := types.NamespacedName{Name: repo.Name, Namespace: repo.Namespace}
+	_, state, err := r.apply(ctx, nn, repo)
+	if err != nil {
+		return v1alpha1.ExtensionRepoStatus{Error: err.Error()}
+	}
+	if state == nil {
+		return v1alpha1.ExtensionRepoStatus{Error: "internal error: could not reconcile"}
+	}
+	if ref == "" || state.status.Error != "" {
+		return state.status
+	}
+
+	if strings.HasPrefix(repo.Spec.URL, "file://") {
+		return v1alpha1.ExtensionRepoStatus{Error: "refs not supported on file:// repos"}
+	}
+	importPath, err := getDownloaderImportPath(repo)
+	if err != nil {
+		return v1alpha1.ExtensionRepoStatus{Error: fmt.Sprintf("invalid: %v", err)}
+	}
+	err = r.dlr.RefSync(importPath, ref)
+	if err != nil {
+		return v1alpha1.ExtensionRepoStatus{Error: fmt.Sprintf("sync to ref %s: %v", ref, err)}
+	}
+	head, err := r.dlr.HeadRef(importPath)
+	if err != nil {
+		return v1alpha1.ExtensionRepoStatus{Error: fmt.Sprintf("determining head: %v", err)}
+	}
+
+	status := state.status
+	status.CheckoutRef = head
+	return status
+}
+
 // Reconciles the extension repo without reading or writing from the API server.
 // Caller must hold the mutex.
 // Returns a nil state if the repo is being deleted.
@@ -178,6 +178,30 @@ func TestRepoAlwaysSyncHead(t *testing.T) {
 	f.assertSteadyState(&repo)
 }
 
+func TestForceApplyAtRef(t *testing.T) {
+	f := newFixture(t)
+
+	repo := v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+		Spec: v1alpha1.ExtensionRepoSpec{
+			URL: "https://github.com/tilt-dev/tilt-extensions",
+		},
+	}
+
+	status := f.r.ForceApplyAtRef(f.Context(), &repo, "pinned")
+	assert.Equal(t, "", status.Error)
+	assert.Equal(t, "pinned", f.dlr.lastRefSync)
+	assert.Equal(t, "", repo.Spec.Ref)
+
+	// Loading another extension at another commit syncs the same repo again.
+	status = f.r.ForceApplyAtRef(f.Context(), &repo, "other-pinned")
+	assert.Equal(t, "", status.Error)
+	assert.Equal(t, "other-pinned", f.dlr.lastRefSync)
+	assert.Equal(t, 1, f.dlr.downloadCount)
+}
+
 type fixture struct {
 	*fake.ControllerFixture
 	r    *Reconciler
@@ -38,6 +38,12 @@ func processFileWatchStatus(ctx context.Context, state *store.EngineState, meta
 		return
 	}
 
+	// The status is also updated when nothing new was seen (e.g., when
+	// an event was suppressed), so skip events we've already processed.
+	if prev, ok := state.FileWatches[meta.GetName()]; ok && prev.Status.LastEventTime.Time.Equal(status.LastEventTime.Time) {
+		return
+	}
+
 	// since the store is called on EVERY update, can always just look at the last event
 	latestEvent := status.FileEvents[len(status.FileEvents)-1]
 
@@ -59,6 +65,9 @@ func processFileWatchStatus(ctx context.Context, state *store.EngineState, meta
 		for _, f := range latestEvent.SeenFiles {
 			ms.AddPendingFileChange(targetID, f, latestEvent.Time.Time)
 		}
+		if latestEvent.BranchSwitch {
+			ms.AddPendingBranchSwitch(targetID, latestEvent.Time.Time)
+		}
 	}
 }
 
@@ -0,0 +1,126 @@
+package filewatch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/watch"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Some filesystems only store modification times to the second.
+const mtimeGranularity = time.Second
+
+// contentHashes tracks the contents of watched files, so that we can drop
+// events for files whose contents didn't change.
+type contentHashes struct {
+	ignore model.PathMatcher
+
+	// When the watch started. A file modified after this may have changed
+	// since the watch started, so its initial hash can't be trusted.
+	startTime time.Time
+
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+func newContentHashes(ignore model.PathMatcher, startTime time.Time) *contentHashes {
+	return &contentHashes{
+		ignore:    ignore,
+		startTime: startTime,
+		hashes:    make(map[string]string),
+	}
+}
+
+// Hash the files under the watched paths, so that the first event for each
+// file can be compared against its contents when the watch started.
+//
+// This reads every watched file, so run it in the background. Until a file
+// has been hashed, its events pass through.
+func (c *contentHashes) hashInitialContents(ctx context.Context, watchedPaths []string) {
+	for _, root := range watchedPaths {
+		_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if skip, _ := c.ignore.MatchesEntireDir(p); skip {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if skip, _ := c.ignore.Matches(p); skip {
+				return nil
+			}
+
+			hash, err := hashFile(p)
+			if err != nil {
+				return nil
+			}
+
+			// Check the modification time after hashing, so that we
+			// don't trust contents that were written after the watch started.
+			info, err := os.Stat(p)
+			if err != nil || !info.ModTime().Add(mtimeGranularity).Before(c.startTime) {
+				return nil
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			p = filepath.Clean(p)
+			// If an event got to this file first, its hash is newer.
+			if _, ok := c.hashes[p]; !ok {
+				c.hashes[p] = hash
+			}
+			return nil
+		})
+	}
+}
+
+// Returns the events for files whose contents changed,
+// and the number of events that were dropped.
+func (c *contentHashes) filter(events []watch.FileEvent) ([]watch.FileEvent, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []watch.FileEvent
+	dropped := 0
+	for _, e := range events {
+		p := filepath.Clean(e.Path())
+		if skip, _ := c.ignore.Matches(p); skip {
+			result = append(result, e)
+			continue
+		}
+
+		hash, err := hashFile(p)
+		if err != nil {
+			// The file was deleted (or we can't read it), which is always a change.
+			delete(c.hashes, p)
+			result = append(result, e)
+			continue
+		}
+
+		if hash == dirHash {
+			result = append(result, e)
+			continue
+		}
+
+		if old, ok := c.hashes[p]; ok && old == hash {
+			dropped++
+			continue
+		}
+		c.hashes[p] = hash
+		result = append(result, e)
+	}
+	return result, dropped
+}
@@ -54,9 +54,10 @@ type Controller struct {
 	clock          clockwork.Clock
 	indexer        *indexer.Indexer
 	requeuer       *indexer.Requeuer
+	selfWrites     *SelfWrites
 }
 
-func NewController(client ctrlclient.Client, store store.RStore, fsWatcherMaker fsevent.WatcherMaker, timerMaker fsevent.TimerMaker, scheme *runtime.Scheme, clock clockwork.Clock) *Controller {
+func NewController(client ctrlclient.Client, store store.RStore, fsWatcherMaker fsevent.WatcherMaker, timerMaker fsevent.TimerMaker, scheme *runtime.Scheme, clock clockwork.Clock, selfWrites *SelfWrites) *Controller {
 	return &Controller{
 		Client:         client,
 		Store:          store,
@@ -66,6 +67,7 @@ func NewController(client ctrlclient.Client, store store.RStore, fsWatcherMaker
 		indexer:        indexer.NewIndexer(scheme, indexFw),
 		requeuer:       indexer.NewRequeuer(),
 		clock:          clock,
+		selfWrites:     selfWrites,
 	}
 }
 
@@ -193,6 +195,12 @@ func (c *Controller) addOrReplace(ctx context.Context, name types.NamespacedName
 	}
 
 	ignoreMatcher := ignore.CreateFileChangeFilter(fw.Spec.Ignores)
+	if fw.Spec.ContentHash {
+		// Compared against file modification times, so use the real clock.
+		w.contentHashes = newContentHashes(ignoreMatcher, time.Now())
+	}
+	w.gitOps = newGitOperations(fw.Spec.WatchedPaths)
+
 	notify, err := c.fsWatcherMaker(
 		append([]string{}, fw.Spec.WatchedPaths...),
 		ignoreMatcher,
@@ -214,6 +222,9 @@ func (c *Controller) addOrReplace(ctx context.Context, name types.NamespacedName
 		ctx, cancel := context.WithCancel(ctx)
 		w.cancel = cancel
 
+		if w.contentHashes != nil {
+			go w.contentHashes.hashInitialContents(ctx, w.spec.WatchedPaths)
+		}
 		go c.dispatchFileChangesLoop(ctx, w)
 	}
 
@@ -222,7 +233,7 @@ func (c *Controller) addOrReplace(ctx context.Context, name types.NamespacedName
 }
 
 func (c *Controller) dispatchFileChangesLoop(ctx context.Context, w *watcher) {
-	eventsCh := fsevent.Coalesce(c.timerMaker, w.notify.Events())
+	eventsCh := fsevent.Coalesce(c.timerMaker, w.gitOps.observe(w.notify.Events()))
 
 	defer func() {
 		c.mu.Lock()
@@ -231,6 +242,9 @@ func (c *Controller) dispatchFileChangesLoop(ctx context.Context, w *watcher) {
 		c.requeuer.Add(w.name)
 	}()
 
+	// Only set while we're holding changes for a git operation.
+	var gitPoll <-chan time.Time
+
 	for {
 		select {
 		case err, ok := <-w.notify.Errors():
@@ -260,12 +274,35 @@ func (c *Controller) dispatchFileChangesLoop(ctx context.Context, w *watcher) {
 			if !ok {
 				return
 			}
-			w.recordEvent(fsEvents)
-			c.requeuer.Add(w.name)
+
+			held, gitDir := w.gitOps.hold(fsEvents, c.clock.Now())
+			if held {
+				if gitDir != "" {
+					logger.Get(ctx).Infof("Git operation in progress in %s; waiting for it to finish before processing file changes", gitDir)
+					gitPoll = c.timerMaker(fsevent.GitPollDuration)
+				}
+				continue
+			}
+			c.handleFileEvents(w, fsEvents, w.gitOps.headMoved())
+
+		case <-gitPoll:
+			if !w.gitOps.finished(c.clock.Now()) {
+				gitPoll = c.timerMaker(fsevent.GitPollDuration)
+				continue
+			}
+			gitPoll = nil
+			c.handleFileEvents(w, w.gitOps.release(), w.gitOps.headMoved())
 		}
 	}
 }
 
+func (c *Controller) handleFileEvents(w *watcher, fsEvents []watch.FileEvent, branchSwitch bool) {
+	fsEvents = w.dropUnchanged(fsEvents)
+	fsEvents = c.selfWrites.filter(fsEvents)
+	w.recordEvent(fsEvents, branchSwitch)
+	c.requeuer.Add(w.name)
+}
+
 // Find all the objects to watch based on the Filewatch model
 func indexFw(obj ctrlclient.Object) []indexer.Key {
 	fw := obj.(*v1alpha1.FileWatch)
@@ -3,6 +3,7 @@ package filewatch
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -72,7 +73,7 @@ func newFixture(t *testing.T) *fixture {
 	cfb := fake.NewControllerFixtureBuilder(t)
 	testingStore := NewTestingStore(cfb.OutWriter())
 	clock := clockwork.NewFakeClock()
-	controller := NewController(cfb.Client, testingStore, fakeMultiWatcher.NewSub, timerMaker.Maker(), filewatches.NewScheme(), clock)
+	controller := NewController(cfb.Client, testingStore, fakeMultiWatcher.NewSub, timerMaker.Maker(), filewatches.NewScheme(), clock, NewSelfWrites())
 
 	indexer.StartSourceForTesting(cfb.Context(), controller.requeuer, controller, nil)
 
@@ -131,9 +132,34 @@ func (f *fixture) WaitForSeenFile(key types.NamespacedName, pathElems ...string)
 	}, 2*time.Second, 20*time.Millisecond, "Did not find path %q, seen: %v", relPath, &seenPaths)
 }
 
+// Writes a file with a modification time from before any watch started.
+func (f *fixture) writeOldFile(path string, contents string) {
+	f.t.Helper()
+	f.tmpdir.WriteFile(path, contents)
+	past := time.Now().Add(-time.Minute)
+	require.NoError(f.t, os.Chtimes(f.tmpdir.JoinPath(path), past, past))
+}
+
+// Waits for the content-hash baseline to include the file.
+func (f *fixture) waitForContentHash(key types.NamespacedName, pathElems ...string) {
+	f.t.Helper()
+	hashes := f.controller.targetWatches[key].contentHashes
+	path := f.tmpdir.JoinPath(pathElems...)
+	require.Eventuallyf(f.t, func() bool {
+		hashes.mu.Lock()
+		defer hashes.mu.Unlock()
+		_, ok := hashes.hashes[path]
+		return ok
+	}, time.Second, 10*time.Millisecond, "File never hashed: %s", path)
+}
+
 func (f *fixture) CreateSimpleFileWatch() (types.NamespacedName, *filewatches.FileWatch) {
 	f.t.Helper()
-	fw := &filewatches.FileWatch{
+	return f.createFileWatch(f.simpleFileWatch())
+}
+
+func (f *fixture) simpleFileWatch() *filewatches.FileWatch {
+	return &filewatches.FileWatch{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: apis.SanitizeName(f.t.Name()),
 			Name:      "test-file-watch",
@@ -148,6 +174,10 @@ func (f *fixture) CreateSimpleFileWatch() (types.NamespacedName, *filewatches.Fi
 			},
 		},
 	}
+}
+
+func (f *fixture) createFileWatch(fw *filewatches.FileWatch) (types.NamespacedName, *filewatches.FileWatch) {
+	f.t.Helper()
 	f.Create(fw)
 
 	f.setDisabled(types.NamespacedName{Namespace: fw.Namespace, Name: fw.Name}, false)
@@ -241,6 +271,174 @@ func TestController_IgnoreEphemeralFiles(t *testing.T) {
 	assert.Equal(t, []string{f.tmpdir.JoinPath("b", "c", "stop")}, fw.Status.FileEvents[1].SeenFiles)
 }
 
+func TestController_IgnoreSelfWrites(t *testing.T) {
+	f := newFixture(t)
+	key, _ := f.CreateSimpleFileWatch()
+
+	f.controller.selfWrites.RecordFile(f.tmpdir.JoinPath("a", "package-lock.json"), []byte("synced"))
+	f.tmpdir.WriteFile(filepath.Join("a", "package-lock.json"), "synced")
+
+	f.ChangeAndWaitForSeenFile(key, "a", "start")
+	f.ChangeFile("a", "package-lock.json")
+	f.ChangeAndWaitForSeenFile(key, "a", "stop")
+
+	// Once the file has new contents, it's a real change.
+	f.tmpdir.WriteFile(filepath.Join("a", "package-lock.json"), "edited")
+	f.ChangeAndWaitForSeenFile(key, "a", "package-lock.json")
+
+	var fw filewatches.FileWatch
+	f.MustGet(key, &fw)
+	require.Equal(t, 3, len(fw.Status.FileEvents), "Wrong file event count")
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "start")}, fw.Status.FileEvents[0].SeenFiles)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "stop")}, fw.Status.FileEvents[1].SeenFiles)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "package-lock.json")}, fw.Status.FileEvents[2].SeenFiles)
+}
+
+func TestController_ContentHash(t *testing.T) {
+	f := newFixture(t)
+	f.writeOldFile(filepath.Join("a", "main.go"), "v1")
+
+	fw := f.simpleFileWatch()
+	fw.Spec.ContentHash = true
+	key, _ := f.createFileWatch(fw)
+	f.waitForContentHash(key, "a", "main.go")
+
+	// Touching the file without changing its contents isn't a change.
+	f.ChangeAndWaitForSeenFile(key, "a", "start")
+	f.ChangeFile("a", "main.go")
+	f.ChangeAndWaitForSeenFile(key, "a", "stop")
+
+	f.tmpdir.WriteFile(filepath.Join("a", "main.go"), "v2")
+	f.ChangeAndWaitForSeenFile(key, "a", "main.go")
+
+	// Touching it again after the change is still a no-op.
+	f.ChangeFile("a", "main.go")
+	f.ChangeAndWaitForSeenFile(key, "a", "end")
+
+	f.MustGet(key, fw)
+	require.Equal(t, 4, len(fw.Status.FileEvents), "Wrong file event count")
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "start")}, fw.Status.FileEvents[0].SeenFiles)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "stop")}, fw.Status.FileEvents[1].SeenFiles)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "main.go")}, fw.Status.FileEvents[2].SeenFiles)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "end")}, fw.Status.FileEvents[3].SeenFiles)
+	assert.Equal(t, int32(2), fw.Status.SuppressedEventCount)
+}
+
+func TestController_ContentHashUntrustedUntilFirstEvent(t *testing.T) {
+	f := newFixture(t)
+	f.writeOldFile(filepath.Join("a", "old.go"), "v1")
+
+	// Written just now, so it may have changed after the watch started.
+	f.tmpdir.WriteFile(filepath.Join("a", "new.go"), "v1")
+	f.writeOldFile(filepath.Join("a", "ignored", "main.go"), "v1")
+
+	fw := f.simpleFileWatch()
+	fw.Spec.ContentHash = true
+	fw.Spec.Ignores = []filewatches.IgnoreDef{{BasePath: f.tmpdir.JoinPath("a", "ignored")}}
+	key, _ := f.createFileWatch(fw)
+	f.waitForContentHash(key, "a", "old.go")
+
+	hashes := f.controller.targetWatches[key].contentHashes
+	hashes.mu.Lock()
+	assert.NotContains(t, hashes.hashes, f.tmpdir.JoinPath("a", "new.go"))
+	assert.NotContains(t, hashes.hashes, f.tmpdir.JoinPath("a", "ignored", "main.go"))
+	hashes.mu.Unlock()
+
+	// The first event for a file that wasn't hashed passes through.
+	f.ChangeAndWaitForSeenFile(key, "a", "new.go")
+
+	// After that, touches are dropped.
+	f.ChangeFile("a", "new.go")
+	f.ChangeAndWaitForSeenFile(key, "a", "end")
+
+	f.MustGet(key, fw)
+	require.Equal(t, 2, len(fw.Status.FileEvents), "Wrong file event count")
+	assert.Equal(t, int32(1), fw.Status.SuppressedEventCount)
+}
+
+func TestController_GitBranchSwitch(t *testing.T) {
+	f := newFixture(t)
+	f.tmpdir.WriteFile(filepath.Join(".git", "HEAD"), "ref: refs/heads/main\n")
+	key, _ := f.CreateSimpleFileWatch()
+
+	f.ChangeAndWaitForSeenFile(key, "a", "start")
+
+	// Hold the timers, so that the changes are batched together,
+	// and the checkout can't finish until we say so.
+	f.fakeTimerMaker.RestTimerLock.Lock()
+	f.fakeTimerMaker.GitPollLock.Lock()
+	f.tmpdir.WriteFile(filepath.Join(".git", "index.lock"), "")
+	f.ChangeFile("a", "1")
+	f.ChangeFile("b", "c", "2")
+	f.ChangeFile("a", "1")
+	f.fakeTimerMaker.RestTimerLock.Unlock()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(f.Stdout(), "Git operation in progress")
+	}, time.Second, 10*time.Millisecond)
+
+	f.tmpdir.WriteFile(filepath.Join(".git", "HEAD"), "ref: refs/heads/feature\n")
+	f.tmpdir.Rm(filepath.Join(".git", "index.lock"))
+	f.fakeTimerMaker.GitPollLock.Unlock()
+
+	f.WaitForSeenFile(key, "b", "c", "2")
+
+	var fw filewatches.FileWatch
+	f.MustGet(key, &fw)
+	require.Equal(t, 2, len(fw.Status.FileEvents), "Wrong file event count")
+	assert.False(t, fw.Status.FileEvents[0].BranchSwitch)
+	assert.True(t, fw.Status.FileEvents[1].BranchSwitch)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "1"), f.tmpdir.JoinPath("b", "c", "2")},
+		fw.Status.FileEvents[1].SeenFiles)
+
+	// Once git is done, changes go through right away.
+	f.ChangeAndWaitForSeenFile(key, "a", "stop")
+	f.MustGet(key, &fw)
+	assert.False(t, fw.Status.FileEvents[2].BranchSwitch)
+}
+
+func TestController_GitOperationFinishedBeforeBatch(t *testing.T) {
+	f := newFixture(t)
+	f.tmpdir.WriteFile(filepath.Join(".git", "HEAD"), "ref: refs/heads/main\n")
+	key, _ := f.CreateSimpleFileWatch()
+
+	f.ChangeAndWaitForSeenFile(key, "a", "start")
+	gitOps := f.controller.targetWatches[key].gitOps
+
+	// The checkout finishes before the changes are coalesced,
+	// so we only know about it from the first raw event.
+	f.fakeTimerMaker.RestTimerLock.Lock()
+	f.fakeTimerMaker.MaxTimerLock.Lock()
+	f.fakeTimerMaker.GitPollLock.Lock()
+	f.tmpdir.WriteFile(filepath.Join(".git", "index.lock"), "")
+	f.ChangeFile("a", "1")
+	require.Eventually(t, func() bool {
+		gitOps.mu.Lock()
+		defer gitOps.mu.Unlock()
+		return gitOps.seenGitDir != ""
+	}, time.Second, 10*time.Millisecond)
+
+	f.tmpdir.WriteFile(filepath.Join(".git", "HEAD"), "ref: refs/heads/feature\n")
+	f.tmpdir.Rm(filepath.Join(".git", "index.lock"))
+	f.ChangeFile("b", "c", "2")
+	f.fakeTimerMaker.RestTimerLock.Unlock()
+	f.fakeTimerMaker.MaxTimerLock.Unlock()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(f.Stdout(), "Git operation in progress")
+	}, time.Second, 10*time.Millisecond)
+	f.fakeTimerMaker.GitPollLock.Unlock()
+
+	f.WaitForSeenFile(key, "b", "c", "2")
+
+	var fw filewatches.FileWatch
+	f.MustGet(key, &fw)
+	require.Equal(t, 2, len(fw.Status.FileEvents), "Wrong file event count")
+	assert.True(t, fw.Status.FileEvents[1].BranchSwitch)
+	assert.Equal(t, []string{f.tmpdir.JoinPath("a", "1"), f.tmpdir.JoinPath("b", "c", "2")},
+		fw.Status.FileEvents[1].SeenFiles)
+}
+
 // TestController_Watcher_Cancel peeks into internal/unexported portions of the controller to inspect the actual
 // filesystem monitor so it can ensure reconciler is not leaking resources; other tests should prefer observing
 // desired state!
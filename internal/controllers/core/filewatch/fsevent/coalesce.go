@@ -19,6 +19,10 @@ const BufferMinRestDuration = 200 * time.Millisecond
 // channel if the threshold is reached even if new file changes are still coming in.
 const BufferMaxDuration = 10 * time.Second
 
+// GitPollDuration is how often we check whether a git operation (e.g., a branch checkout)
+// has finished, while holding back the file changes that it made.
+const GitPollDuration = 100 * time.Millisecond
+
 // Coalesce makes an attempt to read some events from `eventChan` so that multiple file changes
 // that happen at the same time from the user's perspective are grouped together.
 func Coalesce(timerMaker TimerMaker, eventChan <-chan watch.FileEvent) <-chan []watch.FileEvent {
@@ -0,0 +1,183 @@
+package filewatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/git"
+	"github.com/tilt-dev/tilt/internal/watch"
+)
+
+// How long we'll hold file changes for a git operation before giving up,
+// in case a crashed git process left a stale index.lock behind.
+const gitOperationMaxHold = time.Minute
+
+// gitOperations holds back file changes while git is rewriting the
+// working tree (e.g., when switching branches), so that we don't start
+// builds against a half-updated tree.
+//
+// Not thread-safe. Should only be used from the dispatch loop,
+// except for observe(), which guards its own state.
+type gitOperations struct {
+	gitDirs []string
+	heads   map[string]string
+
+	// A quick git operation can finish before its changes have been
+	// coalesced into a batch, so we also check for one as soon as
+	// the first raw event of a batch comes in.
+	mu         sync.Mutex
+	checkedRaw bool
+	seenGitDir string
+
+	holding   bool
+	holdStart time.Time
+	held      []watch.FileEvent
+
+	// Whether any events came in since the last poll.
+	sawEvents bool
+}
+
+// Returns nil if none of the watched paths are in a git repo.
+func newGitOperations(watchedPaths []string) *gitOperations {
+	var gitDirs []string
+	heads := make(map[string]string)
+	for _, p := range watchedPaths {
+		gitDir := git.FindGitDir(p)
+		if gitDir == "" {
+			continue
+		}
+		if _, ok := heads[gitDir]; ok {
+			continue
+		}
+		gitDirs = append(gitDirs, gitDir)
+		heads[gitDir] = git.Head(gitDir)
+	}
+	if len(gitDirs) == 0 {
+		return nil
+	}
+	return &gitOperations{gitDirs: gitDirs, heads: heads}
+}
+
+// Returns the git dir of an operation in progress, or "" if there is none.
+func (g *gitOperations) inProgress() string {
+	for _, gitDir := range g.gitDirs {
+		if git.IsOperationInProgress(gitDir) {
+			return gitDir
+		}
+	}
+	return ""
+}
+
+// Passes the raw file events through, checking whether a git operation
+// is in progress when the first one of each batch arrives.
+func (g *gitOperations) observe(events <-chan watch.FileEvent) <-chan watch.FileEvent {
+	if g == nil {
+		return events
+	}
+
+	ret := make(chan watch.FileEvent)
+	go func() {
+		defer close(ret)
+		for e := range events {
+			g.mu.Lock()
+			if !g.checkedRaw {
+				g.checkedRaw = true
+				if gitDir := g.inProgress(); gitDir != "" {
+					g.seenGitDir = gitDir
+				}
+			}
+			g.mu.Unlock()
+			ret <- e
+		}
+	}()
+	return ret
+}
+
+// Returns the git dir of an operation that observe() saw while
+// the current batch was coming in, and starts watching for the next batch.
+func (g *gitOperations) takeSeen() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	gitDir := g.seenGitDir
+	g.seenGitDir = ""
+	g.checkedRaw = false
+	return gitDir
+}
+
+// Holds the events if a git operation is in progress, or was in progress
+// when the first of the events came in.
+//
+// Returns true if the events were held, and the git dir of the
+// operation if this is the start of a new hold.
+func (g *gitOperations) hold(events []watch.FileEvent, now time.Time) (bool, string) {
+	if g == nil {
+		return false, ""
+	}
+
+	seenGitDir := g.takeSeen()
+	if g.holding {
+		g.held = append(g.held, events...)
+		g.sawEvents = true
+		return true, ""
+	}
+
+	gitDir := g.inProgress()
+	if gitDir == "" {
+		gitDir = seenGitDir
+	}
+	if gitDir == "" {
+		return false, ""
+	}
+
+	g.holding = true
+	g.holdStart = now
+	g.held = append([]watch.FileEvent{}, events...)
+	g.sawEvents = false
+	return true, gitDir
+}
+
+// Returns true once the git operation has finished and the file changes
+// have settled, or we've given up waiting.
+func (g *gitOperations) finished(now time.Time) bool {
+	if now.Sub(g.holdStart) >= gitOperationMaxHold {
+		return true
+	}
+
+	sawEvents := g.sawEvents
+	g.sawEvents = false
+	return !sawEvents && g.inProgress() == ""
+}
+
+// Stop holding, and return all the held events, with duplicates removed.
+func (g *gitOperations) release() []watch.FileEvent {
+	seen := make(map[string]bool, len(g.held))
+	var result []watch.FileEvent
+	for _, e := range g.held {
+		if seen[e.Path()] {
+			continue
+		}
+		seen[e.Path()] = true
+		result = append(result, e)
+	}
+
+	g.holding = false
+	g.held = nil
+	return result
+}
+
+// Returns true if HEAD moved in any repo since the last check.
+func (g *gitOperations) headMoved() bool {
+	if g == nil {
+		return false
+	}
+
+	moved := false
+	for _, gitDir := range g.gitDirs {
+		head := git.Head(gitDir)
+		if head != g.heads[gitDir] {
+			g.heads[gitDir] = head
+			moved = true
+		}
+	}
+	return moved
+}
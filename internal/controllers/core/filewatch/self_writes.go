@@ -0,0 +1,102 @@
+package filewatch
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tilt-dev/tilt/internal/watch"
+)
+
+// Directories don't have contents, so all that matters is that they still exist.
+const dirHash = "dir"
+
+// SelfWrites tracks files that Tilt itself writes to the local filesystem
+// (e.g., files synced back from a container), so that the file watcher
+// doesn't report them as changes and trigger another update.
+//
+// An event is suppressed as long as the file still has the contents that
+// Tilt wrote. Once the file changes again, we stop tracking it.
+type SelfWrites struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+func NewSelfWrites() *SelfWrites {
+	return &SelfWrites{hashes: make(map[string]string)}
+}
+
+// Record that Tilt is about to write the given contents to a file.
+//
+// Call this before writing, so that the watcher can't see the write first.
+func (s *SelfWrites) RecordFile(path string, contents []byte) {
+	sum := sha256.Sum256(contents)
+	s.record(path, string(sum[:]))
+}
+
+// Record that Tilt is about to create a directory.
+func (s *SelfWrites) RecordDir(path string) {
+	s.record(path, dirHash)
+}
+
+func (s *SelfWrites) record(path string, hash string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hashes[filepath.Clean(path)] = hash
+}
+
+// Filter out the events for files that still have the contents that Tilt wrote.
+func (s *SelfWrites) filter(events []watch.FileEvent) []watch.FileEvent {
+	if s == nil {
+		return events
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.hashes) == 0 {
+		return events
+	}
+
+	var result []watch.FileEvent
+	for _, e := range events {
+		p := filepath.Clean(e.Path())
+		expected, ok := s.hashes[p]
+		if ok {
+			hash, err := hashFile(p)
+			if err == nil && hash == expected {
+				continue
+			}
+			delete(s.hashes, p)
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return dirHash, nil
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return string(h.Sum(nil)), nil
+}
@@ -34,6 +34,12 @@ type watcher struct {
 	done           bool
 	notify         watch.Notify
 	cancel         func()
+
+	// Only set in ContentHash mode.
+	contentHashes *contentHashes
+
+	// Only set if the watched paths are in a git repo.
+	gitOps *gitOperations
 }
 
 // Whether we need to restart the watcher.
@@ -90,11 +96,25 @@ func (w *watcher) recordError(err error) {
 	}
 }
 
-func (w *watcher) recordEvent(fsEvents []watch.FileEvent) {
+// Drop the events for files whose contents didn't change, if we're
+// in ContentHash mode.
+func (w *watcher) dropUnchanged(fsEvents []watch.FileEvent) []watch.FileEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.contentHashes == nil {
+		return fsEvents
+	}
+
+	result, dropped := w.contentHashes.filter(fsEvents)
+	w.status.SuppressedEventCount += int32(dropped)
+	return result
+}
+
+func (w *watcher) recordEvent(fsEvents []watch.FileEvent, branchSwitch bool) {
 	now := apis.NowMicro()
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	event := v1alpha1.FileEvent{Time: *now.DeepCopy()}
+	event := v1alpha1.FileEvent{Time: *now.DeepCopy(), BranchSwitch: branchSwitch}
 	for _, fsEvent := range fsEvents {
 		event.SeenFiles = append(event.SeenFiles, fsEvent.Path())
 	}
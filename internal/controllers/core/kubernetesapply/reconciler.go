@@ -25,6 +25,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/imagemap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/trigger"
@@ -53,6 +54,7 @@ type Reconciler struct {
 	st         store.RStore
 	dkc        build.DockerKubeConnection
 	k8sClient  k8s.Client
+	clients    cluster.ClientProvider
 	ctrlClient ctrlclient.Client
 	indexer    *indexer.Indexer
 	execer     localexec.Execer
@@ -83,10 +85,11 @@ func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 	return b, nil
 }
 
-func NewReconciler(ctrlClient ctrlclient.Client, k8sClient k8s.Client, scheme *runtime.Scheme, dkc build.DockerKubeConnection, st store.RStore, execer localexec.Execer) *Reconciler {
+func NewReconciler(ctrlClient ctrlclient.Client, k8sClient k8s.Client, clients cluster.ClientProvider, scheme *runtime.Scheme, dkc build.DockerKubeConnection, st store.RStore, execer localexec.Execer) *Reconciler {
 	return &Reconciler{
 		ctrlClient: ctrlClient,
 		k8sClient:  k8sClient,
+		clients:    clients,
 		indexer:    indexer.NewIndexer(scheme, indexKubernetesApply),
 		execer:     execer,
 		dkc:        dkc,
@@ -305,7 +308,7 @@ func (r *Reconciler) forceApplyHelper(
 	var deployed []k8s.K8sEntity
 	deployCtx := r.indentLogger(ctx)
 	if spec.YAML != "" {
-		deployed, err = r.runYAMLDeploy(deployCtx, spec, imageMaps)
+		deployed, err = r.runYAMLDeploy(deployCtx, spec, cluster, imageMaps)
 		if err != nil {
 			return recordErrorStatus(err)
 		}
@@ -344,9 +347,16 @@ func (r *Reconciler) printAppliedReport(ctx context.Context, msg string, deploye
 	}
 }
 
-func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) ([]k8s.K8sEntity, error) {
+func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec,
+	cluster *v1alpha1.Cluster,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) ([]k8s.K8sEntity, error) {
+	kCli, err := r.k8sClientForCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create API objects.
-	newK8sEntities, err := r.createEntitiesToDeploy(ctx, imageMaps, spec)
+	newK8sEntities, err := r.createEntitiesToDeploy(ctx, kCli, imageMaps, spec)
 	if err != nil {
 		return newK8sEntities, err
 	}
@@ -358,7 +368,7 @@ func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.Kubernetes
 		timeout = v1alpha1.KubernetesApplyTimeoutDefault
 	}
 
-	deployed, err := r.k8sClient.Upsert(ctx, newK8sEntities, timeout)
+	deployed, err := kCli.Upsert(ctx, newK8sEntities, timeout)
 	if err != nil {
 		r.printAppliedReport(ctx, "Tried to apply objects to cluster:", newK8sEntities)
 		return nil, err
@@ -368,6 +378,21 @@ func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.Kubernetes
 	return deployed, nil
 }
 
+// The client for the cluster that the objects are deployed to.
+//
+// The default cluster uses the client that Tilt started with. Clusters declared
+// with k8s_cluster() use the client that the Cluster reconciler connected.
+func (r *Reconciler) k8sClientForCluster(c *v1alpha1.Cluster) (k8s.Client, error) {
+	if c == nil || c.Name == "" || c.Name == v1alpha1.ClusterNameDefault {
+		return r.k8sClient, nil
+	}
+	kCli, _, err := r.clients.GetK8sClient(types.NamespacedName{Name: c.Name})
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %v", c.Name, err)
+	}
+	return kCli, nil
+}
+
 func (r *Reconciler) maybeInjectKubeconfig(cmd *model.Cmd, cluster *v1alpha1.Cluster) {
 	if cluster == nil ||
 		cluster.Status.Connection == nil ||
@@ -456,6 +481,7 @@ type injectResult struct {
 }
 
 func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
+	kCli k8s.Client,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	spec v1alpha1.KubernetesApplySpec) ([]k8s.K8sEntity, error) {
 	newK8sEntities := []k8s.K8sEntity{}
@@ -504,7 +530,7 @@ func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
 		// When working with a local k8s cluster, we set the pull policy to Never,
 		// to ensure that k8s fails hard if the image is missing from docker.
 		policy := v1.PullIfNotPresent
-		if r.dkc.WillBuildToKubeContext(k8s.KubeContext(r.k8sClient.APIConfig().CurrentContext)) {
+		if r.dkc.WillBuildToKubeContext(k8s.KubeContext(kCli.APIConfig().CurrentContext)) {
 			policy = v1.PullNever
 		}
 
@@ -905,7 +931,10 @@ func (r *Reconciler) bestEffortDelete(ctx context.Context, nn types.NamespacedNa
 			l.Infof("→ %s", displayName)
 		}
 
-		err := r.k8sClient.Delete(ctx, toDelete.entities, toDelete.wait)
+		kCli, err := r.k8sClientForCluster(toDelete.cluster)
+		if err == nil {
+			err = kCli.Delete(ctx, toDelete.entities, toDelete.wait)
+		}
 		if err != nil {
 			l.Errorf("Error %s: %v", reason, err)
 		}
@@ -18,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
@@ -112,6 +113,32 @@ func TestBasicApplyYAML(t *testing.T) {
 	assert.Equal(f.T(), f.kClient.Yaml, "")
 }
 
+func TestApplyYAMLToNamedCluster(t *testing.T) {
+	f := newFixture(t)
+	clusterNN := types.NamespacedName{Name: "staging"}
+	stagingCli, _ := f.clients.EnsureK8sCluster(f.Context(), clusterNN)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:    testyaml.SanchoYAML,
+			Cluster: clusterNN.Name,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Contains(f.T(), stagingCli.Yaml, "name: sancho")
+	assert.Equal(f.T(), "", f.kClient.Yaml)
+
+	f.Delete(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Contains(f.T(), stagingCli.DeletedYaml, "name: sancho")
+	assert.Equal(f.T(), "", f.kClient.DeletedYaml)
+}
+
 func TestBasicApplyCmd(t *testing.T) {
 	f := newFixture(t)
 
@@ -771,6 +798,7 @@ type fixture struct {
 	*fake.ControllerFixture
 	r       *Reconciler
 	kClient *k8s.FakeK8sClient
+	clients *cluster.FakeClientProvider
 	execer  *localexec.FakeExecer
 }
 
@@ -786,12 +814,14 @@ func newFixture(t *testing.T) *fixture {
 	execer := localexec.NewFakeExecer(t)
 
 	db := build.NewDockerBuilder(dockerClient, dockerfile.Labels{})
-	r := NewReconciler(cfb.Client, kClient, v1alpha1.NewScheme(), db, cfb.Store, execer)
+	clients := cluster.NewFakeClientProvider(t, cfb.Client)
+	r := NewReconciler(cfb.Client, kClient, clients, v1alpha1.NewScheme(), db, cfb.Store, execer)
 
 	f := &fixture{
 		ControllerFixture: cfb.Build(r),
 		r:                 r,
 		kClient:           kClient,
+		clients:           clients,
 		execer:            execer,
 	}
 	f.Create(&v1alpha1.Cluster{
@@ -713,9 +713,11 @@ func (w *Reconciler) createPodLogStream(ctx context.Context, kd *v1alpha1.Kubern
 		Spec: v1alpha1.PodLogStreamSpec{
 			Pod:              pod.Name,
 			Namespace:        pod.Namespace,
+			Cluster:          kd.Spec.Cluster,
 			SinceTime:        plsTemplate.SinceTime,
 			IgnoreContainers: plsTemplate.IgnoreContainers,
 			OnlyContainers:   plsTemplate.OnlyContainers,
+			Format:           plsTemplate.Format,
 		},
 	}
 
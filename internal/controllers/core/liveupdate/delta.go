@@ -0,0 +1,50 @@
+package liveupdate
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Split the path mappings into the ones that we should try to send by delta,
+// and the ones we should copy whole.
+//
+// A file is sent by delta if it's under a sync with delta enabled,
+// and it's big enough that a delta is likely to be cheaper than a copy.
+func deltaPaths(basePath string, syncs []v1alpha1.LiveUpdateSync, mappings []build.PathMapping) (delta, rest []build.PathMapping) {
+	var dirs []string
+	for _, sync := range syncs {
+		if !sync.Delta {
+			continue
+		}
+		localPath := sync.LocalPath
+		if !filepath.IsAbs(localPath) {
+			localPath = filepath.Join(basePath, localPath)
+		}
+		dirs = append(dirs, localPath)
+	}
+	if len(dirs) == 0 {
+		return nil, mappings
+	}
+
+	for _, pm := range mappings {
+		if ospath.IsChildOfOne(dirs, pm.LocalPath) && isLargeRegularFile(pm.LocalPath) {
+			delta = append(delta, pm)
+		} else {
+			rest = append(rest, pm)
+		}
+	}
+	return delta, rest
+}
+
+func isLargeRegularFile(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Size() >= containerupdate.DeltaMinFileSize
+}
@@ -3,6 +3,7 @@ package liveupdate
 import (
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -11,6 +12,10 @@ type Input struct {
 	// Derived from DockerResource
 	IsDC bool
 
+	// Derived from the Docker Compose project. Files in these
+	// syncs don't need to be copied.
+	MountedSyncs []v1alpha1.LiveUpdateMountedSync
+
 	// Derived from KubernetesResource + KubenetesSelector + DockerResource
 	Containers []liveupdates.Container
 
@@ -24,6 +24,9 @@ type monitor struct {
 	lastTriggerQueue          *v1alpha1.ConfigMap
 	lastImageMap              *v1alpha1.ImageMap
 
+	// Syncs satisfied by bind mounts on the Docker Compose service.
+	mountedSyncs []v1alpha1.LiveUpdateMountedSync
+
 	// History of source file changes.
 	sources map[string]*monitorSource
 
@@ -0,0 +1,79 @@
+package liveupdate
+
+import (
+	"path"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Find the syncs that are already satisfied by a bind mount on the
+// Docker Compose service.
+//
+// A sync is satisfied if its local path is inside the mount source,
+// and its container path is the same place inside the mount target.
+// Files in these syncs show up in the container on their own,
+// so there's no need to copy them.
+func mountedSyncs(basePath string, syncs []v1alpha1.LiveUpdateSync, svc types.ServiceConfig) []v1alpha1.LiveUpdateMountedSync {
+	var result []v1alpha1.LiveUpdateMountedSync
+	for _, sync := range syncs {
+		localPath := sync.LocalPath
+		if !filepath.IsAbs(localPath) {
+			localPath = filepath.Join(basePath, localPath)
+		}
+
+		for _, v := range svc.Volumes {
+			if v.Type != types.VolumeTypeBind || v.Source == "" || v.Target == "" {
+				continue
+			}
+
+			rel, ok := ospath.Child(v.Source, localPath)
+			if !ok {
+				continue
+			}
+
+			if path.Join(v.Target, filepath.ToSlash(rel)) != path.Clean(sync.ContainerPath) {
+				continue
+			}
+
+			result = append(result, v1alpha1.LiveUpdateMountedSync{
+				LocalPath:     sync.LocalPath,
+				ContainerPath: sync.ContainerPath,
+				MountSource:   v.Source,
+				MountTarget:   v.Target,
+			})
+			break
+		}
+	}
+	return result
+}
+
+// Split the path mappings into the ones that a bind mount already takes
+// care of, and the ones we still need to copy.
+func excludeMountedPaths(basePath string, mounted []v1alpha1.LiveUpdateMountedSync, mappings []build.PathMapping) (skipped, rest []build.PathMapping) {
+	if len(mounted) == 0 {
+		return nil, mappings
+	}
+
+	dirs := make([]string, 0, len(mounted))
+	for _, m := range mounted {
+		localPath := m.LocalPath
+		if !filepath.IsAbs(localPath) {
+			localPath = filepath.Join(basePath, localPath)
+		}
+		dirs = append(dirs, localPath)
+	}
+
+	for _, pm := range mappings {
+		if ospath.IsChildOfOne(dirs, pm.LocalPath) {
+			skipped = append(skipped, pm)
+		} else {
+			rest = append(rest, pm)
+		}
+	}
+	return skipped, rest
+}
@@ -0,0 +1,48 @@
+package liveupdate
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestMountedSyncs(t *testing.T) {
+	svc := types.ServiceConfig{
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: types.VolumeTypeBind, Source: "/src", Target: "/app"},
+			{Type: types.VolumeTypeVolume, Source: "cache", Target: "/cache"},
+		},
+	}
+	syncs := []v1alpha1.LiveUpdateSync{
+		// Inside the mount, at the same relative path.
+		{LocalPath: "web", ContainerPath: "/app/web"},
+		// Inside the mount, but synced somewhere else.
+		{LocalPath: "api", ContainerPath: "/srv/api"},
+		// Outside the mount.
+		{LocalPath: "/other", ContainerPath: "/app/other"},
+		// A named volume isn't a bind mount.
+		{LocalPath: "cache", ContainerPath: "/cache"},
+	}
+
+	assert.Equal(t, []v1alpha1.LiveUpdateMountedSync{
+		{LocalPath: "web", ContainerPath: "/app/web", MountSource: "/src", MountTarget: "/app"},
+	}, mountedSyncs("/src", syncs, svc))
+}
+
+func TestExcludeMountedPaths(t *testing.T) {
+	mounted := []v1alpha1.LiveUpdateMountedSync{
+		{LocalPath: "web", ContainerPath: "/app/web", MountSource: "/src", MountTarget: "/app"},
+	}
+	mappings := []build.PathMapping{
+		{LocalPath: "/src/web/index.js", ContainerPath: "/app/web/index.js"},
+		{LocalPath: "/src/api/main.go", ContainerPath: "/srv/api/main.go"},
+	}
+
+	skipped, rest := excludeMountedPaths("/src", mounted, mappings)
+	assert.Equal(t, mappings[:1], skipped)
+	assert.Equal(t, mappings[1:], rest)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -27,7 +28,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
@@ -60,8 +63,10 @@ type Reconciler struct {
 
 	ExecUpdater   containerupdate.ContainerUpdater
 	DockerUpdater containerupdate.ContainerUpdater
+	dcClient      dockercompose.DockerComposeClient
 	updateMode    liveupdates.UpdateMode
 	kubeContext   k8s.KubeContext
+	selfWrites    *filewatch.SelfWrites
 	startedTime   metav1.MicroTime
 
 	monitors map[string]*monitor
@@ -78,15 +83,19 @@ func NewReconciler(
 	st store.RStore,
 	dcu *containerupdate.DockerUpdater,
 	ecu *containerupdate.ExecUpdater,
+	dcClient dockercompose.DockerComposeClient,
 	updateMode liveupdates.UpdateMode,
 	kubeContext k8s.KubeContext,
+	selfWrites *filewatch.SelfWrites,
 	client ctrlclient.Client,
 	scheme *runtime.Scheme) *Reconciler {
 	return &Reconciler{
 		DockerUpdater: dcu,
 		ExecUpdater:   ecu,
+		dcClient:      dcClient,
 		updateMode:    updateMode,
 		kubeContext:   kubeContext,
+		selfWrites:    selfWrites,
 		client:        client,
 		indexer:       indexer.NewIndexer(scheme, indexLiveUpdate),
 		store:         st,
@@ -99,13 +108,17 @@ func NewReconciler(
 func NewFakeReconciler(
 	st store.RStore,
 	cu containerupdate.ContainerUpdater,
+	dcClient dockercompose.DockerComposeClient,
+	selfWrites *filewatch.SelfWrites,
 	client ctrlclient.Client) *Reconciler {
 	scheme := v1alpha1.NewScheme()
 	return &Reconciler{
 		DockerUpdater: cu,
 		ExecUpdater:   cu,
+		dcClient:      dcClient,
 		updateMode:    liveupdates.UpdateModeAuto,
 		kubeContext:   k8s.KubeContext("fake-context"),
+		selfWrites:    selfWrites,
 		client:        client,
 		indexer:       indexer.NewIndexer(scheme, indexLiveUpdate),
 		store:         st,
@@ -467,11 +480,43 @@ func (r *Reconciler) reconcileDockerComposeService(ctx context.Context, monitor
 		changed = true
 	}
 
+	if monitor.lastDockerComposeService == nil ||
+		!apicmp.DeepEqual(monitor.lastDockerComposeService.Spec, dcs.Spec) {
+		monitor.mountedSyncs = r.dockerComposeMountedSyncs(ctx, monitor.spec, dcs.Spec)
+		changed = true
+	}
+
 	monitor.lastDockerComposeService = &dcs
 
 	return changed, nil
 }
 
+// Load the Docker Compose project to find out which syncs are
+// already satisfied by bind mounts.
+//
+// If we can't load the project, we fall back to copying all files.
+func (r *Reconciler) dockerComposeMountedSyncs(ctx context.Context, spec v1alpha1.LiveUpdateSpec, dcSpec v1alpha1.DockerComposeServiceSpec) []v1alpha1.LiveUpdateMountedSync {
+	if r.dcClient == nil || len(spec.Syncs) == 0 {
+		return nil
+	}
+	if len(dcSpec.Project.ConfigPaths) == 0 && dcSpec.Project.YAML == "" {
+		return nil
+	}
+
+	proj, err := r.dcClient.Project(ctx, dcSpec.Project)
+	if err != nil {
+		logger.Get(ctx).Debugf("Loading docker-compose project for live update: %v", err)
+		return nil
+	}
+
+	svc, err := proj.GetService(dcSpec.Service)
+	if err != nil {
+		logger.Get(ctx).Debugf("Loading docker-compose service for live update: %v", err)
+		return nil
+	}
+	return mountedSyncs(spec.BasePath, spec.Syncs, svc)
+}
+
 // Go through all the file changes, and delete files that aren't relevant
 // to the current build.
 //
@@ -618,6 +663,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 		status.Failed = createFailedState(lu, "Invalid", err.Error())
 		return status
 	}
+	status.MountedSyncs = monitor.mountedSyncs
 
 	manifestName := lu.Annotations[v1alpha1.AnnotationManifest]
 	updateMode := lu.Annotations[liveupdate.AnnotationUpdateMode]
@@ -776,6 +822,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 			// Apply the change to the container.
 			oneUpdateStatus = r.applyInternal(ctx, lu.Spec, Input{
 				IsDC:               lu.Spec.Selector.DockerCompose != nil,
+				MountedSyncs:       monitor.mountedSyncs,
 				ChangedFiles:       plan.SyncPaths,
 				Containers:         []liveupdates.Container{c},
 				LastFileTimeSynced: newHighWaterMark,
@@ -917,6 +964,14 @@ func (r *Reconciler) applyInternal(
 		return result
 	}
 
+	// Files under a bind mount are already in the container.
+	mountedRemove, toRemove := excludeMountedPaths(spec.BasePath, input.MountedSyncs, toRemove)
+	mountedArchive, toArchive := excludeMountedPaths(spec.BasePath, input.MountedSyncs, toArchive)
+	skipped := len(mountedRemove) + len(mountedArchive)
+	if skipped > 0 {
+		l.Infof("Skipping %d file(s) already synced to container%s by a bind mount", skipped, suffix)
+	}
+
 	if len(toRemove) > 0 {
 		l.Infof("Will delete %d file(s) from container%s: %s", len(toRemove), suffix, names)
 		for _, pm := range toRemove {
@@ -931,15 +986,37 @@ func (r *Reconciler) applyInternal(
 		}
 	}
 
+	// Large files under a delta sync only need their changed blocks sent,
+	// if the container can run the delta helper.
+	var toDelta []build.PathMapping
+	du, canDelta := cu.(containerupdate.DeltaUpdater)
+	if canDelta {
+		toDelta, toArchive = deltaPaths(spec.BasePath, spec.Syncs, toArchive)
+	}
+
 	var lastExecErrorStatus *v1alpha1.LiveUpdateContainerStatus
 	for _, cInfo := range containers {
+		containerArchive := toArchive
+		if len(toDelta) > 0 {
+			rest, err := containerupdate.SyncDeltas(ctx, du, cInfo, toDelta)
+			if err != nil {
+				l.Infof("Falling back to copying whole files to container %s: %v", cInfo.DisplayName(), err)
+			}
+			containerArchive = append(append([]build.PathMapping{}, toArchive...), rest...)
+		}
+
 		// TODO(nick): We should try to distinguish between cases where the tar writer
 		// fails (which is recoverable) vs when the server-side unpacking
 		// fails (which may not be recoverable).
-		archive := build.TarArchiveForPaths(ctx, toArchive, nil)
+		var archive io.ReadCloser
+		if len(containerArchive) > 0 || (skipped == 0 && len(toDelta) == 0) {
+			archive = build.TarArchiveForPaths(ctx, containerArchive, nil)
+		}
 		err = cu.UpdateContainer(ctx, cInfo, archive,
 			build.PathMappingsToContainerPaths(toRemove), boiledSteps, hotReload)
-		_ = archive.Close()
+		if archive != nil {
+			_ = archive.Close()
+		}
 
 		lastFileTimeSynced := input.LastFileTimeSynced
 		if lastFileTimeSynced.IsZero() {
@@ -993,6 +1070,12 @@ func (r *Reconciler) applyInternal(
 
 		result.Containers = append(result.Containers, cStatus)
 	}
+
+	// Every container should have the same files after the execs,
+	// so we only need to sync back from one of them.
+	if len(spec.SyncBacks) > 0 && len(boiledSteps) > 0 && lastExecErrorStatus == nil && len(containers) > 0 {
+		r.syncBack(ctx, cu, spec, containers[0])
+	}
 	return result
 }
 
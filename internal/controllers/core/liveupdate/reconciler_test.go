@@ -1,6 +1,8 @@
 package liveupdate
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -19,10 +21,12 @@ import (
 	"github.com/tilt-dev/tilt/internal/containerupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
+	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -826,6 +830,204 @@ func TestDockerComposeExecs(t *testing.T) {
 	}
 }
 
+func TestDockerComposeBindMountSkipsCopy(t *testing.T) {
+	f := newFixture(t)
+
+	p, _ := os.Getwd()
+	nowMicro := apis.NowMicro()
+	txtPath := filepath.Join(p, "a.txt")
+	txtChangeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	composeYAML := fmt.Sprintf(`
+services:
+  frontend:
+    image: frontend-image
+    volumes:
+      - %s:/app
+      - cache:/cache
+volumes:
+  cache: {}
+`, p)
+	f.dcCli.ConfigOutput = composeYAML
+	f.setupDockerComposeFrontend()
+
+	var dcs v1alpha1.DockerComposeService
+	f.MustGet(types.NamespacedName{Name: "frontend-service"}, &dcs)
+	dcs.Spec = v1alpha1.DockerComposeServiceSpec{
+		Service: "frontend",
+		Project: v1alpha1.DockerComposeProject{ProjectPath: p, Name: "frontend", YAML: composeYAML},
+	}
+	f.Upsert(&dcs)
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.Execs = []v1alpha1.LiveUpdateExec{
+		{Args: model.ToUnixCmd("yarn install").Argv, TriggerPaths: []string{"a.txt"}},
+	}
+	f.Upsert(&lu)
+
+	f.addFileEvent("frontend-fw", txtPath, txtChangeTime)
+	f.addFileEvent("frontend-fw", filepath.Join(p, "deleted.txt"), txtChangeTime)
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	assert.Contains(t, f.Stdout(), "Skipping 2 file(s) already synced to container by a bind mount")
+	assert.NotContains(t, f.Stdout(), "Will delete")
+	assert.NotContains(t, f.Stdout(), "Will copy")
+	assert.Equal(t, []v1alpha1.LiveUpdateMountedSync{
+		{LocalPath: ".", ContainerPath: "/app", MountSource: p, MountTarget: "/app"},
+	}, lu.Status.MountedSyncs)
+
+	// The run step still happens, but there's nothing to copy or delete.
+	if assert.Equal(t, 1, len(f.cu.Calls)) {
+		assert.Nil(t, f.cu.Calls[0].Archive)
+		assert.Empty(t, f.cu.Calls[0].ToDelete)
+		assert.Equal(t, []model.Cmd{model.ToUnixCmd("yarn install")}, f.cu.Calls[0].Cmds)
+	}
+}
+
+func TestDeltaSync(t *testing.T) {
+	f := newFixture(t)
+	tmp := t.TempDir()
+	nowMicro := apis.NowMicro()
+	changeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	old := bytes.Repeat([]byte("a"), containerupdate.DeltaMinFileSize)
+	updated := append([]byte{}, old...)
+	copy(updated[containerupdate.DeltaBlockSize:], "changed")
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "big.bin"), updated, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "small.txt"), []byte("small"), 0644))
+	f.cu.ContainerFiles = map[string][]byte{"/app/big.bin": old}
+
+	f.setupDockerComposeFrontend()
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.BasePath = tmp
+	lu.Spec.Syncs = []v1alpha1.LiveUpdateSync{{LocalPath: ".", ContainerPath: "/app", Delta: true}}
+	f.Upsert(&lu)
+
+	f.addFileEvent("frontend-fw", filepath.Join(tmp, "big.bin"), changeTime)
+	f.addFileEvent("frontend-fw", filepath.Join(tmp, "small.txt"), changeTime)
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	assert.Equal(t, updated, f.cu.ContainerFiles["/app/big.bin"])
+	if assert.Len(t, f.cu.DeltaCalls, 1) && assert.Len(t, f.cu.DeltaCalls[0], 1) {
+		assert.Equal(t, int64(containerupdate.DeltaBlockSize), f.cu.DeltaCalls[0][0].TransferSize())
+	}
+
+	// Only the small file is copied whole.
+	if assert.Equal(t, 1, len(f.cu.Calls)) {
+		testutils.AssertFilesInTar(t, tar.NewReader(f.cu.Calls[0].Archive), []testutils.ExpectedFile{
+			{Path: "app/small.txt", Contents: "small"},
+			{Path: "app/big.bin", Missing: true},
+		})
+	}
+}
+
+func TestDeltaSyncUnavailableFallsBackToCopy(t *testing.T) {
+	f := newFixture(t)
+	tmp := t.TempDir()
+	nowMicro := apis.NowMicro()
+	changeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	contents := bytes.Repeat([]byte("a"), containerupdate.DeltaMinFileSize)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "big.bin"), contents, 0644))
+	f.cu.ContainerFiles = map[string][]byte{"/app/big.bin": contents}
+	f.cu.DeltaUnavailable = true
+
+	f.setupDockerComposeFrontend()
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.BasePath = tmp
+	lu.Spec.Syncs = []v1alpha1.LiveUpdateSync{{LocalPath: ".", ContainerPath: "/app", Delta: true}}
+	f.Upsert(&lu)
+
+	f.addFileEvent("frontend-fw", filepath.Join(tmp, "big.bin"), changeTime)
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	assert.Empty(t, f.cu.DeltaCalls)
+	if assert.Equal(t, 1, len(f.cu.Calls)) {
+		testutils.AssertFileInTar(t, tar.NewReader(f.cu.Calls[0].Archive),
+			testutils.ExpectedFile{Path: "app/big.bin", Contents: string(contents)})
+	}
+}
+
+func TestSyncBack(t *testing.T) {
+	f := newFixture(t)
+	tmp := t.TempDir()
+	nowMicro := apis.NowMicro()
+	changeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "package.json"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "package-lock.json"), []byte("old lock"), 0644))
+	f.cu.ContainerFiles = map[string][]byte{
+		"/app/package-lock.json": []byte("new lock"),
+		"/app/gen/api/client.go": []byte("package api"),
+	}
+
+	f.setupDockerComposeFrontend()
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.BasePath = tmp
+	lu.Spec.Execs = []v1alpha1.LiveUpdateExec{{Args: model.ToUnixCmd("npm install").Argv}}
+	lu.Spec.SyncBacks = []v1alpha1.LiveUpdateSyncBack{
+		{ContainerPath: "/app/package-lock.json", LocalPath: "package-lock.json"},
+		{ContainerPath: "/app/gen", LocalPath: filepath.Join("src", "generated")},
+	}
+	f.Upsert(&lu)
+
+	f.addFileEvent("frontend-fw", filepath.Join(tmp, "package.json"), changeTime)
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	assert.Equal(t, []string{"/app/package-lock.json", "/app/gen"}, f.cu.ArchiveCalls)
+
+	lock, err := os.ReadFile(filepath.Join(tmp, "package-lock.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "new lock", string(lock))
+
+	client, err := os.ReadFile(filepath.Join(tmp, "src", "generated", "api", "client.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package api", string(client))
+}
+
+func TestSyncBackSkippedOnExecFailure(t *testing.T) {
+	f := newFixture(t)
+	tmp := t.TempDir()
+	nowMicro := apis.NowMicro()
+	changeTime := metav1.MicroTime{Time: nowMicro.Add(time.Second)}
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "package.json"), []byte("{}"), 0644))
+	f.cu.ContainerFiles = map[string][]byte{"/app/package-lock.json": []byte("half-written lock")}
+	f.cu.SetUpdateErr(build.NewRunStepFailure(errors.New("npm install failed")))
+
+	f.setupDockerComposeFrontend()
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.BasePath = tmp
+	lu.Spec.Execs = []v1alpha1.LiveUpdateExec{{Args: model.ToUnixCmd("npm install").Argv}}
+	lu.Spec.SyncBacks = []v1alpha1.LiveUpdateSyncBack{
+		{ContainerPath: "/app/package-lock.json", LocalPath: "package-lock.json"},
+	}
+	f.Upsert(&lu)
+
+	f.addFileEvent("frontend-fw", filepath.Join(tmp, "package.json"), changeTime)
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	assert.Empty(t, f.cu.ArchiveCalls)
+	assert.NoFileExists(t, filepath.Join(tmp, "package-lock.json"))
+}
+
 func TestDockerComposeExecInfraFailure(t *testing.T) {
 	f := newFixture(t)
 
@@ -928,22 +1130,28 @@ func (s *TestingStore) Dispatch(action store.Action) {
 
 type fixture struct {
 	*fake.ControllerFixture
-	r  *Reconciler
-	cu *containerupdate.FakeContainerUpdater
-	st *TestingStore
+	r          *Reconciler
+	cu         *containerupdate.FakeContainerUpdater
+	dcCli      *dockercompose.FakeDCClient
+	selfWrites *filewatch.SelfWrites
+	st         *TestingStore
 }
 
-func newFixture(t testing.TB) *fixture {
+func newFixture(t *testing.T) *fixture {
 	cfb := fake.NewControllerFixtureBuilder(t)
 	cu := &containerupdate.FakeContainerUpdater{}
+	dcCli := dockercompose.NewFakeDockerComposeClient(t, context.Background())
 	st := newTestingStore()
-	r := NewFakeReconciler(st, cu, cfb.Client)
+	selfWrites := filewatch.NewSelfWrites()
+	r := NewFakeReconciler(st, cu, dcCli, selfWrites, cfb.Client)
 	cf := cfb.Build(r)
 	st.ctx = cf.Context()
 	return &fixture{
 		ControllerFixture: cf,
 		r:                 r,
 		cu:                cu,
+		dcCli:             dcCli,
+		selfWrites:        selfWrites,
 		st:                st,
 	}
 }
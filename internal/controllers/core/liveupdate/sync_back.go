@@ -0,0 +1,146 @@
+package liveupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Copy the sync-back paths from the container to the local filesystem.
+//
+// Failures are logged but don't fail the live update, because
+// the container itself is already up-to-date.
+func (r *Reconciler) syncBack(ctx context.Context, cu containerupdate.ContainerUpdater, spec v1alpha1.LiveUpdateSpec, cInfo liveupdates.Container) {
+	l := logger.Get(ctx)
+	sbu, ok := cu.(containerupdate.SyncBackUpdater)
+	if !ok {
+		l.Warnf("Syncing files back from container %s is not supported", cInfo.DisplayName())
+		return
+	}
+
+	for _, sb := range spec.SyncBacks {
+		localPath := sb.LocalPath
+		if !filepath.IsAbs(localPath) {
+			localPath = filepath.Join(spec.BasePath, localPath)
+		}
+
+		written, err := r.syncBackPath(ctx, sbu, cInfo, sb.ContainerPath, localPath)
+		if err != nil {
+			l.Warnf("Failed to sync '%s' back from container %s: %v", sb.ContainerPath, cInfo.DisplayName(), err)
+			continue
+		}
+		if len(written) > 0 {
+			l.Infof("Synced %d file(s) back from container %s:", len(written), cInfo.DisplayName())
+			for _, p := range written {
+				l.Infof("- %s", p)
+			}
+		}
+	}
+}
+
+func (r *Reconciler) syncBackPath(ctx context.Context, sbu containerupdate.SyncBackUpdater, cInfo liveupdates.Container, containerPath, localPath string) ([]string, error) {
+	archive, err := sbu.ArchiveFromContainer(ctx, cInfo, containerPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = archive.Close() }()
+	return extractSyncBack(archive, path.Base(path.Clean(containerPath)), localPath, r.selfWrites)
+}
+
+// Extract a container archive rooted at base into localPath.
+//
+// Only writes files whose contents changed, and records each write
+// so that the file watcher ignores it. Returns the files written.
+func extractSyncBack(archive io.Reader, base string, localPath string, selfWrites *filewatch.SelfWrites) ([]string, error) {
+	var written []string
+	tr := tar.NewReader(archive)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, fmt.Errorf("reading archive: %v", err)
+		}
+
+		name := path.Clean(header.Name)
+		var dest string
+		if name == base {
+			dest = localPath
+		} else if strings.HasPrefix(name, base+"/") {
+			dest = filepath.Join(localPath, filepath.FromSlash(strings.TrimPrefix(name, base+"/")))
+		} else {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err := mkdirAll(dest, selfWrites)
+			if err != nil {
+				return written, err
+			}
+
+		case tar.TypeReg:
+			contents, err := io.ReadAll(tr)
+			if err != nil {
+				return written, fmt.Errorf("reading %s: %v", header.Name, err)
+			}
+
+			existing, err := os.ReadFile(dest)
+			if err == nil && bytes.Equal(existing, contents) {
+				continue
+			}
+
+			err = mkdirAll(filepath.Dir(dest), selfWrites)
+			if err != nil {
+				return written, err
+			}
+
+			selfWrites.RecordFile(dest, contents)
+			err = os.WriteFile(dest, contents, os.FileMode(header.Mode).Perm()|0200)
+			if err != nil {
+				return written, err
+			}
+			written = append(written, dest)
+		}
+	}
+	return written, nil
+}
+
+// Like os.MkdirAll, but records each directory that it creates.
+func mkdirAll(dir string, selfWrites *filewatch.SelfWrites) error {
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("%s: not a directory", dir)
+		}
+		return nil
+	}
+
+	parent := filepath.Dir(dir)
+	if parent != dir {
+		err := mkdirAll(parent, selfWrites)
+		if err != nil {
+			return err
+		}
+	}
+
+	selfWrites.RecordDir(dir)
+	err = os.Mkdir(dir, 0755)
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+	return nil
+}
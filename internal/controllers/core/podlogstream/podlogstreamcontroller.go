@@ -48,7 +48,6 @@ type Controller struct {
 	client    ctrlclient.Client
 	indexer   *indexer.Indexer
 	st        store.RStore
-	kClient   k8s.Client
 	podSource *PodSource
 	mu        sync.Mutex
 	clock     clockwork.Clock
@@ -62,13 +61,12 @@ type Controller struct {
 var _ reconcile.Reconciler = &Controller{}
 var _ store.TearDowner = &Controller{}
 
-func NewController(ctx context.Context, client ctrlclient.Client, scheme *runtime.Scheme, st store.RStore, kClient k8s.Client, podSource *PodSource, clock clockwork.Clock) *Controller {
+func NewController(ctx context.Context, client ctrlclient.Client, scheme *runtime.Scheme, st store.RStore, podSource *PodSource, clock clockwork.Clock) *Controller {
 	return &Controller{
 		ctx:             ctx,
 		client:          client,
 		indexer:         indexer.NewIndexer(scheme, indexPodLogStreamForTiltAPI),
 		st:              st,
-		kClient:         kClient,
 		podSource:       podSource,
 		watches:         make(map[podLogKey]*podLogWatch),
 		hasClosedStream: make(map[podLogKey]bool),
@@ -160,16 +158,18 @@ func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	err = c.podSource.handleReconcileRequest(ctx, streamName, stream)
 	if err != nil {
 		result = c.setErrorStatus(streamName, err)
+	} else if kCli, err := c.podSource.kClientForCluster(stream.Spec.Cluster); err != nil {
+		result = c.setErrorStatus(streamName, err)
 	} else {
 		podNN := types.NamespacedName{Name: stream.Spec.Pod, Namespace: stream.Spec.Namespace}
-		pod, err := c.kClient.PodFromInformerCache(ctx, podNN)
+		pod, err := kCli.PodFromInformerCache(ctx, podNN)
 		if err != nil && apierrors.IsNotFound(err) {
 			c.deleteStreams(streamName)
 			result = c.setErrorStatus(streamName, fmt.Errorf("pod not found: %s", podNN))
 		} else if err != nil {
 			result = c.setErrorStatus(streamName, fmt.Errorf("reading pod: %v", err))
 		} else if pod != nil {
-			result = c.addOrUpdateContainerWatches(ctx, streamName, stream, podNN, pod)
+			result = c.addOrUpdateContainerWatches(ctx, kCli, streamName, stream, podNN, pod)
 		}
 	}
 
@@ -181,7 +181,7 @@ func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return result, nil
 }
 
-func (c *Controller) addOrUpdateContainerWatches(ctx context.Context, streamName types.NamespacedName, stream *v1alpha1.PodLogStream, podNN types.NamespacedName, pod *v1.Pod) reconcile.Result {
+func (c *Controller) addOrUpdateContainerWatches(ctx context.Context, kCli k8s.Client, streamName types.NamespacedName, stream *v1alpha1.PodLogStream, podNN types.NamespacedName, pod *v1.Pod) reconcile.Result {
 	initContainers := c.filterContainers(stream, k8sconv.PodContainers(ctx, pod, pod.Status.InitContainerStatuses))
 	runContainers := c.filterContainers(stream, k8sconv.PodContainers(ctx, pod, pod.Status.ContainerStatuses))
 	containers := []v1alpha1.Container{}
@@ -263,6 +263,7 @@ func (c *Controller) addOrUpdateContainerWatches(ctx context.Context, streamName
 			streamName:     streamName,
 			ctx:            ctx,
 			cancel:         cancel,
+			kClient:        kCli,
 			podID:          k8s.PodID(podNN.Name),
 			cName:          container.Name(co.Name),
 			namespace:      k8s.Namespace(podNN.Namespace),
@@ -270,6 +271,7 @@ func (c *Controller) addOrUpdateContainerWatches(ctx context.Context, streamName
 			debounce:       debounce,
 			doneCh:         make(chan struct{}),
 			shouldPrefix:   shouldPrefix,
+			format:         stream.Spec.Format,
 		}
 		c.watches[key] = w
 
@@ -351,7 +353,7 @@ func (c *Controller) consumeLogs(watch *podLogWatch, st store.RStore) {
 	for retry {
 		retry = false
 		ctx, cancel := context.WithCancel(ctx)
-		readCloser, err := c.kClient.ContainerLogs(ctx, pID, containerName, ns, startReadTime)
+		readCloser, err := watch.kClient.ContainerLogs(ctx, pID, containerName, ns, startReadTime)
 		if err != nil {
 			if ctx.Err() == nil {
 				exitError = err
@@ -406,7 +408,13 @@ func (c *Controller) consumeLogs(watch *podLogWatch, st store.RStore) {
 		c.mu.Unlock()
 		c.podSource.requeueStream(watch.streamName)
 
-		_, err = io.Copy(logger.Get(ctx).Writer(logger.InfoLvl), reader)
+		if watch.format != "" {
+			w := runtimelog.NewStructuredWriter(logger.Get(ctx), watch.format)
+			_, err = io.Copy(w, reader)
+			w.Flush()
+		} else {
+			_, err = io.Copy(logger.Get(ctx).Writer(logger.InfoLvl), reader)
+		}
 		_ = readCloser.Close()
 		close(done)
 
@@ -533,8 +541,9 @@ func (c *Controller) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 }
 
 type podLogWatch struct {
-	ctx    context.Context
-	cancel func()
+	ctx     context.Context
+	cancel  func()
+	kClient k8s.Client
 
 	streamName     types.NamespacedName
 	podID          k8s.PodID
@@ -546,6 +555,8 @@ type podLogWatch struct {
 	doneCh         chan struct{}
 
 	shouldPrefix bool // if true, we'll prefix logs with the container name
+
+	format v1alpha1.PodLogFormat // if set, parse each log line into fields
 }
 
 type podLogKey struct {
@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -63,6 +64,38 @@ func TestLogs(t *testing.T) {
 	}, f.plsc.podSource.indexer.EnqueueKey(indexer.Key{Name: podNN, GVK: podGVK}))
 }
 
+func TestLogsFromNamedCluster(t *testing.T) {
+	f := newPLMFixture(t)
+
+	stagingCli := k8s.NewFakeK8sClient(t)
+	f.clients.SetK8sClient(types.NamespacedName{Name: "staging"}, stagingCli)
+	stagingCli.SetLogsForPodContainer(podID, cName, "hello from staging!")
+	f.kClient.SetLogsForPodContainer(podID, cName, "hello from default!")
+
+	pb := newPodBuilder(podID).addRunningContainer(cName, cID)
+	stagingCli.UpsertPod(pb.toPod())
+
+	pls := plsFromPod("server", pb, time.Time{})
+	pls.Spec.Cluster = "staging"
+	f.Create(pls)
+
+	f.triggerPodEvent(podID)
+	f.AssertOutputContains("hello from staging!")
+	f.AssertOutputDoesNotContain("hello from default!")
+}
+
+func TestLogsFromUnknownCluster(t *testing.T) {
+	f := newPLMFixture(t)
+
+	pb := newPodBuilder(podID).addRunningContainer(cName, cID)
+	pls := plsFromPod("server", pb, time.Time{})
+	pls.Spec.Cluster = "staging"
+	f.Create(pls)
+
+	f.MustGet(types.NamespacedName{Name: pls.Name}, pls)
+	assert.Equal(t, "cluster staging: cluster client does not exist", pls.Status.Error)
+}
+
 func TestLogCleanup(t *testing.T) {
 	f := newPLMFixture(t)
 
@@ -100,6 +133,24 @@ func TestLogActions(t *testing.T) {
 	f.ConsumeLogActionsUntil("hello world!")
 }
 
+func TestStructuredLogs(t *testing.T) {
+	f := newPLMFixture(t)
+
+	f.kClient.SetLogsForPodContainer(podID, cName,
+		`{"level":"error","msg":"connection refused","trace_id":"abc"}`+"\nplain text\n")
+
+	pb := newPodBuilder(podID).addRunningContainer(cName, cID)
+	f.kClient.UpsertPod(pb.toPod())
+
+	pls := plsFromPod("server", pb, time.Time{})
+	pls.Spec.Format = v1alpha1.PodLogFormatJSON
+	f.Create(pls)
+
+	f.triggerPodEvent(podID)
+	f.AssertOutputContains("connection refused\nplain text\n")
+	f.AssertOutputDoesNotContain("trace_id")
+}
+
 func TestLogsFailed(t *testing.T) {
 	f := newPLMFixture(t)
 
@@ -516,6 +567,7 @@ type plmFixture struct {
 	t       testing.TB
 	ctx     context.Context
 	kClient *k8s.FakeK8sClient
+	clients *cluster.FakeClientProvider
 	plsc    *Controller
 	out     *bufsync.ThreadSafeBuffer
 	store   *plmStore
@@ -534,14 +586,16 @@ func newPLMFixture(t testing.TB) *plmFixture {
 
 	clock := clockwork.NewFakeClock()
 	st := newPLMStore(t, out)
-	podSource := NewPodSource(ctx, kClient, cfb.Client.Scheme(), clock)
-	plsc := NewController(ctx, cfb.Client, cfb.Scheme(), st, kClient, podSource, clock)
+	clients := cluster.NewFakeClientProvider(t, cfb.Client)
+	podSource := NewPodSource(ctx, kClient, clients, cfb.Client.Scheme(), clock)
+	plsc := NewController(ctx, cfb.Client, cfb.Scheme(), st, podSource, clock)
 	indexer.StartSourceForTesting(cfb.Context(), plsc.podSource, plsc, nil)
 
 	return &plmFixture{
 		t:                 t,
 		ControllerFixture: cfb.Build(plsc),
 		kClient:           kClient,
+		clients:           clients,
 		plsc:              plsc,
 		ctx:               ctx,
 		out:               out,
@@ -18,6 +18,7 @@ import (
 
 	"github.com/jonboulle/clockwork"
 
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -32,17 +33,25 @@ type PodSource struct {
 	ctx     context.Context
 	indexer *indexer.Indexer
 	kClient k8s.Client
+	clients cluster.ClientProvider
 	handler handler.EventHandler
 	q       workqueue.RateLimitingInterface
 	clock   clockwork.Clock
 
-	watchesByNamespace map[string]*podWatch
+	watchesByNamespace map[podWatchKey]*podWatch
 	mu                 sync.Mutex
 }
 
+// Pods are watched per namespace, in each cluster.
+type podWatchKey struct {
+	cluster   string
+	namespace string
+}
+
 type podWatch struct {
 	ctx       context.Context
 	cancel    func()
+	kClient   k8s.Client
 	namespace string
 
 	// Only populated if ctx.Err() != nil (the context has been cancelled)
@@ -52,12 +61,13 @@ type podWatch struct {
 
 var _ source.Source = &PodSource{}
 
-func NewPodSource(ctx context.Context, kClient k8s.Client, scheme *runtime.Scheme, clock clockwork.Clock) *PodSource {
+func NewPodSource(ctx context.Context, kClient k8s.Client, clients cluster.ClientProvider, scheme *runtime.Scheme, clock clockwork.Clock) *PodSource {
 	return &PodSource{
 		ctx:                ctx,
 		indexer:            indexer.NewIndexer(scheme, indexPodLogStreamForKubernetes),
 		kClient:            kClient,
-		watchesByNamespace: make(map[string]*podWatch),
+		clients:            clients,
+		watchesByNamespace: make(map[podWatchKey]*podWatch),
 		clock:              clock,
 	}
 }
@@ -80,6 +90,21 @@ func (s *PodSource) TearDown() {
 	}
 }
 
+// The client for the cluster that the pod is running in.
+//
+// The default cluster uses the client that Tilt started with. Clusters declared
+// with k8s_cluster() use the client that the Cluster reconciler connected.
+func (s *PodSource) kClientForCluster(name string) (k8s.Client, error) {
+	if name == "" || name == v1alpha1.ClusterNameDefault {
+		return s.kClient, nil
+	}
+	kCli, _, err := s.clients.GetK8sClient(types.NamespacedName{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %v", name, err)
+	}
+	return kCli, nil
+}
+
 // Register the pods for this stream.
 //
 // Set up any watches we need.
@@ -92,11 +117,17 @@ func (s *PodSource) handleReconcileRequest(ctx context.Context, name types.Names
 	var err error
 	ns := pls.Spec.Namespace
 	if ns != "" {
-		pw, ok := s.watchesByNamespace[ns]
+		key := podWatchKey{cluster: pls.Spec.Cluster, namespace: ns}
+		pw, ok := s.watchesByNamespace[key]
 		if !ok {
+			kCli, err := s.kClientForCluster(pls.Spec.Cluster)
+			if err != nil {
+				return err
+			}
+
 			ctx, cancel := context.WithCancel(ctx)
-			pw = &podWatch{ctx: ctx, cancel: cancel, namespace: ns}
-			s.watchesByNamespace[ns] = pw
+			pw = &podWatch{ctx: ctx, cancel: cancel, kClient: kCli, namespace: ns}
+			s.watchesByNamespace[key] = pw
 			go s.doWatch(pw)
 		}
 
@@ -127,7 +158,7 @@ func (s *PodSource) doWatch(pw *podWatch) {
 	pw.finishedAt = time.Time{}
 	pw.error = nil
 
-	podCh, err := s.kClient.WatchPods(s.ctx, k8s.Namespace(pw.namespace))
+	podCh, err := pw.kClient.WatchPods(s.ctx, k8s.Namespace(pw.namespace))
 	if err != nil {
 		pw.error = fmt.Errorf("watching pods: %v", err)
 		return
@@ -38,6 +38,7 @@ type ConfigsReloadedAction struct {
 	AnalyticsTiltfileOpt analytics.Opt
 	VersionSettings      model.VersionSettings
 	UpdateSettings       model.UpdateSettings
+	CISettings           model.CISettings
 	WatchSettings        model.WatchSettings
 
 	// A checkpoint into the logstore when Tiltfile execution started.
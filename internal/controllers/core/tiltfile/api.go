@@ -611,6 +611,26 @@ func toClusterObjects(nn types.NamespacedName, tlr *tiltfile.TiltfileLoadResult,
 					Kubernetes: defaultK8sConnection.DeepCopy(),
 				},
 				DefaultRegistry: tlr.DefaultRegistry,
+				Buildkit:        buildkitConnection(tlr, name),
+			},
+		}
+	}
+
+	// Clusters declared with k8s_cluster(). Each one gets its own client
+	// and its own local registry detection.
+	for name, conn := range tlr.K8sClusters {
+		conn := conn
+		result[name] = &v1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: v1alpha1.ClusterSpec{
+				Connection: &v1alpha1.ClusterConnection{
+					Kubernetes: &conn,
+				},
+				DefaultRegistry: tlr.DefaultRegistry,
+				Buildkit:        buildkitConnection(tlr, name),
 			},
 		}
 	}
@@ -633,6 +653,16 @@ func toClusterObjects(nn types.NamespacedName, tlr *tiltfile.TiltfileLoadResult,
 	return result
 }
 
+// The Buildkit daemon declared with buildkit_builder() for the cluster,
+// or nil if the cluster builds with Docker.
+func buildkitConnection(tlr *tiltfile.TiltfileLoadResult, cluster string) *v1alpha1.BuildkitClusterConnection {
+	conn, ok := tlr.BuildkitBuilders[cluster]
+	if !ok {
+		return nil
+	}
+	return &conn
+}
+
 // Pulls out all the Cmd objects generated by the Tiltfile.
 func toCmdObjects(tlr *tiltfile.TiltfileLoadResult, disableSources disableSourceMap) apiset.TypedObjectSet {
 	result := apiset.TypedObjectSet{}
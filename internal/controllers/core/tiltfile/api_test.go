@@ -279,6 +279,60 @@ func TestCreateClusterDefaultRegistry(t *testing.T) {
 	require.Equal(t, "fake-repo", cluster.Spec.DefaultRegistry.SingleName, "Default registry single name")
 }
 
+func TestCreateNamedK8sClusters(t *testing.T) {
+	f := newAPIFixture(t)
+	fe := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	tf := &v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{Name: model.MainTiltfileManifestName.String()},
+	}
+	nn := apis.Key(tf)
+	tlr := &tiltfile.TiltfileLoadResult{
+		Manifests: []model.Manifest{fe},
+		K8sClusters: map[string]v1alpha1.KubernetesClusterConnection{
+			"staging": {Context: "gke-staging", Namespace: "team-a"},
+		},
+	}
+	err := f.updateOwnedObjects(nn, tf, tlr)
+	assert.NoError(t, err)
+
+	var cluster v1alpha1.Cluster
+	require.NoError(t, f.Get(types.NamespacedName{Name: "default"}, &cluster))
+	require.NoError(t, f.Get(types.NamespacedName{Name: "staging"}, &cluster))
+	require.Equal(t, &v1alpha1.KubernetesClusterConnection{Context: "gke-staging", Namespace: "team-a"},
+		cluster.Spec.Connection.Kubernetes)
+}
+
+func TestCreateClustersWithBuildkit(t *testing.T) {
+	f := newAPIFixture(t)
+	fe := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	tf := &v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{Name: model.MainTiltfileManifestName.String()},
+	}
+	nn := apis.Key(tf)
+	tlr := &tiltfile.TiltfileLoadResult{
+		Manifests: []model.Manifest{fe},
+		K8sClusters: map[string]v1alpha1.KubernetesClusterConnection{
+			"staging": {Context: "gke-staging"},
+		},
+		BuildkitBuilders: map[string]v1alpha1.BuildkitClusterConnection{
+			"staging": {Host: "tcp://buildkitd:1234", LoadToContainerd: true},
+		},
+	}
+	err := f.updateOwnedObjects(nn, tf, tlr)
+	assert.NoError(t, err)
+
+	var cluster v1alpha1.Cluster
+	require.NoError(t, f.Get(types.NamespacedName{Name: "default"}, &cluster))
+	require.Nil(t, cluster.Spec.Buildkit)
+	require.NoError(t, f.Get(types.NamespacedName{Name: "staging"}, &cluster))
+	require.Equal(t, &v1alpha1.BuildkitClusterConnection{Host: "tcp://buildkitd:1234", LoadToContainerd: true},
+		cluster.Spec.Buildkit)
+}
+
 // Ensure that we emit disable-related objects/field appropriately
 func TestDisableObjects(t *testing.T) {
 	f := newAPIFixture(t)
@@ -33,7 +33,7 @@ var _ WatchableTarget = model.ImageTarget{}
 var _ WatchableTarget = model.LocalTarget{}
 var _ WatchableTarget = model.K8sTarget{}
 
-func specForTarget(t WatchableTarget, globalIgnores []model.Dockerignore) *v1alpha1.FileWatchSpec {
+func specForTarget(t WatchableTarget, globalIgnores []model.Dockerignore, contentHash bool) *v1alpha1.FileWatchSpec {
 	watchedPaths := append([]string(nil), t.Dependencies()...)
 	if len(watchedPaths) == 0 {
 		return nil
@@ -42,6 +42,7 @@ func specForTarget(t WatchableTarget, globalIgnores []model.Dockerignore) *v1alp
 	spec := &v1alpha1.FileWatchSpec{
 		WatchedPaths: watchedPaths,
 		Ignores:      t.GetFileWatchIgnores(),
+		ContentHash:  contentHash,
 	}
 
 	// process global ignores last
@@ -84,7 +85,7 @@ func ToFileWatchObjects(watchInputs WatchInputs, disableSources map[model.Manife
 				continue
 			}
 
-			spec := specForTarget(t, globalIgnores)
+			spec := specForTarget(t, globalIgnores, watchInputs.WatchSettings.ContentHash)
 			if spec != nil {
 				fw := &v1alpha1.FileWatch{
 					ObjectMeta: metav1.ObjectMeta{
@@ -123,6 +124,7 @@ func ToFileWatchObjects(watchInputs WatchInputs, disableSources map[model.Manife
 			},
 			Spec: v1alpha1.FileWatchSpec{
 				WatchedPaths: paths,
+				ContentHash:  watchInputs.WatchSettings.ContentHash,
 			},
 		}
 
@@ -132,6 +132,23 @@ func TestFileWatch_IgnoreWatchSettings(t *testing.T) {
 	})
 }
 
+func TestFileWatch_ContentHashWatchSettings(t *testing.T) {
+	f := newFWFixture(t)
+
+	target := model.LocalTarget{
+		Name: "foo",
+		Deps: []string{"."},
+	}
+	f.SetManifestLocalTarget(target)
+
+	f.inputs.WatchSettings.ContentHash = true
+
+	f.RequireFileWatchSpecEqual(target.ID(), v1alpha1.FileWatchSpec{
+		WatchedPaths: []string{"."},
+		ContentHash:  true,
+	})
+}
+
 func TestFileWatch_PickUpTiltIgnoreChanges(t *testing.T) {
 	f := newFWFixture(t)
 
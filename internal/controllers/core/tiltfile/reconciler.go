@@ -385,6 +385,7 @@ func (r *Reconciler) handleLoaded(
 		CheckpointAtExecStart: entry.CheckpointAtExecStart,
 		VersionSettings:       tlr.VersionSettings,
 		UpdateSettings:        tlr.UpdateSettings,
+		CISettings:            tlr.CISettings,
 		WatchSettings:         tlr.WatchSettings,
 	})
 
@@ -193,6 +193,7 @@ func HandleConfigsReloaded(
 		state.VersionSettings = event.VersionSettings
 		state.AnalyticsTiltfileOpt = event.AnalyticsTiltfileOpt
 		state.UpdateSettings = event.UpdateSettings
+		state.CISettings = event.CISettings
 		state.DockerPruneSettings = event.DockerPruneSettings
 	}
 }
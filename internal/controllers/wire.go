@@ -29,6 +29,7 @@ import (
 
 var controllerSet = wire.NewSet(
 	filewatch.NewController,
+	filewatch.NewSelfWrites,
 	kubernetesdiscovery.NewReconciler,
 	portforward.NewReconciler,
 	podlogstream.NewController,
@@ -0,0 +1,209 @@
+/**
+Code for parsing Buildkit cache arguments adapted from Docker Buildx
+
+Adapted from
+https://github.com/docker/buildx/blob/v0.5.1/build/cache.go
+
+
+   Copyright 2013-2017 Docker, Inc.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package buildkit
+
+import (
+	"encoding/csv"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	contentlocal "github.com/containerd/containerd/content/local"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/session"
+	sessioncontent "github.com/moby/buildkit/session/content"
+	"github.com/pkg/errors"
+)
+
+const (
+	CacheTypeRegistry = "registry"
+	CacheTypeLocal    = "local"
+	CacheTypeInline   = "inline"
+)
+
+// Parses cache sources in the same format as `docker buildx build --cache-from`,
+// e.g., "type=local,src=path/to/dir".
+//
+// A plain image reference is a registry cache, for compatibility
+// with `docker build --cache-from`.
+func ParseCacheImports(sl []string) ([]client.CacheOptionsEntry, error) {
+	result := make([]client.CacheOptionsEntry, 0, len(sl))
+	for _, v := range sl {
+		e, err := parseCacheEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Type {
+		case CacheTypeRegistry:
+			if e.Attrs["ref"] == "" {
+				return nil, errors.Errorf("registry cache source %q requires ref", v)
+			}
+		case CacheTypeLocal:
+			if e.Attrs["src"] == "" {
+				return nil, errors.Errorf("local cache source %q requires src", v)
+			}
+		default:
+			return nil, errors.Errorf("unsupported cache source type %q", e.Type)
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// Parses cache destinations in the same format as `docker buildx build --cache-to`,
+// e.g., "type=registry,ref=gcr.io/foo/cache,mode=max".
+func ParseCacheExports(sl []string) ([]client.CacheOptionsEntry, error) {
+	result := make([]client.CacheOptionsEntry, 0, len(sl))
+	for _, v := range sl {
+		e, err := parseCacheEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Type {
+		case CacheTypeRegistry:
+			if e.Attrs["ref"] == "" {
+				return nil, errors.Errorf("registry cache destination %q requires ref", v)
+			}
+		case CacheTypeLocal:
+			if e.Attrs["dest"] == "" {
+				return nil, errors.Errorf("local cache destination %q requires dest", v)
+			}
+		case CacheTypeInline:
+		default:
+			return nil, errors.Errorf("unsupported cache destination type %q", e.Type)
+		}
+		if mode := e.Attrs["mode"]; mode != "" && mode != "min" && mode != "max" {
+			return nil, errors.Errorf("invalid cache mode %q in %q (must be min or max)", mode, v)
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// Returns true if all the cache sources are registry caches,
+// which the Docker /build endpoint supports natively.
+func AllRegistryCaches(entries []client.CacheOptionsEntry) bool {
+	for _, e := range entries {
+		if e.Type != CacheTypeRegistry {
+			return false
+		}
+	}
+	return true
+}
+
+// Serves local cache directories to Buildkit over the session.
+//
+// Returns nil if there are no local caches.
+func CacheContentStores(imports, exports []client.CacheOptionsEntry) (session.Attachable, error) {
+	stores := make(map[string]content.Store)
+	for _, e := range imports {
+		if e.Type != CacheTypeLocal {
+			continue
+		}
+		dir := e.Attrs["src"]
+		if _, err := os.Stat(dir); err != nil {
+			// The cache doesn't exist yet, e.g., on the first CI run.
+			continue
+		}
+		cs, err := contentlocal.NewStore(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening cache %s", dir)
+		}
+		stores["local:"+dir] = cs
+	}
+
+	for _, e := range exports {
+		if e.Type != CacheTypeLocal {
+			continue
+		}
+		dir := e.Attrs["dest"]
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating cache %s", dir)
+		}
+		cs, err := contentlocal.NewStore(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening cache %s", dir)
+		}
+		stores["local:"+dir] = cs
+	}
+
+	if len(stores) == 0 {
+		return nil, nil
+	}
+	return sessioncontent.NewAttachable(stores), nil
+}
+
+func parseCacheEntry(value string) (client.CacheOptionsEntry, error) {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return client.CacheOptionsEntry{}, errors.Wrapf(err, "failed to parse csv cache %q", value)
+	}
+
+	if len(fields) == 1 && !strings.Contains(fields[0], "=") {
+		return client.CacheOptionsEntry{
+			Type:  CacheTypeRegistry,
+			Attrs: map[string]string{"ref": fields[0]},
+		}, nil
+	}
+
+	e := client.CacheOptionsEntry{Attrs: map[string]string{}}
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return client.CacheOptionsEntry{}, errors.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+		key := strings.ToLower(parts[0])
+		if key == "type" {
+			e.Type = parts[1]
+		} else {
+			e.Attrs[key] = parts[1]
+		}
+	}
+	if e.Type == "" {
+		return client.CacheOptionsEntry{}, errors.Errorf("type required for cache %q", value)
+	}
+	return e, nil
+}
+
+// Formats a cache entry in the same format that the Parse functions accept.
+func FormatCacheEntry(e client.CacheOptionsEntry) string {
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := []string{"type=" + e.Type}
+	for _, k := range keys {
+		fields = append(fields, k+"="+e.Attrs[k])
+	}
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	_ = w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,96 @@
+package buildkit
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestParseCacheImports(t *testing.T) {
+	entries, err := ParseCacheImports([]string{
+		"gcr.io/fe:cache",
+		"type=registry,ref=gcr.io/fe:buildcache",
+		"type=local,src=/tmp/cache",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []client.CacheOptionsEntry{
+		{Type: "registry", Attrs: map[string]string{"ref": "gcr.io/fe:cache"}},
+		{Type: "registry", Attrs: map[string]string{"ref": "gcr.io/fe:buildcache"}},
+		{Type: "local", Attrs: map[string]string{"src": "/tmp/cache"}},
+	}, entries)
+	assert.False(t, AllRegistryCaches(entries))
+	assert.True(t, AllRegistryCaches(entries[:2]))
+}
+
+func TestParseCacheImportsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		expected string
+	}{
+		{"type=local", "requires src"},
+		{"type=registry", "requires ref"},
+		{"type=inline", "unsupported cache source type"},
+		{"src=/tmp/cache", "type required"},
+		{"type=local,src", "must be a key=value pair"},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			_, err := ParseCacheImports([]string{tc.spec})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestParseCacheExports(t *testing.T) {
+	entries, err := ParseCacheExports([]string{
+		"type=registry,ref=gcr.io/fe:buildcache,mode=max",
+		"type=local,dest=/tmp/cache",
+		"type=inline",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []client.CacheOptionsEntry{
+		{Type: "registry", Attrs: map[string]string{"ref": "gcr.io/fe:buildcache", "mode": "max"}},
+		{Type: "local", Attrs: map[string]string{"dest": "/tmp/cache"}},
+		{Type: "inline", Attrs: map[string]string{}},
+	}, entries)
+
+	_, err = ParseCacheExports([]string{"type=local,dest=/tmp/cache,mode=most"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cache mode")
+}
+
+func TestCacheContentStores(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+
+	cs, err := CacheContentStores(
+		[]client.CacheOptionsEntry{{Type: "local", Attrs: map[string]string{"src": tf.JoinPath("missing")}}},
+		nil)
+	require.NoError(t, err)
+	assert.Nil(t, cs)
+
+	cs, err = CacheContentStores(nil,
+		[]client.CacheOptionsEntry{{Type: "local", Attrs: map[string]string{"dest": tf.JoinPath("cache")}}})
+	require.NoError(t, err)
+	assert.NotNil(t, cs)
+	assert.DirExists(t, tf.JoinPath("cache"))
+}
+
+func TestFormatCacheEntry(t *testing.T) {
+	for _, spec := range []string{
+		"type=local,dest=/tmp/cache,mode=max",
+		`type=local,"src=/tmp/my,cache"`,
+		"type=inline",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			e, err := parseCacheEntry(spec)
+			require.NoError(t, err)
+			roundTrip, err := parseCacheEntry(FormatCacheEntry(e))
+			require.NoError(t, err)
+			assert.Equal(t, e, roundTrip)
+		})
+	}
+}
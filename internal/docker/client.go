@@ -96,6 +96,9 @@ type Client interface {
 	// Returns an ExitError if the command exits with a non-zero exit code.
 	ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error
 
+	// Copy a file or directory out of a container, as a tar archive.
+	ContainerArchive(ctx context.Context, cID container.ID, srcPath string) (io.ReadCloser, error)
+
 	ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error)
 	ImagePush(ctx context.Context, image reference.NamedTagged) (io.ReadCloser, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error)
@@ -104,6 +107,9 @@ type Client interface {
 	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
 	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
 
+	// Export images as a tarball, in the same format as `docker save`.
+	ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error)
+
 	NewVersionError(APIrequired, feature string) error
 	BuildCachePrune(ctx context.Context, opts types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error)
 	ContainersPrune(ctx context.Context, pruneFilters filters.Args) (types.ContainersPruneReport, error)
@@ -289,33 +295,18 @@ func CreateClientOpts(envMap map[string]string) ([]client.Opt, error) {
 	return result, nil
 }
 
-func (c *Cli) startBuildkitSession(ctx context.Context, key string, syncedDirs []filesync.SyncedDir, sshSpecs []string, secretSpecs []string) (*session.Session, error) {
+func (c *Cli) startBuildkitSession(ctx context.Context, key string, options BuildOptions) (*session.Session, error) {
 	session, err := session.NewSession(ctx, "tilt", key)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(syncedDirs) > 0 {
-		session.Allow(filesync.NewFSSyncProvider(syncedDirs))
-	}
-
-	provider := authprovider.NewDockerAuthProvider(logger.Get(ctx).Writer(logger.InfoLvl))
-	session.Allow(provider)
-
-	if len(secretSpecs) > 0 {
-		ss, err := buildkit.ParseSecretSpecs(secretSpecs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "could not parse secret: %v", secretSpecs)
-		}
-		session.Allow(ss)
+	attachables, err := BuildkitAttachables(ctx, options)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(sshSpecs) > 0 {
-		sshp, err := buildkit.ParseSSHSpecs(sshSpecs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "could not parse ssh: %v", sshSpecs)
-		}
-		session.Allow(sshp)
+	for _, a := range attachables {
+		session.Allow(a)
 	}
 
 	go func() {
@@ -332,6 +323,51 @@ func (c *Cli) startBuildkitSession(ctx context.Context, key string, syncedDirs [
 	return session, nil
 }
 
+// The services that the client side of a Buildkit session provides to the
+// build: the build context, registry credentials, secrets, ssh agents, and
+// local cache directories.
+func BuildkitAttachables(ctx context.Context, options BuildOptions) ([]session.Attachable, error) {
+	var result []session.Attachable
+	if len(options.SyncedDirs) > 0 {
+		result = append(result, filesync.NewFSSyncProvider(options.SyncedDirs))
+	}
+
+	result = append(result, authprovider.NewDockerAuthProvider(logger.Get(ctx).Writer(logger.InfoLvl)))
+
+	if len(options.SecretSpecs) > 0 {
+		ss, err := buildkit.ParseSecretSpecs(options.SecretSpecs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse secret: %v", options.SecretSpecs)
+		}
+		result = append(result, ss)
+	}
+
+	if len(options.SSHSpecs) > 0 {
+		sshp, err := buildkit.ParseSSHSpecs(options.SSHSpecs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse ssh: %v", options.SSHSpecs)
+		}
+		result = append(result, sshp)
+	}
+
+	cacheImports, err := buildkit.ParseCacheImports(options.CacheFrom)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse cache_from: %v", options.CacheFrom)
+	}
+	cacheExports, err := buildkit.ParseCacheExports(options.CacheTo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse cache_to: %v", options.CacheTo)
+	}
+	cs, err := buildkit.CacheContentStores(cacheImports, cacheExports)
+	if err != nil {
+		return nil, err
+	}
+	if cs != nil {
+		result = append(result, cs)
+	}
+	return result, nil
+}
+
 // When we pull from a private docker registry, we have to get credentials
 // from somewhere. These credentials are not stored on the server. The client
 // is responsible for managing them.
@@ -504,9 +540,30 @@ func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options Bu
 	}
 
 	isUsingBuildkit := builderVersion == types.BuilderBuildKit
+	cacheImports, err := buildkit.ParseCacheImports(options.CacheFrom)
+	if err != nil {
+		return types.ImageBuildResponse{}, errors.Wrapf(err, "ImageBuild")
+	}
+
+	// The /build endpoint only supports a single platform and registry cache
+	// sources, so anything else needs to talk to Buildkit directly.
+	if len(options.platforms()) > 1 ||
+		len(options.CacheTo) > 0 ||
+		!buildkit.AllRegistryCaches(cacheImports) {
+		if !isUsingBuildkit {
+			return types.ImageBuildResponse{},
+				fmt.Errorf("Multi-platform builds and cache_to only work on Buildkit, but Buildkit has been disabled")
+		}
+		if len(options.SyncedDirs) == 0 {
+			return types.ImageBuildResponse{},
+				fmt.Errorf("Multi-platform builds and cache_to need to sync the build context to Buildkit, " +
+					"but this build sends it as a tarball")
+		}
+		return c.solve(ctx, options)
+	}
+
 	if isUsingBuildkit {
-		var err error
-		oneTimeSession, err = c.startBuildkitSession(ctx, identity.NewID(), options.SyncedDirs, options.SSHSpecs, options.SecretSpecs)
+		oneTimeSession, err = c.startBuildkitSession(ctx, identity.NewID(), options)
 		if err != nil {
 			return types.ImageBuildResponse{}, errors.Wrapf(err, "ImageBuild")
 		}
@@ -533,9 +590,13 @@ func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options Bu
 	opts.Tags = append([]string{}, options.ExtraTags...)
 	opts.Target = options.Target
 	opts.NetworkMode = options.Network
-	opts.CacheFrom = options.CacheFrom
+	for _, e := range cacheImports {
+		opts.CacheFrom = append(opts.CacheFrom, e.Attrs["ref"])
+	}
 	opts.PullParent = options.PullParent
-	opts.Platform = options.Platform
+	if platforms := options.platforms(); len(platforms) == 1 {
+		opts.Platform = platforms[0]
+	}
 
 	if len(options.SyncedDirs) > 0 {
 		opts.RemoteContext = clientSessionRemote
@@ -565,6 +626,14 @@ func (c *Cli) ContainerRestartNoWait(ctx context.Context, containerID string) er
 	return c.ContainerRestart(ctx, containerID, &dur)
 }
 
+func (c *Cli) ContainerArchive(ctx context.Context, cID container.ID, srcPath string) (io.ReadCloser, error) {
+	reader, _, err := c.CopyFromContainer(ctx, cID.String(), srcPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "ContainerArchive")
+	}
+	return reader, nil
+}
+
 func (c *Cli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	attachStdin := in != nil
 	cfg := types.ExecConfig{
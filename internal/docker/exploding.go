@@ -56,6 +56,9 @@ func (c explodingClient) Run(ctx context.Context, opts RunConfig) (RunResult, er
 func (c explodingClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	return c.err
 }
+func (c explodingClient) ContainerArchive(ctx context.Context, cID container.ID, srcPath string) (io.ReadCloser, error) {
+	return nil, c.err
+}
 func (c explodingClient) ImagePull(_ context.Context, _ reference.Named) (reference.Canonical, error) {
 	return nil, c.err
 }
@@ -77,6 +80,9 @@ func (c explodingClient) ImageList(ctx context.Context, options types.ImageListO
 func (c explodingClient) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
 	return nil, c.err
 }
+func (c explodingClient) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	return nil, c.err
+}
 func (c explodingClient) NewVersionError(apiRequired, feature string) error {
 	return c.err
 }
@@ -7,6 +7,8 @@ import (
 	"io"
 	"io/ioutil"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/go-units"
@@ -80,6 +82,9 @@ var DefaultContainerListOutput = map[string][]types.Container{
 type ExecCall struct {
 	Container string
 	Cmd       model.Cmd
+
+	// The contents of stdin, if any.
+	Stdin []byte
 }
 
 type FakeClient struct {
@@ -99,6 +104,9 @@ type FakeClient struct {
 	ImageListCount int
 	ImageListOpts  []types.ImageListOptions
 
+	// Images exported with ImageSave, in order.
+	SavedImages []string
+
 	TagCount  int
 	TagSource string
 	TagTarget string
@@ -109,9 +117,17 @@ type FakeClient struct {
 	CopyContainer string
 	CopyContent   io.Reader
 
+	// Tar archives returned by ContainerArchive, keyed by source path.
+	ContainerArchives map[string][]byte
+
+	// Exec may be called from several goroutines (e.g., when loading an image into each node).
+	execMu            sync.Mutex
 	ExecCalls         []ExecCall
 	ExecErrorsToThrow []error // next call to exec will throw ExecError[0] (which we then pop)
 
+	// Output written by exec calls, keyed by the command's argv joined with spaces.
+	ExecOutputs map[string]string
+
 	RestartsByContainer map[string]int
 	RemovedImageIDs     []string
 
@@ -205,8 +221,11 @@ func (c *FakeClient) SetDefaultContainerListOutput() {
 
 func (c *FakeClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
 	nameFilter := options.Filters.Get("name")
+	if len(nameFilter) == 0 {
+		nameFilter = options.Filters.Get("label")
+	}
 	if len(nameFilter) != 1 {
-		return nil, fmt.Errorf("expected one filter for 'name', got: %v", nameFilter)
+		return nil, fmt.Errorf("expected one filter for 'name' or 'label', got: %v", nameFilter)
 	}
 
 	if len(c.ContainerListOutput) == 0 {
@@ -241,8 +260,22 @@ func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd
 		Container: cID.String(),
 		Cmd:       cmd,
 	}
+	if in != nil {
+		stdin, err := ioutil.ReadAll(in)
+		if err != nil {
+			return err
+		}
+		execCall.Stdin = stdin
+	}
+
+	c.execMu.Lock()
+	defer c.execMu.Unlock()
 	c.ExecCalls = append(c.ExecCalls, execCall)
 
+	if output, ok := c.ExecOutputs[strings.Join(cmd.Argv, " ")]; ok && out != nil {
+		_, _ = out.Write([]byte(output))
+	}
+
 	// If we're supposed to throw an error on this call, throw it (and pop from
 	// the list of ErrorsToThrow)
 	var err error
@@ -254,6 +287,14 @@ func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd
 	return err
 }
 
+func (c *FakeClient) ContainerArchive(ctx context.Context, cID container.ID, srcPath string) (io.ReadCloser, error) {
+	archive, ok := c.ContainerArchives[srcPath]
+	if !ok {
+		return nil, fmt.Errorf("Could not find the file %s in container %s", srcPath, cID)
+	}
+	return io.NopCloser(bytes.NewReader(archive)), nil
+}
+
 func (c *FakeClient) ImagePull(_ context.Context, ref reference.Named) (reference.Canonical, error) {
 	// fake digest is the reference itself hashed
 	// i.e. docker.io/library/_/nginx -> sha256sum(docker.io/library/_/nginx) -> 2ca21a92e8ee99f672764b7619a413019de5ffc7f06dbc7422d41eca17705802
@@ -327,6 +368,11 @@ func (c *FakeClient) ImageRemove(ctx context.Context, imageID string, options ty
 	}, nil
 }
 
+func (c *FakeClient) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	c.SavedImages = append(c.SavedImages, imageIDs...)
+	return ioutil.NopCloser(strings.NewReader(fmt.Sprintf("fake tarball of %v", imageIDs))), nil
+}
+
 func (c *FakeClient) NewVersionError(apiRequired, feature string) error {
 	if c.ThrowNewVersionError {
 		c.ThrowNewVersionError = false
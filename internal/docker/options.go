@@ -16,9 +16,22 @@ type BuildOptions struct {
 	SecretSpecs        []string
 	Network            string
 	CacheFrom          []string
+	CacheTo            []string
 	PullParent         bool
 	Platform           string
+	Platforms          []string
 	ExtraTags          []string
 	ForceLegacyBuilder bool
 	SyncedDirs         []filesync.SyncedDir
 }
+
+// The platforms to build for, whether the build asked for one or several.
+func (o BuildOptions) platforms() []string {
+	if len(o.Platforms) > 0 {
+		return o.Platforms
+	}
+	if o.Platform != "" {
+		return []string{o.Platform}
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/identity"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/docker/buildkit"
+)
+
+// The Buildkit exporter that writes images into the Docker image store.
+//
+// Only available on the Buildkit instance embedded in the Docker daemon.
+const mobyExporter = "moby"
+
+// Buildkit's response key for the digest of the exported image.
+const exporterImageDigest = "containerimage.digest"
+
+// The command we recommend for installing QEMU emulators, so that
+// a builder can build for other architectures.
+const BinfmtInstallCommand = "docker run --privileged --rm tonistiigi/binfmt --install all"
+
+// Builds an image with features that the Docker /build endpoint doesn't
+// support, like multiple platforms and cache exports.
+//
+// We talk to the Buildkit instance embedded in the Docker daemon directly,
+// the same way the "docker" driver of `docker buildx` does.
+//
+// To keep the build pipeline agnostic of how the image was built, we
+// translate the Buildkit status stream back into the JSON messages that
+// the /build endpoint sends.
+func (c *Cli) solve(ctx context.Context, options BuildOptions) (types.ImageBuildResponse, error) {
+	opt, err := ToSolveOpt(options)
+	if err != nil {
+		return types.ImageBuildResponse{}, errors.Wrap(err, "ImageBuild")
+	}
+
+	bk, err := bkclient.New(ctx, "",
+		bkclient.WithFailFast(),
+		bkclient.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return c.Client.DialHijack(ctx, "/grpc", "h2c", nil)
+		}))
+	if err != nil {
+		return types.ImageBuildResponse{}, errors.Wrap(err, "connecting to Buildkit")
+	}
+
+	if platforms := options.platforms(); len(platforms) > 0 {
+		err = checkBuilderPlatforms(ctx, bk, platforms)
+		if err != nil {
+			_ = bk.Close()
+			return types.ImageBuildResponse{}, err
+		}
+	}
+
+	// The solve closes the session when it's done.
+	session, err := c.startBuildkitSession(ctx, identity.NewID(), options)
+	if err != nil {
+		_ = bk.Close()
+		return types.ImageBuildResponse{}, errors.Wrap(err, "ImageBuild")
+	}
+	opt.SharedSession = session
+	opt.SessionPreInitialized = true
+
+	return types.ImageBuildResponse{Body: StreamSolve(ctx, bk, opt)}, nil
+}
+
+// Runs the solve in the background, and returns its status and result in the
+// same JSON message format as the Docker /build endpoint.
+//
+// Closes the Buildkit client when the solve is done.
+func StreamSolve(ctx context.Context, bk *bkclient.Client, opt bkclient.SolveOpt) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer func() {
+			_ = bk.Close()
+		}()
+		_ = pw.CloseWithError(solveToJSONMessages(ctx, bk, opt, pw))
+	}()
+	return pr
+}
+
+// Runs the solve, writing the status and result to w as Docker JSON messages.
+func solveToJSONMessages(ctx context.Context, bk *bkclient.Client, opt bkclient.SolveOpt, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	statusCh := make(chan *bkclient.SolveStatus)
+	traceDone := make(chan error)
+	go func() {
+		var err error
+		for status := range statusCh {
+			// Keep draining the channel on error, so that the solve doesn't block.
+			if err == nil {
+				err = writeBuildkitTrace(encoder, status)
+			}
+		}
+		traceDone <- err
+	}()
+
+	resp, err := bk.Solve(ctx, nil, opt, statusCh)
+	traceErr := <-traceDone
+	if err != nil {
+		return encoder.Encode(jsonmessage.JSONMessage{ErrorMessage: err.Error()})
+	}
+	if traceErr != nil {
+		return traceErr
+	}
+
+	aux, err := json.Marshal(types.BuildResult{ID: resp.ExporterResponse[exporterImageDigest]})
+	if err != nil {
+		return err
+	}
+	raw := json.RawMessage(aux)
+	return encoder.Encode(jsonmessage.JSONMessage{ID: "moby.image.id", Aux: &raw})
+}
+
+// Encodes the status the same way the Docker daemon does: as a
+// base64-encoded StatusResponse protobuf.
+func writeBuildkitTrace(encoder *json.Encoder, status *bkclient.SolveStatus) error {
+	resp := controlapi.StatusResponse{}
+	for _, v := range status.Vertexes {
+		resp.Vertexes = append(resp.Vertexes, &controlapi.Vertex{
+			Digest:    v.Digest,
+			Inputs:    v.Inputs,
+			Name:      v.Name,
+			Started:   v.Started,
+			Completed: v.Completed,
+			Error:     v.Error,
+			Cached:    v.Cached,
+		})
+	}
+	for _, s := range status.Statuses {
+		resp.Statuses = append(resp.Statuses, &controlapi.VertexStatus{
+			ID:        s.ID,
+			Vertex:    s.Vertex,
+			Name:      s.Name,
+			Total:     s.Total,
+			Current:   s.Current,
+			Timestamp: s.Timestamp,
+			Started:   s.Started,
+			Completed: s.Completed,
+		})
+	}
+	for _, l := range status.Logs {
+		resp.Logs = append(resp.Logs, &controlapi.VertexLog{
+			Vertex:    l.Vertex,
+			Stream:    int64(l.Stream),
+			Msg:       l.Data,
+			Timestamp: l.Timestamp,
+		})
+	}
+
+	dt, err := resp.Marshal()
+	if err != nil {
+		return err
+	}
+	aux, err := json.Marshal(dt)
+	if err != nil {
+		return err
+	}
+	raw := json.RawMessage(aux)
+	return encoder.Encode(jsonmessage.JSONMessage{ID: "moby.buildkit.trace", Aux: &raw})
+}
+
+// Translates our build options into the frontend attributes
+// that `docker buildx build` would send.
+//
+// Exports the image to the Docker image store.
+func ToSolveOpt(options BuildOptions) (bkclient.SolveOpt, error) {
+	attrs := map[string]string{
+		"filename": options.Dockerfile,
+	}
+	if platforms := options.platforms(); len(platforms) > 0 {
+		attrs["platform"] = strings.Join(platforms, ",")
+	}
+	if options.Target != "" {
+		attrs["target"] = options.Target
+	}
+	if options.Network != "" {
+		attrs["force-network-mode"] = options.Network
+	}
+	if options.PullParent {
+		attrs["image-resolve-mode"] = "pull"
+	}
+	for k, v := range options.BuildArgs {
+		if v != nil {
+			attrs["build-arg:"+k] = *v
+		}
+	}
+	for k, v := range BuiltByTiltLabel {
+		attrs["label:"+k] = v
+	}
+
+	cacheImports, err := buildkit.ParseCacheImports(options.CacheFrom)
+	if err != nil {
+		return bkclient.SolveOpt{}, err
+	}
+	cacheExports, err := buildkit.ParseCacheExports(options.CacheTo)
+	if err != nil {
+		return bkclient.SolveOpt{}, err
+	}
+
+	exportAttrs := map[string]string{}
+	if len(options.ExtraTags) > 0 {
+		exportAttrs["name"] = strings.Join(options.ExtraTags, ",")
+	}
+
+	return bkclient.SolveOpt{
+		Frontend:      "dockerfile.v0",
+		FrontendAttrs: attrs,
+		CacheImports:  cacheImports,
+		CacheExports:  cacheExports,
+		Exports: []bkclient.ExportEntry{
+			{Type: mobyExporter, Attrs: exportAttrs},
+		},
+	}, nil
+}
+
+// Checks that the builder can build for all the requested platforms,
+// either natively or with emulation.
+func checkBuilderPlatforms(ctx context.Context, bk *bkclient.Client, requested []string) error {
+	workers, err := bk.ListWorkers(ctx)
+	if err != nil {
+		return errors.Wrap(err, "connecting to Buildkit")
+	}
+
+	var supported []string
+	for _, w := range workers {
+		for _, p := range w.Platforms {
+			supported = append(supported, platforms.Format(platforms.Normalize(p)))
+		}
+	}
+	return missingPlatformsError(requested, supported)
+}
+
+func missingPlatformsError(requested []string, supported []string) error {
+	supportedSet := make(map[string]bool, len(supported))
+	for _, p := range supported {
+		supportedSet[p] = true
+	}
+
+	var missing []string
+	for _, r := range requested {
+		p, err := platforms.Parse(r)
+		if err != nil {
+			return errors.Wrapf(err, "invalid platform %q", r)
+		}
+		if !supportedSet[platforms.Format(platforms.Normalize(p))] {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(supported)
+	return fmt.Errorf("The Docker builder can't build for platform %s (it supports: %s).\n"+
+		"To build for other architectures, install QEMU emulators with:\n  %s",
+		strings.Join(missing, ", "), strings.Join(supported, ", "), BinfmtInstallCommand)
+}
@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOptionsPlatforms(t *testing.T) {
+	assert.Equal(t, []string{"linux/amd64", "linux/arm64"},
+		BuildOptions{Platforms: []string{"linux/amd64", "linux/arm64"}}.platforms())
+	assert.Equal(t, []string{"linux/amd64"}, BuildOptions{Platform: "linux/amd64"}.platforms())
+	assert.Nil(t, BuildOptions{}.platforms())
+}
+
+func TestToSolveOpt(t *testing.T) {
+	arg := "bar"
+	opt, err := ToSolveOpt(BuildOptions{
+		Dockerfile: "Dockerfile",
+		Platforms:  []string{"linux/amd64", "linux/arm64"},
+		Target:     "release",
+		BuildArgs:  map[string]*string{"FOO": &arg, "UNSET": nil},
+		CacheFrom:  []string{"gcr.io/fe:cache", "type=local,src=/tmp/cache"},
+		CacheTo:    []string{"type=local,dest=/tmp/cache,mode=max"},
+		PullParent: true,
+		ExtraTags:  []string{"fe:latest"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "dockerfile.v0", opt.Frontend)
+	assert.Equal(t, map[string]string{
+		"filename":              "Dockerfile",
+		"platform":              "linux/amd64,linux/arm64",
+		"target":                "release",
+		"image-resolve-mode":    "pull",
+		"build-arg:FOO":         "bar",
+		"label:" + BuiltByLabel: BuiltByValue,
+	}, opt.FrontendAttrs)
+	assert.Equal(t, []bkclient.CacheOptionsEntry{
+		{Type: "registry", Attrs: map[string]string{"ref": "gcr.io/fe:cache"}},
+		{Type: "local", Attrs: map[string]string{"src": "/tmp/cache"}},
+	}, opt.CacheImports)
+	assert.Equal(t, []bkclient.CacheOptionsEntry{
+		{Type: "local", Attrs: map[string]string{"dest": "/tmp/cache", "mode": "max"}},
+	}, opt.CacheExports)
+	assert.Equal(t, []bkclient.ExportEntry{
+		{Type: "moby", Attrs: map[string]string{"name": "fe:latest"}},
+	}, opt.Exports)
+}
+
+func TestToSolveOptBadCache(t *testing.T) {
+	_, err := ToSolveOpt(BuildOptions{CacheTo: []string{"type=local"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires dest")
+}
+
+func TestMissingPlatformsError(t *testing.T) {
+	supported := []string{"linux/amd64", "linux/386"}
+	assert.NoError(t, missingPlatformsError([]string{"linux/amd64"}, supported))
+
+	err := missingPlatformsError([]string{"linux/amd64", "linux/arm64"}, supported)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't build for platform linux/arm64 (it supports: linux/386, linux/amd64)")
+	assert.Contains(t, err.Error(), BinfmtInstallCommand)
+
+	// aarch64 is an alias for arm64
+	assert.NoError(t, missingPlatformsError([]string{"linux/aarch64"}, []string{"linux/arm64"}))
+}
+
+func TestWriteBuildkitTrace(t *testing.T) {
+	now := time.Now().UTC()
+	buf := bytes.NewBuffer(nil)
+	err := writeBuildkitTrace(json.NewEncoder(buf), &bkclient.SolveStatus{
+		Vertexes: []*bkclient.Vertex{
+			{Digest: digest.Digest("sha256:abc"), Name: "[1/2] FROM alpine", Started: &now, Cached: true},
+		},
+		Logs: []*bkclient.VertexLog{
+			{Vertex: digest.Digest("sha256:abc"), Data: []byte("hello\n"), Timestamp: now},
+		},
+	})
+	require.NoError(t, err)
+
+	var msg jsonmessage.JSONMessage
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &msg))
+	assert.Equal(t, "moby.buildkit.trace", msg.ID)
+
+	var dt []byte
+	require.NoError(t, json.Unmarshal(*msg.Aux, &dt))
+	var resp controlapi.StatusResponse
+	require.NoError(t, resp.Unmarshal(dt))
+	require.Len(t, resp.Vertexes, 1)
+	assert.Equal(t, "[1/2] FROM alpine", resp.Vertexes[0].Name)
+	assert.True(t, resp.Vertexes[0].Cached)
+	require.Len(t, resp.Logs, 1)
+	assert.Equal(t, "hello\n", string(resp.Logs[0].Msg))
+}
@@ -91,6 +91,9 @@ func (c *switchCli) Run(ctx context.Context, opts RunConfig) (RunResult, error)
 func (c *switchCli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
 	return c.client(ctx).ExecInContainer(ctx, cID, cmd, in, out)
 }
+func (c *switchCli) ContainerArchive(ctx context.Context, cID container.ID, srcPath string) (io.ReadCloser, error) {
+	return c.client(ctx).ContainerArchive(ctx, cID, srcPath)
+}
 func (c *switchCli) ImagePull(ctx context.Context, ref reference.Named) (reference.Canonical, error) {
 	return c.client(ctx).ImagePull(ctx, ref)
 }
@@ -112,6 +115,9 @@ func (c *switchCli) ImageList(ctx context.Context, options types.ImageListOption
 func (c *switchCli) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
 	return c.client(ctx).ImageRemove(ctx, imageID, options)
 }
+func (c *switchCli) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	return c.client(ctx).ImageSave(ctx, imageIDs)
+}
 func (c *switchCli) NewVersionError(apiRequired, feature string) error {
 	return c.client(context.Background()).NewVersionError(apiRequired, feature)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
 
@@ -30,6 +31,12 @@ type InitAction struct {
 	CloudAddress string
 	Token        token.Token
 	TerminalMode store.TerminalMode
+
+	// Overrides the gate resources from the Tiltfile's ci_settings().
+	CIGateResources []model.ManifestName
+
+	// If set, logs truncated from memory are archived to disk.
+	LogArchive *logstore.Archive
 }
 
 func (InitAction) Action() {}
@@ -21,6 +21,7 @@ import (
 	ktypes "k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
@@ -337,12 +338,13 @@ func newBDFixtureWithUpdateMode(t *testing.T, env clusterid.Product, runtime con
 	k8s.Runtime = runtime
 	mode := liveupdates.UpdateModeFlag(um)
 	dcc := dockercompose.NewFakeDockerComposeClient(t, ctx)
-	kl := &fakeKINDLoader{}
+	il := &fakeImageLoader{}
 	ctrlClient := fake.NewFakeTiltClient()
 	st := NewTestingStore(logs)
 	execer := localexec.NewFakeExecer(t)
-	bd, err := provideFakeBuildAndDeployer(ctx, dockerClient, k8s, dir, env, mode, dcc,
-		fakeClock{now: time.Unix(1551202573, 0)}, kl, ta, ctrlClient, st, execer)
+	clients := cluster.NewFakeClientProvider(t, ctrlClient)
+	bd, err := provideFakeBuildAndDeployer(ctx, dockerClient, k8s, clients, dir, env, mode, dcc,
+		fakeClock{now: time.Unix(1551202573, 0)}, il, ta, ctrlClient, st, execer)
 	require.NoError(t, err)
 
 	ret := &bdFixture{
@@ -498,11 +500,11 @@ type fakeClock struct {
 
 func (c fakeClock) Now() time.Time { return c.now }
 
-type fakeKINDLoader struct {
+type fakeImageLoader struct {
 	loadCount int
 }
 
-func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
-	kl.loadCount++
+func (il *fakeImageLoader) LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	il.loadCount++
 	return nil
 }
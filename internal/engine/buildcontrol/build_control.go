@@ -590,7 +590,7 @@ func IsLiveUpdateTargetWaitingOnDeploy(state store.EngineState, mt *store.Manife
 func HoldLiveUpdateTargetsHandledByReconciler(state store.EngineState, mts []*store.ManifestTarget, holds HoldSet) {
 	for _, mt := range mts {
 		// Most types of build reasons trigger a full rebuild. The two exceptions are:
-		// - File-change only
+		// - File-change only (not from a git branch switch)
 		// - Live-update eligible manual triggers
 		reason := mt.NextBuildReason()
 		isLiveUpdateEligible := reason == model.BuildReasonFlagChangedFiles
@@ -312,6 +312,12 @@ func TestLiveUpdateMainImageHold(t *testing.T) {
 	f.st.LiveUpdates["sancho"] = &v1alpha1.LiveUpdate{Spec: luSpec}
 	f.assertNoTargetNextToBuild()
 
+	// If the change came from a git branch switch, we rebuild instead.
+	sancho.State.AddPendingBranchSwitch(sanchoImage.ID(), time.Now())
+	f.assertNextTargetToBuild("sancho")
+	sancho.State.MutableBuildStatus(sanchoImage.ID()).PendingBranchSwitch = time.Time{}
+	f.assertNoTargetNextToBuild()
+
 	// If the base image has a change, we have to rebuild.
 	sancho.State.MutableBuildStatus(baseImage.ID()).PendingFileChanges[srcFile] = time.Now()
 	f.assertNextTargetToBuild("sancho")
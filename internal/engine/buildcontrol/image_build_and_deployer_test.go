@@ -22,7 +22,9 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -270,7 +272,7 @@ func TestMultiStageDockerBuild(t *testing.T) {
 
 	assert.Equal(t, 2, f.docker.BuildCount)
 	assert.Equal(t, 1, f.docker.PushCount)
-	assert.Equal(t, 0, f.kl.loadCount)
+	assert.Equal(t, 0, f.il.loadCount)
 
 	expected := testutils.ExpectedFile{
 		Path: "Dockerfile",
@@ -317,7 +319,7 @@ ENTRYPOINT /go/bin/sancho
 
 	assert.Equal(t, 2, f.docker.BuildCount)
 	assert.Equal(t, 1, f.docker.PushCount)
-	assert.Equal(t, 0, f.kl.loadCount)
+	assert.Equal(t, 0, f.il.loadCount)
 
 	expected := testutils.ExpectedFile{
 		Path: "Dockerfile",
@@ -429,10 +431,33 @@ func TestKINDLoad(t *testing.T) {
 	}
 
 	assert.Equal(t, 1, f.docker.BuildCount)
-	assert.Equal(t, 1, f.kl.loadCount)
+	assert.Equal(t, 1, f.il.loadCount)
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
+func TestK3DLoad(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductK3D)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, f.il.loadCount)
+	assert.Equal(t, 0, f.docker.PushCount)
+}
+
+func TestDockerPushIfMinikubeWithDockerRuntime(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductMinikube)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, v1alpha1.ImageLoaderRegistry, f.cluster.Status.ImageLoader)
+	assert.Equal(t, 0, f.il.loadCount)
+	assert.Equal(t, 1, f.docker.PushCount)
+}
+
 func TestDockerPushIfKINDAndClusterRef(t *testing.T) {
 	f := newIBDFixture(t, clusterid.ProductKIND)
 	f.cluster.Spec.DefaultRegistry = &v1alpha1.RegistryHosting{
@@ -452,7 +477,7 @@ func TestDockerPushIfKINDAndClusterRef(t *testing.T) {
 	refs := f.refs(iTarg)
 
 	assert.Equal(t, 1, f.docker.BuildCount, "Docker build count")
-	assert.Equal(t, 0, f.kl.loadCount, "KIND load count")
+	assert.Equal(t, 0, f.il.loadCount, "image load count")
 	assert.Equal(t, 1, f.docker.PushCount, "Docker push count")
 	assert.Equal(t, refs.LocalRef().String(), container.MustParseNamed(f.docker.PushImage).Name(), "image pushed to Docker as LocalRef")
 
@@ -473,7 +498,7 @@ func TestCustomBuildDisablePush(t *testing.T) {
 	// We didn't try to build or push an image, but we did try to tag it
 	assert.Equal(t, 0, f.docker.BuildCount)
 	assert.Equal(t, 1, f.docker.TagCount)
-	assert.Equal(t, 0, f.kl.loadCount)
+	assert.Equal(t, 0, f.il.loadCount)
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
@@ -502,7 +527,7 @@ func TestCustomBuildSkipsLocalDocker(t *testing.T) {
 	// We didn't try to build, tag, or push an image
 	assert.Equal(t, 0, f.docker.BuildCount)
 	assert.Equal(t, 0, f.docker.TagCount)
-	assert.Equal(t, 0, f.kl.loadCount)
+	assert.Equal(t, 0, f.il.loadCount)
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
@@ -1043,7 +1068,7 @@ type ibdFixture struct {
 	k8s        *k8s.FakeK8sClient
 	ibd        *ImageBuildAndDeployer
 	st         *store.TestingStore
-	kl         *fakeKINDLoader
+	il         *fakeImageLoader
 	ctrlClient ctrlclient.Client
 	cluster    *v1alpha1.Cluster
 }
@@ -1062,7 +1087,7 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	ctx, _, ta := testutils.CtxAndAnalyticsForTest()
 	ctx = logger.WithLogger(ctx, logger.NewTestLogger(out))
 	kClient := k8s.NewFakeK8sClient(t)
-	kl := &fakeKINDLoader{}
+	il := &fakeImageLoader{}
 	clock := fakeClock{time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC)}
 	kubeContext := k8s.KubeContext(fmt.Sprintf("%s-me", env))
 	clusterEnv := docker.ClusterEnv(docker.Env{})
@@ -1074,8 +1099,9 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	ctrlClient := fake.NewFakeTiltClient()
 	st := store.NewTestingStore()
 	execer := localexec.NewFakeExecer(t)
-	ibd, err := ProvideImageBuildAndDeployer(ctx, dockerClient, kClient, env, kubeContext,
-		clusterEnv, dir, clock, kl, ta, ctrlClient, st, execer)
+	clients := cluster.NewFakeClientProvider(t, ctrlClient)
+	ibd, err := ProvideImageBuildAndDeployer(ctx, dockerClient, kClient, clients, env, kubeContext,
+		clusterEnv, dir, clock, il, ta, ctrlClient, st, execer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1088,6 +1114,7 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 					Context: string(kubeContext),
 				},
 			},
+			ImageLoader: build.ChooseImageLoader(env, kClient.ContainerRuntime(ctx), nil),
 		},
 	}
 	ret := &ibdFixture{
@@ -1098,7 +1125,7 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 		k8s:            kClient,
 		ibd:            ibd,
 		st:             st,
-		kl:             kl,
+		il:             il,
 		ctrlClient:     ctrlClient,
 		cluster:        cluster,
 	}
@@ -1201,12 +1228,12 @@ status: {}`, name, name)
 	return model.Manifest{Name: model.ManifestName(name)}.WithDeployTarget(model.NewK8sTargetForTesting(yaml))
 }
 
-type fakeKINDLoader struct {
+type fakeImageLoader struct {
 	loadCount int
 }
 
-func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
-	kl.loadCount++
+func (il *fakeImageLoader) LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	il.loadCount++
 	return nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmdimage"
 	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/controllers/core/dockerimage"
@@ -41,6 +42,7 @@ var BaseWireSet = wire.NewSet(
 	k8s.ProvideMinikubeClient,
 	build.NewDockerBuilder,
 	build.NewCustomBuilder,
+	build.NewBuildkitBuilder,
 	wire.Bind(new(build.DockerKubeConnection), new(*build.DockerBuilder)),
 
 	// BuildOrder
@@ -50,6 +52,7 @@ var BaseWireSet = wire.NewSet(
 	containerupdate.NewDockerUpdater,
 	containerupdate.NewExecUpdater,
 	build.NewImageBuilder,
+	build.ProvideImageCache,
 
 	tracer.InitOpenTelemetry,
 
@@ -60,12 +63,13 @@ func ProvideImageBuildAndDeployer(
 	ctx context.Context,
 	docker docker.Client,
 	kClient k8s.Client,
+	clients cluster.ClientProvider,
 	env clusterid.Product,
 	kubeContext k8s.KubeContext,
 	clusterEnv docker.ClusterEnv,
 	dir *dirs.TiltDevDir,
 	clock build.Clock,
-	kp build.KINDLoader,
+	il build.ImageLoader,
 	analytics *analytics.TiltAnalytics,
 	ctrlclient ctrlclient.Client,
 	st store.RStore,
@@ -92,7 +96,7 @@ func ProvideDockerComposeBuildAndDeployer(
 		BaseWireSet,
 		dockercomposeservice.WireSet,
 		build.ProvideClock,
-		build.NewKINDLoader,
+		build.NewImageLoader,
 		dockerimage.NewReconciler,
 		cmdimage.NewReconciler,
 	)
@@ -9,6 +9,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/jonboulle/clockwork"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -23,9 +24,21 @@ type podManifest struct {
 	manifest model.ManifestName
 }
 
+type rolloutManifest struct {
+	uid      types.UID
+	manifest model.ManifestName
+}
+
+type rolloutUpdate struct {
+	manifestName model.ManifestName
+	previous     k8s.RolloutStatus
+	current      k8s.RolloutStatus
+}
+
 type PodMonitor struct {
 	pods            map[podManifest]podStatus
 	trackingStarted map[podManifest]bool
+	rollouts        map[rolloutManifest]k8s.RolloutStatus
 	startTime       time.Time
 }
 
@@ -33,20 +46,39 @@ func NewPodMonitor(clock clockwork.Clock) *PodMonitor {
 	return &PodMonitor{
 		pods:            make(map[podManifest]podStatus),
 		trackingStarted: make(map[podManifest]bool),
+		rollouts:        make(map[rolloutManifest]k8s.RolloutStatus),
 		startTime:       clock.Now(),
 	}
 }
 
-func (m *PodMonitor) diff(st store.RStore) []podStatus {
+func (m *PodMonitor) diff(st store.RStore) ([]podStatus, []rolloutUpdate) {
 	state := st.RLockState()
 	defer st.RUnlockState()
 
 	updates := make([]podStatus, 0)
+	rolloutUpdates := make([]rolloutUpdate, 0)
 	active := make(map[podManifest]bool)
+	activeRollouts := make(map[rolloutManifest]bool)
 
 	for _, mt := range state.Targets() {
 		ms := mt.State
 		manifest := mt.Manifest
+
+		for _, rollout := range ms.K8sRuntimeState().DeployedRollouts() {
+			key := rolloutManifest{uid: rollout.UID, manifest: manifest.Name}
+			activeRollouts[key] = true
+
+			previous, ok := m.rollouts[key]
+			if !ok || previous != rollout {
+				rolloutUpdates = append(rolloutUpdates, rolloutUpdate{
+					manifestName: manifest.Name,
+					previous:     previous,
+					current:      rollout,
+				})
+				m.rollouts[key] = rollout
+			}
+		}
+
 		pod := ms.MostRecentPod()
 		podID := k8s.PodID(pod.Name)
 		if podID.Empty() {
@@ -69,11 +101,22 @@ func (m *PodMonitor) diff(st store.RStore) []podStatus {
 		}
 	}
 
-	return updates
+	for key := range m.rollouts {
+		if !activeRollouts[key] {
+			delete(m.rollouts, key)
+		}
+	}
+
+	return updates, rolloutUpdates
 }
 
 func (m *PodMonitor) OnChange(ctx context.Context, st store.RStore, _ store.ChangeSummary) error {
-	updates := m.diff(st)
+	updates, rolloutUpdates := m.diff(st)
+	for _, update := range rolloutUpdates {
+		ctx := store.WithManifestLogHandler(ctx, st, update.manifestName, spanIDForRollout(update.manifestName, update.current))
+		m.printRollout(ctx, update)
+	}
+
 	for _, update := range updates {
 		ctx := store.WithManifestLogHandler(ctx, st, update.manifestName, spanIDForPod(update.manifestName, update.podID))
 		m.print(ctx, update)
@@ -82,6 +125,38 @@ func (m *PodMonitor) OnChange(ctx context.Context, st store.RStore, _ store.Chan
 	return nil
 }
 
+// Prints the progress of a workload rollout.
+//
+// A rollout that's already complete the first time we see it isn't interesting,
+// so we only print once a rollout starts making progress (or stops making progress).
+func (m *PodMonitor) printRollout(ctx context.Context, update rolloutUpdate) {
+	l := logger.Get(ctx).WithFields(logger.Fields{logger.FieldNameProgressID: "Rollout"})
+	current := update.current
+	previous := update.previous
+	indent := "     "
+
+	switch {
+	case current.Stalled():
+		if previous.Stalled() && previous.Message == current.Message {
+			return
+		}
+		l.Errorf("%s┃ Rollout of %s stalled (%s): %s", indent, current.DisplayName(), current.StalledReason, current.Message)
+
+	case current.Complete:
+		if previous.UID == "" || previous.Complete {
+			return
+		}
+		l.Infof("%s┊ Rollout of %s complete (%d of %d replicas available)",
+			indent, current.DisplayName(), current.AvailableReplicas, current.DesiredReplicas)
+
+	default:
+		if previous.Message == current.Message {
+			return
+		}
+		l.Infof("%s┊ Rollout of %s - (…) %s", indent, current.DisplayName(), current.Message)
+	}
+}
+
 func (m *PodMonitor) print(ctx context.Context, update podStatus) {
 	key := podManifest{pod: update.podID, manifest: update.manifestName}
 
@@ -182,6 +257,10 @@ func podStatusesEqual(a, b podStatus) bool {
 	return cmp.Equal(a, b, podStatusAllowUnexported)
 }
 
+func spanIDForRollout(mn model.ManifestName, rollout k8s.RolloutStatus) logstore.SpanID {
+	return logstore.SpanID(fmt.Sprintf("monitor:%s:%s", mn, rollout.DisplayName()))
+}
+
 func spanIDForPod(mn model.ManifestName, podID k8s.PodID) logstore.SpanID {
 	return logstore.SpanID(fmt.Sprintf("monitor:%s:%s", mn, podID))
 }
@@ -18,7 +18,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
 	"github.com/tilt-dev/tilt/internal/testutils/manifestutils"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
@@ -189,6 +191,78 @@ func TestJobCompletedAfterReady(t *testing.T) {
 	assertSnapshot(t, f.out.String())
 }
 
+func TestRolloutStalled(t *testing.T) {
+	f := newPMFixture(t)
+
+	m := model.Manifest{Name: "server"}
+	mt := store.NewManifestTarget(m)
+	runtime := store.NewK8sRuntimeState(m)
+	runtime.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{{Kind: "Deployment", Name: "server", UID: "server-uid"}},
+	}
+	mt.State.RuntimeState = runtime
+
+	state := store.NewState()
+	state.UpsertManifestTarget(mt)
+	f.store.SetState(*state)
+
+	rollout := k8s.RolloutStatus{
+		UID:             "server-uid",
+		Kind:            "deployment",
+		Name:            "server",
+		DesiredReplicas: 2,
+		Message:         "1 of 2 new replicas have been updated",
+	}
+	runtime.Rollouts[rollout.UID] = rollout
+	_ = f.pm.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	// No change, so nothing new to print.
+	_ = f.pm.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	rollout.Message = `ReplicaSet "server-abc" has timed out progressing.`
+	rollout.StalledReason = k8s.ProgressDeadlineExceeded
+	runtime.Rollouts[rollout.UID] = rollout
+	_ = f.pm.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	rollout.Message = ""
+	rollout.StalledReason = ""
+	rollout.Complete = true
+	rollout.UpdatedReplicas = 2
+	rollout.AvailableReplicas = 2
+	runtime.Rollouts[rollout.UID] = rollout
+	_ = f.pm.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	assertSnapshot(t, f.out.String())
+}
+
+func TestRolloutAlreadyComplete(t *testing.T) {
+	f := newPMFixture(t)
+
+	m := model.Manifest{Name: "server"}
+	mt := store.NewManifestTarget(m)
+	runtime := store.NewK8sRuntimeState(m)
+	runtime.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{{Kind: "Deployment", Name: "server", UID: "server-uid"}},
+	}
+	runtime.Rollouts["server-uid"] = k8s.RolloutStatus{
+		UID:               "server-uid",
+		Kind:              "deployment",
+		Name:              "server",
+		DesiredReplicas:   1,
+		UpdatedReplicas:   1,
+		AvailableReplicas: 1,
+		Complete:          true,
+	}
+	mt.State.RuntimeState = runtime
+
+	state := store.NewState()
+	state.UpsertManifestTarget(mt)
+	f.store.SetState(*state)
+	_ = f.pm.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	assert.Equal(t, "", f.out.String())
+}
+
 type pmFixture struct {
 	*tempdir.TempDirFixture
 	ctx    context.Context
@@ -4,7 +4,9 @@ import (
 	"net/url"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -23,3 +25,32 @@ func NewServiceChangeAction(service *v1.Service, mn model.ManifestName, url *url
 		URL:          url,
 	}
 }
+
+type RolloutChangeAction struct {
+	Rollout      k8s.RolloutStatus
+	ManifestName model.ManifestName
+}
+
+func (RolloutChangeAction) Action() {}
+
+func NewRolloutChangeAction(rollout k8s.RolloutStatus, mn model.ManifestName) RolloutChangeAction {
+	return RolloutChangeAction{
+		Rollout:      rollout,
+		ManifestName: mn,
+	}
+}
+
+// Sent when a workload whose rollout we reported has been deleted.
+type RolloutDeleteAction struct {
+	UID          types.UID
+	ManifestName model.ManifestName
+}
+
+func (RolloutDeleteAction) Action() {}
+
+func NewRolloutDeleteAction(uid types.UID, mn model.ManifestName) RolloutDeleteAction {
+	return RolloutDeleteAction{
+		UID:          uid,
+		ManifestName: mn,
+	}
+}
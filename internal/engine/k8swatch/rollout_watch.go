@@ -0,0 +1,293 @@
+package k8swatch
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// RolloutWatcher watches the Deployments, StatefulSets, and DaemonSets
+// that Tilt deploys, and reports their rollout status.
+type RolloutWatcher struct {
+	clients   *cluster.ClientManager
+	clientKey watcherClientKey
+
+	mu                sync.RWMutex
+	watcherKnownState watcherKnownState
+	knownRollouts     map[clusterUID]k8s.RolloutStatus
+
+	// The workload kinds that each namespace watch is watching.
+	watchedKinds map[clusterNamespace][]schema.GroupKind
+}
+
+func NewRolloutWatcher(clients cluster.ClientProvider, cfgNS k8s.Namespace) *RolloutWatcher {
+	return &RolloutWatcher{
+		clients:           cluster.NewClientManager(clients),
+		clientKey:         watcherClientKey{name: "rollouts"},
+		watcherKnownState: newWatcherKnownState(cfgNS),
+		knownRollouts:     make(map[clusterUID]k8s.RolloutStatus),
+		watchedKinds:      make(map[clusterNamespace][]schema.GroupKind),
+	}
+}
+
+func (w *RolloutWatcher) diff(st store.RStore) (watcherTaskList, map[clusterNamespace][]schema.GroupKind) {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	return w.watcherKnownState.createTaskList(state), w.workloadKinds(state)
+}
+
+// The workload kinds that Tilt has deployed to each namespace, sorted,
+// so that we only start informers for kinds we'll see rollouts of.
+func (w *RolloutWatcher) workloadKinds(state store.EngineState) map[clusterNamespace][]schema.GroupKind {
+	kindSets := make(map[clusterNamespace]map[schema.GroupKind]bool)
+	for _, mt := range state.Targets() {
+		if !mt.Manifest.IsK8s() {
+			continue
+		}
+
+		applyFilter := mt.State.K8sRuntimeState().ApplyFilter
+		if applyFilter == nil {
+			continue
+		}
+
+		clusterNN := types.NamespacedName{Name: mt.Manifest.ClusterName()}
+		for _, ref := range applyFilter.DeployedRefs {
+			kind := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind()
+			if _, ok := k8s.WorkloadGVRs[kind]; !ok {
+				continue
+			}
+
+			key := clusterNamespace{cluster: clusterNN, namespace: w.watcherKnownState.refNamespace(ref)}
+			if kindSets[key] == nil {
+				kindSets[key] = make(map[schema.GroupKind]bool)
+			}
+			kindSets[key][kind] = true
+		}
+	}
+
+	result := make(map[clusterNamespace][]schema.GroupKind, len(kindSets))
+	for key, kindSet := range kindSets {
+		kinds := make([]schema.GroupKind, 0, len(kindSet))
+		for kind := range kindSet {
+			kinds = append(kinds, kind)
+		}
+		sort.Slice(kinds, func(i, j int) bool {
+			return kinds[i].String() < kinds[j].String()
+		})
+		result[key] = kinds
+	}
+	return result
+}
+
+func (w *RolloutWatcher) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	clusters := w.handleClusterChanges(st, summary)
+
+	taskList, kinds := w.diff(st)
+
+	for _, teardown := range taskList.teardownNamespaces {
+		w.teardownWatch(teardown)
+		w.forgetRollouts(teardown)
+	}
+
+	// Start watches for new namespaces, and restart the watches of namespaces
+	// where Tilt now deploys different kinds of workloads.
+	for _, key := range taskList.watchableNamespaces {
+		_, ok := w.watcherKnownState.namespaceWatches[key]
+		if ok && kindsEqual(w.watchedKinds[key], kinds[key]) {
+			continue
+		}
+		w.teardownWatch(key)
+		w.setupWatch(ctx, st, clusters, key, kinds[key])
+	}
+
+	if len(taskList.newUIDs) > 0 {
+		w.setupNewUIDs(st, taskList.newUIDs)
+	}
+
+	return nil
+}
+
+func (w *RolloutWatcher) handleClusterChanges(st store.RStore, summary store.ChangeSummary) map[types.NamespacedName]*v1alpha1.Cluster {
+	clusters := make(map[types.NamespacedName]*v1alpha1.Cluster)
+	state := st.RLockState()
+	for k, v := range state.Clusters {
+		clusters[types.NamespacedName{Name: k}] = v.DeepCopy()
+	}
+	st.RUnlockState()
+
+	for clusterNN := range summary.Clusters.Changes {
+		c := clusters[clusterNN]
+		if c != nil && !w.clients.Refresh(w.clientKey, c) {
+			// cluster config didn't change
+			continue
+		}
+
+		// cluster config changed, remove all state so it can be re-built
+		for key := range w.knownRollouts {
+			if key.cluster == clusterNN {
+				delete(w.knownRollouts, key)
+			}
+		}
+		for key := range w.watchedKinds {
+			if key.cluster == clusterNN {
+				delete(w.watchedKinds, key)
+			}
+		}
+
+		w.watcherKnownState.resetStateForCluster(clusterNN)
+	}
+
+	return clusters
+}
+
+func (w *RolloutWatcher) setupWatch(ctx context.Context, st store.RStore, clusters map[types.NamespacedName]*v1alpha1.Cluster, key clusterNamespace, kinds []schema.GroupKind) {
+	ctx, cancel := context.WithCancel(ctx)
+	w.watcherKnownState.namespaceWatches[key] = namespaceWatch{cancel: cancel}
+	w.watchedKinds[key] = kinds
+	if len(kinds) == 0 {
+		// Nothing in this namespace has a rollout (yet).
+		return
+	}
+
+	kCli, err := w.clients.GetK8sClient(w.clientKey, clusters[key.cluster])
+	if err != nil {
+		// ignore errors, if the cluster status changes, the subscriber
+		// will be re-run and the namespaces will be picked up again as new
+		// since watcherKnownState isn't updated
+		w.teardownWatch(key)
+		return
+	}
+
+	ch, err := kCli.WatchWorkloads(ctx, key.namespace, kinds)
+	if err != nil {
+		w.teardownWatch(key)
+		err = errors.Wrapf(err, "Error watching deployments. Are you connected to kubernetes?\nTry running `kubectl get deployments -n %q`", key.namespace)
+		st.Dispatch(store.NewErrorAction(err))
+		return
+	}
+
+	go w.dispatchRolloutChangesLoop(ctx, key.cluster, ch, st)
+}
+
+func (w *RolloutWatcher) teardownWatch(key clusterNamespace) {
+	watcher, ok := w.watcherKnownState.namespaceWatches[key]
+	if ok {
+		watcher.cancel()
+	}
+	delete(w.watcherKnownState.namespaceWatches, key)
+	delete(w.watchedKinds, key)
+}
+
+// Forget the rollouts in a namespace we're no longer watching.
+func (w *RolloutWatcher) forgetRollouts(key clusterNamespace) {
+	for uid, rollout := range w.knownRollouts {
+		if uid.cluster == key.cluster && rollout.Namespace == key.namespace {
+			delete(w.knownRollouts, uid)
+		}
+	}
+}
+
+func kindsEqual(a, b []schema.GroupKind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// When new UIDs are deployed, go through all our known rollouts and dispatch
+// new events. This handles the case where we get the rollout change event
+// before the deploy id shows up in the manifest.
+func (w *RolloutWatcher) setupNewUIDs(st store.RStore, newUIDs map[clusterUID]model.ManifestName) {
+	for uid, mn := range newUIDs {
+		w.watcherKnownState.knownDeployedUIDs[uid] = mn
+
+		rollout, ok := w.knownRollouts[uid]
+		if !ok {
+			continue
+		}
+
+		st.Dispatch(NewRolloutChangeAction(rollout, mn))
+	}
+}
+
+// Match up the rollout update to a manifest.
+func (w *RolloutWatcher) triageRolloutUpdate(clusterNN types.NamespacedName, rollout k8s.RolloutStatus) model.ManifestName {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	uid := clusterUID{cluster: clusterNN, uid: rollout.UID}
+	w.knownRollouts[uid] = rollout
+
+	manifestName, ok := w.watcherKnownState.knownDeployedUIDs[uid]
+	if !ok {
+		return ""
+	}
+
+	return manifestName
+}
+
+// Forget a deleted workload, and return the manifest it belonged to, if any.
+func (w *RolloutWatcher) triageRolloutDelete(clusterNN types.NamespacedName, uid types.UID) model.ManifestName {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := clusterUID{cluster: clusterNN, uid: uid}
+	delete(w.knownRollouts, key)
+	return w.watcherKnownState.knownDeployedUIDs[key]
+}
+
+func (w *RolloutWatcher) dispatchRolloutChangesLoop(ctx context.Context, clusterNN types.NamespacedName, ch <-chan k8s.WorkloadUpdate, st store.RStore) {
+	for {
+		select {
+		case update, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			if update.IsDelete {
+				manifestName := w.triageRolloutDelete(clusterNN, update.Entity.UID())
+				if manifestName != "" {
+					st.Dispatch(NewRolloutDeleteAction(update.Entity.UID(), manifestName))
+				}
+				continue
+			}
+
+			rollout, ok := k8s.RolloutStatusFromEntity(update.Entity)
+			if !ok {
+				continue
+			}
+
+			manifestName := w.triageRolloutUpdate(clusterNN, rollout)
+			if manifestName == "" {
+				continue
+			}
+
+			st.Dispatch(NewRolloutChangeAction(rollout, manifestName))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package k8swatch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/k8sconv"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestRolloutWatch(t *testing.T) {
+	f := newRWFixture(t)
+
+	manifest := f.addManifest("server")
+	d := newDeployment("server", "server-uid")
+	f.addDeployedWorkload(manifest, d)
+	f.kClient.UpsertWorkload(k8s.NewK8sEntity(d))
+
+	f.assertObservedRollouts(RolloutChangeAction{
+		ManifestName: manifest.Name,
+		Rollout: k8s.RolloutStatus{
+			UID:                "server-uid",
+			Kind:               "deployment",
+			Name:               "server",
+			Namespace:          k8s.DefaultNamespace,
+			Generation:         2,
+			ObservedGeneration: 2,
+			DesiredReplicas:    1,
+			UpdatedReplicas:    1,
+			Message:            "0 of 1 updated replicas are available",
+		},
+	})
+}
+
+func TestRolloutWatchProgressDeadlineExceeded(t *testing.T) {
+	f := newRWFixture(t)
+
+	manifest := f.addManifest("server")
+	d := newDeployment("server", "server-uid")
+	d.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentProgressing,
+			Reason:  k8s.ProgressDeadlineExceeded,
+			Message: `ReplicaSet "server-abc" has timed out progressing.`,
+		},
+	}
+	f.kClient.UpsertWorkload(k8s.NewK8sEntity(d))
+
+	// The rollout shows up before the apply finishes.
+	placeholder := newDeployment("placeholder", "placeholder-uid")
+	placeholder.Status.AvailableReplicas = 1
+	f.kClient.UpsertWorkload(k8s.NewK8sEntity(placeholder))
+	f.addDeployedWorkload(manifest, placeholder)
+	f.waitUntilRolloutKnown("server-uid")
+	f.store.ClearActions()
+
+	f.addDeployedWorkload(manifest, d)
+	actions := f.observedRollouts(1)
+	require.Len(t, actions, 1)
+	assert.True(t, actions[0].Rollout.Stalled())
+	assert.Equal(t, `ReplicaSet "server-abc" has timed out progressing.`, actions[0].Rollout.Message)
+}
+
+func TestRolloutWatchOnlyWatchesDeployedKinds(t *testing.T) {
+	f := newRWFixture(t)
+
+	manifest := f.addManifest("server")
+	f.addDeployedWorkload(manifest, newDeployment("server", "server-uid"))
+
+	assert.Equal(t, []map[schema.GroupKind]bool{
+		{{Group: appsv1.GroupName, Kind: "Deployment"}: true},
+	}, f.kClient.WorkloadWatchKinds())
+}
+
+func TestRolloutWatchDeletedWorkload(t *testing.T) {
+	f := newRWFixture(t)
+
+	manifest := f.addManifest("server")
+	d := newDeployment("server", "server-uid")
+	f.addDeployedWorkload(manifest, d)
+	f.kClient.UpsertWorkload(k8s.NewK8sEntity(d))
+	f.waitUntilRolloutKnown("server-uid")
+
+	f.kClient.DeleteWorkload(k8s.NewK8sEntity(d))
+	action := f.store.WaitForAction(t, reflect.TypeOf(RolloutDeleteAction{}))
+	assert.Equal(t, NewRolloutDeleteAction("server-uid", manifest.Name), action)
+
+	f.rw.mu.Lock()
+	defer f.rw.mu.Unlock()
+	assert.Empty(t, f.rw.knownRollouts)
+}
+
+func newDeployment(name string, uid types.UID) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  k8s.DefaultNamespace.String(),
+			UID:        uid,
+			Generation: 2,
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+		},
+	}
+}
+
+type rwFixture struct {
+	*tempdir.TempDirFixture
+	t       *testing.T
+	kClient *k8s.FakeK8sClient
+	rw      *RolloutWatcher
+	ctx     context.Context
+	cancel  func()
+	store   *store.TestingStore
+}
+
+func newRWFixture(t *testing.T) *rwFixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx, cancel := context.WithCancel(ctx)
+
+	clients := cluster.NewFakeClientProvider(t, fake.NewFakeTiltClient())
+	kClient := clients.EnsureDefaultK8sCluster(ctx)
+
+	rw := NewRolloutWatcher(clients, k8s.DefaultNamespace)
+	st := store.NewTestingStore()
+
+	state := st.LockMutableStateForTesting()
+	_, createdAt, err := clients.GetK8sClient(types.NamespacedName{Name: "default"})
+	require.NoError(t, err, "Failed to get default cluster client hash")
+	state.Clusters["default"] = &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+		Status: v1alpha1.ClusterStatus{
+			ConnectedAt: createdAt.DeepCopy(),
+		},
+	}
+	st.UnlockMutableState()
+
+	ret := &rwFixture{
+		TempDirFixture: tempdir.NewTempDirFixture(t),
+		kClient:        kClient,
+		rw:             rw,
+		ctx:            ctx,
+		cancel:         cancel,
+		t:              t,
+		store:          st,
+	}
+
+	t.Cleanup(ret.TearDown)
+
+	return ret
+}
+
+func (f *rwFixture) TearDown() {
+	f.cancel()
+	f.store.AssertNoErrorActions(f.t)
+}
+
+func (f *rwFixture) addManifest(manifestName model.ManifestName) model.Manifest {
+	state := f.store.LockMutableStateForTesting()
+	defer f.store.UnlockMutableState()
+
+	m := manifestbuilder.New(f, manifestName).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+	return m
+}
+
+func (f *rwFixture) addDeployedWorkload(m model.Manifest, d *appsv1.Deployment) {
+	defer func() {
+		require.NoError(f.t, f.rw.OnChange(f.ctx, f.store, store.LegacyChangeSummary()))
+	}()
+
+	state := f.store.LockMutableStateForTesting()
+	defer f.store.UnlockMutableState()
+	mState, ok := state.ManifestState(m.Name)
+	if !ok {
+		f.t.Fatalf("Unknown manifest: %s", m.Name)
+	}
+	runtimeState := mState.K8sRuntimeState()
+	runtimeState.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{k8s.NewK8sEntity(d).ToObjectReference()},
+	}
+	mState.RuntimeState = runtimeState
+}
+
+func (f *rwFixture) observedRollouts(count int) []RolloutChangeAction {
+	f.t.Helper()
+	start := time.Now()
+	for time.Since(start) < time.Second {
+		if len(f.store.Actions()) >= count {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var result []RolloutChangeAction
+	for _, a := range f.store.Actions() {
+		rca, ok := a.(RolloutChangeAction)
+		if !ok {
+			f.t.Fatalf("got non-%T: %v", RolloutChangeAction{}, a)
+		}
+		result = append(result, rca)
+	}
+	return result
+}
+
+func (f *rwFixture) assertObservedRollouts(expected ...RolloutChangeAction) {
+	f.t.Helper()
+	if !assert.Equal(f.t, expected, f.observedRollouts(len(expected))) {
+		f.t.FailNow()
+	}
+}
+
+func (f *rwFixture) waitUntilRolloutKnown(uid types.UID) {
+	clusterNN := types.NamespacedName{Name: v1alpha1.ClusterNameDefault}
+	start := time.Now()
+	for time.Since(start) < time.Second {
+		f.rw.mu.Lock()
+		_, known := f.rw.knownRollouts[clusterUID{cluster: clusterNN, uid: uid}]
+		f.rw.mu.Unlock()
+		if known {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f.t.Fatalf("timeout waiting for rollout with UID: %s", uid)
+}
@@ -3,11 +3,11 @@ package k8swatch
 import (
 	"context"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
-	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -60,8 +60,7 @@ func (ks *watcherKnownState) createTaskList(state store.EngineState) watcherTask
 			continue
 		}
 
-		// TODO(milas): read the Cluster object name from the spec once available
-		clusterNN := types.NamespacedName{Name: v1alpha1.ClusterNameDefault}
+		clusterNN := types.NamespacedName{Name: mt.Manifest.ClusterName()}
 
 		name := mt.Manifest.Name
 
@@ -69,14 +68,7 @@ func (ks *watcherKnownState) createTaskList(state store.EngineState) watcherTask
 		applyFilter := mt.State.K8sRuntimeState().ApplyFilter
 		if applyFilter != nil {
 			for _, ref := range applyFilter.DeployedRefs {
-				namespace := k8s.Namespace(ref.Namespace)
-				if namespace == "" {
-					namespace = ks.cfgNS
-				}
-				if namespace == "" {
-					namespace = k8s.DefaultNamespace
-				}
-				nsKey := clusterNamespace{cluster: clusterNN, namespace: namespace}
+				nsKey := clusterNamespace{cluster: clusterNN, namespace: ks.refNamespace(ref)}
 				namespaces[nsKey] = true
 
 				// Our data model allows people to have the same resource defined in
@@ -134,6 +126,18 @@ func (ks *watcherKnownState) createTaskList(state store.EngineState) watcherTask
 	}
 }
 
+// The namespace of a deployed object, falling back to the configured namespace.
+func (ks *watcherKnownState) refNamespace(ref v1.ObjectReference) k8s.Namespace {
+	namespace := k8s.Namespace(ref.Namespace)
+	if namespace == "" {
+		namespace = ks.cfgNS
+	}
+	if namespace == "" {
+		namespace = k8s.DefaultNamespace
+	}
+	return namespace
+}
+
 func (ks *watcherKnownState) resetStateForCluster(clusterKey types.NamespacedName) {
 	for key, watch := range ks.namespaceWatches {
 		if key.cluster == clusterKey {
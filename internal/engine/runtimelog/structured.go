@@ -0,0 +1,241 @@
+package runtimelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Keys that structured loggers commonly use for the log level.
+var levelKeys = []string{"level", "lvl", "severity"}
+
+// Keys that structured loggers commonly use for the human-readable message.
+var messageKeys = []string{"msg", "message"}
+
+// Fields that Tilt's own logger uses to decide how to display a line.
+// A runtime log that happens to use these keys gets them namespaced,
+// so that it can't, e.g., collapse unrelated lines into one progress line.
+var reservedFields = []string{
+	logger.FieldNameProgressID,
+	logger.FieldNameProgressMustPrint,
+	logger.FieldNameBuildEvent,
+}
+
+// The prefix for runtime log keys that collide with reservedFields.
+const reservedFieldPrefix = "log."
+
+// A writer that parses each line of a runtime log in a structured format,
+// and writes it to the logger with its keys promoted to log fields.
+//
+// Lines that don't parse are written as-is.
+type StructuredWriter struct {
+	l      logger.Logger
+	format v1alpha1.PodLogFormat
+
+	// A partial line that we haven't seen the end of yet.
+	buf []byte
+}
+
+func NewStructuredWriter(l logger.Logger, format v1alpha1.PodLogFormat) *StructuredWriter {
+	return &StructuredWriter{l: l, format: format}
+}
+
+func (w *StructuredWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Writes any partial line left at the end of the stream.
+func (w *StructuredWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *StructuredWriter) writeLine(line []byte) {
+	level, fields, text, ok := ParseStructuredLine(w.format, line)
+	if !ok {
+		w.l.Write(logger.InfoLvl, line)
+		return
+	}
+	w.l.WithFields(fields).Write(level, text)
+}
+
+// ParseStructuredLine parses a single log line in the given format.
+//
+// Returns the level of the log, its fields, and the text to display
+// (the message, if the line has one, or else the whole line).
+// Keys that Tilt reserves for its own fields are prefixed with "log.".
+// Returns ok=false if the line isn't in that format.
+func ParseStructuredLine(format v1alpha1.PodLogFormat, line []byte) (level logger.Level, fields logger.Fields, text []byte, ok bool) {
+	trimmed := bytes.TrimSpace(line)
+	switch format {
+	case v1alpha1.PodLogFormatJSON:
+		fields, ok = parseJSONLine(trimmed)
+	case v1alpha1.PodLogFormatLogfmt:
+		fields, ok = parseLogfmtLine(trimmed)
+	}
+	if !ok {
+		return logger.InfoLvl, nil, line, false
+	}
+
+	for _, key := range reservedFields {
+		if v, exists := fields[key]; exists {
+			delete(fields, key)
+			fields[reservedFieldPrefix+key] = v
+		}
+	}
+
+	level = logger.InfoLvl
+	for _, key := range levelKeys {
+		if v, exists := fields[key]; exists {
+			level = levelFromString(v)
+			break
+		}
+	}
+
+	text = line
+	for _, key := range messageKeys {
+		if v, exists := fields[key]; exists {
+			text = []byte(v + "\n")
+			break
+		}
+	}
+	return level, fields, text, true
+}
+
+// Structured logs tend to have lots of levels (debug, trace, fatal, etc).
+// We only distinguish the ones we display differently.
+func levelFromString(s string) logger.Level {
+	switch strings.ToLower(s) {
+	case "warn", "warning":
+		return logger.WarnLvl
+	case "err", "error", "fatal", "panic", "crit", "critical", "alert", "emerg", "emergency":
+		return logger.ErrorLvl
+	}
+	return logger.InfoLvl
+}
+
+// Parses a JSON object. Scalar values are promoted as-is;
+// nested objects and arrays are promoted as JSON.
+func parseJSONLine(line []byte) (logger.Fields, bool) {
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	err := decoder.Decode(&obj)
+	if err != nil || decoder.More() {
+		return nil, false
+	}
+
+	fields := make(logger.Fields, len(obj))
+	for k, v := range obj {
+		switch v := v.(type) {
+		case nil:
+			fields[k] = ""
+		case string:
+			fields[k] = v
+		case json.Number:
+			fields[k] = v.String()
+		case bool:
+			fields[k] = strconv.FormatBool(v)
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, false
+			}
+			fields[k] = string(b)
+		}
+	}
+	return fields, true
+}
+
+// Parses a line of logfmt, e.g.,
+//
+//	level=info msg="starting server" port=8080
+//
+// To avoid mangling plain-text logs that happen to contain an '=',
+// every token must be a key=value pair.
+func parseLogfmtLine(line []byte) (logger.Fields, bool) {
+	s := string(line)
+	fields := logger.Fields{}
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			break
+		}
+
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return nil, false
+		}
+		key := s[:eq]
+		if strings.IndexFunc(key, func(r rune) bool { return unicode.IsSpace(r) || r == '"' }) != -1 {
+			return nil, false
+		}
+		s = s[eq+1:]
+
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			end, ok := quotedStringEnd(s)
+			if !ok {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(s[:end])
+			if err != nil {
+				return nil, false
+			}
+			val = unquoted
+			s = s[end:]
+			if s != "" && !unicode.IsSpace(rune(s[0])) {
+				return nil, false
+			}
+		} else {
+			end := strings.IndexFunc(s, unicode.IsSpace)
+			if end == -1 {
+				end = len(s)
+			}
+			val = s[:end]
+			s = s[end:]
+		}
+		fields[key] = val
+	}
+
+	if len(fields) == 0 {
+		return nil, false
+	}
+	return fields, true
+}
+
+// Returns the index just past the closing quote of the quoted string at the start of s.
+func quotedStringEnd(s string) (int, bool) {
+	escaped := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,123 @@
+package runtimelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestParseJSONLine(t *testing.T) {
+	level, fields, text, ok := ParseStructuredLine(v1alpha1.PodLogFormatJSON,
+		[]byte(`{"level":"error","msg":"connection refused","trace_id":"abc","attempt":3,"retry":true,"ctx":{"db":"users"}}`+"\n"))
+	require.True(t, ok)
+	assert.Equal(t, logger.ErrorLvl, level)
+	assert.Equal(t, "connection refused\n", string(text))
+	assert.Equal(t, logger.Fields{
+		"level":    "error",
+		"msg":      "connection refused",
+		"trace_id": "abc",
+		"attempt":  "3",
+		"retry":    "true",
+		"ctx":      `{"db":"users"}`,
+	}, fields)
+}
+
+func TestParseJSONLineWithoutMessage(t *testing.T) {
+	line := []byte(`{"severity":"WARNING","event":"slow query"}` + "\n")
+	level, fields, text, ok := ParseStructuredLine(v1alpha1.PodLogFormatJSON, line)
+	require.True(t, ok)
+	assert.Equal(t, logger.WarnLvl, level)
+	assert.Equal(t, string(line), string(text))
+	assert.Equal(t, "slow query", fields["event"])
+}
+
+func TestParseJSONLineInvalid(t *testing.T) {
+	for _, line := range []string{
+		"plain text\n",
+		"{not json}\n",
+		`["an", "array"]` + "\n",
+		`{"a":1} {"b":2}` + "\n",
+	} {
+		_, _, text, ok := ParseStructuredLine(v1alpha1.PodLogFormatJSON, []byte(line))
+		assert.False(t, ok, "line: %q", line)
+		assert.Equal(t, line, string(text))
+	}
+}
+
+func TestParseLogfmtLine(t *testing.T) {
+	level, fields, text, ok := ParseStructuredLine(v1alpha1.PodLogFormatLogfmt,
+		[]byte(`level=warn msg="disk \"data\" almost full" pct=91 empty=`+"\n"))
+	require.True(t, ok)
+	assert.Equal(t, logger.WarnLvl, level)
+	assert.Equal(t, "disk \"data\" almost full\n", string(text))
+	assert.Equal(t, logger.Fields{
+		"level": "warn",
+		"msg":   `disk "data" almost full`,
+		"pct":   "91",
+		"empty": "",
+	}, fields)
+}
+
+func TestParseLogfmtLineInvalid(t *testing.T) {
+	for _, line := range []string{
+		"plain text\n",
+		"Listening on port=8080\n",
+		`msg="unterminated` + "\n",
+		`msg="a"b` + "\n",
+		"\n",
+	} {
+		_, _, _, ok := ParseStructuredLine(v1alpha1.PodLogFormatLogfmt, []byte(line))
+		assert.False(t, ok, "line: %q", line)
+	}
+}
+
+func TestParseLineNamespacesReservedFields(t *testing.T) {
+	_, fields, _, ok := ParseStructuredLine(v1alpha1.PodLogFormatJSON,
+		[]byte(`{"msg":"uploading","progressID":"upload","buildEvent":"init"}`+"\n"))
+	require.True(t, ok)
+	assert.Equal(t, logger.Fields{
+		"msg":            "uploading",
+		"log.progressID": "upload",
+		"log.buildEvent": "init",
+	}, fields)
+
+	_, fields, _, ok = ParseStructuredLine(v1alpha1.PodLogFormatLogfmt,
+		[]byte(`msg=done progressMustPrint=1`+"\n"))
+	require.True(t, ok)
+	assert.Equal(t, logger.Fields{
+		"msg":                   "done",
+		"log.progressMustPrint": "1",
+	}, fields)
+}
+
+func TestStructuredWriter(t *testing.T) {
+	var entries []structuredEntry
+	l := logger.NewFuncLogger(false, logger.DebugLvl, func(level logger.Level, fields logger.Fields, b []byte) error {
+		entries = append(entries, structuredEntry{level: level, fields: fields, text: string(b)})
+		return nil
+	})
+
+	w := NewStructuredWriter(l, v1alpha1.PodLogFormatJSON)
+	_, err := w.Write([]byte(`{"level":"error","msg":"boom"}` + "\nstarting up\n" + `{"msg":"par`))
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`tial"}` + "\n" + "no newline"))
+	require.NoError(t, err)
+	w.Flush()
+
+	assert.Equal(t, []structuredEntry{
+		{level: logger.ErrorLvl, fields: logger.Fields{"level": "error", "msg": "boom"}, text: "boom\n"},
+		{level: logger.InfoLvl, text: "starting up\n"},
+		{level: logger.InfoLvl, fields: logger.Fields{"msg": "partial"}, text: "partial\n"},
+		{level: logger.InfoLvl, text: "no newline"},
+	}, entries)
+}
+
+type structuredEntry struct {
+	level  logger.Level
+	fields logger.Fields
+	text   string
+}
@@ -65,6 +65,10 @@ func (c *Controller) OnChange(ctx context.Context, st store.RStore, summary stor
 		}
 	}
 
+	if err := c.handleLatestSpec(ctx, c.makeLatestSpec(st)); err != nil {
+		return err
+	}
+
 	newStatus := c.makeLatestStatus(st)
 	return c.handleLatestStatus(ctx, st, newStatus)
 }
@@ -100,25 +104,65 @@ func (c *Controller) makeSession(st store.RStore) *session.Session {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "Tiltfile",
 		},
-		Spec: session.SessionSpec{
-			TiltfilePath: tf.Spec.Path,
-		},
+		Spec: c.makeSpec(state, tf.Spec.Path),
 		Status: session.SessionStatus{
 			PID:       c.pid,
 			StartTime: apis.NewMicroTime(c.startTime),
 		},
 	}
 
-	// currently, manual + CI are the only supported modes; the apiserver will validate this field and reject
-	// the object on creation if it doesn't conform, so there's no additional validation/error-handling here
+	return s
+}
+
+func (c *Controller) makeSpec(state store.EngineState, tiltfilePath string) session.SessionSpec {
+	spec := session.SessionSpec{
+		TiltfilePath: tiltfilePath,
+	}
+
+	// the apiserver will validate the exit condition and reject the object
+	// if it doesn't conform, so there's no additional validation/error-handling here
 	switch c.engineMode {
 	case store.EngineModeUp:
-		s.Spec.ExitCondition = session.ExitConditionManual
+		spec.ExitCondition = session.ExitConditionManual
 	case store.EngineModeCI:
-		s.Spec.ExitCondition = session.ExitConditionCI
+		spec.ExitCondition = session.ExitConditionCI
+
+		// gate resources on the command line take precedence over the Tiltfile
+		gate := state.CIGateResources
+		if len(gate) == 0 {
+			gate = state.CISettings.GateResources
+		}
+		if len(gate) != 0 {
+			spec.ExitCondition = session.ExitConditionCIGated
+			for _, mn := range gate {
+				spec.GateResources = append(spec.GateResources, mn.String())
+			}
+		}
 	}
+	return spec
+}
 
-	return s
+// The gate resources may not be known until the Tiltfile has loaded,
+// so the spec is recomputed on every change.
+func (c *Controller) makeLatestSpec(st store.RStore) session.SessionSpec {
+	state := st.RLockState()
+	defer st.RUnlockState()
+	return c.makeSpec(state, c.session.Spec.TiltfilePath)
+}
+
+func (c *Controller) handleLatestSpec(ctx context.Context, newSpec session.SessionSpec) error {
+	if apicmp.DeepEqual(c.session.Spec, newSpec) {
+		return nil
+	}
+
+	updated := c.session.DeepCopy()
+	updated.Spec = newSpec
+	if err := c.client.Update(ctx, updated); err != nil {
+		return err
+	}
+
+	c.session = updated
+	return nil
 }
 
 func (c *Controller) makeLatestStatus(st store.RStore) *session.SessionStatus {
@@ -150,10 +194,45 @@ func (c *Controller) makeLatestStatus(st store.RStore) *session.SessionStatus {
 		return status.Targets[i].Name < status.Targets[j].Name
 	})
 
-	processExitCondition(c.session.Spec.ExitCondition, status)
+	var gateDeps []string
+	if c.session.Spec.ExitCondition == session.ExitConditionCIGated {
+		gateDeps = gateDependencies(state, c.session.Spec.GateResources)
+	}
+
+	processExitCondition(c.session.Spec, gateDeps, status)
 	return status
 }
 
+// gateDependencies returns the resources that the gate resources depend on,
+// directly or transitively, that aren't gate resources themselves.
+func gateDependencies(state store.EngineState, gateResources []string) []string {
+	seen := make(map[string]bool, len(gateResources))
+	queue := append([]string{}, gateResources...)
+	for _, name := range gateResources {
+		seen[name] = true
+	}
+
+	var deps []string
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		mt, ok := state.ManifestTargets[model.ManifestName(name)]
+		if !ok {
+			continue
+		}
+		for _, dep := range mt.Manifest.ResourceDependencies {
+			if seen[dep.String()] {
+				continue
+			}
+			seen[dep.String()] = true
+			deps = append(deps, dep.String())
+			queue = append(queue, dep.String())
+		}
+	}
+	return deps
+}
+
 func (c *Controller) handleLatestStatus(ctx context.Context, st store.RStore, newStatus *session.SessionStatus) error {
 	if apicmp.DeepEqual(c.session.Status, *newStatus) {
 		return nil
@@ -172,9 +251,13 @@ func (c *Controller) handleLatestStatus(ctx context.Context, st store.RStore, ne
 	return nil
 }
 
-func processExitCondition(exitCondition session.ExitCondition, status *session.SessionStatus) {
+func processExitCondition(spec session.SessionSpec, gateDeps []string, status *session.SessionStatus) {
+	exitCondition := spec.ExitCondition
 	if exitCondition == session.ExitConditionManual {
 		return
+	} else if exitCondition == session.ExitConditionCIGated {
+		processGatedExitCondition(spec.GateResources, gateDeps, status)
+		return
 	} else if exitCondition != session.ExitConditionCI {
 		status.Done = true
 		status.Error = fmt.Sprintf("unsupported exit condition: %s", exitCondition)
@@ -206,6 +289,72 @@ func processExitCondition(exitCondition session.ExitCondition, status *session.S
 	}
 }
 
+// processGatedExitCondition only considers targets that belong to one of the
+// gate resources or their dependencies (plus the Tiltfile, which every other
+// resource depends on). A gate can't succeed if one of its dependencies fails.
+func processGatedExitCondition(gateResources []string, gateDeps []string, status *session.SessionStatus) {
+	gate := make(map[string]bool, len(gateResources)+len(gateDeps))
+	for _, name := range gateResources {
+		gate[name] = true
+	}
+	for _, name := range gateDeps {
+		gate[name] = true
+	}
+	seen := make(map[string]bool, len(gateResources))
+
+	tiltfileLoaded := false
+	allResourcesOK := true
+	for _, res := range status.Targets {
+		isTiltfile := len(res.Resources) == 1 && res.Resources[0] == model.MainTiltfileManifestName.String()
+		isGated := false
+		for _, r := range res.Resources {
+			if gate[r] {
+				isGated = true
+				seen[r] = true
+			}
+		}
+		if !isGated && !isTiltfile {
+			continue
+		}
+
+		if res.State.Terminated != nil && res.State.Terminated.Error != "" {
+			status.Done = true
+			status.Error = res.State.Terminated.Error
+			return
+		}
+		if isTiltfile {
+			tiltfileLoaded = res.State.Terminated != nil
+		}
+		if res.State.Waiting == nil && res.State.Active == nil && res.State.Terminated == nil {
+			// if all states are nil, the target has not been requested to run, e.g. auto_init=False
+			continue
+		}
+		if res.State.Waiting != nil {
+			allResourcesOK = false
+		} else if res.State.Active != nil && (!res.State.Active.Ready || res.Type == session.TargetTypeJob) {
+			// jobs must run to completion
+			allResourcesOK = false
+		}
+	}
+
+	if !tiltfileLoaded {
+		// resources don't exist until the Tiltfile has loaded
+		return
+	}
+
+	for _, name := range gateResources {
+		if !seen[name] {
+			status.Done = true
+			status.Error = fmt.Sprintf("gate resource %q not found", name)
+			return
+		}
+	}
+
+	if allResourcesOK {
+		status.Done = true
+	}
+}
+
 // errToString returns a stringified version of an error or an empty string if the error is nil.
 func errToString(err error) string {
 	if err == nil {
@@ -231,6 +231,155 @@ func TestExitControlCI_Success(t *testing.T) {
 	f.store.requireExitSignalWithNoError()
 }
 
+func TestExitControlCIGated_IgnoresNonGateFailure(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	f.store.WithState(func(state *store.EngineState) {
+		state.CIGateResources = []model.ManifestName{"fe"}
+	})
+
+	m := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SanchoYAML).
+		WithK8sPodReadiness(model.PodReadinessWait).
+		Build()
+	f.upsertManifest(m)
+	m2 := manifestbuilder.New(f, "fe2").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m2)
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		state.ManifestTargets["fe2"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("does not compile"),
+		})
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeStateWithPods(m, pod("pod-a", true))
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithNoError()
+
+	var s v1alpha1.Session
+	require.NoError(t, f.cli.Get(f.ctx, types.NamespacedName{Name: "Tiltfile"}, &s))
+	assert.Equal(t, v1alpha1.ExitConditionCIGated, s.Spec.ExitCondition)
+	assert.Equal(t, []string{"fe"}, s.Spec.GateResources)
+}
+
+func TestExitControlCIGated_GateFailure(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	f.store.WithState(func(state *store.EngineState) {
+		state.CISettings.GateResources = []model.ManifestName{"fe"}
+	})
+
+	m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m)
+	m2 := manifestbuilder.New(f, "fe2").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m2)
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("does not compile"),
+		})
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("does not compile")
+}
+
+func TestExitControlCIGated_TransitiveDependencyFailure(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	f.store.WithState(func(state *store.EngineState) {
+		state.CIGateResources = []model.ManifestName{"fe"}
+	})
+
+	m := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SanchoYAML).
+		WithResourceDeps("be").
+		Build()
+	f.upsertManifest(m)
+	m2 := manifestbuilder.New(f, "be").
+		WithK8sYAML(testyaml.SanchoYAML).
+		WithResourceDeps("db").
+		Build()
+	f.upsertManifest(m2)
+	m3 := manifestbuilder.New(f, "db").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m3)
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["db"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("migration failed"),
+		})
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("migration failed")
+}
+
+func TestExitControlCIGated_CLIOverridesTiltfile(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	f.store.WithState(func(state *store.EngineState) {
+		state.CISettings.GateResources = []model.ManifestName{"fe"}
+		state.CIGateResources = []model.ManifestName{"fe2"}
+	})
+
+	m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m)
+	m2 := manifestbuilder.New(f, "fe2").
+		WithK8sYAML(testyaml.SecretYaml).
+		WithK8sPodReadiness(model.PodReadinessIgnore).
+		Build()
+	f.upsertManifest(m2)
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("does not compile"),
+		})
+		state.ManifestTargets["fe2"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		krs := store.NewK8sRuntimeState(m2)
+		krs.HasEverDeployedSuccessfully = true
+		state.ManifestTargets["fe2"].State.RuntimeState = krs
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithNoError()
+}
+
+func TestExitControlCIGated_UnknownResource(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	f.store.WithState(func(state *store.EngineState) {
+		state.CIGateResources = []model.ManifestName{"missing"}
+	})
+
+	m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertManifest(m)
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError(`gate resource "missing" not found`)
+}
+
 func TestExitControlCI_PodReadinessMode_Wait(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 
@@ -49,6 +49,7 @@ func ProvideSubscribers(
 	ts *hud.TerminalStream,
 	tp *prompt.TerminalPrompt,
 	sw *k8swatch.ServiceWatcher,
+	rw *k8swatch.RolloutWatcher,
 	bc *BuildController,
 	cc *configs.ConfigsController,
 	tqs *configs.TriggerQueueSubscriber,
@@ -71,6 +72,7 @@ func ProvideSubscribers(
 		hud,
 		tp,
 		sw,
+		rw,
 		bc,
 		cc,
 		tqs,
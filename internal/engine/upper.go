@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"k8s.io/apimachinery/pkg/types"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
@@ -37,6 +38,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
 
@@ -77,6 +79,8 @@ func (u Upper) Start(
 	analyticsUserOpt analytics.Opt,
 	token token.Token,
 	cloudAddress string,
+	ciGateResources []model.ManifestName,
+	logArchive *logstore.Archive,
 ) error {
 
 	startTime := time.Now()
@@ -98,6 +102,8 @@ func (u Upper) Start(
 		Token:            token,
 		CloudAddress:     cloudAddress,
 		TerminalMode:     initTerminalMode,
+		CIGateResources:  ciGateResources,
+		LogArchive:       logArchive,
 	})
 }
 
@@ -136,6 +142,10 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 
 	case k8swatch.ServiceChangeAction:
 		handleServiceEvent(ctx, state, action)
+	case k8swatch.RolloutChangeAction:
+		handleRolloutChange(state, action)
+	case k8swatch.RolloutDeleteAction:
+		handleRolloutDelete(state, action)
 	case store.K8sEventAction:
 		handleK8sEvent(ctx, state, action)
 	case buildcontrols.BuildCompleteAction:
@@ -250,6 +260,56 @@ func handleServiceEvent(ctx context.Context, state *store.EngineState, action k8
 	runtime.LBs[k8s.ServiceName(service.Name)] = action.URL
 }
 
+func handleRolloutChange(state *store.EngineState, action k8swatch.RolloutChangeAction) {
+	ms, ok := state.ManifestState(action.ManifestName)
+	if !ok {
+		return
+	}
+
+	if !ms.IsK8s() {
+		return
+	}
+
+	runtime := ms.K8sRuntimeState()
+	if runtime.Rollouts == nil {
+		runtime.Rollouts = make(map[types.UID]k8s.RolloutStatus)
+		ms.RuntimeState = runtime
+	}
+	runtime.Rollouts[action.Rollout.UID] = action.Rollout
+	pruneRollouts(runtime)
+}
+
+func handleRolloutDelete(state *store.EngineState, action k8swatch.RolloutDeleteAction) {
+	ms, ok := state.ManifestState(action.ManifestName)
+	if !ok {
+		return
+	}
+
+	if !ms.IsK8s() {
+		return
+	}
+
+	delete(ms.K8sRuntimeState().Rollouts, action.UID)
+}
+
+// Drop the rollouts of workloads that are no longer deployed by this manifest,
+// so that redeploys don't accumulate rollouts forever.
+func pruneRollouts(runtime store.K8sRuntimeState) {
+	if runtime.ApplyFilter == nil {
+		return
+	}
+
+	deployed := make(map[types.UID]bool, len(runtime.ApplyFilter.DeployedRefs))
+	for _, ref := range runtime.ApplyFilter.DeployedRefs {
+		deployed[ref.UID] = true
+	}
+	for uid := range runtime.Rollouts {
+		if !deployed[uid] {
+			delete(runtime.Rollouts, uid)
+		}
+	}
+}
+
 func handleK8sEvent(ctx context.Context, state *store.EngineState, action store.K8sEventAction) {
 	// TODO(nick): I think we whould so something more intelligent here, where we
 	// have special treatment for different types of events, e.g.:
@@ -288,6 +348,10 @@ func handleInitAction(ctx context.Context, engineState *store.EngineState, actio
 	engineState.CloudAddress = action.CloudAddress
 	engineState.Token = action.Token
 	engineState.TerminalMode = action.TerminalMode
+	engineState.CIGateResources = action.CIGateResources
+	if action.LogArchive != nil {
+		engineState.LogStore.SetArchive(action.LogArchive)
+	}
 }
 
 func handleHudExitAction(state *store.EngineState, action hud.ExitAction) {
@@ -1975,6 +1975,36 @@ func TestK8sEventNotLoggedIfNoManifestForUID(t *testing.T) {
 		"should not log event message b/c it doesn't have a UID -> Manifest mapping")
 }
 
+func TestRolloutsPrunedToDeployedWorkloads(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	state := store.NewState()
+	m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+
+	ms, _ := state.ManifestState(m.Name)
+	runtime := ms.K8sRuntimeState()
+	runtime.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{{UID: "old-uid"}},
+	}
+	ms.RuntimeState = runtime
+	handleRolloutChange(state, k8swatch.NewRolloutChangeAction(k8s.RolloutStatus{UID: "old-uid"}, m.Name))
+
+	// Redeploy, which replaces the deployment.
+	runtime = ms.K8sRuntimeState()
+	runtime.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{{UID: "new-uid"}},
+	}
+	ms.RuntimeState = runtime
+	handleRolloutChange(state, k8swatch.NewRolloutChangeAction(k8s.RolloutStatus{UID: "new-uid"}, m.Name))
+
+	rollouts := ms.K8sRuntimeState().Rollouts
+	assert.Len(t, rollouts, 1)
+	assert.Contains(t, rollouts, types.UID("new-uid"))
+
+	handleRolloutDelete(state, k8swatch.NewRolloutDeleteAction("new-uid", m.Name))
+	assert.Empty(t, ms.K8sRuntimeState().Rollouts)
+}
+
 func TestHudExitNoError(t *testing.T) {
 	f := newTestFixture(t)
 	f.Start([]model.Manifest{})
@@ -2226,7 +2256,7 @@ func TestEmptyTiltfile(t *testing.T) {
 		err := f.upper.Start(f.ctx, []string{}, model.TiltBuild{},
 			f.JoinPath("Tiltfile"), store.TerminalModeHUD,
 			analytics.OptIn, token.Token("unit test token"),
-			"nonexistent.example.com")
+			"nonexistent.example.com", nil, nil)
 		closeCh <- err
 	}()
 	f.WaitUntil("build is set", func(st store.EngineState) bool {
@@ -2260,7 +2290,7 @@ func TestUpperStart(t *testing.T) {
 	go func() {
 		err := f.upper.Start(f.ctx, []string{"foo", "bar"}, model.TiltBuild{},
 			f.JoinPath("Tiltfile"), store.TerminalModeHUD,
-			analytics.OptIn, tok, cloudAddress)
+			analytics.OptIn, tok, cloudAddress, []model.ManifestName{"e2e"}, nil)
 		closeCh <- err
 	}()
 	f.WaitUntil("init action processed", func(state store.EngineState) bool {
@@ -2273,6 +2303,7 @@ func TestUpperStart(t *testing.T) {
 		require.Equal(t, tok, state.Token)
 		require.Equal(t, analytics.OptIn, state.AnalyticsEffectiveOpt())
 		require.Equal(t, cloudAddress, state.CloudAddress)
+		require.Equal(t, []model.ManifestName{"e2e"}, state.CIGateResources)
 	})
 
 	f.cancel()
@@ -3323,8 +3354,8 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	clock := clockwork.NewRealClock()
 	env := clusterid.ProductDockerDesktop
-	podSource := podlogstream.NewPodSource(ctx, kClient, v1alpha1.NewScheme(), clock)
-	plsc := podlogstream.NewController(ctx, cdc, sch, st, kClient, podSource, clock)
+	podSource := podlogstream.NewPodSource(ctx, kClient, clusterClients, v1alpha1.NewScheme(), clock)
+	plsc := podlogstream.NewController(ctx, cdc, sch, st, podSource, clock)
 	au := engineanalytics.NewAnalyticsUpdater(ta, engineanalytics.CmdTags{}, engineMode)
 	ar := engineanalytics.ProvideAnalyticsReporter(ta, st, kClient, env, feature.MainDefaults)
 	fakeDcc := dockercompose.NewFakeDockerComposeClient(t, ctx)
@@ -3353,11 +3384,13 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	rd := kubernetesdiscovery.NewContainerRestartDetector()
 	kdc := kubernetesdiscovery.NewReconciler(cdc, sch, clusterClients, rd, st)
 	sw := k8swatch.NewServiceWatcher(clusterClients, ns)
+	rw := k8swatch.NewRolloutWatcher(clusterClients, ns)
 	ewm := k8swatch.NewEventWatchManager(clusterClients, ns)
 	tcum := cloud.NewStatusManager(httptest.NewFakeClientEmptyJSON(), clock)
 	fe := cmd.NewFakeExecer()
 	fpm := cmd.NewFakeProberManager()
-	fwc := filewatch.NewController(cdc, st, watcher.NewSub, timerMaker.Maker(), v1alpha1.NewScheme(), clock)
+	selfWrites := filewatch.NewSelfWrites()
+	fwc := filewatch.NewController(cdc, st, watcher.NewSub, timerMaker.Maker(), v1alpha1.NewScheme(), clock, selfWrites)
 	cmds := cmd.NewController(ctx, fe, fpm, cdc, st, clock, v1alpha1.NewScheme())
 	lsc := local.NewServerController(cdc)
 	sessionController := session.NewController(cdc, engineMode)
@@ -3381,7 +3414,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	wsl := server.NewWebsocketList()
 
-	kar := kubernetesapply.NewReconciler(cdc, kClient, sch, docker.Env{}, st, execer)
+	kar := kubernetesapply.NewReconciler(cdc, kClient, clusterClients, sch, docker.Env{}, st, execer)
 	dcds := dockercomposeservice.NewDisableSubscriber(ctx, fakeDcc, clock)
 	dcr := dockercomposeservice.NewReconciler(cdc, fakeDcc, dockerClient, st, sch, dcds)
 
@@ -3393,11 +3426,12 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	cmr := configmap.NewReconciler(cdc, st)
 
 	cu := &containerupdate.FakeContainerUpdater{}
-	lur := liveupdate.NewFakeReconciler(st, cu, cdc)
+	lur := liveupdate.NewFakeReconciler(st, cu, fakeDcc, selfWrites, cdc)
 	dockerBuilder := build.NewDockerBuilder(dockerClient, nil)
+	buildkitBuilder := build.NewBuildkitBuilder(clock)
 	customBuilder := build.NewCustomBuilder(dockerClient, clock)
-	kp := build.NewKINDLoader()
-	ib := build.NewImageBuilder(dockerBuilder, customBuilder, kp)
+	il := build.NewImageLoader(dockerClient)
+	ib := build.NewImageBuilder(dockerBuilder, buildkitBuilder, customBuilder, il, nil)
 	dir := dockerimage.NewReconciler(cdc, st, sch, dockerClient, ib)
 	cir := cmdimage.NewReconciler(cdc, st, sch, dockerClient, ib)
 	clr := cluster.NewReconciler(ctx, cdc, st, clock, clusterClients, docker.LocalEnv{},
@@ -3475,7 +3509,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	uss := uisession.NewSubscriber(cdc)
 	urs := uiresource.NewSubscriber(cdc)
 
-	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, dclm, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs)
+	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, rw, bc, cc, tqs, dclm, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs)
 	ret.upper, err = NewUpper(ctx, st, subs)
 	require.NoError(t, err)
 
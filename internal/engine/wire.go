@@ -19,6 +19,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmdimage"
 	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
@@ -57,12 +58,13 @@ func provideFakeBuildAndDeployer(
 	ctx context.Context,
 	docker docker.Client,
 	kClient k8s.Client,
+	clients cluster.ClientProvider,
 	dir *dirs.TiltDevDir,
 	env clusterid.Product,
 	updateMode liveupdates.UpdateModeFlag,
 	dcc dockercompose.DockerComposeClient,
 	clock build.Clock,
-	kp build.KINDLoader,
+	il build.ImageLoader,
 	analytics *analytics.TiltAnalytics,
 	ctrlClient ctrlclient.Client,
 	st store.RStore,
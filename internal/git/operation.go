@@ -0,0 +1,76 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindGitDir returns the git directory of the repo that contains path,
+// or "" if path isn't in a git repo.
+//
+// Handles worktrees and submodules, where .git is a file that points
+// to the real git directory.
+func FindGitDir(path string) string {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			if info.IsDir() {
+				return dotGit
+			}
+			return readGitDirFile(dotGit)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// A .git file contains a single line of the form "gitdir: <path>".
+func readGitDirFile(dotGit string) string {
+	contents, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+
+	line := strings.TrimSpace(string(contents))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return ""
+	}
+
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(dotGit), gitDir)
+	}
+	return gitDir
+}
+
+// IsOperationInProgress returns true if git is in the middle of
+// rewriting the working tree (e.g., a checkout, merge, or reset).
+//
+// Git holds the index lock for the entire time it's updating files.
+func IsOperationInProgress(gitDir string) bool {
+	_, err := os.Stat(filepath.Join(gitDir, "index.lock"))
+	return err == nil
+}
+
+// Head returns the contents of the HEAD file, e.g., "ref: refs/heads/main"
+// on a branch, or a commit hash when detached.
+//
+// This changes when git switches branches, but not on a commit.
+func Head(gitDir string) string {
+	contents, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(contents))
+}
@@ -0,0 +1,47 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestFindGitDir(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+
+	err := exec.Command("git", "init", tf.JoinPath("repo")).Run()
+	if err != nil {
+		t.Fatalf("failed to init git repo: %+v", err)
+	}
+	tf.WriteFile(filepath.Join("repo", "src", "main.go"), "package main")
+	tf.WriteFile(filepath.Join("other", "main.go"), "package main")
+
+	assert.Equal(t, tf.JoinPath("repo", ".git"), FindGitDir(tf.JoinPath("repo", "src", "main.go")))
+	assert.Equal(t, tf.JoinPath("repo", ".git"), FindGitDir(tf.JoinPath("repo")))
+	assert.Equal(t, "", FindGitDir(tf.JoinPath("other")))
+}
+
+func TestFindGitDirWorktree(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+	tf.WriteFile(filepath.Join("worktree", ".git"), "gitdir: ../repo/.git/worktrees/wt\n")
+
+	assert.Equal(t, tf.JoinPath("repo", ".git", "worktrees", "wt"), FindGitDir(tf.JoinPath("worktree")))
+}
+
+func TestOperationInProgressAndHead(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+	gitDir := tf.JoinPath(".git")
+	tf.WriteFile(filepath.Join(".git", "HEAD"), "ref: refs/heads/main\n")
+
+	assert.False(t, IsOperationInProgress(gitDir))
+	assert.Equal(t, "ref: refs/heads/main", Head(gitDir))
+
+	tf.WriteFile(filepath.Join(".git", "index.lock"), "")
+	tf.WriteFile(filepath.Join(".git", "HEAD"), "ref: refs/heads/feature\n")
+	assert.True(t, IsOperationInProgress(gitDir))
+	assert.Equal(t, "ref: refs/heads/feature", Head(gitDir))
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// LogSource matches the source filter in the web UI.
+type LogSource string
+
+const (
+	LogSourceAll     LogSource = "all"
+	LogSourceBuild   LogSource = "build"
+	LogSourceRuntime LogSource = "runtime"
+)
+
+var logSources = []LogSource{LogSourceAll, LogSourceBuild, LogSourceRuntime}
+
+func ParseLogSource(s string) (LogSource, error) {
+	for _, source := range logSources {
+		if string(source) == s {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("invalid source %q (expected one of: all, build, runtime)", s)
+}
+
+// ParseLogLevel parses the minimum level of logs to print.
+func ParseLogLevel(s string) (logger.Level, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return logger.NoneLvl, nil
+	case "info":
+		return logger.InfoLvl, nil
+	case "warn", "warning":
+		return logger.WarnLvl, nil
+	case "error":
+		return logger.ErrorLvl, nil
+	}
+	return logger.NoneLvl, fmt.Errorf("invalid level %q (expected one of: info, warn, error)", s)
+}
+
+// LogStreamOptions controls which logs `tilt logs` prints, and how.
+type LogStreamOptions struct {
+	// If non-zero, skip logs from before this time.
+	Since time.Time
+
+	// If set, skip logs less severe than this level.
+	Level logger.Level
+
+	// If set, only print build logs or runtime logs.
+	Source LogSource
+
+	// If set, only print logs that match this regexp.
+	Grep *regexp.Regexp
+
+	// If set, only print logs whose fields have all of these values.
+	Fields map[string]string
+
+	// If non-negative, only print this many lines of the logs that
+	// already exist when we connect. Logs that arrive later are always printed.
+	Tail int
+
+	// Print each log segment as a JSON object, rather than as text.
+	JSON bool
+
+	// Also print logs that Tilt has truncated from memory and archived to disk.
+	Archived bool
+}
+
+// Mirrors isBuildSpanId in the web UI.
+func isBuildSpanID(spanID string) bool {
+	return strings.HasPrefix(spanID, "build:")
+}
+
+// Whether any option filters out logs.
+func (o LogStreamOptions) filtering() bool {
+	return !o.Since.IsZero() || o.Level != logger.NoneLvl ||
+		(o.Source != "" && o.Source != LogSourceAll) ||
+		o.Grep != nil || len(o.Fields) > 0
+}
+
+// Whether a line, made up of one or more segments, matches the filters.
+//
+// Segments of a line always have the same span and level, so we take
+// everything but the text from the first one.
+func (o LogStreamOptions) matchesLine(line []*proto_webview.LogSegment) bool {
+	seg := line[0]
+	if !o.Since.IsZero() && seg.Time != nil && seg.Time.AsTime().Before(o.Since) {
+		return false
+	}
+
+	if o.Level != logger.NoneLvl && !logger.LevelFromProtoID(int32(seg.Level)).AsSevereAs(o.Level) {
+		return false
+	}
+
+	switch o.Source {
+	case LogSourceBuild:
+		if !isBuildSpanID(seg.SpanId) {
+			return false
+		}
+	case LogSourceRuntime:
+		if isBuildSpanID(seg.SpanId) {
+			return false
+		}
+	}
+
+	if o.Grep != nil && !o.Grep.MatchString(lineText(line)) {
+		return false
+	}
+
+	for k, v := range o.Fields {
+		actual, ok := seg.Fields[k]
+		if !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
+func lineText(line []*proto_webview.LogSegment) string {
+	if len(line) == 1 {
+		return line[0].Text
+	}
+	var sb strings.Builder
+	for _, seg := range line {
+		sb.WriteString(seg.Text)
+	}
+	return sb.String()
+}
+
+// Groups segments into lines, so that filters see whole lines, even when
+// a line arrives in several segments.
+//
+// Mirrors how the LogStore decides whether a segment continues a line.
+type lineFilter struct {
+	// Segments of lines that haven't ended yet, by span.
+	pending map[string][]*proto_webview.LogSegment
+}
+
+func newLineFilter() *lineFilter {
+	return &lineFilter{
+		pending: make(map[string][]*proto_webview.LogSegment),
+	}
+}
+
+// Returns the segments of the lines that ended and match the filters.
+//
+// Holds on to lines that haven't ended yet, until they end or we flush.
+func (f *lineFilter) filter(o LogStreamOptions, segments []*proto_webview.LogSegment) []*proto_webview.LogSegment {
+	var result []*proto_webview.LogSegment
+	for _, seg := range segments {
+		line := f.pending[seg.SpanId]
+		if len(line) > 0 && line[len(line)-1].Level != seg.Level {
+			result = append(result, o.matching(line)...)
+			line = nil
+		}
+
+		line = append(line, seg)
+		if strings.HasSuffix(seg.Text, "\n") {
+			result = append(result, o.matching(line)...)
+			delete(f.pending, seg.SpanId)
+		} else {
+			f.pending[seg.SpanId] = line
+		}
+	}
+	return result
+}
+
+// Returns the segments of the lines that haven't ended, if they match.
+func (f *lineFilter) flush(o LogStreamOptions) []*proto_webview.LogSegment {
+	spanIDs := make([]string, 0, len(f.pending))
+	for spanID := range f.pending {
+		spanIDs = append(spanIDs, spanID)
+	}
+	sort.Strings(spanIDs)
+
+	var result []*proto_webview.LogSegment
+	for _, spanID := range spanIDs {
+		result = append(result, o.matching(f.pending[spanID])...)
+	}
+	f.pending = make(map[string][]*proto_webview.LogSegment)
+	return result
+}
+
+func (o LogStreamOptions) matching(line []*proto_webview.LogSegment) []*proto_webview.LogSegment {
+	if !o.matchesLine(line) {
+		return nil
+	}
+	return line
+}
+
+// ParseLogField parses a field filter of the form key=value.
+func ParseLogField(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid field filter %q (expected key=value)", s)
+	}
+	return key, value, nil
+}
@@ -2,7 +2,13 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/gorilla/websocket"
@@ -31,11 +37,6 @@ type WebsocketReader struct {
 	handler      ViewHandler
 }
 
-func newWebsocketReaderForLogs(conn WebsocketConn, persistent bool, resources []string, p *hud.IncrementalPrinter) *WebsocketReader {
-	ls := NewLogStreamer(resources, p)
-	return newWebsocketReader(conn, persistent, ls)
-}
-
 func newWebsocketReader(conn WebsocketConn, persistent bool, handler ViewHandler) *WebsocketReader {
 	return &WebsocketReader{
 		conn:         conn,
@@ -60,10 +61,23 @@ type LogStreamer struct {
 	// This value should only be used to compare to other server values, NOT client checkpoints.
 	serverWatermark int32
 	resources       model.ManifestNameSet // if present, resource(s) to stream logs for
+	options         LogStreamOptions
 	printer         *hud.IncrementalPrinter
+
+	// Whether we've handled the logs that existed when we connected (for --tail).
+	handledFirstLogs bool
+
+	// Logs from the on-disk archive, printed before the first logs from the server.
+	archived *proto_webview.LogList
+
+	// Holds partial lines until they end, so that we filter whole lines.
+	lines *lineFilter
+
+	// All the spans we've seen.
+	spans map[string]*proto_webview.LogSpan
 }
 
-func NewLogStreamer(resources []string, p *hud.IncrementalPrinter) *LogStreamer {
+func NewLogStreamer(resources []string, options LogStreamOptions, p *hud.IncrementalPrinter) *LogStreamer {
 	mnSet := make(map[model.ManifestName]bool, len(resources))
 	for _, r := range resources {
 		mnSet[model.ManifestName(r)] = true
@@ -72,7 +86,10 @@ func NewLogStreamer(resources []string, p *hud.IncrementalPrinter) *LogStreamer
 	return &LogStreamer{
 		resources: mnSet,
 		logstore:  logstore.NewLogStore(),
+		options:   options,
 		printer:   p,
+		lines:     newLineFilter(),
+		spans:     make(map[string]*proto_webview.LogSpan),
 	}
 }
 
@@ -92,22 +109,118 @@ func (ls *LogStreamer) Handle(v *proto_webview.View) error {
 		segments = segments[deleteCount:]
 	}
 
+	if !ls.handledFirstLogs && ls.archived != nil {
+		segments = append(append([]*proto_webview.LogSegment{}, ls.archived.Segments...), segments...)
+		for id, span := range ls.archived.Spans {
+			ls.spans[id] = span
+		}
+		ls.archived = nil
+	}
+	for id, span := range v.LogList.Spans {
+		ls.spans[id] = span
+	}
+
+	if ls.options.filtering() {
+		segments = ls.lines.filter(ls.options, segments)
+	}
+	lines := ls.toLines(segments, suppressPrefix)
+
+	if !ls.handledFirstLogs {
+		ls.handledFirstLogs = true
+		if ls.options.Tail >= 0 && len(lines) > ls.options.Tail {
+			lines = lines[len(lines)-ls.options.Tail:]
+		}
+	}
+
+	ls.printer.Print(lines)
+
+	ls.checkpoint = ls.logstore.Checkpoint()
+	ls.serverWatermark = v.LogList.ToCheckpoint
+
+	return nil
+}
+
+// Prints any lines that haven't ended yet, e.g., when the stream closes.
+func (ls *LogStreamer) Flush() {
+	if !ls.options.filtering() {
+		return
+	}
+	lines := ls.toLines(ls.lines.flush(ls.options), len(ls.resources) == 1)
+	ls.printer.Print(lines)
+	ls.checkpoint = ls.logstore.Checkpoint()
+}
+
+func (ls *LogStreamer) toLines(segments []*proto_webview.LogSegment, suppressPrefix bool) []logstore.LogLine {
+	if ls.options.JSON {
+		return ls.jsonLines(segments)
+	}
+
 	for _, seg := range segments {
 		// TODO(maia): secrets???
-		ls.logstore.Append(webview.LogSegmentToEvent(seg, v.LogList.Spans), model.SecretSet{})
+		ls.logstore.Append(webview.LogSegmentToEvent(seg, ls.spans), model.SecretSet{})
 	}
 
-	ls.printer.Print(ls.logstore.ContinuingLinesWithOptions(ls.checkpoint, logstore.LineOptions{
+	return ls.logstore.ContinuingLinesWithOptions(ls.checkpoint, logstore.LineOptions{
 		ManifestNames:  ls.resources,
 		SuppressPrefix: suppressPrefix,
-	}))
+	})
+}
 
-	ls.checkpoint = ls.logstore.Checkpoint()
-	ls.serverWatermark = v.LogList.ToCheckpoint
+type jsonLogSegment struct {
+	Time         *time.Time        `json:"time,omitempty"`
+	SpanID       string            `json:"spanId"`
+	ManifestName string            `json:"manifestName,omitempty"`
+	Level        string            `json:"level"`
+	Fields       map[string]string `json:"fields,omitempty"`
+	Text         string            `json:"text"`
+}
 
-	return nil
+// Converts each segment to a line of JSON.
+func (ls *LogStreamer) jsonLines(segments []*proto_webview.LogSegment) []logstore.LogLine {
+	var lines []logstore.LogLine
+	for _, seg := range segments {
+		span, ok := ls.spans[seg.SpanId]
+		if !ok {
+			continue
+		}
+		if len(ls.resources) != 0 && !ls.resources[model.ManifestName(span.ManifestName)] {
+			continue
+		}
+
+		entry := jsonLogSegment{
+			SpanID:       seg.SpanId,
+			ManifestName: span.ManifestName,
+			Level:        seg.Level.String(),
+			Fields:       seg.Fields,
+			Text:         seg.Text,
+		}
+		if seg.Time != nil {
+			t := seg.Time.AsTime()
+			entry.Time = &t
+		}
+
+		b, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, logstore.LogLine{
+			Text:   string(b) + "\n",
+			SpanID: logstore.SpanID(seg.SpanId),
+		})
+	}
+	return lines
 }
-func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []string, printer *hud.IncrementalPrinter) error {
+
+func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []string, options LogStreamOptions, printer *hud.IncrementalPrinter) error {
+	var archived *proto_webview.LogList
+	if options.Archived {
+		var err error
+		archived, err = fetchArchivedLogs(ctx, url, resources)
+		if err != nil {
+			return err
+		}
+	}
+
 	url.Scheme = "ws"
 	url.Path = "/ws/view"
 	logger.Get(ctx).Debugf("connecting to %s", url.String())
@@ -118,8 +231,47 @@ func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []
 	}
 	defer conn.Close()
 
-	wsr := newWebsocketReaderForLogs(conn, follow, resources, printer)
-	return wsr.Listen(ctx)
+	ls := NewLogStreamer(resources, options, printer)
+	ls.archived = archived
+	wsr := newWebsocketReader(conn, follow, ls)
+	err = wsr.Listen(ctx)
+	ls.Flush()
+	return err
+}
+
+func fetchArchivedLogs(ctx context.Context, u model.WebURL, resources []string) (*proto_webview.LogList, error) {
+	u.Scheme = "http"
+	u.Path = "/api/logs/archive"
+	query := url.Values{}
+	for _, r := range resources {
+		query.Add("manifest", r)
+	}
+	u.RawQuery = query.Encode()
+	logger.Get(ctx).Debugf("fetching %s", u.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching archived logs from %s", u.String())
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("fetching archived logs: %s", strings.TrimSpace(string(body)))
+	}
+
+	logList := &proto_webview.LogList{}
+	err = (&jsonpb.Unmarshaler{}).Unmarshal(resp.Body, logList)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing archived logs")
+	}
+	return logList, nil
 }
 
 func (wsr *WebsocketReader) Listen(ctx context.Context) error {
@@ -3,12 +3,16 @@ package server
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 
 	"github.com/tilt-dev/tilt/internal/hud"
@@ -131,6 +135,122 @@ func TestLogStreamerCheckpointHandlingWithFiltering(t *testing.T) {
 	f.assertExpectedLogLines(expected)
 }
 
+func TestLogStreamerFiltersOnLevel(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Level: logger.WarnLvl})
+	view := f.newViewWithLogsForManifest(alphabet[:3], "foo", 0)
+	view.LogList.Segments[0].Level = proto_webview.LogLevel_INFO
+	view.LogList.Segments[1].Level = proto_webview.LogLevel_WARN
+	view.LogList.Segments[2].Level = proto_webview.LogLevel_ERROR
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"bravo", "charlie"}, "foo"))
+}
+
+func TestLogStreamerFiltersOnSource(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Source: LogSourceRuntime})
+	view := f.newViewWithLogsForManifest(alphabet[:3], "foo", 0)
+	view.LogList.Segments[1].SpanId = "build:1"
+	view.LogList.Spans["build:1"] = &proto_webview.LogSpan{ManifestName: "foo"}
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"alpha", "charlie"}, "foo"))
+}
+
+func TestLogStreamerFiltersOnGrep(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Grep: regexp.MustCompile("^(alpha|delta)")})
+	view := f.newViewWithLogsForManifest(alphabet[:5], "foo", 0)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"alpha", "delta"}, "foo"))
+}
+
+func TestLogStreamerFiltersOnGrepAcrossSegments(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Grep: regexp.MustCompile("^alpha")})
+	view := f.newViewWithLogsForManifest([]string{"al", "pha", "bravo"}, "foo", 0)
+	view.LogList.Segments[0].Text = "al"
+	f.handle(view)
+
+	view = f.newViewWithLogsForManifest([]string{"alpha", "char"}, "foo", view.LogList.ToCheckpoint)
+	view.LogList.Segments[1].Text = "char"
+	f.handle(view)
+
+	view = f.newViewWithLogsForManifest([]string{"lie alpha"}, "foo", view.LogList.ToCheckpoint)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"alpha", "alpha"}, "foo"))
+}
+
+func TestLogStreamerFlushesPartialLines(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Grep: regexp.MustCompile("^alpha")})
+	view := f.newViewWithLogsForManifest([]string{"bravo", "alpha"}, "foo", 0)
+	view.LogList.Segments[1].Text = "alpha"
+	f.handle(view)
+	f.ls.Flush()
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"alpha"}, "foo"))
+}
+
+func TestLogStreamerFiltersOnFields(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Fields: map[string]string{"level": "error"}})
+	view := f.newViewWithLogsForManifest(alphabet[:3], "foo", 0)
+	view.LogList.Segments[0].Fields = map[string]string{"level": "info"}
+	view.LogList.Segments[1].Fields = map[string]string{"level": "error", "trace_id": "abc"}
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"bravo"}, "foo"))
+}
+
+func TestLogStreamerFiltersOnSince(t *testing.T) {
+	now := time.Now()
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: -1, Since: now.Add(-time.Minute)})
+	view := f.newViewWithLogsForManifest(alphabet[:3], "foo", 0)
+	view.LogList.Segments[0].Time = timestamppb.New(now.Add(-time.Hour))
+	view.LogList.Segments[1].Time = timestamppb.New(now.Add(-time.Second))
+	view.LogList.Segments[2].Time = timestamppb.New(now)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"bravo", "charlie"}, "foo"))
+}
+
+func TestLogStreamerTailOnlyAppliesToFirstLogs(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: 2})
+	view := f.newViewWithLogsForManifest(alphabet[:4], "foo", 0)
+	f.handle(view)
+
+	view = f.newViewWithLogsForManifest(alphabet[4:8], "foo", view.LogList.ToCheckpoint)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix(
+		[]string{"charlie", "delta", "echo", "foxtrot", "golf", "hotel"}, "foo"))
+}
+
+func TestLogStreamerJSON(t *testing.T) {
+	f := newLogStreamerFixture(t).withResourceNames("foo").withOptions(LogStreamOptions{Tail: -1, JSON: true})
+	view := f.newViewWithLogsForManifests(alphabet[:3], []string{"foo", "bar", "foo"}, 0)
+	view.LogList.Segments[2].Level = proto_webview.LogLevel_WARN
+	view.LogList.Segments[2].Fields = map[string]string{"progressID": "layer 1"}
+	view.LogList.Segments[2].Time = timestamppb.New(time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC))
+	f.handle(view)
+
+	assert.Equal(t,
+		`{"spanId":"spanID-foo","manifestName":"foo","level":"NONE","text":"alpha\n"}`+"\n"+
+			`{"time":"2022-05-01T12:00:00Z","spanId":"spanID-foo","manifestName":"foo","level":"WARN","fields":{"progressID":"layer 1"},"text":"charlie\n"}`+"\n",
+		f.fakeStdout.String())
+}
+
+func TestLogStreamerPrintsArchivedLogsFirst(t *testing.T) {
+	f := newLogStreamerFixture(t).withOptions(LogStreamOptions{Tail: 3})
+	f.ls.archived = f.newViewWithLogsForManifest(alphabet[:2], "foo", 0).LogList
+	view := f.newViewWithLogsForManifest(alphabet[2:4], "foo", 0)
+	f.handle(view)
+
+	view = f.newViewWithLogsForManifest(alphabet[4:5], "foo", view.LogList.ToCheckpoint)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix(
+		[]string{"bravo", "charlie", "delta", "echo"}, "foo"))
+}
+
 type logStreamerFixture struct {
 	t          *testing.T
 	fakeStdout *bytes.Buffer
@@ -145,7 +265,7 @@ func newLogStreamerFixture(t *testing.T) *logStreamerFixture {
 		t:          t,
 		fakeStdout: fakeStdout,
 		printer:    printer,
-		ls:         NewLogStreamer(nil, printer),
+		ls:         NewLogStreamer(nil, LogStreamOptions{Tail: -1}, printer),
 	}
 }
 
@@ -158,6 +278,11 @@ func (f *logStreamerFixture) withResourceNames(resourceNames ...string) *logStre
 	return f
 }
 
+func (f *logStreamerFixture) withOptions(options LogStreamOptions) *logStreamerFixture {
+	f.ls.options = options
+	return f
+}
+
 func (f *logStreamerFixture) handle(view *proto_webview.View) {
 	err := f.ls.Handle(view)
 	require.NoError(f.t, err)
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"strconv"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -84,6 +85,7 @@ func ProvideHeadsUpServer(
 	}
 
 	r.HandleFunc("/api/view", s.ViewJSON)
+	r.HandleFunc("/api/logs/archive", s.LogArchiveJSON)
 	r.HandleFunc("/api/dump/engine", s.DumpEngineJSON)
 	r.HandleFunc("/api/analytics", s.HandleAnalytics)
 	r.HandleFunc("/api/analytics_opt", s.HandleAnalyticsOpt)
@@ -137,6 +139,65 @@ func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Serves logs that have been truncated from memory and archived to disk.
+//
+// Query params:
+// * manifest: only return logs for this manifest (may be repeated)
+// * before: only return logs from before this position in the archive, for paging backwards
+// * limit: only return the newest `limit` log segments
+//
+// The response's from_checkpoint is the position of its first segment,
+// to pass as `before` for the previous page.
+func (s *HeadsUpServer) LogArchiveJSON(w http.ResponseWriter, req *http.Request) {
+	state := s.store.RLockState()
+	archive := state.LogStore.Archive()
+	s.store.RUnlockState()
+
+	if archive == nil {
+		http.Error(w, "log archive not enabled (start tilt with --log-archive)", http.StatusNotFound)
+		return
+	}
+
+	query := req.URL.Query()
+	mns := model.ManifestNameSet{}
+	for _, mn := range query["manifest"] {
+		mns[model.ManifestName(mn)] = true
+	}
+
+	var before int64
+	if b := query.Get("before"); b != "" {
+		var err error
+		before, err = strconv.ParseInt(b, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid before: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := 0
+	if l := query.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	logList, err := archive.ToLogList(mns, before, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error reading log archive: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	var m jsonpb.Marshaler
+	err = m.Marshal(w, logList)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering log archive: %v", err), http.StatusInternalServerError)
+	}
+}
+
 // Dump the JSON engine over http. Only intended for 'tilt dump engine'.
 func (s *HeadsUpServer) DumpEngineJSON(w http.ResponseWriter, req *http.Request) {
 	state := s.store.RLockState()
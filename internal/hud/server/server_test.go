@@ -9,10 +9,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +31,8 @@ import (
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
 
@@ -313,6 +317,64 @@ func TestSetTiltfileArgs(t *testing.T) {
 	)
 }
 
+func TestLogArchiveNotEnabled(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, respBody := f.makeReq("/api/logs/archive", f.serv.LogArchiveJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusNotFound, status)
+	require.Contains(t, respBody, "log archive not enabled")
+}
+
+func TestLogArchive(t *testing.T) {
+	f := newTestFixture(t)
+
+	archive, err := logstore.NewArchive(t.TempDir(), "session", logstore.ArchiveOptions{})
+	require.NoError(t, err)
+	defer archive.Close()
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = archive.Write([]logstore.LogSegment{
+		{SpanID: "fe", Time: start, Text: []byte("fe 1\n")},
+		{SpanID: "be", Time: start.Add(time.Second), Text: []byte("be 1\n")},
+		{SpanID: "fe", Time: start.Add(2 * time.Second), Text: []byte("fe 2\n")},
+	}, map[logstore.SpanID]*logstore.Span{
+		"fe": {ManifestName: "fe"},
+		"be": {ManifestName: "be"},
+	}, nil)
+	require.NoError(t, err)
+
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.SetArchive(archive)
+	f.st.UnlockMutableState()
+
+	status, respBody := f.makeReq("/api/logs/archive?manifest=fe", f.serv.LogArchiveJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"fe 1\n", "fe 2\n"}, archivedTexts(t, respBody))
+
+	status, respBody = f.makeReq("/api/logs/archive?limit=1", f.serv.LogArchiveJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"fe 2\n"}, archivedTexts(t, respBody))
+
+	var page proto_webview.LogList
+	require.NoError(t, jsonpb.UnmarshalString(respBody, &page))
+	before := strconv.Itoa(int(page.FromCheckpoint))
+	status, respBody = f.makeReq("/api/logs/archive?limit=1&before="+before, f.serv.LogArchiveJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"be 1\n"}, archivedTexts(t, respBody))
+
+	status, _ = f.makeReq("/api/logs/archive?limit=ten", f.serv.LogArchiveJSON, http.MethodGet, "")
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func archivedTexts(t *testing.T, body string) []string {
+	var logList proto_webview.LogList
+	require.NoError(t, jsonpb.UnmarshalString(body, &logList))
+	var result []string
+	for _, seg := range logList.Segments {
+		result = append(result, seg.Text)
+	}
+	return result
+}
+
 type serverFixture struct {
 	t            *testing.T
 	ctx          context.Context
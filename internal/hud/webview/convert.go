@@ -296,6 +296,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 			Queued:            s.ManifestInTriggerQueue(mn),
 			DisableStatus:     drs,
 			Waiting:           holdToWaiting(hold),
+			Overrides:         toUIResourceOverrides(mt.Manifest.Overrides),
 		},
 	}
 
@@ -308,6 +309,21 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 	return r, nil
 }
 
+func toUIResourceOverrides(overrides []model.ManifestOverride) []v1alpha1.UIResourceOverride {
+	if len(overrides) == 0 {
+		return nil
+	}
+	result := make([]v1alpha1.UIResourceOverride, 0, len(overrides))
+	for _, o := range overrides {
+		result = append(result, v1alpha1.UIResourceOverride{
+			Setting: o.Setting,
+			Source:  o.Source,
+			Value:   o.Value,
+		})
+	}
+	return result
+}
+
 // The "Ready" condition is a cross-resource status report that's synthesized
 // from the more type-specific fields of UIResource.
 func UIResourceReadyCondition(r v1alpha1.UIResourceStatus) v1alpha1.UIResourceCondition {
@@ -69,6 +69,10 @@ func (ec *explodingClient) WatchServices(ctx context.Context, ns Namespace) (<-c
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
 
+func (ec *explodingClient) WatchWorkloads(ctx context.Context, ns Namespace, kinds []schema.GroupKind) (<-chan WorkloadUpdate, error) {
+	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
+}
+
 func (ec *explodingClient) WatchEvents(ctx context.Context, ns Namespace) (<-chan *v1.Event, error) {
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
@@ -65,12 +65,14 @@ type FakeK8sClient struct {
 	LastPodLogPipeWriter     *io.PipeWriter
 	ContainerLogsError       error
 
-	podWatches     []fakePodWatch
-	serviceWatches []fakeServiceWatch
-	eventWatches   []fakeEventWatch
-	events         map[types.NamespacedName]*v1.Event
-	services       map[types.NamespacedName]*v1.Service
-	pods           map[types.NamespacedName]*v1.Pod
+	podWatches      []fakePodWatch
+	serviceWatches  []fakeServiceWatch
+	eventWatches    []fakeEventWatch
+	workloadWatches []fakeWorkloadWatch
+	workloads       map[types.UID]K8sEntity
+	events          map[types.NamespacedName]*v1.Event
+	services        map[types.NamespacedName]*v1.Service
+	pods            map[types.NamespacedName]*v1.Pod
 
 	EventsWatchErr error
 
@@ -126,6 +128,13 @@ type fakePodWatch struct {
 	ch     chan ObjectUpdate
 }
 
+type fakeWorkloadWatch struct {
+	cancel func()
+	ns     Namespace
+	kinds  map[schema.GroupKind]bool
+	ch     chan WorkloadUpdate
+}
+
 type fakeEventWatch struct {
 	cancel func()
 	ns     Namespace
@@ -147,6 +156,48 @@ func (c *FakeK8sClient) UpsertService(s *v1.Service) {
 	}
 }
 
+// Simulates a change to a Deployment, StatefulSet, or DaemonSet in the cluster.
+func (c *FakeK8sClient) UpsertWorkload(e K8sEntity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e = e.DeepCopy()
+	c.workloads[e.UID()] = e
+	c.emitWorkload(WorkloadUpdate{Entity: e})
+}
+
+func (c *FakeK8sClient) DeleteWorkload(e K8sEntity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e = e.DeepCopy()
+	delete(c.workloads, e.UID())
+	c.emitWorkload(WorkloadUpdate{Entity: e, IsDelete: true})
+}
+
+// Must be called while holding the lock.
+func (c *FakeK8sClient) emitWorkload(update WorkloadUpdate) {
+	for _, w := range c.workloadWatches {
+		if w.ns != update.Entity.Namespace() || !w.kinds[update.Entity.GVK().GroupKind()] {
+			continue
+		}
+
+		w.ch <- update
+	}
+}
+
+// The kinds of each workload watch, in the order the watches were started.
+func (c *FakeK8sClient) WorkloadWatchKinds() []map[schema.GroupKind]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []map[schema.GroupKind]bool
+	for _, w := range c.workloadWatches {
+		result = append(result, w.kinds)
+	}
+	return result
+}
+
 func (c *FakeK8sClient) UpsertPod(pod *v1.Pod) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -233,6 +284,56 @@ func (c *FakeK8sClient) WatchServices(ctx context.Context, ns Namespace) (<-chan
 	return ch, nil
 }
 
+func (c *FakeK8sClient) WatchWorkloads(ctx context.Context, ns Namespace, kinds []schema.GroupKind) (<-chan WorkloadUpdate, error) {
+	if ns == "" {
+		return nil, fmt.Errorf("missing namespace from watch request")
+	}
+
+	kindSet := make(map[schema.GroupKind]bool)
+	for _, kind := range kinds {
+		if _, ok := WorkloadGVRs[kind]; !ok {
+			return nil, fmt.Errorf("WatchWorkloads: %s is not a workload kind", kind)
+		}
+		kindSet[kind] = true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	c.mu.Lock()
+	ch := make(chan WorkloadUpdate, 20)
+	c.workloadWatches = append(c.workloadWatches, fakeWorkloadWatch{cancel, ns, kindSet, ch})
+	toEmit := []K8sEntity{}
+	for _, e := range c.workloads {
+		if e.Namespace() == ns && kindSet[e.GVK().GroupKind()] {
+			toEmit = append(toEmit, e)
+		}
+	}
+	c.mu.Unlock()
+
+	go func() {
+		// Initial list of objects
+		for _, obj := range toEmit {
+			ch <- WorkloadUpdate{Entity: obj}
+		}
+
+		// when ctx is canceled, remove the watch from the list of watches
+		<-ctx.Done()
+
+		c.mu.Lock()
+		var newWatches []fakeWorkloadWatch
+		for _, e := range c.workloadWatches {
+			if e.ch != ch {
+				newWatches = append(newWatches, e)
+			}
+		}
+		c.workloadWatches = newWatches
+		c.mu.Unlock()
+
+		close(ch)
+	}()
+	return ch, nil
+}
+
 func (c *FakeK8sClient) WatchEvents(ctx context.Context, ns Namespace) (<-chan *v1.Event, error) {
 	if ns == "" {
 		return nil, fmt.Errorf("missing namespace from watch request")
@@ -350,6 +451,7 @@ func NewFakeK8sClient(t testing.TB) *FakeK8sClient {
 		PodLogsByPodAndContainer: make(map[PodAndCName]ReaderCloser),
 		pods:                     make(map[types.NamespacedName]*v1.Pod),
 		services:                 make(map[types.NamespacedName]*v1.Service),
+		workloads:                make(map[types.UID]K8sEntity),
 		events:                   make(map[types.NamespacedName]*v1.Event),
 		entities:                 make(map[types.UID]K8sEntity),
 		currentVersions:          make(map[string]types.UID),
@@ -378,6 +480,7 @@ func (c *FakeK8sClient) tearDown() {
 	podWatches := append([]fakePodWatch{}, c.podWatches...)
 	serviceWatches := append([]fakeServiceWatch{}, c.serviceWatches...)
 	eventWatches := append([]fakeEventWatch{}, c.eventWatches...)
+	workloadWatches := append([]fakeWorkloadWatch{}, c.workloadWatches...)
 	c.mu.Unlock()
 
 	for _, watch := range podWatches {
@@ -395,6 +498,11 @@ func (c *FakeK8sClient) tearDown() {
 		for range watch.ch {
 		}
 	}
+	for _, watch := range workloadWatches {
+		watch.cancel()
+		for range watch.ch {
+		}
+	}
 }
 
 func (c *FakeK8sClient) Upsert(_ context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
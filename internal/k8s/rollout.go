@@ -0,0 +1,189 @@
+package k8s
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The reason on a Deployment's Progressing condition when the rollout
+// has taken longer than its progressDeadlineSeconds.
+const ProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+// RolloutStatus summarizes the progress of a rollout of a workload
+// (a Deployment, StatefulSet, or DaemonSet), modeled on `kubectl rollout status`.
+type RolloutStatus struct {
+	UID       types.UID
+	Kind      string
+	Name      string
+	Namespace Namespace
+
+	Generation         int64
+	ObservedGeneration int64
+
+	DesiredReplicas   int32
+	UpdatedReplicas   int32
+	AvailableReplicas int32
+
+	// True when every replica is running the latest spec and is available.
+	Complete bool
+
+	// A human-readable description of what the rollout is waiting on.
+	// Empty when the rollout is complete.
+	Message string
+
+	// If non-empty, the rollout has stalled and won't make progress
+	// without intervention (e.g., ProgressDeadlineExceeded).
+	StalledReason string
+}
+
+func (s RolloutStatus) Stalled() bool {
+	return s.StalledReason != ""
+}
+
+// The name of the workload, e.g., "deployment/frontend".
+func (s RolloutStatus) DisplayName() string {
+	return fmt.Sprintf("%s/%s", s.Kind, s.Name)
+}
+
+// RolloutStatusFromEntity computes the rollout status of a workload.
+//
+// Returns false if the entity isn't a workload that we know how to track.
+func RolloutStatusFromEntity(e K8sEntity) (RolloutStatus, bool) {
+	status := RolloutStatus{
+		UID:       e.UID(),
+		Name:      e.Name(),
+		Namespace: e.Namespace(),
+	}
+
+	switch obj := e.Obj.(type) {
+	case *appsv1.Deployment:
+		status.Kind = "deployment"
+		deploymentRolloutStatus(obj, &status)
+	case *appsv1.StatefulSet:
+		status.Kind = "statefulset"
+		statefulSetRolloutStatus(obj, &status)
+	case *appsv1.DaemonSet:
+		status.Kind = "daemonset"
+		daemonSetRolloutStatus(obj, &status)
+	default:
+		return RolloutStatus{}, false
+	}
+	return status, true
+}
+
+func deploymentRolloutStatus(d *appsv1.Deployment, status *RolloutStatus) {
+	status.Generation = d.Generation
+	status.ObservedGeneration = d.Status.ObservedGeneration
+	status.DesiredReplicas = 1
+	if d.Spec.Replicas != nil {
+		status.DesiredReplicas = *d.Spec.Replicas
+	}
+	status.UpdatedReplicas = d.Status.UpdatedReplicas
+	status.AvailableReplicas = d.Status.AvailableReplicas
+
+	if d.Generation > d.Status.ObservedGeneration {
+		status.Message = "Waiting for deployment spec update to be observed"
+		return
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == ProgressDeadlineExceeded {
+			status.StalledReason = ProgressDeadlineExceeded
+			status.Message = c.Message
+			return
+		}
+	}
+
+	switch {
+	case d.Status.UpdatedReplicas < status.DesiredReplicas:
+		status.Message = fmt.Sprintf("%d of %d new replicas have been updated",
+			d.Status.UpdatedReplicas, status.DesiredReplicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d old replicas are pending termination",
+			d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d of %d updated replicas are available",
+			d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	default:
+		status.Complete = true
+	}
+}
+
+func statefulSetRolloutStatus(s *appsv1.StatefulSet, status *RolloutStatus) {
+	status.Generation = s.Generation
+	status.ObservedGeneration = s.Status.ObservedGeneration
+	status.DesiredReplicas = 1
+	if s.Spec.Replicas != nil {
+		status.DesiredReplicas = *s.Spec.Replicas
+	}
+	status.UpdatedReplicas = s.Status.UpdatedReplicas
+	status.AvailableReplicas = s.Status.AvailableReplicas
+
+	if s.Status.ObservedGeneration == 0 || s.Generation > s.Status.ObservedGeneration {
+		status.Message = "Waiting for statefulset spec update to be observed"
+		return
+	}
+
+	if s.Status.ReadyReplicas < status.DesiredReplicas {
+		status.Message = fmt.Sprintf("%d of %d pods are ready",
+			s.Status.ReadyReplicas, status.DesiredReplicas)
+		return
+	}
+
+	// OnDelete statefulsets never roll out on their own.
+	if s.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		status.Complete = true
+		return
+	}
+
+	rollingUpdate := s.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 {
+		expected := status.DesiredReplicas - *rollingUpdate.Partition
+		if s.Status.UpdatedReplicas < expected {
+			status.Message = fmt.Sprintf("%d of %d pods have been updated",
+				s.Status.UpdatedReplicas, expected)
+			return
+		}
+		status.Complete = true
+		return
+	}
+
+	if s.Status.UpdateRevision != s.Status.CurrentRevision {
+		status.Message = fmt.Sprintf("%d of %d pods are at revision %s",
+			s.Status.UpdatedReplicas, status.DesiredReplicas, s.Status.UpdateRevision)
+		return
+	}
+	status.Complete = true
+}
+
+func daemonSetRolloutStatus(d *appsv1.DaemonSet, status *RolloutStatus) {
+	status.Generation = d.Generation
+	status.ObservedGeneration = d.Status.ObservedGeneration
+	status.DesiredReplicas = d.Status.DesiredNumberScheduled
+	status.UpdatedReplicas = d.Status.UpdatedNumberScheduled
+	status.AvailableReplicas = d.Status.NumberAvailable
+
+	if d.Generation > d.Status.ObservedGeneration {
+		status.Message = "Waiting for daemonset spec update to be observed"
+		return
+	}
+
+	// OnDelete daemonsets never roll out on their own.
+	if d.Spec.UpdateStrategy.Type != appsv1.RollingUpdateDaemonSetStrategyType {
+		status.Complete = true
+		return
+	}
+
+	switch {
+	case d.Status.UpdatedNumberScheduled < d.Status.DesiredNumberScheduled:
+		status.Message = fmt.Sprintf("%d of %d updated pods have been scheduled",
+			d.Status.UpdatedNumberScheduled, d.Status.DesiredNumberScheduled)
+	case d.Status.NumberAvailable < d.Status.DesiredNumberScheduled:
+		status.Message = fmt.Sprintf("%d of %d updated pods are available",
+			d.Status.NumberAvailable, d.Status.DesiredNumberScheduled)
+	default:
+		status.Complete = true
+	}
+}
@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	replicas := int32(3)
+	newDeployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "server", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     status,
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		status   appsv1.DeploymentStatus
+		complete bool
+		message  string
+	}{
+		{"spec not observed", appsv1.DeploymentStatus{ObservedGeneration: 1},
+			false, "Waiting for deployment spec update to be observed"},
+		{"not updated", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1},
+			false, "1 of 3 new replicas have been updated"},
+		{"old replicas", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3},
+			false, "1 old replicas are pending termination"},
+		{"not available", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2},
+			false, "2 of 3 updated replicas are available"},
+		{"complete", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+			true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status, ok := RolloutStatusFromEntity(NewK8sEntity(newDeployment(tc.status)))
+			require.True(t, ok)
+			assert.Equal(t, "deployment/server", status.DisplayName())
+			assert.Equal(t, tc.complete, status.Complete)
+			assert.Equal(t, tc.message, status.Message)
+			assert.False(t, status.Stalled())
+		})
+	}
+}
+
+func TestDeploymentRolloutStalled(t *testing.T) {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "server", Generation: 2},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           2,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:    appsv1.DeploymentProgressing,
+					Status:  v1.ConditionFalse,
+					Reason:  ProgressDeadlineExceeded,
+					Message: `ReplicaSet "server-abc" has timed out progressing.`,
+				},
+			},
+		},
+	}
+	status, ok := RolloutStatusFromEntity(NewK8sEntity(d))
+	require.True(t, ok)
+	assert.True(t, status.Stalled())
+	assert.Equal(t, ProgressDeadlineExceeded, status.StalledReason)
+	assert.Equal(t, `ReplicaSet "server-abc" has timed out progressing.`, status.Message)
+
+	// If the deployment has been updated since, the old condition is stale.
+	d.Generation = 3
+	status, _ = RolloutStatusFromEntity(NewK8sEntity(d))
+	assert.False(t, status.Stalled())
+}
+
+func TestStatefulSetRolloutStatus(t *testing.T) {
+	replicas := int32(2)
+	s := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 1},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       &replicas,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			ReadyReplicas:      2,
+			UpdatedReplicas:    1,
+			CurrentRevision:    "db-1",
+			UpdateRevision:     "db-2",
+		},
+	}
+	status, ok := RolloutStatusFromEntity(NewK8sEntity(s))
+	require.True(t, ok)
+	assert.Equal(t, "statefulset/db", status.DisplayName())
+	assert.False(t, status.Complete)
+	assert.Equal(t, "1 of 2 pods are at revision db-2", status.Message)
+
+	s.Status.CurrentRevision = "db-2"
+	status, _ = RolloutStatusFromEntity(NewK8sEntity(s))
+	assert.True(t, status.Complete)
+}
+
+func TestDaemonSetRolloutStatus(t *testing.T) {
+	d := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Generation: 1},
+		Spec: appsv1.DaemonSetSpec{
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType},
+		},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     1,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberAvailable:        1,
+		},
+	}
+	status, ok := RolloutStatusFromEntity(NewK8sEntity(d))
+	require.True(t, ok)
+	assert.False(t, status.Complete)
+	assert.Equal(t, "1 of 3 updated pods are available", status.Message)
+}
+
+func TestRolloutStatusNotWorkload(t *testing.T) {
+	_, ok := RolloutStatusFromEntity(NewK8sEntity(&v1.Service{}))
+	assert.False(t, ok)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/singleflight"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -35,6 +36,12 @@ type InformerSet interface {
 
 	WatchEvents(ctx context.Context, ns Namespace) (<-chan *v1.Event, error)
 
+	// Watches workloads of the given kinds (Deployments, StatefulSets, or DaemonSets).
+	//
+	// Unlike the other watches, the informers aren't shared,
+	// and stop when the context is canceled.
+	WatchWorkloads(ctx context.Context, ns Namespace, kinds []schema.GroupKind) (<-chan WorkloadUpdate, error)
+
 	// Fetch a pod from the informer cache.
 	//
 	// If no informer has started, start one now on the given ctx.
@@ -65,6 +72,16 @@ func newInformerSet(clientset kubernetes.Interface, dynamic dynamic.Interface) *
 var PodGVR = v1.SchemeGroupVersion.WithResource("pods")
 var ServiceGVR = v1.SchemeGroupVersion.WithResource("services")
 var EventGVR = v1.SchemeGroupVersion.WithResource("events")
+var DeploymentGVR = appsv1.SchemeGroupVersion.WithResource("deployments")
+var StatefulSetGVR = appsv1.SchemeGroupVersion.WithResource("statefulsets")
+var DaemonSetGVR = appsv1.SchemeGroupVersion.WithResource("daemonsets")
+
+// The kinds of workloads that have rollouts, and their resources.
+var WorkloadGVRs = map[schema.GroupKind]schema.GroupVersionResource{
+	{Group: appsv1.GroupName, Kind: "Deployment"}:  DeploymentGVR,
+	{Group: appsv1.GroupName, Kind: "StatefulSet"}: StatefulSetGVR,
+	{Group: appsv1.GroupName, Kind: "DaemonSet"}:   DaemonSetGVR,
+}
 
 // Inspired by:
 // https://groups.google.com/g/kubernetes-sig-api-machinery/c/PbSCXdLDno0/m/v9gH3HXVDAAJ
@@ -325,6 +342,69 @@ func (s *informerSet) WatchServices(ctx context.Context, ns Namespace) (<-chan *
 	return ch, nil
 }
 
+// A workload that was added, updated, or deleted.
+type WorkloadUpdate struct {
+	Entity K8sEntity
+
+	// For deletes, Entity is the last state of the workload that we saw.
+	IsDelete bool
+}
+
+func (s *informerSet) WatchWorkloads(ctx context.Context, ns Namespace, kinds []schema.GroupKind) (<-chan WorkloadUpdate, error) {
+	if ns == "" {
+		return nil, fmt.Errorf("missing namespace from watch request")
+	}
+
+	var workloadInformers []cache.SharedInformer
+	for _, kind := range kinds {
+		gvr, ok := WorkloadGVRs[kind]
+		if !ok {
+			return nil, fmt.Errorf("WatchWorkloads: %s is not a workload kind", kind)
+		}
+
+		// Don't use the shared informers, because those run until Tilt exits.
+		informer, err := s.makeInformerHelper(ctx, ns, gvr)
+		if err != nil {
+			return nil, errors.Wrap(err, "WatchWorkloads")
+		}
+		workloadInformers = append(workloadInformers, informer)
+	}
+
+	ch := make(chan WorkloadUpdate)
+	send := func(obj interface{}, isDelete bool) {
+		if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = unknown.Obj
+		}
+		mObj, ok := obj.(runtime.Object)
+		if !ok {
+			return
+		}
+
+		// Don't block the informer once nobody is reading.
+		select {
+		case ch <- WorkloadUpdate{Entity: NewK8sEntity(mObj), IsDelete: isDelete}:
+		case <-ctx.Done():
+		}
+	}
+	for _, informer := range workloadInformers {
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				send(obj, false)
+			},
+			UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+				if oldObj != newObj {
+					send(newObj, false)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				send(obj, true)
+			},
+		})
+	}
+
+	return ch, nil
+}
+
 func supportsPartialMetadata(v *version.Info) bool {
 	k1dot15, err := semver.ParseTolerant("v1.15.0")
 	if err != nil {
@@ -0,0 +1,177 @@
+package kustomize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	yaml "gopkg.in/yaml.v2"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// BuildError is an error rendering a kustomization.
+//
+// Path is the kustomization file that was being processed when the error
+// occurred, so that the user knows where to look.
+type BuildError struct {
+	Path string
+	Err  error
+}
+
+func (e BuildError) Error() string {
+	return fmt.Sprintf("kustomize build failed in %s: %v", e.Path, e.Err)
+}
+
+func (e BuildError) Unwrap() error {
+	return e.Err
+}
+
+// Build renders the kustomization in dir in-process, equivalent to
+// `kustomize build dir`.
+//
+// Returns the rendered YAML and the files that kustomize read. The files are
+// returned even if the build fails, so that the caller can watch them
+// and try again when the user fixes the error.
+func Build(dir string) (string, []string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fs := newRecordingFS(filesys.MakeFsOnDisk())
+	m, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, dir)
+	if err != nil {
+		return "", fs.paths(), BuildError{Path: culprit(dir, fs.kustomizations()), Err: err}
+	}
+
+	out, err := m.AsYaml()
+	if err != nil {
+		return "", fs.paths(), BuildError{Path: dir, Err: err}
+	}
+	return string(out), fs.paths(), nil
+}
+
+// Find the kustomization file that caused a build to fail.
+//
+// Kustomize loads bases depth-first, and stops at the first error. So the
+// failing kustomizations are the culprit and its parents, and the culprit is
+// the one that was read last. We find it by building each base on its own,
+// most recently read first.
+func culprit(dir string, kfs []string) string {
+	for i := len(kfs) - 1; i > 0; i-- {
+		if isComponent(kfs[i]) {
+			// Components can't be built on their own.
+			continue
+		}
+
+		_, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).
+			Run(filesys.MakeFsOnDisk(), filepath.Dir(kfs[i]))
+		if err != nil {
+			return kfs[i]
+		}
+	}
+	if len(kfs) > 0 {
+		return kfs[0]
+	}
+	return dir
+}
+
+func isKustomizationFile(path string) bool {
+	for _, kf := range konfig.RecognizedKustomizationFileNames() {
+		if filepath.Base(path) == kf {
+			return true
+		}
+	}
+	return false
+}
+
+func isComponent(path string) bool {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var k types.Kustomization
+	err = yaml.Unmarshal(contents, &k)
+	return err == nil && k.Kind == types.ComponentKind
+}
+
+// A filesystem that records every file that kustomize reads.
+type recordingFS struct {
+	filesys.FileSystem
+
+	mu   sync.Mutex
+	read map[string]bool
+
+	// Kustomization files, in the order they were read.
+	kfs []string
+}
+
+func newRecordingFS(fs filesys.FileSystem) *recordingFS {
+	return &recordingFS{
+		FileSystem: fs,
+		read:       make(map[string]bool),
+	}
+}
+
+func (fs *recordingFS) record(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.read[abs] {
+		return
+	}
+	fs.read[abs] = true
+	if isKustomizationFile(abs) {
+		fs.kfs = append(fs.kfs, abs)
+	}
+}
+
+func (fs *recordingFS) ReadFile(path string) ([]byte, error) {
+	contents, err := fs.FileSystem.ReadFile(path)
+	if err == nil {
+		fs.record(path)
+	}
+	return contents, err
+}
+
+func (fs *recordingFS) Open(path string) (filesys.File, error) {
+	f, err := fs.FileSystem.Open(path)
+	if err == nil {
+		fs.record(path)
+	}
+	return f, err
+}
+
+func (fs *recordingFS) kustomizations() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]string{}, fs.kfs...)
+}
+
+// The sorted list of files that were read.
+//
+// Skips files that no longer exist, e.g., files in remote bases that
+// kustomize cloned into a temp dir and has since cleaned up.
+func (fs *recordingFS) paths() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	result := make([]string, 0, len(fs.read))
+	for path := range fs.read {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
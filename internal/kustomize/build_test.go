@@ -0,0 +1,119 @@
+package kustomize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+const baseKustomization = `
+resources:
+- deployment.yaml
+`
+
+const baseDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: server
+spec:
+  template:
+    spec:
+      containers:
+      - name: server
+        image: server-image
+`
+
+const overlayKustomization = `
+resources:
+- ../base
+namePrefix: dev-
+patchesStrategicMerge:
+- patch.yaml
+`
+
+const overlayPatch = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: server
+spec:
+  replicas: 2
+`
+
+func TestBuildOverlay(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("base/kustomization.yaml", baseKustomization)
+	f.WriteFile("base/deployment.yaml", baseDeployment)
+	f.WriteFile("base/unused.yaml", "")
+	f.WriteFile("overlay/kustomization.yaml", overlayKustomization)
+	f.WriteFile("overlay/patch.yaml", overlayPatch)
+
+	yaml, deps, err := Build(f.JoinPath("overlay"))
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "name: dev-server")
+	assert.Contains(t, yaml, "replicas: 2")
+	assert.Equal(t, []string{
+		f.JoinPath("base/deployment.yaml"),
+		f.JoinPath("base/kustomization.yaml"),
+		f.JoinPath("overlay/kustomization.yaml"),
+		f.JoinPath("overlay/patch.yaml"),
+	}, deps)
+}
+
+func TestBuildErrorPointsAtKustomization(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("base/kustomization.yaml", baseKustomization)
+	f.WriteFile("base/deployment.yaml", baseDeployment)
+	f.WriteFile("overlay/kustomization.yaml", `
+resources:
+- ../base
+- missing.yaml
+`)
+
+	_, deps, err := Build(f.JoinPath("overlay"))
+	require.Error(t, err)
+
+	var buildErr BuildError
+	require.True(t, errors.As(err, &buildErr))
+	assert.Equal(t, f.JoinPath("overlay/kustomization.yaml"), buildErr.Path)
+	assert.Contains(t, err.Error(), "kustomize build failed in "+f.JoinPath("overlay/kustomization.yaml"))
+	assert.Contains(t, err.Error(), "missing.yaml")
+
+	// Files read before the error are still reported, so that
+	// the caller can watch them.
+	assert.Contains(t, deps, f.JoinPath("overlay/kustomization.yaml"))
+}
+
+func TestBuildErrorInBase(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("base/kustomization.yaml", `
+resources:
+- deployment.yaml
+- missing.yaml
+`)
+	f.WriteFile("base/deployment.yaml", baseDeployment)
+	f.WriteFile("overlay/kustomization.yaml", overlayKustomization)
+	f.WriteFile("overlay/patch.yaml", overlayPatch)
+
+	_, _, err := Build(f.JoinPath("overlay"))
+	require.Error(t, err)
+
+	var buildErr BuildError
+	require.True(t, errors.As(err, &buildErr))
+	assert.Equal(t, f.JoinPath("base/kustomization.yaml"), buildErr.Path)
+}
+
+func TestBuildNoKustomization(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+
+	_, deps, err := Build(f.Path())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kustomize build failed in "+f.Path())
+	assert.Contains(t, err.Error(), "unable to find one of 'kustomization.yaml', 'kustomization.yml' or 'Kustomization'")
+	assert.Empty(t, deps)
+}
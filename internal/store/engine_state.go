@@ -47,6 +47,13 @@ type EngineState struct {
 
 	UpdateSettings model.UpdateSettings
 
+	// Settings for `tilt ci` from the Tiltfile.
+	CISettings model.CISettings
+
+	// Gate resources passed on the command line (e.g., `tilt ci --gate=e2e`).
+	// Overrides the gate resources in CISettings.
+	CIGateResources []model.ManifestName
+
 	FatalError error
 
 	// The user has indicated they want to exit
@@ -433,6 +440,10 @@ type BuildStatus struct {
 	// dependency-tracking in the short-term, without having to switch over to a
 	// full dependency graph in one swoop.
 	PendingDependencyChanges map[model.TargetID]time.Time
+
+	// The time of the latest pending file change that came from
+	// a git branch switch, if any.
+	PendingBranchSwitch time.Time
 }
 
 func newBuildStatus() *BuildStatus {
@@ -445,6 +456,7 @@ func newBuildStatus() *BuildStatus {
 func (s BuildStatus) IsEmpty() bool {
 	return len(s.PendingFileChanges) == 0 &&
 		len(s.PendingDependencyChanges) == 0 &&
+		s.PendingBranchSwitch.IsZero() &&
 		s.LastResult == nil
 }
 
@@ -459,6 +471,9 @@ func (s *BuildStatus) ClearPendingChangesBefore(startTime time.Time) {
 			delete(s.PendingDependencyChanges, file)
 		}
 	}
+	if timecmp.BeforeOrEqual(s.PendingBranchSwitch, startTime) {
+		s.PendingBranchSwitch = time.Time{}
+	}
 }
 
 type ManifestState struct {
@@ -696,6 +711,14 @@ func (ms *ManifestState) AddPendingFileChange(targetID model.TargetID, file stri
 	bs.PendingFileChanges[file] = timestamp
 }
 
+// Mark the pending file changes for a target as coming from a git branch switch.
+func (ms *ManifestState) AddPendingBranchSwitch(targetID model.TargetID, timestamp time.Time) {
+	bs := ms.MutableBuildStatus(targetID)
+	if timestamp.After(bs.PendingBranchSwitch) {
+		bs.PendingBranchSwitch = timestamp
+	}
+}
+
 func (ms *ManifestState) HasPendingFileChanges() bool {
 	for _, status := range ms.BuildStatuses {
 		if len(status.PendingFileChanges) > 0 {
@@ -705,6 +728,15 @@ func (ms *ManifestState) HasPendingFileChanges() bool {
 	return false
 }
 
+func (ms *ManifestState) HasPendingBranchSwitch() bool {
+	for _, status := range ms.BuildStatuses {
+		if !status.PendingBranchSwitch.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
 func (ms *ManifestState) HasPendingDependencyChanges() bool {
 	for _, status := range ms.BuildStatuses {
 		if len(status.PendingDependencyChanges) > 0 {
@@ -720,6 +752,9 @@ func (mt *ManifestTarget) NextBuildReason() model.BuildReason {
 	if mt.State.HasPendingFileChanges() {
 		reason = reason.With(model.BuildReasonFlagChangedFiles)
 	}
+	if mt.State.HasPendingBranchSwitch() {
+		reason = reason.With(model.BuildReasonFlagBranchSwitch)
+	}
 	if mt.State.HasPendingDependencyChanges() {
 		reason = reason.With(model.BuildReasonFlagChangedDeps)
 	}
@@ -77,6 +77,21 @@ func TestNextBuildReason(t *testing.T) {
 		mt.NextBuildReason().String())
 }
 
+func TestNextBuildReasonBranchSwitch(t *testing.T) {
+	m := k8sManifest(t, model.UnresourcedYAMLManifestName, testyaml.SanchoYAML)
+	mt := NewManifestTarget(m)
+	mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+
+	changeTime := time.Now()
+	mt.State.AddPendingFileChange(m.K8sTarget().ID(), "a.txt", changeTime)
+	mt.State.AddPendingBranchSwitch(m.K8sTarget().ID(), changeTime)
+	assert.Equal(t, "Changed Files | Branch Switch",
+		mt.NextBuildReason().String())
+
+	mt.State.MutableBuildStatus(m.K8sTarget().ID()).ClearPendingChangesBefore(changeTime)
+	assert.Equal(t, model.BuildReasonNone, mt.NextBuildReason())
+}
+
 func TestManifestTargetEndpoints(t *testing.T) {
 	cases := []endpointsCase{
 		{
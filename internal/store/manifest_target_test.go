@@ -5,7 +5,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -44,3 +47,34 @@ func TestK8sRuntimeStatus(t *testing.T) {
 	assert.Equal(t, v1alpha1.UpdateStatusNone, mt.UpdateStatus())
 	assert.Equal(t, v1alpha1.RuntimeStatusNone, mt.RuntimeStatus())
 }
+
+func TestK8sRuntimeStatusStalledRollout(t *testing.T) {
+	m := model.Manifest{Name: "k8s"}.WithDeployTarget(model.NewK8sTargetForTesting(""))
+	pod := v1alpha1.Pod{
+		Name:       "pod-id",
+		Phase:      string(v1.PodRunning),
+		Containers: []v1alpha1.Container{{Ready: true}},
+	}
+	state := NewK8sRuntimeStateWithPods(m, pod)
+	state.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{{Kind: "Deployment", Name: "server", UID: "server-uid"}},
+	}
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, state.RuntimeStatus())
+
+	state.Rollouts["server-uid"] = k8s.RolloutStatus{
+		UID:           "server-uid",
+		Kind:          "deployment",
+		Name:          "server",
+		StalledReason: k8s.ProgressDeadlineExceeded,
+		Message:       `ReplicaSet "server-abc" has timed out progressing.`,
+	}
+	assert.Equal(t, v1alpha1.RuntimeStatusError, state.RuntimeStatus())
+	assert.EqualError(t, state.RuntimeStatusError(),
+		`Rollout of deployment/server stalled (ProgressDeadlineExceeded): ReplicaSet "server-abc" has timed out progressing.`)
+
+	// Rollouts from previous applies don't count.
+	state.ApplyFilter = &k8sconv.KubernetesApplyFilter{
+		DeployedRefs: k8s.ObjRefList{{Kind: "Deployment", Name: "server", UID: "server-uid-2"}},
+	}
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, state.RuntimeStatus())
+}
@@ -8,6 +8,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -91,6 +92,13 @@ type K8sRuntimeState struct {
 	UpdateStartTime map[k8s.PodID]time.Time
 
 	PodReadinessMode model.PodReadinessMode
+
+	// The rollout status of each workload (Deployment, StatefulSet, DaemonSet)
+	// we've seen in the cluster, keyed by UID.
+	//
+	// May contain workloads that are no longer deployed; use DeployedRollouts()
+	// to get the ones from the most recent apply.
+	Rollouts map[types.UID]k8s.RolloutStatus
 }
 
 func (K8sRuntimeState) RuntimeState() {}
@@ -109,6 +117,7 @@ func NewK8sRuntimeState(m model.Manifest) K8sRuntimeState {
 		PodReadinessMode: m.PodReadinessMode(),
 		LBs:              make(map[k8s.ServiceName]*url.URL),
 		UpdateStartTime:  make(map[k8s.PodID]time.Time),
+		Rollouts:         make(map[types.UID]k8s.RolloutStatus),
 	}
 }
 
@@ -117,10 +126,40 @@ func (s K8sRuntimeState) RuntimeStatusError() error {
 	if status != v1alpha1.RuntimeStatusError {
 		return nil
 	}
+	if rollout, ok := s.StalledRollout(); ok {
+		return fmt.Errorf("Rollout of %s stalled (%s): %s", rollout.DisplayName(), rollout.StalledReason, rollout.Message)
+	}
 	pod := s.MostRecentPod()
 	return fmt.Errorf("Pod %s in error state: %s", pod.Name, pod.Status)
 }
 
+// The rollout status of each workload from the most recent apply,
+// in the order they were deployed.
+func (s K8sRuntimeState) DeployedRollouts() []k8s.RolloutStatus {
+	if s.ApplyFilter == nil {
+		return nil
+	}
+
+	var result []k8s.RolloutStatus
+	for _, ref := range s.ApplyFilter.DeployedRefs {
+		rollout, ok := s.Rollouts[ref.UID]
+		if ok {
+			result = append(result, rollout)
+		}
+	}
+	return result
+}
+
+// Returns the first deployed workload whose rollout has stalled.
+func (s K8sRuntimeState) StalledRollout() (k8s.RolloutStatus, bool) {
+	for _, rollout := range s.DeployedRollouts() {
+		if rollout.Stalled() {
+			return rollout, true
+		}
+	}
+	return k8s.RolloutStatus{}, false
+}
+
 func (s K8sRuntimeState) RuntimeStatus() v1alpha1.RuntimeStatus {
 	if !s.HasEverDeployedSuccessfully {
 		return v1alpha1.RuntimeStatusPending
@@ -137,6 +176,12 @@ func (s K8sRuntimeState) RuntimeStatus() v1alpha1.RuntimeStatus {
 		return v1alpha1.RuntimeStatusOK
 	}
 
+	// A stalled rollout won't recover on its own, even if
+	// the pods from the previous rollout are still healthy.
+	if _, ok := s.StalledRollout(); ok {
+		return v1alpha1.RuntimeStatusError
+	}
+
 	pod := s.MostRecentPod()
 	switch v1.PodPhase(pod.Phase) {
 	case v1.PodRunning:
@@ -1,4 +1,4 @@
-from typing import Dict, Union, List, Callable, Any, Optional
+from typing import Dict, Union, List, Callable, Any, Optional, Tuple
 
 # Our documentation generation framework doesn't properly handle __file__,
 # so we call it __file__ and edit it later.
@@ -134,7 +134,7 @@ def set_team(team_id: str) -> None:
   """
   pass
 
-def sync(local_path: str, remote_path: str) -> LiveUpdateStep:
+def sync(local_path: str, remote_path: str, delta: bool = False) -> LiveUpdateStep:
   """Specify that any changes to `localPath` should be synced to `remotePath`
 
   May not follow any `run` steps in a `live_update`.
@@ -145,6 +145,30 @@ def sync(local_path: str, remote_path: str) -> LiveUpdateStep:
       localPath: A path relative to the Tiltfile's directory. Changes to files matching this path will be synced to `remotePath`.
           Can be a file (in which case just that file will be synced) or directory (in which case any files recursively under that directory will be synced).
       remotePath: container path to which changes will be synced. Must be absolute.
+      delta: If True, large files that already exist in the container are updated by sending only the blocks that
+          changed, instead of the whole file. Useful for big binaries (e.g., compiled artifacts) where a rebuild only
+          touches a small part of the file. Requires ``sh``, ``dd``, and ``md5sum`` in the container; if they're missing,
+          Tilt falls back to copying the whole file.
+  """
+  pass
+
+def sync_back(remote_path: str, local_path: str) -> LiveUpdateStep:
+  """Specify that `remote_path` should be copied from the container back to `local_path`
+  after the `run` steps of a live update finish.
+
+  Useful for files that are generated in the container, like lockfiles updated by
+  ``npm install`` or generated code, that you want to keep on your machine.
+  Only files whose contents changed are written, and Tilt ignores the file changes that
+  it caused, so a sync_back doesn't trigger another update.
+
+  Files are only synced back if at least one `run` step ran and they all succeeded.
+  Files deleted in the container aren't deleted locally.
+
+  For more info, see the `Live Update Reference <live_update_reference.html>`_.
+
+  Args:
+      remote_path: container path to copy. Must be absolute. Can be a file or a directory.
+      local_path: A path relative to the Tiltfile's directory to copy `remote_path` to.
   """
   pass
 
@@ -194,8 +218,10 @@ def docker_build(ref: str,
                  extra_tag: Union[str, List[str]] = "",
                  container_args: List[str] = None,
                  cache_from: Union[str, List[str]] = [],
+                 cache_to: Union[str, List[str]] = [],
                  pull: bool = False,
-                 platform: str = "") -> None:
+                 platform: str = "",
+                 platforms: List[str] = []) -> None:
   """Builds a docker image.
 
   The invocation
@@ -237,9 +263,11 @@ def docker_build(ref: str,
     secret: Include secrets in your build in a way that won't show up in the image. Uses the same syntax as the `docker build --secret flag <https://docs.docker.com/develop/develop-images/build_enhancements/#new-docker-build-secret-information>`_.
     extra_tag: Tag an image with one or more extra references after each build. Useful when running Tilt in a CI pipeline, where you want each image to be tagged with the pipeline ID so you can find it later. Uses the same syntax as the ``docker build --tag`` flag.
     container_args: args to run when this container starts. Takes precedence over a `container args specified in k8s YAML <https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/>`_.
-    cache_from: Cache image builds from a remote registry. Uses the same syntax as `docker build --cache-from flag <https://docs.docker.com/engine/reference/commandline/build/#specifying-external-cache-sources>`_.
+    cache_from: Cache image builds from a remote registry or a local directory. Either an image reference, like the `docker build --cache-from flag <https://docs.docker.com/engine/reference/commandline/build/#specifying-external-cache-sources>`_, or a Buildkit cache source, like ``type=registry,ref=gcr.io/myproj/cache`` or ``type=local,src=.cache/build``.
+    cache_to: Export the build cache to a remote registry or a local directory, so that later builds (e.g., in ``tilt ci``) can import it with ``cache_from``. Uses the same syntax as the `docker buildx build --cache-to flag <https://docs.docker.com/engine/reference/commandline/buildx_build/#cache-to>`_, e.g., ``type=registry,ref=gcr.io/myproj/cache,mode=max``, ``type=local,dest=.cache/build``, or ``type=inline``. Requires Buildkit.
     pull: Force pull the latest version of parent images. Equivalent to the ``docker build --pull`` flag.
-    platform: Target platform for build (e.g. ``linux/amd64``). Defaults to the value of the ``DOCKER_DEFAULT_PLATFORM`` environment variable. Equivalent to the ``docker build --platform`` flag.
+    platform: Target platform for build (e.g. ``linux/amd64``). Defaults to the value of the ``DOCKER_DEFAULT_PLATFORM`` environment variable, or the architecture of the cluster. Equivalent to the ``docker build --platform`` flag.
+    platforms: Target platforms for a multi-platform build (e.g. ``['linux/amd64', 'linux/arm64']``). Builds a single image with a manifest list, like ``docker buildx build --platform``. Requires Buildkit, and a Docker image store that supports multi-platform images. Cannot be combined with ``platform``.
   """
   pass
 
@@ -283,7 +311,7 @@ def docker_compose(configPaths: Union[str, Blob, List[Union[str, Blob]]], env_fi
 
 
 
-def k8s_yaml(yaml: Union[str, List[str], Blob], allow_duplicates: bool = False) -> None:
+def k8s_yaml(yaml: Union[str, List[str], Blob], allow_duplicates: bool = False, cluster: str = "") -> None:
   """Call this with a path to a file that contains YAML, or with a ``Blob`` of YAML.
 
   We will infer what (if any) of the k8s resources defined in your YAML
@@ -312,7 +340,70 @@ def k8s_yaml(yaml: Union[str, List[str], Blob], allow_duplicates: bool = False)
     allow_duplicates: If you try to register the same Kubernetes
       resource twice, this function will assume this is a mistake and emit an error.
       Set allow_duplicates=True to allow duplicates. There are some Helm charts
-      that have duplicate resources for esoteric reasons.
+      that have duplicate resources for esoteric reasons. The same resource may
+      be registered once for each cluster.
+    cluster: The name of a cluster declared with :meth:`k8s_cluster` to deploy this
+      YAML to. By default, the YAML deploys to the cluster of the resource it ends up in
+      (see :meth:`k8s_resource`), or else to the cluster from your current kubectl context.
+  """
+  pass
+
+
+def k8s_cluster(name: str, context: str = "", namespace: str = "") -> None:
+  """Declares an additional Kubernetes cluster to deploy to.
+
+  By default, Tilt deploys everything to the cluster of your current kubectl
+  context. Use ``k8s_cluster`` to deploy some resources to another cluster,
+  then pass its name as the ``cluster`` argument of :meth:`k8s_yaml` or
+  :meth:`k8s_resource`.
+
+  Each cluster gets its own connection, registry detection, pod log streams,
+  and port forwards. Images are pushed to (or loaded into) the cluster that
+  the resource deploys to.
+
+  Example:
+
+  .. code-block:: python
+
+    k8s_cluster('staging', context='gke_my-project_us-east1_staging')
+    k8s_yaml('frontend.yaml')
+    k8s_yaml('backend.yaml', cluster='staging')
+
+  Args:
+    name: The name of the cluster. Must be a valid Kubernetes object name.
+      The names ``default`` and ``docker`` are reserved.
+    context: The kubeconfig context to connect with. Defaults to the current context.
+    namespace: The default namespace for objects that don't specify one.
+      Defaults to the namespace of the context.
+  """
+  pass
+
+
+def buildkit_builder(host: str, cluster: str = "default", load_to_containerd: bool = False) -> None:
+  """Builds the images for a cluster with a Buildkit daemon, instead of Docker.
+
+  Use this for clusters that run containerd and don't have a Docker daemon.
+  Tilt connects to ``buildkitd`` directly, so you don't need Docker installed.
+
+  By default, Tilt pushes the images to the cluster's registry.
+  If Buildkit shares its containerd with the cluster (e.g., ``buildkitd``
+  runs on the node), set ``load_to_containerd=True`` to skip the registry.
+
+  Example:
+
+  .. code-block:: python
+
+    buildkit_builder('tcp://buildkitd.example.com:1234')
+    docker_build('frontend', '.')
+
+  Args:
+    host: The address of ``buildkitd``, like ``unix:///run/buildkit/buildkitd.sock``
+      or ``tcp://buildkitd.example.com:1234``. To use a ``buildkitd`` that runs
+      in the cluster, expose it with a Service or a port-forward.
+    cluster: The cluster to build images for. Must be ``default`` or a cluster
+      declared with :meth:`k8s_cluster`.
+    load_to_containerd: Whether the images land in the cluster's containerd
+      image store, so that they don't need to be pushed.
   """
   pass
 
@@ -389,6 +480,59 @@ def k8s_custom_deploy(name: str,
   """
   pass
 
+def helm_release(name: str,
+                 chart: str,
+                 release_name: str="",
+                 namespace: str="",
+                 values: List[str]=[],
+                 set: Union[str, List[str]]=[],
+                 flags: Union[str, List[str]]=[],
+                 deps: List[str]=[],
+                 image_deps: List[str]=[],
+                 image_keys: List[Union[str, Tuple[str, str]]]=[]) -> None:
+  """Deploy a Helm chart as a Helm release.
+
+  Unlike :meth:`helm`, which renders the chart with ``helm template`` and
+  deploys the YAML itself, ``helm_release`` runs ``helm upgrade --install``
+  on every update and ``helm uninstall`` on ``tilt down``. Use it for charts
+  that rely on hooks, release history, or ``helm`` commands outside of Tilt.
+
+  Tilt reads the release's objects back from the cluster after every install,
+  so pod logs, port forwards, and Live Update work the same as with :meth:`k8s_yaml`.
+
+  Port forwards and other behavior can be configured using :meth:`k8s_resource`
+  using the ``name`` as specified here.
+
+  Requires Helm 3 and ``kubectl``. The deploy commands are run with ``sh``,
+  or with PowerShell on Windows.
+
+  Example ::
+
+    docker_build('my-app-image', '.')
+    helm_release('my-app', './charts/my-app',
+                 values=['./charts/values-dev.yaml'],
+                 image_deps=['my-app-image'])
+
+  Args:
+    name: resource name to use in Tilt UI and for further customization via :meth:`k8s_resource`
+    chart: path to a local chart directory, or a reference to a chart in a repository (e.g., ``bitnami/redis``).
+      Local charts are watched for changes.
+    release_name: name of the Helm release. Defaults to ``name``.
+    namespace: namespace to install the release into. Equivalent to the helm ``--namespace`` flag.
+    values: values files to pass to Helm. Equivalent to the helm ``--values`` flag. Values files are watched for changes.
+    set: values to set on the command line. Equivalent to the helm ``--set`` flag.
+    flags: additional flags to pass to ``helm upgrade``, e.g., ``['--create-namespace', '--atomic']``.
+    deps: additional paths to watch and trigger a re-install on change.
+    image_deps: a list of image builds that this release depends on. The built images are
+      injected into the chart's values with ``--set``.
+    image_keys: for each image in ``image_deps``, where to inject it into the chart's values.
+      Each entry is either a single key that takes the whole image reference (e.g., ``'image'``),
+      or a tuple of keys that take the repository and the tag (e.g., ``('image.repository', 'image.tag')``).
+      If there's only one image, defaults to ``('image.repository', 'image.tag')``, which matches
+      the layout of charts generated by ``helm create``. Required if there's more than one image.
+  """
+  pass
+
 
 class TriggerMode:
   """A set of constants that describe how Tilt triggers an update for a resource.
@@ -457,7 +601,9 @@ def k8s_resource(workload: str = "", new_name: str = "",
                  pod_readiness: str = "",
                  links: Union[str, Link, List[Union[str, Link]]]=[],
                  labels: Union[str, List[str]] = [],
-                 discovery_strategy: str = "") -> None:
+                 discovery_strategy: str = "",
+                 log_format: str = "",
+                 cluster: str = "") -> None:
   """
 
   Configures or creates the specified Kubernetes resource.
@@ -556,6 +702,14 @@ def k8s_resource(workload: str = "", new_name: str = "",
       `Accessing Resource Endpoints <accessing_resource_endpoints.html#arbitrary-links>`_.
     labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
     discovery_strategy: Possible values: '', 'default', 'selectors-only'. When '' or 'default', Tilt both uses `extra_pod_selectors` and traces k8s owner references to identify this resource's pods. When 'selectors-only', Tilt uses only `extra_pod_selectors`.
+    log_format: Possible values: '', 'json', 'logfmt'. When set, Tilt parses each line of this resource's pod logs
+      in that format, and promotes its keys (like ``level``, ``msg``, and ``trace_id``) to log fields that you can
+      filter on (e.g., ``tilt logs --field level=error``). Lines that don't parse are shown as-is. Keys that Tilt
+      uses for its own log fields (``progressID``, ``progressMustPrint``, ``buildEvent``) are prefixed with ``log.``.
+    cluster: The name of a cluster declared with :meth:`k8s_cluster`. When set, objects in this
+      resource that were loaded without ``k8s_yaml(..., cluster=...)`` deploy to that cluster, and
+      objects loaded for a different cluster are an error. By default, the resource deploys to
+      the cluster of its objects.
   """
   pass
 
@@ -704,12 +858,15 @@ def watch_file(file_path: str) -> None:
 
 def kustomize(pathToDir: str, kustomize_bin: str = None) -> Blob:
   """Run `kustomize <https://github.com/kubernetes-sigs/kustomize>`_ on a given directory and return the resulting YAML as a Blob
-  Directory is watched (see ``watch_file``). Checks for and uses separately installed kustomize first, if it exists. Otherwise,
-  uses kubectl's kustomize. See `blog post <https://blog.tilt.dev/2020/02/04/are-you-my-kustomize.html>`_.
+
+  Tilt renders the kustomization with a built-in copy of kustomize, so no separate install is needed.
+  Every file that kustomize reads (kustomization files, bases, patches, etc.) is watched (see ``watch_file``).
+  If the build fails, the error names the kustomization file that caused it.
 
   Args:
     pathToDir: Path to the directory locally (absolute, or relative to the location of the Tiltfile).
-    kustomize_bin: Custom path to the ``kustomize`` binary executable. Defaults to searching $PATH for kustomize."""
+    kustomize_bin: Custom path to a ``kustomize`` binary executable. If set, Tilt runs ``kustomize build`` with
+      this binary instead of the built-in kustomize, and watches the kustomization's directories."""
   pass
 
 def helm(pathToChartDir: str, name: str = "", namespace: str = "", values: Union[str, List[str]]=[], set: Union[str, List[str]]=[], kube_version: str = "") -> Blob:
@@ -1101,7 +1258,12 @@ def local_resource(name: str,
                    readiness_probe: Probe = None,
                    dir: str = "",
                    serve_dir: str = "",
-                   labels: List[str] = []) -> None:
+                   labels: List[str] = [],
+                   teardown_cmd: Union[str, List[str]] = "",
+                   teardown_cmd_bat: Union[str, List[str]] = "",
+                   teardown_env: Dict[str, str] = {},
+                   teardown_dir: str = "",
+                   teardown_timeout: str = "") -> None:
   """Configures one or more commands to run on the *host* machine (not in a remote cluster).
 
   By default, Tilt performs an update on local resources on ``tilt up`` and whenever any of their ``deps`` change.
@@ -1144,6 +1306,13 @@ def local_resource(name: str,
     dir: Working directory for ``cmd``. Defaults to the Tiltfile directory.
     serve_dir: Working directory for ``serve_cmd``. Defaults to the Tiltfile directory.
     labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed seperately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
+    teardown_cmd: command to run on the host machine when the resource is deleted by ``tilt down``.
+      Teardowns run in reverse dependency order, i.e., a resource is torn down before the resources it depends on.
+    teardown_cmd_bat: If non-empty and on Windows, takes precedence over ``teardown_cmd``. Ignored on other platforms.
+    teardown_env: Environment variables to pass to the executed ``teardown_cmd``.
+    teardown_dir: Working directory for ``teardown_cmd``. Defaults to the Tiltfile directory.
+    teardown_timeout: how long to wait for ``teardown_cmd`` before giving up (e.g., ``"1m"``). Must not be negative.
+      Defaults to 30s, which is also what ``"0s"`` means.
   """
   pass
 
@@ -1252,7 +1421,16 @@ def update_settings(
       Accepts a list of image names, or '*' to suppress warnings for all images.
 """
 
-def watch_settings(ignore: Union[str, List[str]]) -> None:
+def ci_settings(gate: Union[str, List[str]]=[]) -> None:
+  """Configures how ``tilt ci`` decides when it's done.
+
+  Args:
+    gate: one or more resource names. If specified, ``tilt ci`` exits once these resources
+      are ready or have finished, and only fails if one of them fails. Other resources still
+      run, but their failures are ignored. Overridden by ``tilt ci --gate``.
+"""
+
+def watch_settings(ignore: Union[str, List[str]] = [], content_hash: bool = False) -> None:
   """Configures global watches.
 
   May be called multiple times to add more ignore patterns.
@@ -1261,6 +1439,10 @@ def watch_settings(ignore: Union[str, List[str]]) -> None:
     ignore: A string or list of strings that should not trigger updates. Equivalent to adding
       patterns to .tiltignore. Relative patterns are evaluated relative to the current working dir.
       See `Debugging File Changes <file_changes.html>`_ for more details.
+    content_hash: If True, Tilt keeps a hash of each watched file, and ignores changes that don't
+      actually change the file contents (e.g., a ``git checkout`` back and forth, a formatter that
+      rewrites identical bytes, or a ``touch``). Tilt reads every watched file at startup,
+      so this works best with ``ignore`` patterns for large generated directories.
   """
 
 
@@ -1,4 +1,4 @@
-from typing import Dict, Union, List, Callable, Any
+from typing import Dict, Union, List, Callable, Any, Optional
 
 tilt_subcommand: str = ''
 """The sub-command with which `tilt` was invoked. Does not include extra args or options.
@@ -18,7 +18,7 @@ main_dir: str = ''
 
 Often used to determine the location of vendored code and caches."""
 
-def define_string_list(name: str, args: bool=False, usage: str="") -> None:
+def define_string_list(name: str, args: bool=False, usage: str="", required: bool=False, default: Optional[List[str]]=None) -> None:
     """
     Defines a config setting of type `List[str]`.
 
@@ -35,10 +35,14 @@ def define_string_list(name: str, args: bool=False, usage: str="") -> None:
 
             If True, the config setting is specified by unnamed positional args. (e.g.,
             in ``tilt up -- 1 2 3``, this setting would be ``["1" "2" "3"]``.)
-      usage: When arg parsing fails, what to print for this setting's description.
+      usage: When arg parsing fails, or in ``tilt args --help``, what to print for this setting's description.
+      required: If True, :meth:`parse` fails if the setting isn't specified in
+                tilt_config.json or the command-line args.
+      default: The value to use if the setting isn't specified. Must be a valid
+               value for the setting. Cannot be combined with ``required``.
     """
 
-def define_string(name: str, args: bool=False, usage: str="") -> None:
+def define_string(name: str, args: bool=False, usage: str="", required: bool=False, default: Optional[str]=None) -> None:
     """
     Defines a config setting of type `str`.
 
@@ -57,10 +61,14 @@ def define_string(name: str, args: bool=False, usage: str="") -> None:
 
             If True, the config setting is specified by unnamed positional args. (e.g.,
             in ``tilt up -- 1``, this setting would be ``"1"``.)
-      usage: When arg parsing fails, what to print for this setting's description.
+      usage: When arg parsing fails, or in ``tilt args --help``, what to print for this setting's description.
+      required: If True, :meth:`parse` fails if the setting isn't specified in
+                tilt_config.json or the command-line args.
+      default: The value to use if the setting isn't specified. Must be a valid
+               value for the setting. Cannot be combined with ``required``.
     """
 
-def define_bool(name: str, args: bool=False, usage: str="") -> None:
+def define_bool(name: str, args: bool=False, usage: str="", required: bool=False, default: Optional[bool]=None) -> None:
     """
     Defines a config setting of type `bool`.
 
@@ -70,9 +78,9 @@ def define_bool(name: str, args: bool=False, usage: str="") -> None:
     For instance, at runtime, to set a flag of this type named `foo` to value `True`, run ``tilt up -- --foo``.
     To set a value to ``False``, you can run ``tilt up -- --foo=False``, or use a default value, e.g.::
 
-      config.define_bool('foo')
+      config.define_bool('foo', default=False)
       cfg = config.parse()
-      do_stuff = cfg.get('foo', False)
+      do_stuff = cfg['foo']
 
     See the `Tiltfile config documentation <tiltfile_config.html>`_ for examples
     and more information.
@@ -85,18 +93,85 @@ def define_bool(name: str, args: bool=False, usage: str="") -> None:
             If True, the config setting is specified by unnamed positional args. (e.g.,
             in ``tilt up -- True``, this setting would be ``True``.) (This usage
             isn't likely to be what you want)
-      usage: When arg parsing fails, what to print for this setting's description.
+      usage: When arg parsing fails, or in ``tilt args --help``, what to print for this setting's description.
+      required: If True, :meth:`parse` fails if the setting isn't specified in
+                tilt_config.json or the command-line args.
+      default: The value to use if the setting isn't specified. Must be a valid
+               value for the setting. Cannot be combined with ``required``.
+    """
+
+def define_int(name: str, args: bool=False, usage: str="", required: bool=False, default: Optional[int]=None) -> None:
+    """
+    Defines a config setting of type `int`.
+
+    Allows the user invoking Tilt to configure a key named ``name`` to be in the
+    dict returned by :meth:`parse`.
+
+    For instance, at runtime, to set a flag of this type named `replicas` to value 3, run ``tilt up -- --replicas 3``.
+
+    See the `Tiltfile config documentation <tiltfile_config.html>`_ for examples
+    and more information.
+
+    Args:
+      name: The name of the config setting
+      args: If False, the config setting is specified by its name. (e.g., if it's named "foo",
+            ``tilt up -- --foo 3`` this setting would be ``3``.)
+
+            If True, the config setting is specified by unnamed positional args. (e.g.,
+            in ``tilt up -- 3``, this setting would be ``3``.)
+      usage: When arg parsing fails, or in ``tilt args --help``, what to print for this setting's description.
+      required: If True, :meth:`parse` fails if the setting isn't specified in
+                tilt_config.json or the command-line args.
+      default: The value to use if the setting isn't specified. Must be a valid
+               value for the setting. Cannot be combined with ``required``.
+    """
+
+def define_enum(name: str, choices: List[str], args: bool=False, usage: str="", required: bool=False, default: Optional[str]=None) -> None:
+    """
+    Defines a config setting of type `str`, which must be one of ``choices``.
+
+    Allows the user invoking Tilt to configure a key named ``name`` to be in the
+    dict returned by :meth:`parse`.
+
+    For example::
+
+      config.define_enum('env', choices=['dev', 'staging'], default='dev')
+      cfg = config.parse()
+
+    lets you run ``tilt up -- --env staging``, and fails on ``tilt up -- --env prod``.
+
+    See the `Tiltfile config documentation <tiltfile_config.html>`_ for examples
+    and more information.
+
+    Args:
+      name: The name of the config setting
+      choices: The allowed values of the setting.
+      args: If False, the config setting is specified by its name. (e.g., if it's named "foo",
+            ``tilt up -- --foo dev`` this setting would be ``"dev"``.)
+
+            If True, the config setting is specified by unnamed positional args. (e.g.,
+            in ``tilt up -- dev``, this setting would be ``"dev"``.)
+      usage: When arg parsing fails, or in ``tilt args --help``, what to print for this setting's description.
+      required: If True, :meth:`parse` fails if the setting isn't specified in
+                tilt_config.json or the command-line args.
+      default: The value to use if the setting isn't specified. Must be a valid
+               value for the setting. Cannot be combined with ``required``.
     """
 
 def parse() -> Dict[str, Any]:
     """
     Loads config settings from tilt_config.json, overlays config settings from
-    Tiltfile command-line args, validates them using the setting definitions
-    specified in the Tiltfile, and returns a Dict of the resulting settings.
+    the ``config`` section of the user's tilt_overrides.json (next to the main
+    Tiltfile) and then from Tiltfile command-line args, validates them using
+    the setting definitions specified in the Tiltfile, and returns a Dict of
+    the resulting settings.
 
     Settings that are defined in the Tiltfile but not specified in the config
-    file or command-line args will be absent from the dict. Access values via,
-    e.g., `cfg.get('foo', ["hello"])` to have a default value.
+    file or command-line args take their ``default`` value, if they have one.
+    Otherwise, they will be absent from the dict. Access values via,
+    e.g., `cfg.get('foo', ["hello"])` to have a fallback value.
+
+    Fails if a setting with ``required=True`` isn't specified.
 
     Note: by default, Tilt interprets the Tilt command-line args as the names of
     Tilt resources to run. When a Tiltfile calls :meth:`parse`, that behavior is
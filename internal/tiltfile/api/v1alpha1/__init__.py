@@ -337,6 +337,7 @@ def file_watch(
   watched_paths: List[str] = None,
   ignores: List[IgnoreDef] = None,
   disable_source: Optional[DisableSource] = None,
+  content_hash: bool = False,
 ):
   """
   FileWatch
@@ -350,6 +351,12 @@ def file_watch(
     ignores: Ignores are optional rules to filter out a subset of changes matched by WatchedPaths.
     disable_source: Specifies how to disable this.
       
+    content_hash: ContentHash enables content-hash change detection.
+      
+      The watcher keeps a hash of each watched file, and drops events for files
+      whose contents didn't actually change (e.g., a formatter that rewrites
+      identical bytes, or a touch).
+      
 """
   pass
 def kubernetes_apply(
@@ -819,6 +826,7 @@ def object_selector(
 def pod_log_stream_template_spec(
   only_containers: List[str] = None,
   ignore_containers: List[str] = None,
+  format: str = "",
 ) -> PodLogStreamTemplateSpec:
   """
   PodLogStreamTemplateSpec describes common attributes for PodLogStreams
@@ -835,6 +843,10 @@ def pod_log_stream_template_spec(
       If `onlyContainers` and `ignoreContainers` are not set,
       will watch all containers in the pod.
       
+    format: How to parse log lines into structured fields.
+      
+      If not set, log lines are not parsed.
+      
 """
   pass
 
@@ -0,0 +1,40 @@
+package tiltfile
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func (s *tiltfileState) buildkitBuilder(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var host, cluster string
+	var loadToContainerd bool
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"host", &host,
+		"cluster?", &cluster,
+		"load_to_containerd?", &loadToContainerd,
+	); err != nil {
+		return nil, err
+	}
+
+	if host == "" {
+		return nil, fmt.Errorf("%s: host must not be empty", fn.Name())
+	}
+	if err := s.validateClusterName(cluster); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	if cluster == "" {
+		cluster = v1alpha1.ClusterNameDefault
+	}
+	if _, ok := s.buildkitBuilders[cluster]; ok {
+		return nil, fmt.Errorf("%s: cluster %q already has a Buildkit builder", fn.Name(), cluster)
+	}
+
+	s.buildkitBuilders[cluster] = v1alpha1.BuildkitClusterConnection{
+		Host:             host,
+		LoadToContainerd: loadToContainerd,
+	}
+	return starlark.None, nil
+}
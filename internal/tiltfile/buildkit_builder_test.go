@@ -0,0 +1,53 @@
+package tiltfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestBuildkitBuilder(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+buildkit_builder('unix:///run/buildkit/buildkitd.sock')
+buildkit_builder('tcp://buildkitd:1234', cluster='staging', load_to_containerd=True)
+`)
+
+	f.load()
+
+	assert.Equal(t, map[string]v1alpha1.BuildkitClusterConnection{
+		"default": {Host: "unix:///run/buildkit/buildkitd.sock"},
+		"staging": {Host: "tcp://buildkitd:1234", LoadToContainerd: true},
+	}, f.loadResult.BuildkitBuilders)
+}
+
+func TestBuildkitBuilderUnknownCluster(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `buildkit_builder('tcp://buildkitd:1234', cluster='staging')`)
+
+	f.loadErrString(`buildkit_builder: unknown cluster "staging". Declare it with k8s_cluster() first.`)
+}
+
+func TestBuildkitBuilderDuplicate(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+buildkit_builder('tcp://buildkitd:1234')
+buildkit_builder('tcp://buildkitd:5678', cluster='default')
+`)
+
+	f.loadErrString(`buildkit_builder: cluster "default" already has a Buildkit builder`)
+}
+
+func TestBuildkitBuilderEmptyHost(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `buildkit_builder('')`)
+
+	f.loadErrString(`buildkit_builder: host must not be empty`)
+}
@@ -0,0 +1,60 @@
+package cisettings
+
+import (
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Implements functions for dealing with `tilt ci` settings.
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (e Plugin) NewState() interface{} {
+	return model.CISettings{}
+}
+
+func (e Plugin) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("ci_settings", e.ciSettings)
+}
+
+func (e Plugin) ciSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var gate value.StringOrStringList
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"gate?", &gate); err != nil {
+		return nil, err
+	}
+
+	err := starkit.SetState(thread, func(settings model.CISettings) model.CISettings {
+		if len(gate.Values) != 0 {
+			settings.GateResources = nil
+			for _, name := range gate.Values {
+				settings.GateResources = append(settings.GateResources, model.ManifestName(name))
+			}
+		}
+		return settings
+	})
+
+	return starlark.None, err
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) model.CISettings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.CISettings, error) {
+	var state model.CISettings
+	err := m.Load(&state)
+	return state, err
+}
@@ -0,0 +1,54 @@
+package cisettings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestGate(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+ci_settings(gate=['e2e', 'lint'])
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, []model.ManifestName{"e2e", "lint"}, MustState(result).GateResources)
+}
+
+func TestGateString(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+ci_settings(gate='e2e')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, []model.ManifestName{"e2e"}, MustState(result).GateResources)
+}
+
+func TestGateLastCallWins(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+ci_settings(gate=['e2e'])
+ci_settings(gate=['lint'])
+ci_settings()
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, []model.ManifestName{"lint"}, MustState(result).GateResources)
+}
+
+func TestGateDefault(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "")
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Empty(t, MustState(result).GateResources)
+}
+
+func newFixture(tb testing.TB) *starkit.Fixture {
+	return starkit.NewFixture(tb, NewPlugin())
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
@@ -14,6 +15,12 @@ import (
 
 const UserConfigFileName = "tilt_config.json"
 
+// UserOverridesFileName is the per-user overrides file next to the main Tiltfile.
+//
+// Its "config" section takes precedence over tilt_config.json, but not over
+// args passed on the command line.
+const UserOverridesFileName = "tilt_overrides.json"
+
 type Settings struct {
 	disableAll       bool
 	enabledResources []model.ManifestName
@@ -27,12 +34,53 @@ type Settings struct {
 
 type Plugin struct {
 	tiltSubcommand model.TiltSubcommand
+
+	mu           sync.Mutex
+	describeOnly bool
+	described    *string
 }
 
 func NewPlugin(tiltSubcommand model.TiltSubcommand) *Plugin {
 	return &Plugin{tiltSubcommand: tiltSubcommand}
 }
 
+// SetDescribeOnly makes config.parse() stop executing the Tiltfile,
+// after recording the usage of the settings that were defined.
+//
+// Used to describe the Tiltfile's args without running the rest of the Tiltfile.
+func (e *Plugin) SetDescribeOnly(describeOnly bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.describeOnly = describeOnly
+	e.described = nil
+}
+
+// DescribedUsage returns the usage of the settings that the Tiltfile
+// defined, if config.parse() was called in describe-only mode.
+func (e *Plugin) DescribedUsage() (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.described == nil {
+		return "", false
+	}
+	return *e.described, true
+}
+
+// If we're in describe-only mode, records the usage and returns
+// an error that stops the Tiltfile.
+func (e *Plugin) maybeDescribe(cd ConfigDef) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.describeOnly {
+		return nil
+	}
+	usage := cd.Usage()
+	e.described = &usage
+	return errDescribeOnly
+}
+
+var errDescribeOnly = errors.New("stopped at config.parse() to describe the Tiltfile args")
+
 func (e *Plugin) NewState() interface{} {
 	return Settings{
 		configDef: ConfigDef{configSettings: make(map[string]configSetting)},
@@ -75,6 +123,10 @@ func (e *Plugin) OnStart(env *starkit.Environment) error {
 		{"config.define_object", configSettingDefinitionBuiltin(func() configValue {
 			return &objectSetting{}
 		})},
+		{"config.define_int", configSettingDefinitionBuiltin(func() configValue {
+			return &intSetting{}
+		})},
+		{"config.define_enum", defineEnum},
 	} {
 		err := env.AddBuiltin(b.name, b.f)
 		if err != nil {
@@ -138,6 +190,11 @@ func (e *Plugin) parse(thread *starlark.Thread, fn *starlark.Builtin, args starl
 		return starlark.None, err
 	}
 
+	err = e.maybeDescribe(settings.configDef)
+	if err != nil {
+		return starlark.None, err
+	}
+
 	userConfigPath := filepath.Join(wd, UserConfigFileName)
 
 	err = io.RecordReadPath(thread, io.WatchFileOnly, userConfigPath)
@@ -145,7 +202,14 @@ func (e *Plugin) parse(thread *starlark.Thread, fn *starlark.Builtin, args starl
 		return starlark.None, err
 	}
 
-	ret, out, err := settings.configDef.parse(userConfigPath, tf.Spec.Args)
+	overridesPath := filepath.Join(filepath.Dir(tf.Spec.Path), UserOverridesFileName)
+
+	err = io.RecordReadPath(thread, io.WatchFileOnly, overridesPath)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	ret, out, err := settings.configDef.parse(userConfigPath, overridesPath, tf.Spec.Args)
 	if out != "" {
 		thread.Print(thread, out)
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -11,7 +12,9 @@ import (
 	flag "github.com/spf13/pflag"
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 )
 
 type configValue interface {
@@ -26,6 +29,20 @@ type configMap map[string]configValue
 type configSetting struct {
 	newValue func() configValue
 	usage    string
+	required bool
+
+	// The value to use when the setting isn't specified, as structured data.
+	// nil if there's no default.
+	defaultValue interface{}
+}
+
+func (s configSetting) newDefaultValue() (configValue, error) {
+	v := s.newValue()
+	err := v.setFromInterface(s.defaultValue)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
 }
 
 type ConfigDef struct {
@@ -73,17 +90,28 @@ func (cd ConfigDef) incorporateArgs(config configMap, args []string) (ret config
 	return config, output, nil
 }
 
-func (cd ConfigDef) parse(configPath string, args []string) (v starlark.Value, output string, err error) {
+func (cd ConfigDef) parse(configPath string, overridesPath string, args []string) (v starlark.Value, output string, err error) {
 	config, err := cd.readFromFile(configPath)
 	if err != nil {
 		return starlark.None, "", err
 	}
 
+	overrides, err := cd.readFromOverridesFile(overridesPath)
+	if err != nil {
+		return starlark.None, "", err
+	}
+	config = mergeConfigMaps(config, overrides)
+
 	config, output, err = cd.incorporateArgs(config, args)
 	if err != nil {
 		return starlark.None, output, err
 	}
 
+	config, err = cd.applyDefaults(config, configPath)
+	if err != nil {
+		return starlark.None, output, err
+	}
+
 	ret, err := config.toStarlark()
 	if err != nil {
 		return nil, output, err
@@ -92,6 +120,100 @@ func (cd ConfigDef) parse(configPath string, args []string) (v starlark.Value, o
 	return ret, output, nil
 }
 
+// fill in defaults for settings that weren't specified, and make sure that
+// all required settings were
+func (cd ConfigDef) applyDefaults(config configMap, configPath string) (configMap, error) {
+	var missing []string
+	for name, def := range cd.configSettings {
+		if v, ok := config[name]; ok && v.IsSet() {
+			continue
+		}
+
+		if def.defaultValue != nil {
+			v, err := def.newDefaultValue()
+			if err != nil {
+				return nil, fmt.Errorf("invalid default for setting %s: %v", name, err)
+			}
+			config[name] = v
+			continue
+		}
+
+		if def.required {
+			if name == cd.positionalSettingName {
+				missing = append(missing, fmt.Sprintf("%s (positional args)", name))
+			} else {
+				missing = append(missing, fmt.Sprintf("%s (--%s)", name, name))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required Tiltfile config settings: %s. Specify them as Tiltfile args, or in %s",
+			strings.Join(missing, ", "), configPath)
+	}
+	return config, nil
+}
+
+// Usage describes the settings, in the style of CLI flag help.
+func (cd ConfigDef) Usage() string {
+	var sb strings.Builder
+	flags := cd.flagUsages()
+	if strings.TrimSpace(flags) != "" {
+		sb.WriteString(flags)
+	}
+
+	if cd.positionalSettingName != "" {
+		def := cd.configSettings[cd.positionalSettingName]
+		fs := flag.NewFlagSet("", flag.ContinueOnError)
+		cd.addUsageFlag(fs, cd.positionalSettingName, def)
+		name, usage := flag.UnquoteUsage(fs.Lookup(cd.positionalSettingName))
+		line := fmt.Sprintf("  [%s...]", cd.positionalSettingName)
+		if name != "" {
+			line += " " + name
+		}
+		if usage != "" {
+			line += "   " + usage
+		}
+		sb.WriteString("\nPositional args:\n" + line + "\n")
+	}
+	return sb.String()
+}
+
+// Usage for all the settings that can be specified as flags.
+func (cd ConfigDef) flagUsages() string {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	for name, def := range cd.configSettings {
+		if name == cd.positionalSettingName {
+			continue
+		}
+		cd.addUsageFlag(fs, name, def)
+	}
+	return fs.FlagUsagesWrapped(80)
+}
+
+// Registers a flag that's only used for its usage message.
+//
+// The flag's value is set to the default (if any), so that it shows up in the usage.
+func (cd ConfigDef) addUsageFlag(fs *flag.FlagSet, name string, def configSetting) {
+	v := def.newValue()
+	if def.defaultValue != nil {
+		dv, err := def.newDefaultValue()
+		if err == nil {
+			v = dv
+		}
+	}
+
+	usage := def.usage
+	if def.required {
+		usage = strings.TrimSpace(usage + " (required)")
+	}
+	fs.Var(v, name, usage)
+	if _, ok := v.(*boolSetting); ok {
+		fs.Lookup(name).NoOptDefVal = "true"
+	}
+}
+
 // parse command-line args
 func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err error) {
 	fs := flag.NewFlagSet("", flag.ContinueOnError)
@@ -113,7 +235,7 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 
 	err = fs.Parse(args)
 	if err != nil {
-		usage := fs.FlagUsagesWrapped(80)
+		usage := cd.flagUsages()
 		if strings.TrimSpace(usage) != "" {
 			usage = "\nUsage:\n" + usage
 		}
@@ -158,72 +280,152 @@ func (cd ConfigDef) readFromFile(tiltConfigPath string) (ret configMap, err erro
 		return nil, errors.Wrapf(err, "error parsing json from %s", tiltConfigPath)
 	}
 
+	return cd.settingsFromMap(tiltConfigPath, m)
+}
+
+// parse settings from the "config" section of the user's overrides file
+//
+// The rest of the overrides file is validated when the overrides are applied
+// to the resources, so we ignore it here.
+func (cd ConfigDef) readFromOverridesFile(overridesPath string) (ret configMap, err error) {
+	contents, err := os.ReadFile(overridesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(configMap), nil
+		}
+		return nil, errors.Wrapf(err, "error opening %s", overridesPath)
+	}
+
+	var overrides struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	err = jsoniter.Unmarshal(contents, &overrides)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing json from %s", overridesPath)
+	}
+
+	return cd.settingsFromMap(overridesPath, overrides.Config)
+}
+
+func (cd ConfigDef) settingsFromMap(path string, m map[string]interface{}) (configMap, error) {
+	ret := make(configMap)
 	for k, v := range m {
 		def, ok := cd.configSettings[k]
 		if !ok {
-			return nil, fmt.Errorf("%s specified unknown setting name '%s'", tiltConfigPath, k)
+			return nil, fmt.Errorf("%s specified unknown setting name '%s'", path, k)
 		}
 		ret[k] = def.newValue()
-		err = ret[k].setFromInterface(v)
+		err := ret[k].setFromInterface(v)
 		if err != nil {
-			return nil, errors.Wrapf(err, "%s specified invalid value for setting %s", tiltConfigPath, k)
+			return nil, errors.Wrapf(err, "%s specified invalid value for setting %s", path, k)
 		}
 	}
 	return ret, nil
 }
 
+// The arguments common to all the config.define_* builtins.
+type settingDefinition struct {
+	name         string
+	isArgs       bool
+	usage        string
+	required     bool
+	defaultValue starlark.Value
+}
+
+// Unpacks the builtin's arguments. Any extra (required) pairs go after the name.
+func (d *settingDefinition) unpack(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, extra ...interface{}) error {
+	pairs := []interface{}{"name", &d.name}
+	pairs = append(pairs, extra...)
+	pairs = append(pairs,
+		"args?", &d.isArgs,
+		"usage?", &d.usage,
+		"required?", &d.required,
+		"default?", &d.defaultValue,
+	)
+	return starkit.UnpackArgs(thread, fn.Name(), args, kwargs, pairs...)
+}
+
 // makes a new builtin with the given configValue constructor
 // newConfigValue: a constructor for the `configValue` that we're making a function for
 //              (it's the same logic for all types, except for the `configValue` that gets saved)
 func configSettingDefinitionBuiltin(newConfigValue func() configValue) starkit.Function {
 	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-		var name string
-		var isArgs bool
-		var usage string
-		err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
-			"name",
-			&name,
-			"args?",
-			&isArgs,
-			"usage?",
-			&usage,
-		)
+		var def settingDefinition
+		err := def.unpack(thread, fn, args, kwargs)
 		if err != nil {
 			return starlark.None, err
 		}
 
-		if name == "" {
-			return starlark.None, errors.New("'name' is required")
-		}
+		return starlark.None, defineSetting(thread, fn, def, newConfigValue)
+	}
+}
 
-		err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
-			if settings.configParseCalled {
-				return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
-			}
+func defineEnum(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var def settingDefinition
+	var choices value.StringList
+	err := def.unpack(thread, fn, args, kwargs, "choices", &choices)
+	if err != nil {
+		return starlark.None, err
+	}
 
-			if _, ok := settings.configDef.configSettings[name]; ok {
-				return settings, fmt.Errorf("%s defined multiple times", name)
-			}
+	if len(choices) == 0 {
+		return starlark.None, fmt.Errorf("%s: 'choices' must not be empty", fn.Name())
+	}
 
-			if isArgs {
-				if settings.configDef.positionalSettingName != "" {
-					return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
-				}
+	return starlark.None, defineSetting(thread, fn, def, func() configValue {
+		return &enumSetting{choices: choices}
+	})
+}
 
-				settings.configDef.positionalSettingName = name
-			}
+func defineSetting(thread *starlark.Thread, fn *starlark.Builtin, def settingDefinition, newConfigValue func() configValue) error {
+	name := def.name
+	if name == "" {
+		return errors.New("'name' is required")
+	}
 
-			settings.configDef.configSettings[name] = configSetting{
-				newValue: newConfigValue,
-				usage:    usage,
-			}
+	setting := configSetting{
+		newValue: newConfigValue,
+		usage:    def.usage,
+		required: def.required,
+	}
 
-			return settings, nil
-		})
+	if def.defaultValue != nil && def.defaultValue != starlark.None {
+		if def.required {
+			return fmt.Errorf("%s: setting %s cannot be both required and have a default", fn.Name(), name)
+		}
+
+		dv, err := encoding.ConvertStarlarkToStructuredData(def.defaultValue)
 		if err != nil {
-			return starlark.None, err
+			return fmt.Errorf("%s: invalid default for setting %s: %v", fn.Name(), name, err)
 		}
+		setting.defaultValue = dv
 
-		return starlark.None, nil
+		// Make sure the default is valid now, rather than when the config is parsed.
+		_, err = setting.newDefaultValue()
+		if err != nil {
+			return fmt.Errorf("%s: invalid default for setting %s: %v", fn.Name(), name, err)
+		}
 	}
+
+	return starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		if settings.configParseCalled {
+			return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
+		}
+
+		if _, ok := settings.configDef.configSettings[name]; ok {
+			return settings, fmt.Errorf("%s defined multiple times", name)
+		}
+
+		if def.isArgs {
+			if settings.configDef.positionalSettingName != "" {
+				return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
+			}
+
+			settings.configDef.positionalSettingName = name
+		}
+
+		settings.configDef.configSettings[name] = setting
+
+		return settings, nil
+	})
 }
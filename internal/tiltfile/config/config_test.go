@@ -60,7 +60,7 @@ config.parse()`
 			require.NoError(t, err)
 
 			manifests := []model.Manifest{{Name: "a"}, {Name: "b"}}
-			actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests)
+			actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests, nil)
 			require.NoError(t, err)
 
 			require.Equal(t, tc.expectedResources, actual)
@@ -79,7 +79,7 @@ func TestClearEnabledResources(t *testing.T) {
 	require.NoError(t, err)
 
 	manifests := []model.Manifest{{Name: "a"}, {Name: "b"}}
-	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests)
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests, nil)
 	require.NoError(t, err)
 
 	require.Len(t, actual, 0)
@@ -281,6 +281,54 @@ Usage:
 	require.EqualError(t, err, expected)
 }
 
+func TestUsageShowsTypesAndDefaults(t *testing.T) {
+	f := NewFixture(t, []string{"--bar", "hello"}, "")
+
+	f.File("Tiltfile", `
+config.define_enum('env', choices=['dev', 'prod'], default='dev', usage='where to deploy')
+config.define_int('replicas', required=True, usage='how many')
+config.define_string('name', default='api')
+config.parse()
+`)
+
+	expected := `invalid Tiltfile config args: unknown flag: --bar
+Usage:
+      --env dev|prod   where to deploy (default dev)
+      --name string     (default "api")
+      --replicas int   how many (required)
+`
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.EqualError(t, err, expected)
+}
+
+func TestDescribeOnly(t *testing.T) {
+	ext := NewPlugin("args")
+	f := starkit.NewFixture(t, ext, io.NewPlugin(), include.IncludeFn{})
+	f.UseRealFS()
+	ext.SetDescribeOnly(true)
+
+	f.File("Tiltfile", `
+config.define_string_list('to-run', args=True, usage='resources to run')
+config.define_bool('debug', usage='turn on debug logging')
+config.parse()
+fail('should not get here')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stopped at config.parse()")
+
+	usage, ok := ext.DescribedUsage()
+	require.True(t, ok)
+	require.Equal(t, `      --debug   turn on debug logging
+
+Positional args:
+  [to-run...] list[string]   resources to run
+`, usage)
+}
+
 // i.e., tilt up foo bar gets you resources foo and bar
 func TestDefaultTiltBehavior(t *testing.T) {
 	f := NewFixture(t, []string{"foo", "bar"}, "")
@@ -294,7 +342,7 @@ config.set_enabled_resources(config.parse()['resources'])
 	require.NoError(t, err)
 
 	manifests := []model.Manifest{{Name: "foo"}, {Name: "bar"}, {Name: "baz"}}
-	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests)
+	actual, err := MustState(result).EnabledResources(f.Tiltfile(), manifests, nil)
 	require.NoError(t, err)
 	require.Equal(t, []model.ManifestName{"foo", "bar"}, actual)
 }
@@ -411,6 +459,52 @@ print("foo:",cfg.get('foo', []))
 	require.Contains(t, err.Error(), "specified invalid value for setting foo: expected array")
 }
 
+func TestSettingsFromOverridesFile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"overrides trump config", nil, `a= ["3"]`},
+		{"args trump overrides", []string{"--a", "4"}, `a= ["4"]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFixture(t, tc.args, "")
+
+			f.File("Tiltfile", `
+config.define_string_list('a')
+config.define_string_list('b')
+cfg = config.parse()
+print("a=", cfg.get('a', 'missing'))
+print("b=", cfg.get('b', 'missing'))
+`)
+			f.File(UserConfigFileName, `{"a": ["1"], "b": ["2"]}`)
+			f.File(UserOverridesFileName, `{"resources": {"foo": {"disabled": true}}, "config": {"a": ["3"]}}`)
+
+			_, err := f.ExecFile("Tiltfile")
+			require.NoError(t, err)
+
+			require.Contains(t, f.PrintOutput(), tc.expected)
+			require.Contains(t, f.PrintOutput(), `b= ["2"]`)
+		})
+	}
+}
+
+func TestUndefinedArgInOverridesFile(t *testing.T) {
+	f := NewFixture(t, nil, "")
+
+	f.File("Tiltfile", `
+config.define_string_list('foo')
+cfg = config.parse()
+`)
+
+	f.File(UserOverridesFileName, `{"config": {"bar": ["1"]}}`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "tilt_overrides.json specified unknown setting name 'bar'")
+}
+
 func TestConfigParseFromMultipleDirs(t *testing.T) {
 	f := NewFixture(t, nil, "")
 
@@ -582,6 +676,34 @@ func TestTypes(t *testing.T) {
 		newTypeTestCase("obj from config", "config.define_object('foo')").
 			withConfigFile(`{"foo": ["a", "b", "c"]}`).
 			withExpectedVal(`["a", "b", "c"]`),
+
+		newTypeTestCase("int from args", "config.define_int('foo')").withArgs("--foo", "5").withExpectedVal("5"),
+		newTypeTestCase("int from config", "config.define_int('foo')").withConfigFile(`{"foo": 5}`).withExpectedVal("5"),
+		newTypeTestCase("int defined multiple times", "config.define_int('foo')").withArgs("--foo", "1", "--foo", "2").withExpectedError("int settings can only be specified once"),
+		newTypeTestCase("invalid int from args", "config.define_int('foo')").withArgs("--foo", "five").withExpectedError(`invalid argument "five" for "--foo" flag: expected int, got "five"`),
+		newTypeTestCase("invalid int from config", "config.define_int('foo')").withConfigFile(`{"foo": 1.5}`).withExpectedError("specified invalid value for setting foo: expected int, found 1.5"),
+
+		newTypeTestCase("enum from args", "config.define_enum('foo', choices=['dev', 'prod'])").withArgs("--foo", "prod").withExpectedVal("'prod'"),
+		newTypeTestCase("enum from config", "config.define_enum('foo', choices=['dev', 'prod'])").withConfigFile(`{"foo": "dev"}`).withExpectedVal("'dev'"),
+		newTypeTestCase("invalid enum from args", "config.define_enum('foo', choices=['dev', 'prod'])").withArgs("--foo", "staging").withExpectedError(`invalid argument "staging" for "--foo" flag: "staging" is not one of the allowed values (dev, prod)`),
+		newTypeTestCase("invalid enum from config", "config.define_enum('foo', choices=['dev', 'prod'])").withConfigFile(`{"foo": "staging"}`).withExpectedError(`specified invalid value for setting foo: "staging" is not one of the allowed values (dev, prod)`),
+		newTypeTestCase("enum without choices", "config.define_enum('foo', choices=[])").withExpectedError("config.define_enum: 'choices' must not be empty"),
+
+		newTypeTestCase("string default", "config.define_string('foo', default='bar')").withExpectedVal("'bar'"),
+		newTypeTestCase("string default overridden by args", "config.define_string('foo', default='bar')").withArgs("--foo", "baz").withExpectedVal("'baz'"),
+		newTypeTestCase("string default overridden by config", "config.define_string('foo', default='bar')").withConfigFile(`{"foo": "baz"}`).withExpectedVal("'baz'"),
+		newTypeTestCase("int default", "config.define_int('foo', default=3)").withExpectedVal("3"),
+		newTypeTestCase("bool default", "config.define_bool('foo', default=True)").withExpectedVal("True"),
+		newTypeTestCase("string_list default", "config.define_string_list('foo', default=['a', 'b'])").withExpectedVal("['a', 'b']"),
+		newTypeTestCase("enum default", "config.define_enum('foo', choices=['dev', 'prod'], default='dev')").withExpectedVal("'dev'"),
+		newTypeTestCase("invalid int default", "config.define_int('foo', default='three')").withExpectedError("config.define_int: invalid default for setting foo: expected int, found string"),
+		newTypeTestCase("invalid enum default", "config.define_enum('foo', choices=['dev', 'prod'], default='staging')").withExpectedError(`config.define_enum: invalid default for setting foo: "staging" is not one of the allowed values (dev, prod)`),
+
+		newTypeTestCase("required from args", "config.define_int('foo', required=True)").withArgs("--foo", "5").withExpectedVal("5"),
+		newTypeTestCase("required from config", "config.define_int('foo', required=True)").withConfigFile(`{"foo": 5}`).withExpectedVal("5"),
+		newTypeTestCase("required and missing", "config.define_int('foo', required=True)").withExpectedError("missing required Tiltfile config settings: foo (--foo). Specify them as Tiltfile args, or in "),
+		newTypeTestCase("required positional and missing", "config.define_string_list('foo', args=True, required=True)").withExpectedError("missing required Tiltfile config settings: foo (positional args)"),
+		newTypeTestCase("required with default", "config.define_int('foo', required=True, default=5)").withExpectedError("config.define_int: setting foo cannot be both required and have a default"),
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			f := NewFixture(t, tc.args, "")
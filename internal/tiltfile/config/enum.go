@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+)
+
+type enumSetting struct {
+	choices []string
+	value   string
+	isSet   bool
+}
+
+var _ configValue = &enumSetting{}
+var _ flag.Value = &enumSetting{}
+
+func (s *enumSetting) starlark() starlark.Value {
+	return starlark.String(s.value)
+}
+
+func (s *enumSetting) IsSet() bool {
+	return s.isSet
+}
+
+// Shows the choices in usage messages, e.g., "--env dev|prod".
+func (s *enumSetting) Type() string {
+	return strings.Join(s.choices, "|")
+}
+
+func (s *enumSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("expected string, found %T", i)
+	}
+
+	err := s.validate(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+
+	return nil
+}
+
+func (s *enumSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("enum settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+
+	err := s.validate(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+	return nil
+}
+
+func (s *enumSetting) validate(v string) error {
+	for _, choice := range s.choices {
+		if v == choice {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of the allowed values (%s)", v, strings.Join(s.choices, ", "))
+}
+
+func (s *enumSetting) String() string {
+	return s.value
+}
@@ -60,29 +60,69 @@ func clearEnabledResources(thread *starlark.Thread, fn *starlark.Builtin, args s
 }
 
 // for the given args and list of full manifests, figure out which manifests the user actually selected
-func (s Settings) EnabledResources(tf *v1alpha1.Tiltfile, manifests []model.Manifest) ([]model.ManifestName, error) {
-	if s.disableAll {
-		return nil, nil
+//
+// disabledOverrides holds the resources that the overrides file enables (false)
+// or disables (true). From highest to lowest precedence:
+//  1. resources named in the args (and their deps)
+//  2. the overrides file
+//  3. the Tiltfile's own selection (e.g., from tilt_config.json)
+func (s Settings) EnabledResources(tf *v1alpha1.Tiltfile, manifests []model.Manifest, disabledOverrides map[model.ManifestName]bool) ([]model.ManifestName, error) {
+	args := tf.Spec.Args
+
+	// if the user has not called config.parse and has specified args, use those
+	// to select which resources, unless set_enabled_resources trumps them
+	if args != nil && !s.configParseCalled && !s.disableAll && s.enabledResources == nil {
+		var requestedManifests []model.ManifestName
+		for _, arg := range args {
+			requestedManifests = append(requestedManifests, model.ManifestName(arg))
+		}
+		return match(manifests, requestedManifests)
 	}
 
-	// by default, nil = match all resources
-	var requestedManifests []model.ManifestName
+	var selected []model.ManifestName
+	if !s.disableAll {
+		// by default, nil = match all resources
+		var err error
+		selected, err = match(manifests, s.enabledResources)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// if the user called set_enabled_resources, that trumps everything
-	if s.enabledResources != nil {
-		requestedManifests = s.enabledResources
-	} else {
-		args := tf.Spec.Args
+	if len(disabledOverrides) == 0 {
+		return selected, nil
+	}
 
-		// if the user has not called config.parse and has specified args, use those to select which resources
-		if args != nil && !s.configParseCalled {
-			for _, arg := range args {
-				requestedManifests = append(requestedManifests, model.ManifestName(arg))
-			}
+	manifestsByName := make(map[model.ManifestName]model.Manifest, len(manifests))
+	for _, m := range manifests {
+		manifestsByName[m.Name] = m
+	}
+
+	// Resources named in the args (e.g., passed through config.parse())
+	// win over the overrides file.
+	namedInArgs := make(map[model.ManifestName]bool)
+	for _, arg := range args {
+		if _, ok := manifestsByName[model.ManifestName(arg)]; ok {
+			addManifestAndDeps(namedInArgs, manifestsByName, model.ManifestName(arg))
 		}
 	}
 
-	return match(manifests, requestedManifests)
+	isSelected := make(map[model.ManifestName]bool, len(selected))
+	for _, mn := range selected {
+		isSelected[mn] = true
+	}
+
+	var result []model.ManifestName
+	for _, m := range manifests {
+		enabled := isSelected[m.Name]
+		if disabled, ok := disabledOverrides[m.Name]; ok && !namedInArgs[m.Name] {
+			enabled = !disabled
+		}
+		if enabled {
+			result = append(result, m.Name)
+		}
+	}
+	return result, nil
 }
 
 // add `manifestToAdd` and all of its transitive deps to `result`
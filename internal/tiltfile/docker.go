@@ -11,10 +11,12 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/builder/dockerignore"
+	bkclient "github.com/moby/buildkit/client"
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker/buildkit"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
@@ -44,8 +46,10 @@ type dockerImage struct {
 	network          string
 	extraTags        []string // Extra tags added at build-time.
 	cacheFrom        []string
+	cacheTo          []string
 	pullParent       bool
 	platform         string
+	platforms        []string
 
 	// Overrides the container args. Used as an escape hatch in case people want the old entrypoint behavior.
 	// See discussion here:
@@ -101,6 +105,31 @@ func (d *dockerImage) Type() dockerImageBuildType {
 	return d.buildType
 }
 
+// Validates Buildkit cache specs, and resolves local cache directories
+// relative to the Tiltfile.
+func absCacheSpecs(thread *starlark.Thread, specs []string,
+	parse func([]string) ([]bkclient.CacheOptionsEntry, error)) ([]string, error) {
+	entries, err := parse(specs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(specs))
+	for i, e := range entries {
+		if e.Type != buildkit.CacheTypeLocal {
+			result = append(result, specs[i])
+			continue
+		}
+		for _, key := range []string{"src", "dest"} {
+			if dir, ok := e.Attrs[key]; ok {
+				e.Attrs[key] = starkit.AbsPath(thread, dir)
+			}
+		}
+		result = append(result, buildkit.FormatCacheEntry(e))
+	}
+	return result, nil
+}
+
 func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var dockerRef, targetStage string
 	contextVal := value.NewLocalPathUnpacker(thread)
@@ -113,7 +142,8 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		entrypoint starlark.Value
 	var buildArgs value.StringStringMap
 	var network, platform value.Stringable
-	var ssh, secret, extraTags, cacheFrom value.StringOrStringList
+	var ssh, secret, extraTags, cacheFrom, cacheTo value.StringOrStringList
+	var platforms value.StringList
 	var matchInEnvVars, pullParent bool
 	var overrideArgsVal starlark.Sequence
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
@@ -135,8 +165,10 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		"network?", &network,
 		"extra_tag?", &extraTags,
 		"cache_from?", &cacheFrom,
+		"cache_to?", &cacheTo,
 		"pull?", &pullParent,
 		"platform?", &platform,
+		"platforms?", &platforms,
 	); err != nil {
 		return nil, err
 	}
@@ -216,7 +248,27 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		}
 	}
 
-	if platform.Value == "" {
+	cacheFromSpecs, err := absCacheSpecs(thread, cacheFrom.Values, buildkit.ParseCacheImports)
+	if err != nil {
+		return nil, fmt.Errorf("Argument cache_from: %v", err)
+	}
+	cacheToSpecs, err := absCacheSpecs(thread, cacheTo.Values, buildkit.ParseCacheExports)
+	if err != nil {
+		return nil, fmt.Errorf("Argument cache_to: %v", err)
+	}
+
+	if len(platforms) > 0 {
+		if platform.Value != "" {
+			return nil, fmt.Errorf("Cannot specify both platform and platforms")
+		}
+		for _, p := range platforms {
+			if p == "" || strings.Contains(p, ",") {
+				return nil, fmt.Errorf("Argument platforms: invalid platform %q", p)
+			}
+		}
+	}
+
+	if platform.Value == "" && len(platforms) == 0 {
 		// for compatibility with Docker CLI, support the env var fallback
 		// see https://docs.docker.com/engine/reference/commandline/cli/#environment-variables
 		platform.Value = os.Getenv(dockerPlatformEnv)
@@ -251,9 +303,11 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		targetStage:      targetStage,
 		network:          network.Value,
 		extraTags:        extraTags.Values,
-		cacheFrom:        cacheFrom.Values,
+		cacheFrom:        cacheFromSpecs,
+		cacheTo:          cacheToSpecs,
 		pullParent:       pullParent,
 		platform:         platform.Value,
+		platforms:        platforms,
 		tiltfilePath:     starkit.CurrentExecPath(thread),
 	}
 	err = s.buildIndex.addImage(r)
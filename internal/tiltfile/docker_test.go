@@ -131,6 +131,37 @@ func TestCustomPlatform(t *testing.T) {
 	}
 }
 
+func TestCustomPlatforms(t *testing.T) {
+	testutils.Setenv(t, dockerPlatformEnv, "linux/arm64")
+
+	f := newFixture(t)
+
+	f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+	f.file("Dockerfile", `FROM alpine`)
+	f.file("Tiltfile", `
+k8s_yaml('fe.yaml')
+docker_build('gcr.io/fe', '.', platforms=['linux/amd64', 'linux/arm64'])
+`)
+
+	f.load()
+	m := f.assertNextManifest("fe")
+	require.Equal(t, []string{"linux/amd64", "linux/arm64"}, m.ImageTargetAt(0).DockerBuildInfo().Platforms)
+	require.Equal(t, "", m.ImageTargetAt(0).DockerBuildInfo().Platform)
+}
+
+func TestCustomPlatformAndPlatforms(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+	f.file("Dockerfile", `FROM alpine`)
+	f.file("Tiltfile", `
+k8s_yaml('fe.yaml')
+docker_build('gcr.io/fe', '.', platform='linux/amd64', platforms=['linux/amd64', 'linux/arm64'])
+`)
+
+	f.loadErrString("Cannot specify both platform and platforms")
+}
+
 func TestCustomBuildDepsAreLocalRepos(t *testing.T) {
 	f := newFixture(t)
 
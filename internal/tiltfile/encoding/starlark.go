@@ -68,7 +68,7 @@ func ConvertStructuredDataToStarlark(j interface{}) (starlark.Value, error) {
 	return nil, errors.New(fmt.Sprintf("Unable to convert to starlark value, unexpected type %T", j))
 }
 
-func convertStarlarkToStructuredData(v starlark.Value) (interface{}, error) {
+func ConvertStarlarkToStructuredData(v starlark.Value) (interface{}, error) {
 	switch v := v.(type) {
 	case starlark.Bool:
 		return bool(v), nil
@@ -85,7 +85,7 @@ func convertStarlarkToStructuredData(v starlark.Value) (interface{}, error) {
 		defer it.Done()
 		var e starlark.Value
 		for it.Next(&e) {
-			ee, err := convertStarlarkToStructuredData(e)
+			ee, err := ConvertStarlarkToStructuredData(e)
 			if err != nil {
 				return nil, err
 			}
@@ -96,7 +96,7 @@ func convertStarlarkToStructuredData(v starlark.Value) (interface{}, error) {
 		ret := make(map[string]interface{})
 		for _, t := range v.Items() {
 			key := t.Index(0)
-			kk, err := convertStarlarkToStructuredData(key)
+			kk, err := ConvertStarlarkToStructuredData(key)
 			if err != nil {
 				return nil, err
 			}
@@ -107,7 +107,7 @@ func convertStarlarkToStructuredData(v starlark.Value) (interface{}, error) {
 			}
 
 			val := t.Index(1)
-			vv, err := convertStarlarkToStructuredData(val)
+			vv, err := ConvertStarlarkToStructuredData(val)
 			if err != nil {
 				return nil, err
 			}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
+	"sigs.k8s.io/kustomize/api/konfig"
 
 	"github.com/tilt-dev/tilt/internal/kustomize"
 )
@@ -144,19 +145,16 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 		return nil, err
 	}
 
-	kustomizeArgs := []string{"kustomize", "build"}
-
-	if kustomizeBin.Value != "" {
-		kustomizeArgs[0] = kustomizeBin.Value
+	// Render with the kustomize library that Tilt is built with, unless the
+	// Tiltfile asks for a specific kustomize binary.
+	if kustomizeBin.Value == "" {
+		return s.kustomizeInProcess(thread, path.Value)
 	}
 
+	kustomizeArgs := []string{kustomizeBin.Value, "build"}
 	_, err = exec.LookPath(kustomizeArgs[0])
 	if err != nil {
-		if kustomizeBin.Value != "" {
-			return nil, err
-		}
-		s.logger.Infof("Falling back to `kubectl kustomize` since `%s` was not found in PATH", kustomizeArgs[0])
-		kustomizeArgs = []string{"kubectl", "kustomize"}
+		return nil, err
 	}
 
 	// NOTE(nick): There's a bug in kustomize where it doesn't properly
@@ -189,6 +187,30 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", path.Value)), nil
 }
 
+// Render the kustomization with the vendored kustomize library, and watch
+// exactly the files that it read.
+func (s *tiltfileState) kustomizeInProcess(thread *starlark.Thread, path string) (starlark.Value, error) {
+	yaml, deps, err := kustomize.Build(path)
+	for _, d := range deps {
+		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, d)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		// Watch for the kustomization file to be created, in case it's missing.
+		for _, kf := range konfig.RecognizedKustomizationFileNames() {
+			err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, filepath.Join(path, kf))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", path)), nil
+}
+
 func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	path := value.NewLocalPathUnpacker(thread)
 	var name string
@@ -0,0 +1,321 @@
+package tiltfile
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The values that the image is injected into when image_keys isn't specified.
+//
+// This matches the chart layout that `helm create` generates.
+var defaultHelmImageKeys = helmImageKeys{repository: "image.repository", tag: "image.tag"}
+
+// Describes where in the chart's values an image should be injected.
+//
+// Either a single key that takes the whole image reference,
+// or a pair of keys that take the repository and the tag.
+type helmImageKeys struct {
+	image      string
+	repository string
+	tag        string
+}
+
+// Builds the --set flags that inject image #i, for sh.
+//
+// The image reference isn't known until the image is built, so we read it
+// from the TILT_IMAGE_i env variable that the KubernetesApply reconciler injects.
+func (k helmImageKeys) shellSetFlags(i int) string {
+	envVar := fmt.Sprintf("TILT_IMAGE_%d", i)
+	if k.image != "" {
+		return fmt.Sprintf(`--set %s"${%s}"`, shellquote.Join(k.image+"="), envVar)
+	}
+	return fmt.Sprintf(`--set %s"${%s%%:*}" --set %s"${%s##*:}"`,
+		shellquote.Join(k.repository+"="), envVar,
+		shellquote.Join(k.tag+"="), envVar)
+}
+
+// Builds the --set flags that inject image #i, for PowerShell.
+func (k helmImageKeys) powerShellSetFlags(i int) string {
+	envVar := fmt.Sprintf("$env:TILT_IMAGE_%d", i)
+	if k.image != "" {
+		return fmt.Sprintf(`'--set' (%s + %s)`, powerShellQuote(k.image+"="), envVar)
+	}
+	return fmt.Sprintf(`'--set' (%s + %s.Substring(0, %s.LastIndexOf(':'))) '--set' (%s + %s.Substring(%s.LastIndexOf(':') + 1))`,
+		powerShellQuote(k.repository+"="), envVar, envVar,
+		powerShellQuote(k.tag+"="), envVar, envVar)
+}
+
+// The helm commands that deploy and delete a release.
+type helmReleaseCmds struct {
+	releaseName string
+	namespace   string
+	upgradeArgs []string
+	imageKeys   []helmImageKeys
+}
+
+func (h helmReleaseCmds) getManifestArgs() []string {
+	return append([]string{"helm", "get", "manifest", h.releaseName}, helmNamespaceArgs(h.namespace)...)
+}
+
+// `helm get manifest` doesn't include UIDs, so we read the objects
+// back from the cluster.
+func (h helmReleaseCmds) kubectlGetArgs() []string {
+	args := append([]string{"kubectl", "get"}, helmNamespaceArgs(h.namespace)...)
+	return append(args, "-o", "yaml", "-f", "-")
+}
+
+func (h helmReleaseCmds) statusArgs() []string {
+	return append([]string{"helm", "status", h.releaseName}, helmNamespaceArgs(h.namespace)...)
+}
+
+func (h helmReleaseCmds) uninstallArgs() []string {
+	return append([]string{"helm", "uninstall", h.releaseName}, helmNamespaceArgs(h.namespace)...)
+}
+
+// Helm prints release notes to stdout, but the apply cmd must only print
+// the YAML of the deployed objects, so that Tilt can discover pods.
+//
+// A release with no objects has an empty manifest, and `kubectl get -f -`
+// fails on empty input, so we skip it.
+//
+// `helm uninstall` fails if the release doesn't exist, but the delete cmd
+// must be idempotent.
+func (h helmReleaseCmds) unixCmds(dir string) (apply model.Cmd, delete model.Cmd) {
+	upgradeCmd := shellquote.Join(h.upgradeArgs...)
+	for i, keys := range h.imageKeys {
+		upgradeCmd += " " + keys.shellSetFlags(i)
+	}
+
+	applyScript := strings.Join([]string{
+		"set -e",
+		upgradeCmd + " 1>&2",
+		"manifest=$(" + shellquote.Join(h.getManifestArgs()...) + ")",
+		`if [ -n "$manifest" ]; then`,
+		`  printf '%s\n' "$manifest" | ` + shellquote.Join(h.kubectlGetArgs()...),
+		"fi",
+	}, "\n")
+
+	deleteScript := strings.Join([]string{
+		shellquote.Join(h.statusArgs()...) + " >/dev/null 2>&1 || exit 0",
+		shellquote.Join(h.uninstallArgs()...),
+	}, "\n")
+
+	return model.ToUnixCmdInDir(applyScript, dir), model.ToUnixCmdInDir(deleteScript, dir)
+}
+
+// Same as unixCmds, but for Windows.
+//
+// The scripts need to split image references and capture output,
+// which cmd.exe can't do, so they run in PowerShell.
+func (h helmReleaseCmds) windowsCmds(dir string) (apply model.Cmd, delete model.Cmd) {
+	upgradeCmd := powerShellJoin(h.upgradeArgs...)
+	for i, keys := range h.imageKeys {
+		upgradeCmd += " " + keys.powerShellSetFlags(i)
+	}
+
+	applyScript := strings.Join([]string{
+		upgradeCmd + " | ForEach-Object { [Console]::Error.WriteLine($_) }",
+		"if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }",
+		"$manifest = " + powerShellJoin(h.getManifestArgs()...) + " | Out-String",
+		"if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }",
+		"if ($manifest.Trim()) { $manifest | " + powerShellJoin(h.kubectlGetArgs()...) + "; exit $LASTEXITCODE }",
+	}, "; ")
+
+	deleteScript := strings.Join([]string{
+		powerShellJoin(h.statusArgs()...) + " *> $null",
+		"if ($LASTEXITCODE -ne 0) { exit 0 }",
+		powerShellJoin(h.uninstallArgs()...),
+		"exit $LASTEXITCODE",
+	}, "; ")
+
+	return toPowerShellCmdInDir(applyScript, dir), toPowerShellCmdInDir(deleteScript, dir)
+}
+
+func (h helmReleaseCmds) hostCmds(dir string) (apply model.Cmd, delete model.Cmd) {
+	if runtime.GOOS == "windows" {
+		return h.windowsCmds(dir)
+	}
+	return h.unixCmds(dir)
+}
+
+func toPowerShellCmdInDir(script string, dir string) model.Cmd {
+	return model.Cmd{
+		Argv: []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", script},
+		Dir:  dir,
+	}
+}
+
+// Quotes a string as a PowerShell literal.
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Joins the args into a PowerShell command that runs the first as an executable.
+func powerShellJoin(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = powerShellQuote(arg)
+	}
+	return "& " + strings.Join(quoted, " ")
+}
+
+func (s *tiltfileState) helmRelease(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, chart, releaseName, namespace string
+	var set, flags value.StringOrStringList
+	var imageDeps value.ImageList
+	var imageKeysVal starlark.Value
+
+	values := value.NewLocalPathListUnpacker(thread)
+	deps := value.NewLocalPathListUnpacker(thread)
+
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"chart", &chart,
+		"release_name?", &releaseName,
+		"namespace?", &namespace,
+		"values?", &values,
+		"set?", &set,
+		"flags?", &flags,
+		"deps?", &deps,
+		"image_deps?", &imageDeps,
+		"image_keys?", &imageKeysVal,
+	); err != nil {
+		return nil, err
+	}
+
+	if chart == "" {
+		return nil, fmt.Errorf("%s: chart cannot be empty", fn.Name())
+	}
+	if releaseName == "" {
+		releaseName = name
+	}
+
+	imageKeys, err := helmImageKeysFromValue(imageKeysVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: for parameter %q", fn.Name(), "image_keys")
+	}
+	if len(imageKeys) == 0 {
+		// The default keys only make sense for a single image. With more than one,
+		// every image would be written to the same keys, and the last would win.
+		if len(imageDeps) > 1 {
+			return nil, fmt.Errorf("%s: image_keys must be set when there is more than one image in image_deps (got %d image_deps)",
+				fn.Name(), len(imageDeps))
+		}
+		for range imageDeps {
+			imageKeys = append(imageKeys, defaultHelmImageKeys)
+		}
+	} else if len(imageKeys) != len(imageDeps) {
+		return nil, fmt.Errorf("%s: image_keys must have one entry per image in image_deps (got %d image_keys for %d image_deps)",
+			fn.Name(), len(imageKeys), len(imageDeps))
+	}
+
+	// A chart may either be a local directory or a reference to a chart
+	// in a repository (e.g., "bitnami/redis"). We only watch local charts.
+	allDeps := append([]string{}, deps.Value...)
+	chartPath := starkit.AbsPath(thread, chart)
+	if info, err := os.Stat(chartPath); err == nil && info.IsDir() {
+		chart = chartPath
+		allDeps = append(allDeps, chartPath)
+
+		subcharts, err := localSubchartDependenciesFromPath(chartPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range subcharts {
+			allDeps = append(allDeps, starkit.AbsPath(thread, d))
+		}
+	}
+	allDeps = append(allDeps, values.Value...)
+
+	var upgradeArgs []string
+	upgradeArgs = append(upgradeArgs, "helm", "upgrade", "--install", releaseName, chart)
+	upgradeArgs = append(upgradeArgs, helmNamespaceArgs(namespace)...)
+	for _, v := range values.Value {
+		upgradeArgs = append(upgradeArgs, "--values", v)
+	}
+	for _, v := range set.Values {
+		upgradeArgs = append(upgradeArgs, "--set", v)
+	}
+	upgradeArgs = append(upgradeArgs, flags.Values...)
+
+	cmds := helmReleaseCmds{
+		releaseName: releaseName,
+		namespace:   namespace,
+		upgradeArgs: upgradeArgs,
+		imageKeys:   imageKeys,
+	}
+
+	res, err := s.makeK8sResource(name)
+	if err != nil {
+		return nil, fmt.Errorf("error making resource for %s: %v", name, err)
+	}
+
+	applyCmd, deleteCmd := cmds.hostCmds(starkit.AbsWorkingDir(thread))
+	res.customDeploy = &k8sCustomDeploy{
+		applyCmd:  applyCmd,
+		deleteCmd: deleteCmd,
+		deps:      allDeps,
+	}
+
+	// The image deps must be added first and in order, so that
+	// TILT_IMAGE_i lines up with image_keys[i].
+	for _, imageDep := range imageDeps {
+		res.addImageDep(imageDep, true)
+	}
+
+	return starlark.None, nil
+}
+
+func helmNamespaceArgs(namespace string) []string {
+	if namespace == "" {
+		return nil
+	}
+	return []string{"--namespace", namespace}
+}
+
+// Parses image_keys, a list where each entry is either a string
+// (e.g., "image") or a tuple of two strings (e.g., ("image.repository", "image.tag")).
+func helmImageKeysFromValue(v starlark.Value) ([]helmImageKeys, error) {
+	if v == nil || v == starlark.None {
+		return nil, nil
+	}
+
+	list, ok := v.(*starlark.List)
+	if !ok {
+		return nil, fmt.Errorf("must be a list, got %s", v.Type())
+	}
+
+	result := make([]helmImageKeys, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		switch entry := list.Index(i).(type) {
+		case starlark.String:
+			result = append(result, helmImageKeys{image: entry.GoString()})
+		case starlark.Tuple:
+			if entry.Len() != 2 {
+				return nil, fmt.Errorf("tuples must have exactly 2 keys (repository, tag), got %d", entry.Len())
+			}
+			repo, ok := starlark.AsString(entry.Index(0))
+			if !ok {
+				return nil, fmt.Errorf("repository key must be a string, got %s", entry.Index(0).Type())
+			}
+			tag, ok := starlark.AsString(entry.Index(1))
+			if !ok {
+				return nil, fmt.Errorf("tag key must be a string, got %s", entry.Index(1).Type())
+			}
+			result = append(result, helmImageKeys{repository: repo, tag: tag})
+		default:
+			return nil, fmt.Errorf("entries must be a string or a tuple of two strings, got %s", entry.Type())
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package tiltfile
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmRelease(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helm_release uses PowerShell on Windows")
+	}
+
+	f := newFixture(t)
+
+	f.setupHelm()
+	f.file("Tiltfile", `
+helm_release('rose-quartz', './helm',
+             namespace='garnet',
+             values=['./dev/helm/values-dev.yaml'],
+             set=['service.port=8080'],
+             flags=['--atomic'])
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("rose-quartz")
+	assert.Empty(t, m.ImageTargets)
+
+	spec := m.K8sTarget().KubernetesApplySpec
+	require.Empty(t, spec.YAML)
+	require.NotNil(t, spec.ApplyCmd)
+	require.NotNil(t, spec.DeleteCmd)
+	assert.Equal(t, f.Path(), spec.ApplyCmd.Dir)
+
+	applyArgs := spec.ApplyCmd.Args
+	require.Len(t, applyArgs, 3)
+	assert.Equal(t, []string{"sh", "-c"}, applyArgs[:2])
+	assert.Equal(t, `set -e
+helm upgrade --install rose-quartz `+f.JoinPath("helm")+` --namespace garnet `+
+		`--values `+f.JoinPath("dev/helm/values-dev.yaml")+` --set service.port=8080 --atomic 1>&2
+manifest=$(helm get manifest rose-quartz --namespace garnet)
+if [ -n "$manifest" ]; then
+  printf '%s\n' "$manifest" | kubectl get --namespace garnet -o yaml -f -
+fi`, applyArgs[2])
+
+	assert.Equal(t, []string{"sh", "-c", `helm status rose-quartz --namespace garnet >/dev/null 2>&1 || exit 0
+helm uninstall rose-quartz --namespace garnet`}, spec.DeleteCmd.Args)
+
+	assert.ElementsMatch(t,
+		[]string{f.JoinPath("helm"), f.JoinPath("dev/helm/values-dev.yaml")},
+		m.K8sTarget().Dependencies())
+}
+
+func TestHelmReleaseRemoteChart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helm_release uses PowerShell on Windows")
+	}
+
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+helm_release('redis', 'bitnami/redis', release_name='cache')
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("redis")
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Contains(t, spec.ApplyCmd.Args[2], "helm upgrade --install cache bitnami/redis 1>&2")
+	assert.Contains(t, spec.DeleteCmd.Args[2], "helm uninstall cache")
+	assert.Empty(t, m.K8sTarget().Dependencies())
+}
+
+func TestHelmReleaseImageDeps(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupHelm()
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('image-a', '.')
+docker_build('image-b', '.')
+helm_release('chart', './helm', image_deps=['image-a', 'image-b'])
+`)
+
+	f.loadErrString("helm_release: image_keys must be set when there is more than one image in image_deps (got 2 image_deps)")
+}
+
+func TestHelmReleaseImageDep(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helm_release uses PowerShell on Windows")
+	}
+
+	f := newFixture(t)
+
+	f.setupHelm()
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('image-a', '.')
+helm_release('chart', './helm', image_deps=['image-a'])
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("chart")
+	assert.Len(t, m.ImageTargets, 1)
+
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Equal(t, []string{"image-a"}, spec.ImageMaps)
+	assert.Contains(t, spec.ApplyCmd.Args[2],
+		`--set image.repository="${TILT_IMAGE_0%:*}" --set image.tag="${TILT_IMAGE_0##*:}" 1>&2`)
+}
+
+func TestHelmReleaseImageKeys(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helm_release uses PowerShell on Windows")
+	}
+
+	f := newFixture(t)
+
+	f.setupHelm()
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('image-a', '.')
+docker_build('image-b', '.')
+helm_release('chart', './helm',
+             image_deps=['image-a', 'image-b'],
+             image_keys=['frontend.image', ('backend.image.repo', 'backend.image.tag')])
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("chart")
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Contains(t, spec.ApplyCmd.Args[2],
+		`--set frontend.image="${TILT_IMAGE_0}" `+
+			`--set backend.image.repo="${TILT_IMAGE_1%:*}" --set backend.image.tag="${TILT_IMAGE_1##*:}" 1>&2`)
+}
+
+func TestHelmReleaseImageKeysMismatch(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupHelm()
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('image-a', '.')
+helm_release('chart', './helm', image_deps=['image-a'], image_keys=['a', 'b'])
+`)
+
+	f.loadErrString("helm_release: image_keys must have one entry per image in image_deps (got 2 image_keys for 1 image_deps)")
+}
+
+func TestHelmReleaseImageDepsMissing(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupHelm()
+	f.file("Tiltfile", `
+helm_release('chart', './helm', image_deps=['image-a'])
+`)
+
+	f.loadErrString(`resource "chart": image build "image-a" not found`)
+}
+
+func TestHelmReleaseWindowsCmds(t *testing.T) {
+	cmds := helmReleaseCmds{
+		releaseName: "chart",
+		namespace:   "garnet",
+		upgradeArgs: []string{"helm", "upgrade", "--install", "chart", `C:\charts\it's`},
+		imageKeys: []helmImageKeys{
+			{image: "frontend.image"},
+			defaultHelmImageKeys,
+		},
+	}
+
+	apply, del := cmds.windowsCmds(`C:\project`)
+	assert.Equal(t, `C:\project`, apply.Dir)
+	assert.Equal(t, []string{"powershell", "-NoProfile", "-NonInteractive", "-Command"}, apply.Argv[:4])
+	assert.Equal(t,
+		`& 'helm' 'upgrade' '--install' 'chart' 'C:\charts\it''s' `+
+			`'--set' ('frontend.image=' + $env:TILT_IMAGE_0) `+
+			`'--set' ('image.repository=' + $env:TILT_IMAGE_1.Substring(0, $env:TILT_IMAGE_1.LastIndexOf(':'))) `+
+			`'--set' ('image.tag=' + $env:TILT_IMAGE_1.Substring($env:TILT_IMAGE_1.LastIndexOf(':') + 1)) `+
+			`| ForEach-Object { [Console]::Error.WriteLine($_) }; `+
+			`if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }; `+
+			`$manifest = & 'helm' 'get' 'manifest' 'chart' '--namespace' 'garnet' | Out-String; `+
+			`if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE }; `+
+			`if ($manifest.Trim()) { $manifest | & 'kubectl' 'get' '--namespace' 'garnet' '-o' 'yaml' '-f' '-'; exit $LASTEXITCODE }`,
+		apply.Argv[4])
+
+	assert.Equal(t,
+		`& 'helm' 'status' 'chart' '--namespace' 'garnet' *> $null; `+
+			`if ($LASTEXITCODE -ne 0) { exit 0 }; `+
+			`& 'helm' 'uninstall' 'chart' '--namespace' 'garnet'; `+
+			`exit $LASTEXITCODE`,
+		del.Argv[4])
+}
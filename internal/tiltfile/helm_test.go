@@ -33,7 +33,7 @@ k8s_yaml(yml)
 	f.assertNextManifestUnresourced("chart-helloworld-chart")
 	f.assertConfigFiles(
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"helm",
 	)
 }
@@ -64,7 +64,7 @@ k8s_yaml(yml)
 	expectedNames := []string{"rose-quartz-helloworld-chart:service"}
 	assert.ElementsMatch(t, expectedNames, names)
 
-	f.assertConfigFiles("./helm/", "./dev/helm/values-dev.yaml", ".tiltignore", "Tiltfile")
+	f.assertConfigFiles("./helm/", "./dev/helm/values-dev.yaml", ".tiltignore", "tilt_overrides.json", "Tiltfile")
 }
 
 func TestHelmNamespaceFlagDoesNotInsertNSEntityIfNSInChart(t *testing.T) {
@@ -147,7 +147,7 @@ k8s_yaml(yml)
 	f.assertNextManifestUnresourced("chart-helloworld-chart")
 	f.assertConfigFiles(
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"helm",
 	)
 }
@@ -165,7 +165,7 @@ k8s_yaml(yml)
 	f.loadErrString("error unmarshaling JSON")
 	f.assertConfigFiles(
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"helm",
 	)
 }
@@ -429,7 +429,7 @@ k8s_yaml(yml)
 	f.assertNextManifestUnresourced("chart-helloworld-chart")
 	f.assertConfigFiles(
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"helm",
 	)
 }
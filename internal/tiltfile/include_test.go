@@ -34,7 +34,7 @@ k8s_yaml(['foo.yaml', 'bar.yaml'])
 		db(image("gcr.io/bar")),
 		deployment("bar"))
 
-	f.assertConfigFiles(".tiltignore", "Tiltfile",
+	f.assertConfigFiles(".tiltignore", "tilt_overrides.json", "Tiltfile",
 		"bar.yaml", "bar/.dockerignore", "bar/Dockerfile", "bar/Tiltfile",
 		"foo.yaml", "foo/.dockerignore", "foo/Dockerfile", "foo/Tiltfile")
 }
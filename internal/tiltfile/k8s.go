@@ -57,6 +57,8 @@ type k8sResource struct {
 
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
 
+	logFormat v1alpha1.PodLogFormat
+
 	imageMapDeps []string
 
 	triggerMode triggerMode
@@ -71,6 +73,10 @@ type k8sResource struct {
 	labels map[string]string
 
 	customDeploy *k8sCustomDeploy
+
+	// The name of the cluster to deploy to. Empty until assembly, when it's
+	// inferred from the objects if k8s_resource() didn't set it.
+	cluster string
 }
 
 // holds options passed to `k8s_resource` until assembly happens
@@ -88,8 +94,10 @@ type k8sResourceOptions struct {
 	manuallyGrouped   bool
 	podReadinessMode  model.PodReadinessMode
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
+	logFormat         v1alpha1.PodLogFormat
 	links             []model.Link
 	labels            map[string]string
+	cluster           string
 }
 
 // Count image injection for analytics.
@@ -142,13 +150,20 @@ func (r *k8sResource) addEntities(entities []k8s.K8sEntity,
 func (s *tiltfileState) k8sYaml(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var yamlValue starlark.Value
 	var allowDuplicates bool
+	var cluster string
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"yaml", &yamlValue,
 		"allow_duplicates?", &allowDuplicates,
+		"cluster?", &cluster,
 	); err != nil {
 		return nil, err
 	}
+
+	if err := s.validateClusterName(cluster); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+
 	//normalize the starlark value into a slice
 	value := starlarkValueOrSequenceToSlice(yamlValue)
 
@@ -166,12 +181,19 @@ func (s *tiltfileState) k8sYaml(thread *starlark.Thread, fn *starlark.Builtin, a
 		if len(entities) == 0 && val == "" {
 			return nil, emptyYAMLError
 		}
-		err = s.k8sObjectIndex.Append(thread, entities, allowDuplicates)
+		indexCluster := cluster
+		if indexCluster == "" {
+			indexCluster = v1alpha1.ClusterNameDefault
+		}
+		err = s.k8sObjectIndex.Append(thread, entities, indexCluster, allowDuplicates)
 		if err != nil {
 			return nil, err
 		}
 
 		s.k8sUnresourced = append(s.k8sUnresourced, entities...)
+		for _, e := range entities {
+			s.k8sEntityClusters[newK8sEntityID(e, cluster)] = e.Obj
+		}
 
 	} else {
 		return nil, emptyYAMLError
@@ -305,6 +327,8 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 	var autoInit = value.Optional[starlark.Bool]{Value: true}
 	var labels value.LabelSet
 	var discoveryStrategy tiltfile_k8s.DiscoveryStrategy
+	var logFormat tiltfile_k8s.LogFormat
+	var cluster string
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"workload?", &workload,
@@ -319,10 +343,16 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		"links?", &links,
 		"labels?", &labels,
 		"discovery_strategy?", &discoveryStrategy,
+		"log_format?", &logFormat,
+		"cluster?", &cluster,
 	); err != nil {
 		return nil, err
 	}
 
+	if err := s.validateClusterName(cluster); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+
 	resourceName := workload.String()
 	manuallyGrouped := false
 	if workload == "" {
@@ -379,6 +409,8 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		links:             links.Links,
 		labels:            labelMap,
 		discoveryStrategy: v1alpha1.KubernetesDiscoveryStrategy(discoveryStrategy),
+		logFormat:         v1alpha1.PodLogFormat(logFormat),
+		cluster:           cluster,
 	})
 
 	return starlark.None, nil
@@ -28,22 +28,28 @@ type ObjectSpec struct {
 	StackTrace string
 }
 
+// Identifies an object on a cluster.
+type ObjectKey struct {
+	Cluster string
+	Ref     v1.ObjectReference
+}
+
 // Keeps track of all the Kuberentes objects registered during Tiltfile Execution.
 type State struct {
-	ObjectSpecRefs  []v1.ObjectReference
-	ObjectSpecIndex map[v1.ObjectReference]ObjectSpec
+	ObjectSpecKeys  []ObjectKey
+	ObjectSpecIndex map[ObjectKey]ObjectSpec
 }
 
 func NewState() *State {
 	return &State{
-		ObjectSpecIndex: make(map[v1.ObjectReference]ObjectSpec),
+		ObjectSpecIndex: make(map[ObjectKey]ObjectSpec),
 	}
 }
 
 func (s *State) Entities() []k8s.K8sEntity {
 	result := make([]k8s.K8sEntity, len(s.ObjectSpecIndex))
-	for i, ref := range s.ObjectSpecRefs {
-		result[i] = s.ObjectSpecIndex[ref].Entity
+	for i, key := range s.ObjectSpecKeys {
+		result[i] = s.ObjectSpecIndex[key].Entity
 	}
 	return result
 }
@@ -52,11 +58,15 @@ func (s *State) EntityCount() int {
 	return len(s.ObjectSpecIndex)
 }
 
-func (s *State) Append(t *starlark.Thread, entities []k8s.K8sEntity, dupesOK bool) error {
+// Registers objects that deploy to the given cluster.
+//
+// The same object may be registered on different clusters.
+func (s *State) Append(t *starlark.Thread, entities []k8s.K8sEntity, cluster string, dupesOK bool) error {
 	stackTrace := t.CallStack().String()
 	for _, e := range entities {
 		ref := e.ToObjectReference()
-		old, exists := s.ObjectSpecIndex[ref]
+		key := ObjectKey{Cluster: cluster, Ref: ref}
+		old, exists := s.ObjectSpecIndex[key]
 		if exists && !dupesOK {
 			humanRef := ""
 			if ref.Namespace == "" {
@@ -68,9 +78,9 @@ func (s *State) Append(t *starlark.Thread, entities []k8s.K8sEntity, dupesOK boo
 		}
 
 		if !exists {
-			s.ObjectSpecRefs = append(s.ObjectSpecRefs, ref)
+			s.ObjectSpecKeys = append(s.ObjectSpecKeys, key)
 		}
-		s.ObjectSpecIndex[ref] = ObjectSpec{
+		s.ObjectSpecIndex[key] = ObjectSpec{
 			Entity:     e,
 			StackTrace: stackTrace,
 		}
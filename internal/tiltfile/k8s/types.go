@@ -30,3 +30,25 @@ func (ds *DiscoveryStrategy) Unpack(v starlark.Value) error {
 	*ds = DiscoveryStrategy(kdStrategy)
 	return nil
 }
+
+// Deserializing pod log format from starlark values.
+type LogFormat v1alpha1.PodLogFormat
+
+func (f *LogFormat) Unpack(v starlark.Value) error {
+	s, ok := value.AsString(v)
+	if !ok {
+		return fmt.Errorf("Must be a string. Got: %s", v.Type())
+	}
+
+	format := v1alpha1.PodLogFormat(s)
+	if !(format == "" ||
+		format == v1alpha1.PodLogFormatJSON ||
+		format == v1alpha1.PodLogFormatLogfmt) {
+		return fmt.Errorf("Invalid. Must be one of: %q, %q",
+			v1alpha1.PodLogFormatJSON,
+			v1alpha1.PodLogFormatLogfmt)
+	}
+
+	*f = LogFormat(format)
+	return nil
+}
@@ -0,0 +1,203 @@
+package tiltfile
+
+import (
+	"fmt"
+	"sort"
+
+	"go.starlark.net/starlark"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func (s *tiltfileState) k8sCluster(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, context, namespace string
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"context?", &context,
+		"namespace?", &namespace,
+	); err != nil {
+		return nil, err
+	}
+
+	if name == v1alpha1.ClusterNameDefault || name == v1alpha1.ClusterNameDocker {
+		return nil, fmt.Errorf("%s: cluster name %q is reserved", fn.Name(), name)
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return nil, fmt.Errorf("%s: invalid cluster name %q: %s", fn.Name(), name, errs[0])
+	}
+	if _, ok := s.k8sClusters[name]; ok {
+		return nil, fmt.Errorf("%s: cluster %q already exists", fn.Name(), name)
+	}
+
+	s.k8sClusters[name] = v1alpha1.KubernetesClusterConnection{
+		Context:   context,
+		Namespace: namespace,
+	}
+	return starlark.None, nil
+}
+
+// Make sure that the cluster was declared with k8s_cluster().
+//
+// The empty string refers to the default cluster.
+func (s *tiltfileState) validateClusterName(cluster string) error {
+	if cluster == "" || cluster == v1alpha1.ClusterNameDefault {
+		return nil
+	}
+	if _, ok := s.k8sClusters[cluster]; ok {
+		return nil
+	}
+
+	known := []string{v1alpha1.ClusterNameDefault}
+	for name := range s.k8sClusters {
+		known = append(known, name)
+	}
+	sort.Strings(known[1:])
+	return fmt.Errorf("unknown cluster %q. Declare it with k8s_cluster() first. Known clusters: %v", cluster, known)
+}
+
+// Identifies an object from k8s_yaml() on a cluster, across copies of its entity.
+//
+// K8sEntity holds a pointer to the decoded object, so two copies of the same
+// object aren't equal as map keys.
+type k8sEntityID struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	cluster   string
+}
+
+func newK8sEntityID(e k8s.K8sEntity, cluster string) k8sEntityID {
+	return k8sEntityID{
+		gvk:       e.GVK(),
+		namespace: e.Namespace().String(),
+		name:      e.Name(),
+		cluster:   cluster,
+	}
+}
+
+// The cluster that k8s_yaml() loaded the object for, or the empty string if
+// the object was loaded without a cluster.
+//
+// When the same object was loaded for several clusters, the entity's own
+// object tells them apart.
+func (s *tiltfileState) loadedEntityCluster(e k8s.K8sEntity) string {
+	clusters := []string{"", v1alpha1.ClusterNameDefault}
+	for name := range s.k8sClusters {
+		clusters = append(clusters, name)
+	}
+	sort.Strings(clusters[2:])
+
+	var found []string
+	for _, cluster := range clusters {
+		obj, ok := s.k8sEntityClusters[newK8sEntityID(e, cluster)]
+		if !ok {
+			continue
+		}
+		if obj == e.Obj {
+			return cluster
+		}
+		found = append(found, cluster)
+	}
+	if len(found) > 0 {
+		// A copy of the entity.
+		return found[0]
+	}
+	return ""
+}
+
+// The cluster that an object from k8s_yaml() will be deployed to.
+func (s *tiltfileState) entityCluster(e k8s.K8sEntity) string {
+	cluster := s.loadedEntityCluster(e)
+	if cluster == "" {
+		return v1alpha1.ClusterNameDefault
+	}
+	return cluster
+}
+
+// Split the entities into those that will be deployed to the given cluster, and the rest.
+func (s *tiltfileState) filterEntitiesByCluster(entities []k8s.K8sEntity, cluster string) (match, rest []k8s.K8sEntity) {
+	for _, e := range entities {
+		if s.entityCluster(e) == cluster {
+			match = append(match, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	return match, rest
+}
+
+// Decide which cluster the resource deploys to, and make sure that all of its
+// objects agree.
+//
+// Objects loaded without a cluster deploy to the resource's cluster.
+func (s *tiltfileState) assignK8sResourceCluster(r *k8sResource) error {
+	var unassigned []k8s.K8sEntity
+	for _, e := range r.entities {
+		cluster := s.loadedEntityCluster(e)
+		if cluster == "" {
+			unassigned = append(unassigned, e)
+			continue
+		}
+		if r.cluster == "" {
+			r.cluster = cluster
+			continue
+		}
+		if r.cluster != cluster {
+			return fmt.Errorf("resource %q: object %s is deployed to cluster %q, but the resource is deployed to cluster %q",
+				r.name, fullNameFromK8sEntity(e), cluster, r.cluster)
+		}
+	}
+	if r.cluster == "" {
+		r.cluster = v1alpha1.ClusterNameDefault
+	}
+
+	for _, e := range unassigned {
+		delete(s.k8sEntityClusters, newK8sEntityID(e, ""))
+		id := newK8sEntityID(e, r.cluster)
+		if _, ok := s.k8sEntityClusters[id]; !ok {
+			s.k8sEntityClusters[id] = e.Obj
+		}
+	}
+	return nil
+}
+
+// Group the YAML that isn't part of any resource by cluster.
+//
+// The default cluster's objects go in the usual "uncategorized" manifest;
+// every other cluster gets its own, after it.
+func (s *tiltfileState) unresourcedByCluster(unresourced []k8s.K8sEntity) []*k8sResource {
+	var result []*k8sResource
+	byCluster := make(map[string]*k8sResource)
+	for _, e := range unresourced {
+		cluster := s.entityCluster(e)
+		r, ok := byCluster[cluster]
+		if !ok {
+			mn := model.UnresourcedYAMLManifestName
+			if cluster != v1alpha1.ClusterNameDefault {
+				mn = model.ManifestName(fmt.Sprintf("%s-%s", model.UnresourcedYAMLManifestName, cluster))
+			}
+			r = &k8sResource{
+				name:             mn.String(),
+				cluster:          cluster,
+				podReadinessMode: model.PodReadinessIgnore,
+			}
+			byCluster[cluster] = r
+			result = append(result, r)
+		}
+		r.entities = append(r.entities, e)
+	}
+
+	// Grouping shuffles the unresourced YAML between clusters, so order by
+	// cluster for a stable manifest order.
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].cluster == v1alpha1.ClusterNameDefault || result[j].cluster == v1alpha1.ClusterNameDefault {
+			return result[i].cluster == v1alpha1.ClusterNameDefault && result[j].cluster != v1alpha1.ClusterNameDefault
+		}
+		return result[i].cluster < result[j].cluster
+	})
+	return result
+}
@@ -0,0 +1,211 @@
+package tiltfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestK8sCluster(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo"))
+	f.yaml("bar.yaml", deployment("bar"))
+	f.file("Tiltfile", `
+k8s_cluster('staging', context='gke-staging', namespace='team-a')
+k8s_yaml('foo.yaml')
+k8s_yaml('bar.yaml', cluster='staging')
+`)
+
+	f.load()
+
+	foo := f.assertNextManifest("foo", deployment("foo"))
+	assert.Equal(t, v1alpha1.ClusterNameDefault, foo.K8sTarget().KubernetesApplySpec.Cluster)
+	assert.Equal(t, v1alpha1.ClusterNameDefault, foo.ClusterName())
+
+	bar := f.assertNextManifest("bar", deployment("bar"))
+	assert.Equal(t, "staging", bar.K8sTarget().KubernetesApplySpec.Cluster)
+	assert.Equal(t, "staging", bar.ClusterName())
+
+	assert.Equal(t, map[string]v1alpha1.KubernetesClusterConnection{
+		"staging": {Context: "gke-staging", Namespace: "team-a"},
+	}, f.loadResult.K8sClusters)
+}
+
+func TestK8sClusterDoesNotGroupAcrossClusters(t *testing.T) {
+	f := newFixture(t)
+
+	labels := map[string]string{"app": "foo"}
+	f.yaml("foo.yaml", deployment("foo", withLabels(labels)))
+	f.yaml("svc.yaml", service("foo-svc", withLabels(labels)), secret("staging-secret"))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml')
+k8s_yaml('svc.yaml', cluster='staging')
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo", deployment("foo"))
+	m := f.assertNextManifest("uncategorized-staging")
+	assert.Equal(t, "staging", m.ClusterName())
+	assert.Contains(t, m.K8sTarget().YAML, "name: foo-svc")
+	assert.Contains(t, m.K8sTarget().YAML, "name: staging-secret")
+	f.assertNoMoreManifests()
+}
+
+func TestK8sResourceCluster(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo"))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml', cluster='staging')
+k8s_resource('foo', cluster='staging')
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("foo", deployment("foo"))
+	assert.Equal(t, "staging", m.ClusterName())
+}
+
+func TestK8sResourceClusterAssignsUnclusteredObjects(t *testing.T) {
+	f := newFixture(t)
+
+	labels := map[string]string{"app": "foo"}
+	f.yaml("foo.yaml", deployment("foo", withLabels(labels)), service("foo-svc", withLabels(labels)))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', cluster='staging')
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("foo", deployment("foo"))
+	assert.Equal(t, "staging", m.ClusterName())
+	assert.Contains(t, m.K8sTarget().YAML, "name: foo-svc")
+	f.assertNoMoreManifests()
+}
+
+func TestK8sResourceClusterMismatch(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo"))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml', cluster='default')
+k8s_resource('foo', cluster='staging')
+`)
+
+	f.loadErrString(`resource "foo": object foo:Deployment:default is deployed to cluster "default", but the resource is deployed to cluster "staging"`)
+}
+
+func TestK8sClusterSameObjectOnTwoClusters(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo"), secret("creds"))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml')
+k8s_yaml('foo.yaml', cluster='staging')
+`)
+
+	f.load()
+
+	// The workloads have the same name, so they get disambiguated like
+	// any other name collision.
+	foo := f.assertNextManifest("foo:deployment:default:apps:0", deployment("foo"))
+	assert.Equal(t, v1alpha1.ClusterNameDefault, foo.ClusterName())
+
+	fooStaging := f.assertNextManifest("foo:deployment:default:apps:1", deployment("foo"))
+	assert.Equal(t, "staging", fooStaging.ClusterName())
+
+	uncategorized := f.assertNextManifest("uncategorized")
+	assert.Equal(t, v1alpha1.ClusterNameDefault, uncategorized.ClusterName())
+	assert.Contains(t, uncategorized.K8sTarget().YAML, "name: creds")
+
+	uncategorizedStaging := f.assertNextManifest("uncategorized-staging")
+	assert.Equal(t, "staging", uncategorizedStaging.ClusterName())
+	assert.Contains(t, uncategorizedStaging.K8sTarget().YAML, "name: creds")
+	f.assertNoMoreManifests()
+}
+
+func TestK8sClusterDuplicateOnSameCluster(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo"))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml', cluster='staging')
+k8s_yaml('foo.yaml', cluster='staging')
+`)
+
+	f.loadErrString("Duplicate YAML: Deployment foo")
+}
+
+func TestK8sClusterUnknown(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo"))
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_yaml('foo.yaml', cluster='prod')
+`)
+
+	f.loadErrString(`k8s_yaml: unknown cluster "prod". Declare it with k8s_cluster() first. Known clusters: [default staging]`)
+}
+
+func TestK8sClusterInvalidName(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		expectedErr string
+	}{
+		{"default", `k8s_cluster: cluster name "default" is reserved`},
+		{"docker", `k8s_cluster: cluster name "docker" is reserved`},
+		{"Not_Valid", `k8s_cluster: invalid cluster name "Not_Valid"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			f.file("Tiltfile", `k8s_cluster('`+tc.name+`')`)
+			f.loadErrString(tc.expectedErr)
+		})
+	}
+}
+
+func TestK8sClusterDuplicate(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+k8s_cluster('staging')
+k8s_cluster('staging')
+`)
+
+	f.loadErrString(`k8s_cluster: cluster "staging" already exists`)
+	require.Empty(t, f.loadResult.Manifests)
+}
+
+func TestEntityClusterSurvivesCopy(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	s := &tiltfileState{
+		k8sClusters:       map[string]v1alpha1.KubernetesClusterConnection{"staging": {}},
+		k8sEntityClusters: make(map[k8sEntityID]runtime.Object),
+	}
+	s.k8sEntityClusters[newK8sEntityID(entities[0], "staging")] = entities[0].Obj
+
+	assert.Equal(t, "staging", s.entityCluster(entities[0].DeepCopy()))
+
+	other, err := k8s.ParseYAMLFromString(testyaml.DoggosDeploymentYaml)
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.ClusterNameDefault, s.entityCluster(other[0]))
+}
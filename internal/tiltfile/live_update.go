@@ -60,6 +60,7 @@ func (l liveUpdateFallBackOnStep) declarationPos() string { return l.position.St
 
 type liveUpdateSyncStep struct {
 	localPath, remotePath string
+	delta                 bool
 	position              syntax.Position
 }
 
@@ -75,11 +76,33 @@ func (l liveUpdateSyncStep) Truth() starlark.Bool {
 	return len(l.localPath) > 0 || len(l.remotePath) > 0
 }
 func (l liveUpdateSyncStep) Hash() (uint32, error) {
-	return starlark.Tuple{starlark.String(l.localPath), starlark.String(l.remotePath)}.Hash()
+	return starlark.Tuple{starlark.String(l.localPath), starlark.String(l.remotePath), starlark.Bool(l.delta)}.Hash()
 }
 func (l liveUpdateSyncStep) liveUpdateStep()        {}
 func (l liveUpdateSyncStep) declarationPos() string { return l.position.String() }
 
+type liveUpdateSyncBackStep struct {
+	remotePath, localPath string
+	position              syntax.Position
+}
+
+var _ starlark.Value = liveUpdateSyncBackStep{}
+var _ liveUpdateStep = liveUpdateSyncBackStep{}
+
+func (l liveUpdateSyncBackStep) String() string {
+	return fmt.Sprintf("sync_back step: '%s'->'%s'", l.remotePath, l.localPath)
+}
+func (l liveUpdateSyncBackStep) Type() string { return "live_update_sync_back_step" }
+func (l liveUpdateSyncBackStep) Freeze()      {}
+func (l liveUpdateSyncBackStep) Truth() starlark.Bool {
+	return len(l.remotePath) > 0 || len(l.localPath) > 0
+}
+func (l liveUpdateSyncBackStep) Hash() (uint32, error) {
+	return starlark.Tuple{starlark.String(l.remotePath), starlark.String(l.localPath)}.Hash()
+}
+func (l liveUpdateSyncBackStep) liveUpdateStep()        {}
+func (l liveUpdateSyncBackStep) declarationPos() string { return l.position.String() }
+
 type liveUpdateRunStep struct {
 	command  model.Cmd
 	triggers []string
@@ -148,13 +171,33 @@ func (s *tiltfileState) liveUpdateFallBackOn(thread *starlark.Thread, fn *starla
 
 func (s *tiltfileState) liveUpdateSync(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var localPath, remotePath string
-	if err := s.unpackArgs(fn.Name(), args, kwargs, "local_path", &localPath, "remote_path", &remotePath); err != nil {
+	var delta bool
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"local_path", &localPath,
+		"remote_path", &remotePath,
+		"delta?", &delta); err != nil {
 		return nil, err
 	}
 
 	ret := liveUpdateSyncStep{
 		localPath:  starkit.AbsPath(thread, localPath),
 		remotePath: remotePath,
+		delta:      delta,
+		position:   thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
+func (s *tiltfileState) liveUpdateSyncBack(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var remotePath, localPath string
+	if err := s.unpackArgs(fn.Name(), args, kwargs, "remote_path", &remotePath, "local_path", &localPath); err != nil {
+		return nil, err
+	}
+
+	ret := liveUpdateSyncBackStep{
+		remotePath: remotePath,
+		localPath:  starkit.AbsPath(thread, localPath),
 		position:   thread.CallFrame(1).Pos,
 	}
 	s.recordLiveUpdateStep(ret)
@@ -265,6 +308,25 @@ func (s *tiltfileState) liveUpdateFromSteps(t *starlark.Thread, maybeSteps starl
 			spec.Syncs = append(spec.Syncs, v1alpha1.LiveUpdateSync{
 				LocalPath:     localPath,
 				ContainerPath: x.remotePath,
+				Delta:         x.delta,
+			})
+
+		case liveUpdateSyncBackStep:
+			if noMoreRuns {
+				return v1alpha1.LiveUpdateSpec{}, fmt.Errorf("restart container is only valid as the last step")
+			}
+			noMoreFallbacks = true
+
+			localPath := x.localPath
+			if filepath.IsAbs(localPath) {
+				localPath, err = filepath.Rel(basePath, x.localPath)
+				if err != nil {
+					return v1alpha1.LiveUpdateSpec{}, err
+				}
+			}
+			spec.SyncBacks = append(spec.SyncBacks, v1alpha1.LiveUpdateSyncBack{
+				ContainerPath: x.remotePath,
+				LocalPath:     localPath,
 			})
 
 		case liveUpdateRunStep:
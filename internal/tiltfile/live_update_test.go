@@ -237,6 +237,82 @@ k8s_yaml('foo.yaml')
 		db(image("gcr.io/image-b"), lu))
 }
 
+func TestLiveUpdateSyncDelta(t *testing.T) {
+	f := newFixture(t)
+
+	f.gitInit("")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               sync('a/bin', '/app/bin', delta=True),
+               sync('a/src', '/app/src'),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: filepath.Join("a", "bin"), ContainerPath: "/app/bin", Delta: true},
+			{LocalPath: filepath.Join("a", "src"), ContainerPath: "/app/src"},
+		},
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateSyncBack(t *testing.T) {
+	f := newFixture(t)
+
+	f.gitInit("")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               sync('a', '/app'),
+               run('npm install'),
+               sync_back('/app/package-lock.json', 'a/package-lock.json'),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: "a", ContainerPath: "/app"},
+		},
+		Execs: []v1alpha1.LiveUpdateExec{
+			{Args: []string{"sh", "-c", "npm install"}},
+		},
+		SyncBacks: []v1alpha1.LiveUpdateSyncBack{
+			{ContainerPath: "/app/package-lock.json", LocalPath: filepath.Join("a", "package-lock.json")},
+		},
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateSyncBackRelSource(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/app'),
+    sync_back('app/package-lock.json', 'foo/package-lock.json'),
+  ]
+)`)
+	f.loadErrString("sync back source", "app/package-lock.json", "is not absolute")
+}
+
 func TestLiveUpdateRun(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -3,6 +3,7 @@ package tiltfile
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
@@ -23,6 +24,9 @@ type localResource struct {
 	name      string
 	updateCmd model.Cmd
 	serveCmd  model.Cmd
+	// Run by `tilt down`, in reverse dependency order.
+	teardownCmd     model.Cmd
+	teardownTimeout time.Duration
 	// The working directory of the execution thread where the local resource was created.
 	threadDir     string
 	deps          []string
@@ -40,7 +44,9 @@ type localResource struct {
 func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var name value.Name
 	var updateCmdVal, updateCmdBatVal, serveCmdVal, serveCmdBatVal starlark.Value
-	var updateEnv, serveEnv value.StringStringMap
+	var teardownCmdVal, teardownCmdBatVal, teardownCmdDirVal starlark.Value
+	var teardownTimeout value.Duration
+	var updateEnv, serveEnv, teardownEnv value.StringStringMap
 	var triggerMode triggerMode
 	var readinessProbe probe.Probe
 	var updateCmdDirVal, serveCmdDirVal starlark.Value
@@ -78,6 +84,11 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		"readiness_probe?", &readinessProbe,
 		"dir?", &updateCmdDirVal,
 		"serve_dir?", &serveCmdDirVal,
+		"teardown_cmd?", &teardownCmdVal,
+		"teardown_cmd_bat?", &teardownCmdBatVal,
+		"teardown_env?", &teardownEnv,
+		"teardown_dir?", &teardownCmdDirVal,
+		"teardown_timeout?", &teardownTimeout,
 	); err != nil {
 		return nil, err
 	}
@@ -101,6 +112,11 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		return nil, err
 	}
 
+	teardownCmd, err := value.ValueGroupToCmdHelper(thread, teardownCmdVal, teardownCmdBatVal, teardownCmdDirVal, teardownEnv)
+	if err != nil {
+		return nil, err
+	}
+
 	if updateCmd.Empty() && serveCmd.Empty() {
 		return nil, fmt.Errorf("local_resource must have a cmd and/or a serve_cmd, but both were empty")
 	}
@@ -111,20 +127,29 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		probeSpec = nil
 	}
 
+	if teardownTimeout.AsDuration() < 0 {
+		return nil, fmt.Errorf("%s: teardown_timeout must be non-negative (0 means the default of 30s), got %s", fn.Name(), teardownTimeout.AsDuration())
+	}
+	if !teardownTimeout.IsZero() && teardownCmd.Empty() {
+		s.logger.Warnf("Ignoring teardown_timeout for local resource %q (no teardown_cmd was defined)", name)
+	}
+
 	res := &localResource{
-		name:           string(name),
-		updateCmd:      updateCmd,
-		serveCmd:       serveCmd,
-		threadDir:      filepath.Dir(starkit.CurrentExecPath(thread)),
-		deps:           deps.Value,
-		triggerMode:    triggerMode,
-		autoInit:       autoInit,
-		resourceDeps:   resourceDeps,
-		ignores:        ignores,
-		allowParallel:  allowParallel,
-		links:          links.Links,
-		labels:         labels.Values,
-		readinessProbe: probeSpec,
+		name:            string(name),
+		updateCmd:       updateCmd,
+		serveCmd:        serveCmd,
+		teardownCmd:     teardownCmd,
+		teardownTimeout: teardownTimeout.AsDuration(),
+		threadDir:       filepath.Dir(starkit.CurrentExecPath(thread)),
+		deps:            deps.Value,
+		triggerMode:     triggerMode,
+		autoInit:        autoInit,
+		resourceDeps:    resourceDeps,
+		ignores:         ignores,
+		allowParallel:   allowParallel,
+		links:           links.Links,
+		labels:          labels.Values,
+		readinessProbe:  probeSpec,
 	}
 
 	// check for duplicate resources by name and throw error if found
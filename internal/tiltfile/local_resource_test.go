@@ -1,6 +1,13 @@
 package tiltfile
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
 
 func TestTestFnDeprecated(t *testing.T) {
 	f := newFixture(t)
@@ -10,3 +17,52 @@ test("test", "echo hi")
 `)
 	f.loadAssertWarnings(testDeprecationMsg)
 }
+
+func TestLocalResourceTeardownCmd(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="docker run --name my-db postgres",
+               teardown_cmd="docker rm -f my-db", teardown_env={"KEY": "value"},
+               teardown_timeout="10s")
+`)
+
+	f.load()
+	m := f.assertNextManifest("test")
+	lt := m.LocalTarget()
+	assert.Equal(t, model.ToHostCmdInDirWithEnv("docker rm -f my-db", f.Path(), []string{"KEY=value"}), lt.TeardownCmd)
+	assert.Equal(t, 10*time.Second, lt.TeardownTimeout)
+}
+
+func TestLocalResourceTeardownCmdDefaults(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", "echo hi")
+`)
+
+	f.load()
+	m := f.assertNextManifest("test")
+	assert.True(t, m.LocalTarget().TeardownCmd.Empty())
+	assert.Equal(t, time.Duration(0), m.LocalTarget().TeardownTimeout)
+}
+
+func TestLocalResourceTeardownTimeoutInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", "echo hi", teardown_cmd="echo bye", teardown_timeout="soon")
+`)
+
+	f.loadErrString("teardown_timeout", "invalid duration")
+}
+
+func TestLocalResourceTeardownTimeoutNegative(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", "echo hi", teardown_cmd="echo bye", teardown_timeout="-1s")
+`)
+
+	f.loadErrString("teardown_timeout must be non-negative (0 means the default of 30s), got -1s")
+}
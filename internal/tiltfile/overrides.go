@@ -0,0 +1,227 @@
+package tiltfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/tiltfile/config"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// OverridesFileName is the name of the optional file, next to the main Tiltfile,
+// where each user can override resource settings without editing the Tiltfile.
+//
+// It's meant to be gitignored. The overrides are applied after the Tiltfile
+// executes, so they win over anything that k8s_resource() or local_resource() set.
+//
+// Its "config" section is read by config.parse() instead.
+const OverridesFileName = config.UserOverridesFileName
+
+func OverridesFilePath(tiltfilePath string) string {
+	return filepath.Join(filepath.Dir(tiltfilePath), OverridesFileName)
+}
+
+type overridesFile struct {
+	// Overrides, keyed by resource name.
+	Resources map[string]resourceOverrides `json:"resources"`
+
+	// Values for the settings declared with config.define_*(), in the same
+	// form as tilt_config.json. Handled by config.parse().
+	Config map[string]json.RawMessage `json:"config,omitempty"`
+}
+
+type resourceOverrides struct {
+	// "auto" or "manual"
+	TriggerMode *string `json:"trigger_mode,omitempty"`
+
+	AutoInit *bool `json:"auto_init,omitempty"`
+
+	// Port-forwards to add to the resource, in the same forms
+	// that k8s_resource() accepts (e.g., 8000 or "8000:80").
+	PortForwards []json.RawMessage `json:"port_forwards,omitempty"`
+
+	// Labels to add to the resource.
+	Labels []string `json:"labels,omitempty"`
+
+	Disabled *bool `json:"disabled,omitempty"`
+}
+
+// readOverridesFile reads the overrides file at path.
+//
+// Returns nil if the file doesn't exist.
+func readOverridesFile(path string) (*overridesFile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := &overridesFile{}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(result)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return result, nil
+}
+
+// Apply the overrides file on top of the manifests that the Tiltfile declared.
+//
+// Records which resources the overrides enable or disable, so that
+// the resource selection can weigh them against the args.
+func (s *tiltfileState) applyOverrides(manifests []model.Manifest) ([]model.Manifest, error) {
+	overrides, err := readOverridesFile(s.overridesPath)
+	if err != nil || overrides == nil {
+		return manifests, err
+	}
+
+	indexByName := make(map[string]int, len(manifests))
+	for i, m := range manifests {
+		indexByName[m.Name.String()] = i
+	}
+
+	names := make([]string, 0, len(overrides.Resources))
+	for name := range overrides.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		i, ok := indexByName[name]
+		if !ok {
+			// The overrides file isn't checked in, so it's easy for it to fall
+			// behind the Tiltfile. Don't make a stale entry break the whole load.
+			s.logger.Warnf("%s: ignoring overrides for unknown resource %q", s.overridesPath, name)
+			continue
+		}
+		m, err := s.applyResourceOverrides(manifests[i], overrides.Resources[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: resource %q: %v", s.overridesPath, name, err)
+		}
+		manifests[i] = m
+	}
+	return manifests, nil
+}
+
+func (s *tiltfileState) applyResourceOverrides(m model.Manifest, r resourceOverrides) (model.Manifest, error) {
+	addOverride := func(setting, value string) {
+		m.Overrides = append(m.Overrides, model.ManifestOverride{
+			Setting: setting,
+			Source:  s.overridesPath,
+			Value:   value,
+		})
+	}
+
+	if r.TriggerMode != nil || r.AutoInit != nil {
+		tm := TriggerModeManual
+		if m.TriggerMode.AutoOnChange() {
+			tm = TriggerModeAuto
+		}
+		autoInit := m.TriggerMode.AutoInitial()
+
+		if r.TriggerMode != nil {
+			switch *r.TriggerMode {
+			case "auto":
+				tm = TriggerModeAuto
+			case "manual":
+				tm = TriggerModeManual
+			default:
+				return m, fmt.Errorf("trigger_mode: must be one of \"auto\" or \"manual\", got %q", *r.TriggerMode)
+			}
+			addOverride("trigger_mode", *r.TriggerMode)
+		}
+		if r.AutoInit != nil {
+			autoInit = *r.AutoInit
+			addOverride("auto_init", strconv.FormatBool(autoInit))
+		}
+
+		mode, err := starlarkTriggerModeToModel(tm, autoInit)
+		if err != nil {
+			return m, err
+		}
+		m = m.WithTriggerMode(mode)
+	}
+
+	if len(r.PortForwards) > 0 {
+		var pfs []model.PortForward
+		var descriptions []string
+		for _, raw := range r.PortForwards {
+			pf, err := overridePortForward(raw)
+			if err != nil {
+				return m, fmt.Errorf("port_forwards: %v", err)
+			}
+			pfs = append(pfs, pf)
+			descriptions = append(descriptions, strings.TrimSpace(string(raw)))
+		}
+
+		if !m.IsK8s() {
+			return m, fmt.Errorf("port_forwards: only Kubernetes resources can have port-forwards")
+		}
+
+		kTarget := m.K8sTarget()
+		spec := kTarget.KubernetesApplySpec.DeepCopy()
+		if spec.PortForwardTemplateSpec == nil {
+			spec.PortForwardTemplateSpec = k8s.PortForwardTemplateSpec(s.defaultedPortForwards(pfs))
+		} else {
+			added := k8s.PortForwardTemplateSpec(s.defaultedPortForwards(pfs))
+			spec.PortForwardTemplateSpec.Forwards = append(spec.PortForwardTemplateSpec.Forwards, added.Forwards...)
+		}
+		kTarget.KubernetesApplySpec = *spec
+		m = m.WithDeployTarget(kTarget)
+		addOverride("port_forwards", strings.Join(descriptions, ", "))
+	}
+
+	if len(r.Labels) > 0 {
+		var ls value.LabelSet
+		err := ls.Unpack(value.StringSliceToList(r.Labels))
+		if err != nil {
+			return m, fmt.Errorf("labels: %v", err)
+		}
+
+		labels := make(map[string]string, len(m.Labels)+len(ls.Values))
+		for k, v := range m.Labels {
+			labels[k] = v
+		}
+		for k, v := range ls.Values {
+			labels[k] = v
+		}
+		m = m.WithLabels(labels)
+		addOverride("labels", strings.Join(r.Labels, ", "))
+	}
+
+	if r.Disabled != nil {
+		s.disabledOverrides[m.Name] = *r.Disabled
+		addOverride("disabled", strconv.FormatBool(*r.Disabled))
+	}
+
+	return m, nil
+}
+
+// Port-forwards in the overrides file are either an int or a string,
+// mirroring the port_forwards argument of k8s_resource().
+func overridePortForward(raw json.RawMessage) (model.PortForward, error) {
+	var i int64
+	if err := json.Unmarshal(raw, &i); err == nil {
+		return intToPortForward(starlark.MakeInt64(i))
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return stringToPortForward(starlark.String(str))
+	}
+
+	return model.PortForward{}, fmt.Errorf("expected an int or a string, got %s", string(raw))
+}
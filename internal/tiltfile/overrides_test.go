@@ -0,0 +1,180 @@
+package tiltfile
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestOverridesTriggerMode(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+local_resource('bar', 'echo bar', trigger_mode=TRIGGER_MODE_MANUAL)
+`)
+	f.file(OverridesFileName, `{
+  "resources": {
+    "foo": {"trigger_mode": "manual", "auto_init": false},
+    "bar": {"trigger_mode": "auto"}
+  }
+}`)
+
+	f.load()
+
+	foo := f.assertNextManifest("foo")
+	assert.Equal(t, model.TriggerModeManual, foo.TriggerMode)
+	assert.Equal(t, []model.ManifestOverride{
+		{Setting: "trigger_mode", Source: f.JoinPath(OverridesFileName), Value: "manual"},
+		{Setting: "auto_init", Source: f.JoinPath(OverridesFileName), Value: "false"},
+	}, foo.Overrides)
+
+	bar := f.assertNextManifest("bar")
+	assert.Equal(t, model.TriggerModeAuto, bar.TriggerMode)
+
+	f.assertConfigFiles("Tiltfile", ".tiltignore", OverridesFileName)
+}
+
+func TestOverridesPortForwardsAndLabels(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', port_forwards=8000, labels=['backend'])
+`)
+	f.file(OverridesFileName, `{
+  "resources": {
+    "foo": {"port_forwards": [9000, "9001:80"], "labels": ["mine"]}
+  }
+}`)
+
+	f.load()
+
+	m := f.assertNextManifest("foo",
+		[]model.PortForward{{LocalPort: 8000}, {LocalPort: 9000}, {LocalPort: 9001, ContainerPort: 80}},
+		deployment("foo"))
+	assert.Equal(t, map[string]string{"backend": "backend", "mine": "mine"}, m.Labels)
+	assert.Equal(t, []model.ManifestOverride{
+		{Setting: "port_forwards", Source: f.JoinPath(OverridesFileName), Value: `9000, "9001:80"`},
+		{Setting: "labels", Source: f.JoinPath(OverridesFileName), Value: "mine"},
+	}, m.Overrides)
+}
+
+func TestOverridesDisabled(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+local_resource('bar', 'echo bar')
+local_resource('baz', 'echo baz')
+`)
+	f.file(OverridesFileName, `{
+  "resources": {
+    "foo": {"disabled": true},
+    "baz": {"disabled": false}
+  }
+}`)
+
+	f.load()
+	assert.Equal(t, []model.ManifestName{"bar", "baz"}, f.loadResult.EnabledManifests)
+
+	// Resources named on the command line win over the overrides file.
+	f.load("foo")
+	assert.Equal(t, []model.ManifestName{"foo"}, f.loadResult.EnabledManifests)
+}
+
+func TestOverridesDisabledWinsOverTiltfileSelection(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+config.define_string_list('to-run', args=True)
+cfg = config.parse()
+config.set_enabled_resources(cfg.get('to-run', []))
+local_resource('foo', 'echo foo')
+local_resource('bar', 'echo bar')
+local_resource('baz', 'echo baz')
+`)
+	f.file("tilt_config.json", `{"to-run": ["foo", "bar"]}`)
+	f.file(OverridesFileName, `{
+  "resources": {
+    "foo": {"disabled": true},
+    "baz": {"disabled": false}
+  }
+}`)
+
+	f.load()
+	assert.Equal(t, []model.ManifestName{"bar", "baz"}, f.loadResult.EnabledManifests)
+
+	// Args passed through config.parse() still win.
+	f.load("foo")
+	assert.Equal(t, []model.ManifestName{"foo", "baz"}, f.loadResult.EnabledManifests)
+}
+
+func TestOverridesConfigArgs(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+config.define_string_list('to-run', args=True)
+cfg = config.parse()
+config.set_enabled_resources(cfg.get('to-run', []))
+local_resource('foo', 'echo foo')
+local_resource('bar', 'echo bar')
+`)
+	f.file(OverridesFileName, `{
+  "config": {"to-run": ["bar"]},
+  "resources": {"bar": {"trigger_mode": "manual"}}
+}`)
+
+	f.load()
+
+	assert.Equal(t, []model.ManifestName{"bar"}, f.loadResult.EnabledManifests)
+}
+
+func TestOverridesUnknownResource(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `local_resource('foo', 'echo foo')`)
+	f.file(OverridesFileName, `{"resources": {"bar": {"disabled": true}}}`)
+
+	f.loadAllowWarnings()
+
+	f.assertWarnings(fmt.Sprintf("%s: ignoring overrides for unknown resource \"bar\"", f.JoinPath(OverridesFileName)))
+	assert.Equal(t, []model.ManifestName{"foo"}, f.loadResult.EnabledManifests)
+}
+
+func TestOverridesUnknownSetting(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `local_resource('foo', 'echo foo')`)
+	f.file(OverridesFileName, `{"resources": {"foo": {"trigger": "manual"}}}`)
+
+	f.loadErrString(OverridesFileName, `unknown field "trigger"`)
+}
+
+func TestOverridesInvalidValues(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		overrides   string
+		expectedErr string
+	}{
+		{"trigger_mode", `{"trigger_mode": "sometimes"}`, `resource "foo": trigger_mode: must be one of "auto" or "manual", got "sometimes"`},
+		{"port_forwards", `{"port_forwards": [true]}`, `resource "foo": port_forwards: expected an int or a string, got true`},
+		{"port_forwards_range", `{"port_forwards": [70000]}`, `resource "foo": port_forwards: portForward port value 70000 is not in the valid range`},
+		{"port_forwards_local", `{"port_forwards": [8000]}`, `resource "foo": port_forwards: only Kubernetes resources can have port-forwards`},
+		{"labels", `{"labels": ["not valid!"]}`, `resource "foo": labels:`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			f.file("Tiltfile", `local_resource('foo', 'echo foo')`)
+			f.file(OverridesFileName, `{"resources": {"foo": `+tc.overrides+`}}`)
+
+			f.loadErrString(tc.expectedErr)
+		})
+	}
+}
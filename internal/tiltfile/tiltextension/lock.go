@@ -0,0 +1,152 @@
+package tiltextension
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LockFileName is the name of the file, next to the main Tiltfile, that pins
+// each ext:// load to the exact code that it resolved to.
+const LockFileName = "tilt_extensions.lock"
+
+type LockFile struct {
+	// Extensions, keyed by the name used in the ext:// load.
+	Extensions map[string]LockedExtension `json:"extensions"`
+}
+
+type LockedExtension struct {
+	// The URL of the extension repo.
+	Repo string `json:"repo"`
+
+	// The commit that the extension repo was checked out at.
+	//
+	// Empty for repos that aren't fetched with git (e.g., file:// repos).
+	Commit string `json:"commit,omitempty"`
+
+	// A hash of the contents of the extension directory.
+	Hash string `json:"hash"`
+}
+
+func NewLockFile() *LockFile {
+	return &LockFile{Extensions: make(map[string]LockedExtension)}
+}
+
+func LockFilePath(tiltfilePath string) string {
+	return filepath.Join(filepath.Dir(tiltfilePath), LockFileName)
+}
+
+// ReadLockFile reads the lockfile at path.
+//
+// Returns nil if the lockfile doesn't exist.
+func ReadLockFile(path string) (*LockFile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	lock := NewLockFile()
+	err = json.Unmarshal(contents, lock)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	if lock.Extensions == nil {
+		lock.Extensions = make(map[string]LockedExtension)
+	}
+	return lock, nil
+}
+
+func WriteLockFile(path string, lock *LockFile) error {
+	contents, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// Names returns the locked extension names in sorted order.
+func (l *LockFile) Names() []string {
+	result := make([]string, 0, len(l.Extensions))
+	for name := range l.Extensions {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Check that the extension resolved to the locked version.
+func (l LockedExtension) verify(name string, resolved LockedExtension) error {
+	if l.Repo != resolved.Repo {
+		return lockMismatchError(name, "repo", l.Repo, resolved.Repo)
+	}
+	if l.Commit != "" && l.Commit != resolved.Commit {
+		return lockMismatchError(name, "commit", l.Commit, resolved.Commit)
+	}
+	if l.Hash != resolved.Hash {
+		return lockMismatchError(name, "hash", l.Hash, resolved.Hash)
+	}
+	return nil
+}
+
+func lockMismatchError(name, field, expected, actual string) error {
+	return fmt.Errorf("extension %s does not match %s: expected %s %s, got %s.\n"+
+		"If this change is expected, run `tilt alpha extensions update` to update the lockfile",
+		name, LockFileName, field, expected, actual)
+}
+
+// HashDir computes a hash of the contents of an extension directory.
+//
+// The hash covers the relative path and contents of every regular file, and the
+// target of every symlink, so that renames are detected as well as edits.
+// VCS metadata is skipped.
+func HashDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		var contents []byte
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			contents = []byte("symlink:" + target)
+		} else if d.Type().IsRegular() {
+			contents, err = os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+		} else {
+			return nil
+		}
+
+		fmt.Fprintf(h, "%x  %s\n", sha256.Sum256(contents), strings.ReplaceAll(rel, "\n", `\n`))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hashing %s: %v", dir, err)
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
@@ -7,7 +7,11 @@ package tiltextension
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"go.starlark.net/starlark"
 
@@ -16,6 +20,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apiset"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extension"
 	"github.com/tilt-dev/tilt/internal/controllers/core/extensionrepo"
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	tiltfilev1alpha1 "github.com/tilt-dev/tilt/internal/tiltfile/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/apis"
@@ -26,9 +31,45 @@ import (
 const extensionPrefix = "ext://"
 const defaultRepoName = "default"
 
+// LockMode controls how ext:// loads interact with the lockfile.
+type LockMode int
+
+const (
+	// Pin each extension to the version in the lockfile, and fail
+	// the load if it resolves to anything else.
+	//
+	// If there's no lockfile, extensions aren't pinned.
+	LockModeVerify LockMode = iota
+
+	// Ignore the lockfile, and record what each extension resolves to.
+	LockModeUpdate
+)
+
 type Plugin struct {
 	repoReconciler ExtRepoReconciler
 	extReconciler  ExtReconciler
+
+	mu              sync.Mutex
+	lockMode        LockMode
+	ignoreVendorDir bool
+	resolved        map[string]ResolvedExtension
+}
+
+// ResolvedExtension describes where an ext:// load was loaded from.
+type ResolvedExtension struct {
+	// The name used in the ext:// load.
+	Name string
+
+	// The repo URL and commit that the extension resolved to.
+	//
+	// The hash is only filled in when the lockfile is in use.
+	LockedExtension
+
+	// The directory that contains the extension's Tiltfile.
+	Dir string
+
+	// True if the extension was loaded from the vendor directory.
+	Vendored bool
 }
 
 func NewPlugin(repoReconciler *extensionrepo.Reconciler, extReconciler *extension.Reconciler) *Plugin {
@@ -47,9 +88,76 @@ func NewFakePlugin(repoReconciler ExtRepoReconciler, extReconciler ExtReconciler
 
 type State struct {
 	ExtsLoaded map[string]bool
+
+	// The lockfile next to the main Tiltfile.
+	// Read on the first ext:// load; nil if there is no lockfile.
+	lock     *LockFile
+	lockRead bool
+}
+
+// SetLockMode changes how subsequent Tiltfile loads use the lockfile.
+//
+// Clears the extensions recorded by earlier loads.
+func (e *Plugin) SetLockMode(mode LockMode) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lockMode = mode
+	e.resolved = nil
 }
 
-func (e Plugin) NewState() interface{} {
+// SetIgnoreVendorDir controls whether subsequent Tiltfile loads
+// skip vendored extensions and always resolve extensions from their repos.
+//
+// The vendor dir is always ignored in LockModeUpdate.
+func (e *Plugin) SetIgnoreVendorDir(ignore bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ignoreVendorDir = ignore
+}
+
+// UpdatedLockFile returns the versions that extensions resolved to
+// while in LockModeUpdate.
+func (e *Plugin) UpdatedLockFile() *LockFile {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := NewLockFile()
+	for name, r := range e.resolved {
+		result.Extensions[name] = r.LockedExtension
+	}
+	return result
+}
+
+// Resolved returns the extensions loaded since the lock mode was last set,
+// sorted by name.
+func (e *Plugin) Resolved() []ResolvedExtension {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := make([]ResolvedExtension, 0, len(e.resolved))
+	for _, r := range e.resolved {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (e *Plugin) loadOptions() (LockMode, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lockMode, e.ignoreVendorDir || e.lockMode == LockModeUpdate
+}
+
+func (e *Plugin) recordResolved(r ResolvedExtension) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.resolved == nil {
+		e.resolved = make(map[string]ResolvedExtension)
+	}
+	e.resolved[r.Name] = r
+}
+
+func (e *Plugin) NewState() interface{} {
 	return State{
 		ExtsLoaded: make(map[string]bool),
 	}
@@ -99,9 +207,45 @@ func (e *Plugin) LocalPath(t *starlark.Thread, arg string) (localPath string, er
 		return "", err
 	}
 
+	lockMode, ignoreVendorDir := e.loadOptions()
+	if !ignoreVendorDir {
+		path, ok, err := e.loadVendored(t, objSet, moduleName)
+		if err != nil || ok {
+			return path, err
+		}
+	}
+
 	ext := e.ensureExtension(t, objSet, moduleName)
 	repo := e.ensureRepo(t, objSet, ext.Spec.RepoName)
-	repoStatus := e.repoReconciler.ForceApply(ctx, repo)
+
+	var locked *LockedExtension
+	var lockedRef string
+	if lockMode == LockModeVerify {
+		lock, err := e.readLockFile(t)
+		if err != nil {
+			return "", err
+		}
+		if lock != nil {
+			entry, ok := lock.Extensions[moduleName]
+			if !ok {
+				return "", fmt.Errorf("extension %s is not in %s.\n"+
+					"Run `tilt alpha extensions update` to add it", moduleName, LockFileName)
+			}
+			if entry.Repo != repo.Spec.URL {
+				return "", lockMismatchError(moduleName, "repo", entry.Repo, repo.Spec.URL)
+			}
+			locked = &entry
+
+			// Check out the locked commit, unless the Tiltfile asked for a
+			// specific ref. Extensions in the same repo may be locked to
+			// different commits, so this doesn't change the registered repo.
+			if repo.Spec.Ref == "" {
+				lockedRef = entry.Commit
+			}
+		}
+	}
+
+	repoStatus := e.repoReconciler.ForceApplyAtRef(ctx, repo, lockedRef)
 	if repoStatus.Error != "" {
 		return "", fmt.Errorf("loading extension repo %s: %s", repo.Name, repoStatus.Error)
 	}
@@ -119,15 +263,165 @@ func (e *Plugin) LocalPath(t *starlark.Thread, arg string) (localPath string, er
 		return "", fmt.Errorf("extension not resolved: %s", ext.Name)
 	}
 
+	resolved := ResolvedExtension{
+		Name: moduleName,
+		LockedExtension: LockedExtension{
+			Repo:   repo.Spec.URL,
+			Commit: repoStatus.CheckoutRef,
+		},
+		Dir: filepath.Dir(extStatus.Path),
+	}
+	if lockMode == LockModeUpdate || locked != nil {
+		hash, err := HashDir(resolved.Dir)
+		if err != nil {
+			return "", fmt.Errorf("loading extension %s: %v", ext.Name, err)
+		}
+		resolved.Hash = hash
+		if locked != nil {
+			err := locked.verify(moduleName, resolved.LockedExtension)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	e.recordResolved(resolved)
+
 	return extStatus.Path, nil
 }
 
+// Loads the extension from the vendor dir, if it's been vendored.
+//
+// Vendored extensions don't register any API objects, so that Tilt never tries
+// to fetch their repos. If there's a lockfile, the vendored copy must match it.
+func (e *Plugin) loadVendored(t *starlark.Thread, objSet apiset.ObjectSet, moduleName string) (string, bool, error) {
+	tfPath, ok := startTiltfilePath(t)
+	if !ok {
+		return "", false, nil
+	}
+
+	dir, ok := vendoredExtensionDir(VendorDirPath(tfPath), moduleName)
+	if !ok {
+		return "", false, nil
+	}
+	path := filepath.Join(dir, "Tiltfile")
+	_, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("loading vendored extension %s: %v", moduleName, err)
+	}
+
+	ext, _ := e.findExtension(objSet, moduleName)
+	repo, _ := e.findRepo(objSet, ext.Spec.RepoName)
+	resolved := ResolvedExtension{
+		Name:            moduleName,
+		LockedExtension: LockedExtension{Repo: repo.Spec.URL},
+		Dir:             dir,
+		Vendored:        true,
+	}
+
+	lock, err := e.readLockFile(t)
+	if err != nil {
+		return "", false, err
+	}
+	if lock != nil {
+		entry, ok := lock.Extensions[moduleName]
+		if !ok {
+			return "", false, fmt.Errorf("extension %s is not in %s.\n"+
+				"Run `tilt alpha extensions update` to add it", moduleName, LockFileName)
+		}
+
+		hash, err := HashDir(dir)
+		if err != nil {
+			return "", false, fmt.Errorf("loading vendored extension %s: %v", moduleName, err)
+		}
+
+		// The vendored copy has no git history, so it's identified by its contents.
+		resolved.Commit = entry.Commit
+		resolved.Hash = hash
+		err = entry.verify(moduleName, resolved.LockedExtension)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	e.recordResolved(resolved)
+	return path, true, nil
+}
+
+// Reads the lockfile next to the main Tiltfile, at most once per load.
+// Returns nil if there is no lockfile, or no main Tiltfile.
+func (e *Plugin) readLockFile(t *starlark.Thread) (*LockFile, error) {
+	starkitModel, err := starkit.ModelFromThread(t)
+	if err != nil {
+		return nil, err
+	}
+	state, err := GetState(starkitModel)
+	if err != nil {
+		return nil, err
+	}
+	if state.lockRead {
+		return state.lock, nil
+	}
+
+	tfPath, ok := startTiltfilePath(t)
+	if !ok {
+		return nil, nil
+	}
+	path := LockFilePath(tfPath)
+
+	// Reload the Tiltfile when the pins change.
+	err = io.RecordReadPath(t, io.WatchFileOnly, path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := ReadLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = starkit.SetState(t, func(existing State) (State, error) {
+		existing.lock = lock
+		existing.lockRead = true
+		return existing, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Returns the path of the main Tiltfile, or false if we're resolving ext://
+// paths outside of a Tiltfile load (e.g., in the language server).
+func startTiltfilePath(t *starlark.Thread) (string, bool) {
+	tf, err := starkit.StartTiltfileFromThread(t)
+	if err != nil {
+		return "", false
+	}
+	return tf.Spec.Path, true
+}
+
 // Check to see if an extension has already been registered.
 //
 // If it has, returns the existing object (which should only have a spec).
 //
 // Otherwise, infers an extension object that points to the default repo.
 func (e *Plugin) ensureExtension(t *starlark.Thread, objSet apiset.ObjectSet, moduleName string) *v1alpha1.Extension {
+	ext, exists := e.findExtension(objSet, moduleName)
+	if exists {
+		metav1.SetMetaDataAnnotation(&ext.ObjectMeta, v1alpha1.AnnotationManagedBy, "tiltfile.loader")
+		return ext
+	}
+
+	objSet.GetOrCreateTypedSet(ext)[ext.Name] = ext
+	return ext
+}
+
+// Returns the registered extension, or the inferred default if there isn't one.
+// Never registers anything.
+func (e *Plugin) findExtension(objSet apiset.ObjectSet, moduleName string) (*v1alpha1.Extension, bool) {
 	extName := apis.SanitizeName(moduleName)
 	defaultExt := &v1alpha1.Extension{
 		ObjectMeta: metav1.ObjectMeta{
@@ -142,16 +436,11 @@ func (e *Plugin) ensureExtension(t *starlark.Thread, objSet apiset.ObjectSet, mo
 		},
 	}
 
-	typedSet := objSet.GetOrCreateTypedSet(defaultExt)
-	existing, exists := typedSet[extName]
+	existing, exists := objSet.GetOrCreateTypedSet(defaultExt)[extName]
 	if exists {
-		ext := existing.(*v1alpha1.Extension)
-		metav1.SetMetaDataAnnotation(&ext.ObjectMeta, v1alpha1.AnnotationManagedBy, "tiltfile.loader")
-		return ext
+		return existing.(*v1alpha1.Extension), true
 	}
-
-	typedSet[extName] = defaultExt
-	return defaultExt
+	return defaultExt, false
 }
 
 // Check to see if an extension repo has already been registered.
@@ -160,6 +449,18 @@ func (e *Plugin) ensureExtension(t *starlark.Thread, objSet apiset.ObjectSet, mo
 //
 // Otherwise, register the default repo.
 func (e *Plugin) ensureRepo(t *starlark.Thread, objSet apiset.ObjectSet, repoName string) *v1alpha1.ExtensionRepo {
+	repo, exists := e.findRepo(objSet, repoName)
+	if exists {
+		return repo
+	}
+
+	objSet.GetOrCreateTypedSet(repo)[repoName] = repo
+	return repo
+}
+
+// Returns the registered extension repo, or the default repo if there isn't one.
+// Never registers anything.
+func (e *Plugin) findRepo(objSet apiset.ObjectSet, repoName string) (*v1alpha1.ExtensionRepo, bool) {
 	defaultRepo := &v1alpha1.ExtensionRepo{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: repoName,
@@ -169,14 +470,11 @@ func (e *Plugin) ensureRepo(t *starlark.Thread, objSet apiset.ObjectSet, repoNam
 		},
 	}
 
-	typedSet := objSet.GetOrCreateTypedSet(defaultRepo)
-	existing, exists := typedSet[repoName]
+	existing, exists := objSet.GetOrCreateTypedSet(defaultRepo)[repoName]
 	if exists {
-		return existing.(*v1alpha1.ExtensionRepo)
+		return existing.(*v1alpha1.ExtensionRepo), true
 	}
-
-	typedSet[repoName] = defaultRepo
-	return defaultRepo
+	return defaultRepo, false
 }
 
 var _ starkit.LoadInterceptor = (*Plugin)(nil)
@@ -8,11 +8,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/tiltfile/include"
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	tiltfilev1alpha1 "github.com/tilt-dev/tilt/internal/tiltfile/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
 func TestFetchableAlreadyPresentWorks(t *testing.T) {
@@ -197,6 +200,168 @@ type extensionFixture struct {
 	tmp   *tempdir.TempDirFixture
 	extr  *FakeExtReconciler
 	extrr *FakeExtRepoReconciler
+	ext   *Plugin
+}
+
+func TestLockFileUpdateAndVerify(t *testing.T) {
+	f := newExtensionFixture(t)
+	f.extrr.HeadRef = "abc123"
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.writeModuleLocally("fetchable", libText)
+
+	f.ext.SetLockMode(LockModeUpdate)
+	f.assertExecOutput("foo")
+	lock := f.ext.UpdatedLockFile()
+	require.Equal(t, []string{"fetchable"}, lock.Names())
+	assert.Equal(t, "https://github.com/tilt-dev/tilt-extensions", lock.Extensions["fetchable"].Repo)
+	assert.Equal(t, "abc123", lock.Extensions["fetchable"].Commit)
+	assert.Contains(t, lock.Extensions["fetchable"].Hash, "sha256:")
+	require.NoError(t, WriteLockFile(f.skf.JoinPath(LockFileName), lock))
+
+	f.ext.SetLockMode(LockModeVerify)
+	f.assertExecOutput("foo")
+
+	f.writeModuleLocally("fetchable", libText+"\n# local edit\n")
+	f.assertError("extension fetchable does not match tilt_extensions.lock: expected hash " + lock.Extensions["fetchable"].Hash)
+}
+
+func TestLockFilePinsCommit(t *testing.T) {
+	f := newExtensionFixture(t)
+	f.extrr.HeadRef = "newer"
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.writeModuleLocally("fetchable", libText)
+	f.writeLockFile("fetchable", "pinned")
+
+	result := f.assertExecOutput("foo")
+	require.Len(t, f.ext.Resolved(), 1)
+	assert.Equal(t, "pinned", f.ext.Resolved()[0].Commit)
+
+	// The registered repo is shared by every extension in it,
+	// so it isn't pinned.
+	objSet := tiltfilev1alpha1.MustState(result)
+	repo := objSet.GetOrCreateTypedSet(&v1alpha1.ExtensionRepo{})["default"].(*v1alpha1.ExtensionRepo)
+	assert.Equal(t, "", repo.Spec.Ref)
+}
+
+func TestLockFilePinsCommitPerExtension(t *testing.T) {
+	f := newExtensionFixture(t)
+	f.extrr.HeadRef = "newer"
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+load("ext://other", printOtherFoo="printFoo")
+printFoo()
+printOtherFoo()
+`)
+	f.writeModuleLocally("fetchable", libText)
+	f.writeModuleLocally("other", libText)
+	f.writeLockFile("fetchable", "pinned")
+	f.addToLockFile("other", "other-pinned")
+
+	f.assertExecOutput("foo\nfoo")
+	resolved := f.ext.Resolved()
+	require.Len(t, resolved, 2)
+	assert.Equal(t, "fetchable", resolved[0].Name)
+	assert.Equal(t, "pinned", resolved[0].Commit)
+	assert.Equal(t, "other", resolved[1].Name)
+	assert.Equal(t, "other-pinned", resolved[1].Commit)
+}
+
+func TestLockFileCommitMismatch(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+v1alpha1.extension_repo(name='default', url='https://github.com/tilt-dev/tilt-extensions', ref='v1.0')
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.writeModuleLocally("fetchable", libText)
+	f.writeLockFile("fetchable", "pinned")
+
+	f.assertError("extension fetchable does not match tilt_extensions.lock: expected commit pinned, got v1.0")
+}
+
+func TestLockFileMissingExtension(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://unlocked", "printFoo")
+printFoo()
+`)
+	f.writeModuleLocally("fetchable", libText)
+	f.writeModuleLocally("unlocked", libText)
+	f.writeLockFile("fetchable", "")
+
+	f.assertError("extension unlocked is not in tilt_extensions.lock")
+}
+
+func TestVendoredExtensionPreferred(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://vendored", "printFoo")
+printFoo()
+`)
+	f.skf.File(filepath.Join(VendorDirName, "vendored", "Tiltfile"), libText)
+	f.extrr.Error = "no network"
+
+	result := f.assertExecOutput("foo")
+	f.assertLoadRecorded(result, "vendored")
+
+	// Vendored extensions shouldn't register repos that Tilt will try to fetch.
+	objSet := tiltfilev1alpha1.MustState(result)
+	assert.Empty(t, objSet.GetOrCreateTypedSet(&v1alpha1.ExtensionRepo{}))
+	assert.Empty(t, objSet.GetOrCreateTypedSet(&v1alpha1.Extension{}))
+
+	resolved := f.ext.Resolved()
+	require.Len(t, resolved, 1)
+	assert.True(t, resolved[0].Vendored)
+	assert.Equal(t, f.skf.JoinPath(VendorDirName, "vendored"), resolved[0].Dir)
+}
+
+func TestIgnoreVendorDir(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.skf.File(filepath.Join(VendorDirName, "fetchable", "Tiltfile"), `fail("vendored copy loaded")`)
+	f.writeModuleLocally("fetchable", libText)
+
+	f.ext.SetIgnoreVendorDir(true)
+	f.assertExecOutput("foo")
+
+	resolved := f.ext.Resolved()
+	require.Len(t, resolved, 1)
+	assert.False(t, resolved[0].Vendored)
+	assert.Equal(t, f.tmp.JoinPath("tilt-extensions", "fetchable"), resolved[0].Dir)
+}
+
+func TestVendoredExtensionVerifiedAgainstLockFile(t *testing.T) {
+	f := newExtensionFixture(t)
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.writeModuleLocally("fetchable", libText)
+	f.writeLockFile("fetchable", "pinned")
+	require.NoError(t, VendorExtension(f.skf.JoinPath(VendorDirName), "fetchable", f.tmp.JoinPath("tilt-extensions", "fetchable")))
+	f.extrr.Error = "no network"
+
+	f.assertExecOutput("foo")
+
+	f.skf.File(filepath.Join(VendorDirName, "fetchable", "Tiltfile"), libText+"\n# local edit\n")
+	f.assertError("extension fetchable does not match tilt_extensions.lock: expected hash")
 }
 
 func newExtensionFixture(t *testing.T) *extensionFixture {
@@ -208,7 +373,7 @@ func newExtensionFixture(t *testing.T) *extensionFixture {
 		extrr,
 		extr,
 	)
-	skf := starkit.NewFixture(t, ext, include.IncludeFn{}, tiltfilev1alpha1.NewPlugin())
+	skf := starkit.NewFixture(t, ext, include.IncludeFn{}, tiltfilev1alpha1.NewPlugin(), io.NewPlugin())
 	skf.UseRealFS()
 
 	return &extensionFixture{
@@ -217,6 +382,7 @@ func newExtensionFixture(t *testing.T) *extensionFixture {
 		tmp:   tmp,
 		extr:  extr,
 		extrr: extrr,
+		ext:   ext,
 	}
 }
 
@@ -265,6 +431,36 @@ func (f *extensionFixture) writeModuleLocally(name string, contents string) {
 	f.tmp.WriteFile(filepath.Join("tilt-extensions", name, "Tiltfile"), contents)
 }
 
+// Lock the extension to its current contents.
+func (f *extensionFixture) writeLockFile(name string, commit string) {
+	hash, err := HashDir(f.tmp.JoinPath("tilt-extensions", name))
+	require.NoError(f.t, err)
+
+	lock := NewLockFile()
+	lock.Extensions[name] = LockedExtension{
+		Repo:   "https://github.com/tilt-dev/tilt-extensions",
+		Commit: commit,
+		Hash:   hash,
+	}
+	require.NoError(f.t, WriteLockFile(f.skf.JoinPath(LockFileName), lock))
+}
+
+// Add the extension to the lockfile, at its current contents.
+func (f *extensionFixture) addToLockFile(name string, commit string) {
+	hash, err := HashDir(f.tmp.JoinPath("tilt-extensions", name))
+	require.NoError(f.t, err)
+
+	path := f.skf.JoinPath(LockFileName)
+	lock, err := ReadLockFile(path)
+	require.NoError(f.t, err)
+	lock.Extensions[name] = LockedExtension{
+		Repo:   "https://github.com/tilt-dev/tilt-extensions",
+		Commit: commit,
+		Hash:   hash,
+	}
+	require.NoError(f.t, WriteLockFile(path, lock))
+}
+
 const libText = `
 def printFoo():
   print("foo")
@@ -9,7 +9,7 @@ import (
 
 // Interfaces with just the reconciler methods we need.
 type ExtRepoReconciler interface {
-	ForceApply(ctx context.Context, repo *v1alpha1.ExtensionRepo) v1alpha1.ExtensionRepoStatus
+	ForceApplyAtRef(ctx context.Context, repo *v1alpha1.ExtensionRepo, ref string) v1alpha1.ExtensionRepoStatus
 }
 
 type ExtReconciler interface {
@@ -20,18 +20,28 @@ type ExtReconciler interface {
 type FakeExtRepoReconciler struct {
 	path  string
 	Error string
+
+	// The commit that repos are checked out at, if they don't specify a ref.
+	HeadRef string
 }
 
 func NewFakeExtRepoReconciler(path string) *FakeExtRepoReconciler {
 	return &FakeExtRepoReconciler{path: path}
 }
 
-func (r *FakeExtRepoReconciler) ForceApply(ctx context.Context, repo *v1alpha1.ExtensionRepo) v1alpha1.ExtensionRepoStatus {
+func (r *FakeExtRepoReconciler) ForceApplyAtRef(ctx context.Context, repo *v1alpha1.ExtensionRepo, ref string) v1alpha1.ExtensionRepoStatus {
 	if r.Error != "" {
 		return v1alpha1.ExtensionRepoStatus{Error: r.Error}
 	}
+	if ref == "" {
+		ref = repo.Spec.Ref
+	}
+	if ref == "" {
+		ref = r.HeadRef
+	}
 	return v1alpha1.ExtensionRepoStatus{
-		Path: filepath.Join(r.path, filepath.Base(repo.Spec.URL)),
+		Path:        filepath.Join(r.path, filepath.Base(repo.Spec.URL)),
+		CheckoutRef: ref,
 	}
 }
 
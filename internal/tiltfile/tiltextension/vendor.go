@@ -0,0 +1,159 @@
+package tiltextension
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// VendorDirName is the name of the directory, next to the main Tiltfile, that
+// holds local copies of extensions.
+//
+// When an extension has been vendored, ext:// loads use the local copy instead
+// of fetching the extension repo, so that Tiltfiles can load without network access.
+const VendorDirName = "tilt_extensions"
+
+func VendorDirPath(tiltfilePath string) string {
+	return filepath.Join(filepath.Dir(tiltfilePath), VendorDirName)
+}
+
+// The directory that an extension is vendored to.
+//
+// Returns false if the extension name can't be mapped to a directory
+// inside the vendor dir.
+func vendoredExtensionDir(vendorDir string, name string) (string, bool) {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(rel) || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.Join(vendorDir, rel), true
+}
+
+// VendorExtension copies the extension in src to the vendor directory,
+// replacing any existing copy.
+func VendorExtension(vendorDir string, name string, src string) error {
+	dest, ok := vendoredExtensionDir(vendorDir, name)
+	if !ok {
+		return fmt.Errorf("vendoring extension %s: invalid name", name)
+	}
+
+	err := os.RemoveAll(dest)
+	if err != nil {
+		return fmt.Errorf("vendoring extension %s: %v", name, err)
+	}
+
+	err = copyDir(src, dest)
+	if err != nil {
+		return fmt.Errorf("vendoring extension %s: %v", name, err)
+	}
+	return nil
+}
+
+// VendoredExtensions returns the names of the extensions in the vendor directory,
+// in sorted order.
+//
+// An extension is any directory with a Tiltfile in it.
+func VendoredExtensions(vendorDir string) ([]string, error) {
+	var result []string
+	err := filepath.WalkDir(vendorDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == vendorDir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() || path == vendorDir {
+			return nil
+		}
+
+		_, err = os.Stat(filepath.Join(path, "Tiltfile"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(vendorDir, path)
+		if err != nil {
+			return err
+		}
+		result = append(result, filepath.ToSlash(rel))
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// RemoveVendoredExtension deletes an extension from the vendor directory,
+// along with any parent directories that are left empty.
+func RemoveVendoredExtension(vendorDir string, name string) error {
+	dir, ok := vendoredExtensionDir(vendorDir, name)
+	if !ok {
+		return fmt.Errorf("removing extension %s: invalid name", name)
+	}
+
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return fmt.Errorf("removing extension %s: %v", name, err)
+	}
+
+	for parent := filepath.Dir(dir); parent != vendorDir && parent != filepath.Dir(vendorDir); parent = filepath.Dir(parent) {
+		// Fails if the directory isn't empty.
+		if os.Remove(parent) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// Copies the regular files, directories, and symlinks in src to dest.
+// VCS metadata is skipped, to match HashDir.
+func copyDir(src string, dest string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, contents, info.Mode().Perm())
+	})
+}
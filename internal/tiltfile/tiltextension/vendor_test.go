@@ -0,0 +1,68 @@
+package tiltextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestVendorExtension(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("src/Tiltfile", "print('hi')")
+	f.WriteFile("src/lib/helpers.star", "x = 1")
+	f.WriteFile("src/.git/HEAD", "ref: refs/heads/main")
+	f.WriteFile("vendor/foo/stale.txt", "stale")
+
+	err := VendorExtension(f.JoinPath("vendor"), "foo", f.JoinPath("src"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "print('hi')", f.ReadFile("vendor/foo/Tiltfile"))
+	assert.Equal(t, "x = 1", f.ReadFile("vendor/foo/lib/helpers.star"))
+	assert.NoFileExists(t, f.JoinPath("vendor/foo/.git/HEAD"))
+	assert.NoFileExists(t, f.JoinPath("vendor/foo/stale.txt"))
+
+	srcHash, err := HashDir(f.JoinPath("src"))
+	require.NoError(t, err)
+	vendorHash, err := HashDir(f.JoinPath("vendor/foo"))
+	require.NoError(t, err)
+	assert.Equal(t, srcHash, vendorHash)
+}
+
+func TestVendorExtensionInvalidName(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("src/Tiltfile", "print('hi')")
+
+	err := VendorExtension(f.JoinPath("vendor"), "../escape", f.JoinPath("src"))
+	require.EqualError(t, err, "vendoring extension ../escape: invalid name")
+}
+
+func TestVendoredExtensionsAndRemove(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("vendor/foo/Tiltfile", "")
+	f.WriteFile("vendor/foo/nested/Tiltfile", "")
+	f.WriteFile("vendor/org/bar/Tiltfile", "")
+	f.WriteFile("vendor/README.md", "")
+
+	names, err := VendoredExtensions(f.JoinPath("vendor"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "org/bar"}, names)
+
+	require.NoError(t, RemoveVendoredExtension(f.JoinPath("vendor"), "org/bar"))
+	assert.NoDirExists(t, f.JoinPath("vendor/org"))
+	assert.DirExists(t, f.JoinPath("vendor"))
+
+	names, err = VendoredExtensions(f.JoinPath("vendor"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, names)
+}
+
+func TestVendoredExtensionsNoVendorDir(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+
+	names, err := VendoredExtensions(f.JoinPath("vendor"))
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	tiltfileanalytics "github.com/tilt-dev/tilt/internal/tiltfile/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/cisettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/internal/tiltfile/dockerprune"
 	"github.com/tilt-dev/tilt/internal/tiltfile/hasher"
@@ -55,8 +56,11 @@ type TiltfileLoadResult struct {
 	AnalyticsOpt        wmanalytics.Opt
 	VersionSettings     model.VersionSettings
 	UpdateSettings      model.UpdateSettings
+	CISettings          model.CISettings
 	WatchSettings       model.WatchSettings
 	DefaultRegistry     *corev1alpha1.RegistryHosting
+	K8sClusters         map[string]corev1alpha1.KubernetesClusterConnection
+	BuildkitBuilders    map[string]corev1alpha1.BuildkitClusterConnection
 	ObjectSet           apiset.ObjectSet
 	Hashes              hasher.Hashes
 
@@ -154,8 +158,9 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	}
 
 	tiltignorePath := watch.TiltignorePath(absFilename)
+	overridesPath := OverridesFilePath(absFilename)
 	tlr := TiltfileLoadResult{
-		ConfigFiles: []string{absFilename, tiltignorePath},
+		ConfigFiles: []string{absFilename, tiltignorePath, overridesPath},
 	}
 
 	tiltignore, err := watch.ReadTiltignore(tiltignorePath)
@@ -170,11 +175,14 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 
 	s := newTiltfileState(ctx, tfl.dcCli, tfl.webHost, tfl.execer, tfl.k8sContextPlugin, tfl.versionPlugin,
 		tfl.configPlugin, tfl.extensionPlugin, feature.FromDefaults(tfl.fDefaults))
+	s.overridesPath = overridesPath
 
 	manifests, result, err := s.loadManifests(tf)
 
 	tlr.BuiltinCalls = result.BuiltinCalls
 	tlr.DefaultRegistry = s.defaultReg
+	tlr.K8sClusters = s.k8sClusters
+	tlr.BuildkitBuilders = s.buildkitBuilders
 
 	// All data models are loaded with GetState. We ignore the error if the state
 	// isn't properly loaded. This is necessary for handling partial Tiltfile
@@ -217,9 +225,12 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	us, _ := updatesettings.GetState(result)
 	tlr.UpdateSettings = us
 
+	ciSettings, _ := cisettings.GetState(result)
+	tlr.CISettings = ciSettings
+
 	configSettings, _ := config.GetState(result)
 	if tlr.Error == nil {
-		tlr.EnabledManifests, tlr.Error = configSettings.EnabledResources(tf, manifests)
+		tlr.EnabledManifests, tlr.Error = configSettings.EnabledResources(tf, manifests, s.disabledOverrides)
 	}
 
 	duration := time.Since(start)
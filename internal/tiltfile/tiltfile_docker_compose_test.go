@@ -116,7 +116,7 @@ func TestDockerComposeManifest(t *testing.T) {
 
 	expectedConfFiles := []string{
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"docker-compose.yml",
 		f.JoinPath("foo", ".dockerignore"),
 	}
@@ -140,7 +140,7 @@ func TestDockerComposeEnvFile(t *testing.T) {
 
 	expectedConfFiles := []string{
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"local.env",
 		"docker-compose.yml",
 	}
@@ -164,7 +164,7 @@ func TestDockerComposeServiceEnvFile(t *testing.T) {
 
 	expectedConfFiles := []string{
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"docker-compose.yml",
 		"bar.env",
 	}
@@ -212,7 +212,7 @@ func TestDockerComposeYAMLBlob(t *testing.T) {
 
 	expectedConfFiles := []string{
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"docker-compose.yml",
 		f.JoinPath("foo", ".dockerignore"),
 	}
@@ -267,7 +267,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "tilt_overrides.json", "docker-compose.yml"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -298,7 +298,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml", "baz/.dockerignore"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "tilt_overrides.json", "docker-compose.yml", "baz/.dockerignore"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -331,7 +331,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml", "baz/.dockerignore"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "tilt_overrides.json", "docker-compose.yml", "baz/.dockerignore"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -365,7 +365,7 @@ networks:
 
 	expectedConfFiles := []string{
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		"docker-compose.yml",
 		"baz/alternate-Dockerfile.dockerignore",
 	}
@@ -469,7 +469,7 @@ services:
 
 	expectedConfFiles := []string{
 		"Tiltfile",
-		".tiltignore",
+		".tiltignore", "tilt_overrides.json",
 		filepath.Join("foo", "docker-compose.yml"),
 		filepath.Join("foo", ".dockerignore"),
 	}
@@ -727,7 +727,7 @@ fail("deliberate exit")
 
 	// Make sure that even though tiltfile execution failed, we still
 	// loaded config files correctly.
-	f.assertConfigFiles(".tiltignore", "Tiltfile", "docker-compose.yml", "foo/Dockerfile")
+	f.assertConfigFiles(".tiltignore", "tilt_overrides.json", "Tiltfile", "docker-compose.yml", "foo/Dockerfile")
 }
 
 func TestDockerComposeDoesntSupportEntrypointOverride(t *testing.T) {
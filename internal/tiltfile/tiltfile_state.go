@@ -13,6 +13,7 @@ import (
 	"go.starlark.net/syntax"
 	"golang.org/x/mod/semver"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/tilt-dev/tilt/internal/controllers/apis/cmdimage"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/dockerimage"
@@ -34,6 +35,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/internal/tiltfile/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/cisettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/internal/tiltfile/dockerprune"
 	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
@@ -101,6 +103,19 @@ type tiltfileState struct {
 	k8sByName      map[string]*k8sResource
 	k8sUnresourced []k8s.K8sEntity
 
+	// Kubernetes clusters declared with k8s_cluster(), other than the default.
+	k8sClusters map[string]v1alpha1.KubernetesClusterConnection
+
+	// Buildkit daemons declared with buildkit_builder(), by cluster name.
+	buildkitBuilders map[string]v1alpha1.BuildkitClusterConnection
+
+	// The objects from k8s_yaml(), by identity and cluster, so that the same
+	// object can be deployed to several clusters.
+	//
+	// Objects loaded without a cluster are under the empty cluster name
+	// until a resource picks a cluster for them.
+	k8sEntityClusters map[k8sEntityID]runtime.Object
+
 	dc           dcResourceSet // currently only support one d-c.yml
 	dcByName     map[string]*dcService
 	dcResOptions map[string]*dcResourceOptions
@@ -150,6 +165,11 @@ type tiltfileState struct {
 	// these will never be read. Remove these when you can!!!
 	postExecReadFiles []string
 
+	// The path of the per-user overrides file, and the disabled state of
+	// each resource that it sets.
+	overridesPath     string
+	disabledOverrides map[model.ManifestName]bool
+
 	// Temporary directory for storing generated artifacts during the lifetime of the tiltfile context.
 	// The directory is recursively deleted when the context is done.
 	scratchDir *fwatch.TempDir
@@ -177,6 +197,10 @@ func newTiltfileState(
 		buildIndex:                newBuildIndex(),
 		k8sObjectIndex:            tiltfile_k8s.NewState(),
 		k8sByName:                 make(map[string]*k8sResource),
+		k8sClusters:               make(map[string]v1alpha1.KubernetesClusterConnection),
+		buildkitBuilders:          make(map[string]v1alpha1.BuildkitClusterConnection),
+		disabledOverrides:         make(map[model.ManifestName]bool),
+		k8sEntityClusters:         make(map[k8sEntityID]runtime.Object),
 		dcByName:                  make(map[string]*dcService),
 		dcResOptions:              make(map[string]*dcResourceOptions),
 		localByName:               make(map[string]*localResource),
@@ -225,6 +249,7 @@ func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest,
 		telemetry.NewPlugin(),
 		metrics.NewPlugin(),
 		updatesettings.NewPlugin(),
+		cisettings.NewPlugin(),
 		secretsettings.NewPlugin(),
 		encoding.NewPlugin(),
 		shlex.NewPlugin(),
@@ -307,13 +332,8 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 	}
 	manifests = append(manifests, localManifests...)
 
-	if len(unresourced) > 0 {
-		mn := model.UnresourcedYAMLManifestName
-		r := &k8sResource{
-			name:             mn.String(),
-			entities:         unresourced,
-			podReadinessMode: model.PodReadinessIgnore,
-		}
+	for _, r := range s.unresourcedByCluster(unresourced) {
+		mn := model.ManifestName(r.name)
 		kt, err := s.k8sDeployTarget(mn.TargetName(), r, nil, us)
 		if err != nil {
 			return nil, starkit.Model{}, err
@@ -328,6 +348,11 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 		return nil, starkit.Model{}, err
 	}
 
+	manifests, err = s.applyOverrides(manifests)
+	if err != nil {
+		return nil, result, err
+	}
+
 	for i := range manifests {
 		// ensure all manifests have a label indicating they're owned
 		// by the Tiltfile - some reconcilers have special handling
@@ -369,6 +394,9 @@ const (
 	k8sImageJSONPathN           = "k8s_image_json_path"
 	workloadToResourceFunctionN = "workload_to_resource_function"
 	k8sCustomDeployN            = "k8s_custom_deploy"
+	helmReleaseN                = "helm_release"
+	k8sClusterN                 = "k8s_cluster"
+	buildkitBuilderN            = "buildkit_builder"
 
 	// local resource functions
 	localResourceN = "local_resource"
@@ -382,6 +410,7 @@ const (
 	// live update functions
 	fallBackOnN       = "fall_back_on"
 	syncN             = "sync"
+	syncBackN         = "sync_back"
 	runN              = "run"
 	restartContainerN = "restart_container"
 
@@ -551,6 +580,9 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{filterYamlN, s.filterYaml},
 		{k8sResourceN, s.k8sResource},
 		{k8sCustomDeployN, s.k8sCustomDeploy},
+		{helmReleaseN, s.helmRelease},
+		{k8sClusterN, s.k8sCluster},
+		{buildkitBuilderN, s.buildkitBuilder},
 		{localResourceN, s.localResource},
 		{testN, s.localResource},
 		{portForwardN, s.portForward},
@@ -562,6 +594,7 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{triggerModeN, s.triggerModeFn},
 		{fallBackOnN, s.liveUpdateFallBackOn},
 		{syncN, s.liveUpdateSync},
+		{syncBackN, s.liveUpdateSyncBack},
 		{runN, s.liveUpdateRun},
 		{restartContainerN, s.liveUpdateRestartContainer},
 		{enableFeatureN, s.enableFeature},
@@ -793,6 +826,12 @@ func (s *tiltfileState) assembleK8s() error {
 			if opts.discoveryStrategy != "" {
 				r.discoveryStrategy = opts.discoveryStrategy
 			}
+			if opts.logFormat != "" {
+				r.logFormat = opts.logFormat
+			}
+			if opts.cluster != "" {
+				r.cluster = opts.cluster
+			}
 			r.portForwards = append(r.portForwards, opts.portForwards...)
 			if opts.triggerMode != TriggerModeUnset {
 				r.triggerMode = opts.triggerMode
@@ -865,6 +904,9 @@ func (s *tiltfileState) assembleK8s() error {
 	}
 
 	for _, r := range s.k8s {
+		if err := s.assignK8sResourceCluster(r); err != nil {
+			return err
+		}
 		if err := s.validateK8s(r); err != nil {
 			return err
 		}
@@ -943,9 +985,10 @@ func (s *tiltfileState) assembleK8sByWorkload() error {
 			return err
 		}
 
-		// find any other entities that match the workload's labels (e.g., services),
-		// and move them from unresourced to this resource
-		match, rest, err := k8s.FilterByMatchesPodTemplateSpec(workload, s.k8sUnresourced)
+		// find any other entities in the same cluster that match the workload's
+		// labels (e.g., services), and move them from unresourced to this resource
+		sameCluster, otherClusters := s.filterEntitiesByCluster(s.k8sUnresourced, s.entityCluster(workload))
+		match, rest, err := k8s.FilterByMatchesPodTemplateSpec(workload, sameCluster)
 		if err != nil {
 			return err
 		}
@@ -955,7 +998,7 @@ func (s *tiltfileState) assembleK8sByWorkload() error {
 			return err
 		}
 
-		s.k8sUnresourced = rest
+		s.k8sUnresourced = append(rest, otherClusters...)
 	}
 
 	return nil
@@ -1006,12 +1049,13 @@ func (s *tiltfileState) assembleK8sUnresourced() error {
 		}
 		target.entities = append(target.entities, e)
 
-		match, rest, err := k8s.FilterByMatchesPodTemplateSpec(e, allRest)
+		sameCluster, otherClusters := s.filterEntitiesByCluster(allRest, s.entityCluster(e))
+		match, rest, err := k8s.FilterByMatchesPodTemplateSpec(e, sameCluster)
 		if err != nil {
 			return err
 		}
 		target.entities = append(target.entities, match...)
-		allRest = rest
+		allRest = append(rest, otherClusters...)
 	}
 
 	s.k8sUnresourced = allRest
@@ -1164,8 +1208,12 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 	}
 
 	sinceTime := apis.NewTime(pkgInitTime)
+	cluster := r.cluster
+	if cluster == "" {
+		cluster = v1alpha1.ClusterNameDefault
+	}
 	applySpec := v1alpha1.KubernetesApplySpec{
-		Cluster:                         v1alpha1.ClusterNameDefault,
+		Cluster:                         cluster,
 		Timeout:                         metav1.Duration{Duration: updateSettings.K8sUpsertTimeout()},
 		PortForwardTemplateSpec:         k8s.PortForwardTemplateSpec(s.defaultedPortForwards(r.portForwards)),
 		DiscoveryStrategy:               r.discoveryStrategy,
@@ -1176,6 +1224,7 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 				string(container.IstioInitContainerName),
 				string(container.IstioSidecarContainerName),
 			},
+			Format: r.logFormat,
 		},
 	}
 
@@ -1440,8 +1489,10 @@ func (s *tiltfileState) imgTargetsForDepsHelper(mn model.ManifestName, imageMapD
 				Secrets:            image.secretSpecs,
 				Network:            image.network,
 				CacheFrom:          image.cacheFrom,
+				CacheTo:            image.cacheTo,
 				Pull:               image.pullParent,
 				Platform:           image.platform,
+				Platforms:          image.platforms,
 				ExtraTags:          image.extraTags,
 				ContextIgnores:     contextIgnores,
 			}
@@ -1591,7 +1642,8 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 		lt := model.NewLocalTarget(model.TargetName(r.name), r.updateCmd, r.serveCmd, r.deps).
 			WithAllowParallel(r.allowParallel || r.updateCmd.Empty()).
 			WithLinks(r.links).
-			WithReadinessProbe(r.readinessProbe)
+			WithReadinessProbe(r.readinessProbe).
+			WithTeardownCmd(r.teardownCmd, r.teardownTimeout)
 		lt.FileWatchIgnores = ignores
 
 		var mds []model.ManifestName
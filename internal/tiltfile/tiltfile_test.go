@@ -112,7 +112,7 @@ k8s_yaml('foo.yaml')
 	m := f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 
 	iTarget := m.ImageTargetAt(0)
 
@@ -139,7 +139,7 @@ k8s_yaml('foo.yaml')
 	f.assertNextManifest("foo",
 		db(image("fooimage")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestExplicitDockerfileIsConfigFile(t *testing.T) {
@@ -151,7 +151,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile='other/Dockerfile')
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
 }
 
 func TestDockerfileNone(t *testing.T) {
@@ -162,7 +162,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile=None)
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 func TestExplicitDockerfileAsLocalPath(t *testing.T) {
@@ -175,7 +175,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile=r.paths('other/Dockerfile'))
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
 }
 
 func TestExplicitDockerfileContents(t *testing.T) {
@@ -186,7 +186,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile_contents='FROM alpine')
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "foo/.dockerignore")
 	f.assertNextManifest("foo", db(image("gcr.io/foo")))
 }
 
@@ -200,7 +200,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile_contents=df)
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
 	f.assertNextManifest("foo", db(image("gcr.io/foo")))
 }
 
@@ -532,7 +532,7 @@ k8s_yaml(yaml)
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestKustomize(t *testing.T) {
@@ -551,7 +551,7 @@ k8s_resource("the-deployment", "foo")
 `)
 	f.load()
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
 }
 
 func TestKustomizeBin(t *testing.T) {
@@ -594,6 +594,75 @@ func TestKustomizeError(t *testing.T) {
 	f.loadErrString("unable to find one of 'kustomization.yaml', 'kustomization.yml' or 'Kustomization'")
 }
 
+func TestKustomizeIgnoresPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake kustomize")
+	}
+
+	f := newFixture(t)
+	f.file("kustomization.yaml", kustomizeFileText)
+	f.file("configMap.yaml", kustomizeConfigMapText)
+	f.file("deployment.yaml", kustomizeDeploymentText)
+	f.file("service.yaml", kustomizeServiceText)
+	sentinel := f.JoinPath("kustomize.txt")
+	binDir := f.JoinPath("bin")
+	wrapper := f.WriteFile(filepath.Join("bin", "kustomize"), fmt.Sprintf(`#!/bin/sh
+echo "$@" > %s
+exit 1
+`, sentinel))
+	require.NoError(t, os.Chmod(wrapper, 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// Without kustomize_bin, the kustomize on the PATH isn't used.
+	f.file("Tiltfile", `
+k8s_yaml(kustomize("."))
+`)
+	f.load()
+	f.assertNextManifest("the-deployment")
+	assert.NoFileExists(t, sentinel)
+}
+
+func TestKustomizeOverlayWatchesFilesRead(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("base/kustomization.yaml", kustomizeFileText)
+	f.file("base/configMap.yaml", kustomizeConfigMapText)
+	f.file("base/deployment.yaml", kustomizeDeploymentText)
+	f.file("base/service.yaml", kustomizeServiceText)
+	f.file("base/unused.yaml", kustomizeServiceText)
+	f.file("overlay/kustomization.yaml", `
+resources:
+- ../base
+namePrefix: dev-
+`)
+	f.file("Tiltfile", `
+
+docker_build("gcr.io/foo", "foo")
+k8s_yaml(kustomize("overlay"))
+k8s_resource("dev-the-deployment", "foo")
+`)
+	f.load()
+	f.assertNextManifest("foo", deployment("dev-the-deployment"), numEntities(2))
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore",
+		"base/configMap.yaml", "base/deployment.yaml", "base/kustomization.yaml", "base/service.yaml",
+		"overlay/kustomization.yaml")
+}
+
+func TestKustomizeErrorInBase(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("base/kustomization.yaml", kustomizeFileText)
+	f.file("base/deployment.yaml", kustomizeDeploymentText)
+	f.file("overlay/kustomization.yaml", `
+resources:
+- ../base
+`)
+	f.file("Tiltfile", `k8s_yaml(kustomize("overlay"))`)
+	f.loadErrString(fmt.Sprintf("kustomize build failed in %s", f.JoinPath("base", "kustomization.yaml")),
+		"service.yaml")
+}
+
 func TestKustomization(t *testing.T) {
 	f := newFixture(t)
 
@@ -610,7 +679,7 @@ k8s_resource("the-deployment", "foo")
 `)
 	f.load()
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "Kustomization", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "Kustomization", "service.yaml")
 }
 
 func TestDockerBuildTarget(t *testing.T) {
@@ -691,6 +760,34 @@ docker_build("gcr.io/foo", "foo", cache_from='gcr.io/foo')
 	assert.Equal(t, []string{"gcr.io/foo"}, m.ImageTargets[0].BuildDetails.(model.DockerBuild).CacheFrom)
 }
 
+func TestDockerBuildCacheTo(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo",
+             cache_from=['gcr.io/foo', 'type=local,src=.cache'],
+             cache_to=['type=local,dest=.cache,mode=max', 'type=inline'])
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	db := m.ImageTargets[0].BuildDetails.(model.DockerBuild)
+	assert.Equal(t, []string{"gcr.io/foo", fmt.Sprintf("type=local,src=%s", f.JoinPath(".cache"))}, db.CacheFrom)
+	assert.Equal(t, []string{fmt.Sprintf("type=local,dest=%s,mode=max", f.JoinPath(".cache")), "type=inline"}, db.CacheTo)
+}
+
+func TestDockerBuildCacheToInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", cache_to='type=local')
+`)
+	f.loadErrString("Argument cache_to: local cache destination \"type=local\" requires dest")
+}
+
 func TestDockerBuildExtraTagString(t *testing.T) {
 	f := newFixture(t)
 
@@ -1064,7 +1161,7 @@ docker_build('gcr.io/d', 'd')
 	f.assertNextManifest("c", db(image("gcr.io/c")), deployment("c"))
 	f.assertNextManifest("d", db(image("gcr.io/d")), deployment("d"))
 	f.assertNoMoreManifests() // should be no unresourced yaml remaining
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "all.yaml", "a/Dockerfile", "a/.dockerignore", "b/Dockerfile", "b/.dockerignore", "c/Dockerfile", "c/.dockerignore", "d/Dockerfile", "d/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "all.yaml", "a/Dockerfile", "a/.dockerignore", "b/Dockerfile", "b/.dockerignore", "c/Dockerfile", "c/.dockerignore", "d/Dockerfile", "d/.dockerignore")
 }
 
 func TestExpandUnresourced(t *testing.T) {
@@ -1265,7 +1362,7 @@ k8s_yaml('bar.yaml')
 	f.load("foo")
 	require.Equal(t, []model.ManifestName{"foo"}, f.loadResult.EnabledManifests)
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml")
 }
 
 func TestUncategorizedEnabledEvenIfNotSpecified(t *testing.T) {
@@ -1622,7 +1719,7 @@ if True:
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestTopLevelForLoop(t *testing.T) {
@@ -1834,6 +1931,34 @@ k8s_resource('foo', discovery_strategy='typo')
 	f.loadErrString("Invalid. Must be one of: \"default\", \"selectors-only\"")
 }
 
+func TestK8sLogFormat(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', log_format='json')
+`)
+
+	f.load("foo")
+	f.assertNextManifest("foo",
+		deployment("foo"),
+		v1alpha1.PodLogFormatJSON,
+	)
+}
+
+func TestK8sLogFormatInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', log_format='xml')
+`)
+
+	f.loadErrString("Invalid. Must be one of: \"json\", \"logfmt\"")
+}
+
 func TestPodReadinessOverrideDeployment(t *testing.T) {
 	f := newFixture(t)
 
@@ -2242,7 +2367,7 @@ func TestDir(t *testing.T) {
 
 	f.load("foo", "bar")
 	f.assertNumManifests(2)
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "config/foo.yaml", "config/bar.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "config/foo.yaml", "config/bar.yaml")
 }
 
 func TestDirRecursive(t *testing.T) {
@@ -2258,7 +2383,7 @@ for f in files:
 `)
 
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo", "foo/bar", "foo/baz/qux")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo", "foo/bar", "foo/baz/qux")
 }
 
 func TestCallCounts(t *testing.T) {
@@ -2399,7 +2524,7 @@ custom_build(
 
 	f.load("foo")
 	f.assertNumManifests(1)
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "foo/.dockerignore")
 	m := f.assertNextManifest("foo",
 		cb(
 			image("gcr.io/foo"),
@@ -2427,7 +2552,7 @@ hfb = custom_build(
 
 	f.load("foo")
 	f.assertNumManifests(1)
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "foo/.dockerignore")
 	f.assertNextManifest("foo",
 		cb(
 			image("gcr.io/foo"),
@@ -2966,7 +3091,7 @@ default_registry('bar.com')
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo").withLocalRef("bar.com/gcr.io_foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestDefaultRegistryTwoImagesOnlyDifferByTag(t *testing.T) {
@@ -2996,7 +3121,7 @@ default_registry('example.com')
 	f.assertNextManifest("baz",
 		db(image("gcr.io/foo:baz").withLocalRef("example.com/gcr.io_foo")),
 		deployment("baz"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml", "baz/Dockerfile", "baz/.dockerignore", "baz.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml", "baz/Dockerfile", "baz/.dockerignore", "baz.yaml")
 }
 
 func TestDefaultRegistrySingleName(t *testing.T) {
@@ -3060,7 +3185,7 @@ k8s_yaml(str(result) + '.yaml')
 		db(image("gcr.io/foo")),
 		deployment("foo"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "this_file_does_not_exist", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "this_file_does_not_exist", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 func TestWatchFile(t *testing.T) {
@@ -3080,7 +3205,7 @@ k8s_yaml('foo.yaml')
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "hello")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "hello")
 }
 
 func TestAssemblyBasic(t *testing.T) {
@@ -3099,7 +3224,7 @@ k8s_yaml('foo.yaml')
 		db(image("gcr.io/foo")),
 		deployment("foo"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 func TestAssemblyTwoWorkloadsSameImage(t *testing.T) {
@@ -3123,7 +3248,7 @@ k8s_yaml(['foo.yaml', 'bar.yaml'])
 		db(image("gcr.io/foo")),
 		deployment("bar"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "bar.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo.yaml", "bar.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 // Fix a bug where a service with no selectors trivially matched all pods, so Tilt grouped
@@ -3608,7 +3733,7 @@ docker_build('gcr.io/foo', 'foo')
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 
 }
 
@@ -4277,7 +4402,7 @@ local_resource("test", "echo hi", deps=["foo/bar", "foo/a.txt"])
 		{BasePath: f.JoinPath(".git")},
 	}, lt.GetFileWatchIgnores())
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json")
 }
 
 func TestLocalResourceOnlyServeCmd(t *testing.T) {
@@ -4292,7 +4417,7 @@ local_resource("test", serve_cmd="sleep 1000")
 	f.assertNumManifests(1)
 	f.assertNextManifest("test", localTarget(serveCmd(f.Path(), "sleep 1000", nil)))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json")
 }
 
 func TestLocalResourceUpdateAndServeCmd(t *testing.T) {
@@ -4310,7 +4435,7 @@ local_resource("test", cmd="echo hi", serve_cmd="sleep 1000")
 		serveCmd(f.Path(), "sleep 1000", nil),
 	))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "tilt_overrides.json")
 }
 
 func TestLocalResourceNeitherUpdateOrServeCmd(t *testing.T) {
@@ -6153,6 +6278,8 @@ func (f *fixture) assertNextManifest(name model.ManifestName, opts ...interface{
 			}
 		case v1alpha1.KubernetesDiscoveryStrategy:
 			assert.Equal(f.t, opt, m.K8sTarget().DiscoveryStrategy)
+		case v1alpha1.PodLogFormat:
+			assert.Equal(f.t, opt, m.K8sTarget().PodLogStreamTemplateSpec.Format)
 		case podReadinessHelper:
 			assert.Equal(f.t, opt.podReadiness, m.K8sTarget().PodReadinessMode)
 		case namespaceHelper:
@@ -309,6 +309,7 @@ func (p Plugin) fileWatch(t *starlark.Thread, fn *starlark.Builtin, args starlar
 		"watched_paths?", &watchedPaths,
 		"ignores?", &ignores,
 		"disable_source?", &disableSource,
+		"content_hash?", &obj.Spec.ContentHash,
 	)
 	if err != nil {
 		return nil, err
@@ -2592,16 +2593,18 @@ func (p Plugin) podLogStreamTemplateSpec(t *starlark.Thread, fn *starlark.Builti
 	var sinceTime starlark.Value
 	var onlyContainers starlark.Value
 	var ignoreContainers starlark.Value
+	var format starlark.Value
 	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
 		"since_time?", &sinceTime,
 		"only_containers?", &onlyContainers,
 		"ignore_containers?", &ignoreContainers,
+		"format?", &format,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	dict := starlark.NewDict(3)
+	dict := starlark.NewDict(4)
 
 	if sinceTime != nil {
 		err := dict.SetKey(starlark.String("since_time"), sinceTime)
@@ -2621,6 +2624,12 @@ func (p Plugin) podLogStreamTemplateSpec(t *starlark.Thread, fn *starlark.Builti
 			return nil, err
 		}
 	}
+	if format != nil {
+		err := dict.SetKey(starlark.String("format"), format)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var obj *PodLogStreamTemplateSpec = &PodLogStreamTemplateSpec{t: t}
 	err = obj.Unpack(dict)
 	if err != nil {
@@ -2668,6 +2677,14 @@ func (o *PodLogStreamTemplateSpec) Unpack(v starlark.Value) error {
 			obj.IgnoreContainers = v
 			continue
 		}
+		if key == "format" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Format = v1alpha1.PodLogFormat(v)
+			continue
+		}
 		return fmt.Errorf("Unexpected attribute name: %s", key)
 	}
 
@@ -26,12 +26,18 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 func (e Plugin) setWatchSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	err := starkit.SetState(thread, func(settings model.WatchSettings) (model.WatchSettings, error) {
 		var ignores value.StringOrStringList
+		var contentHash value.Optional[starlark.Bool]
 		if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 			"ignore?", &ignores,
+			"content_hash?", &contentHash,
 		); err != nil {
 			return settings, err
 		}
 
+		if contentHash.IsSet {
+			settings.ContentHash = bool(contentHash.Value)
+		}
+
 		if len(ignores.Values) != 0 {
 			settings.Ignores = append(settings.Ignores, model.Dockerignore{
 				LocalPath: starkit.AbsWorkingDir(thread),
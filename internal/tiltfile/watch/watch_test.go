@@ -27,6 +27,21 @@ watch_settings(ignore=['foo'])
 	}, MustState(result))
 }
 
+func TestContentHash(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+watch_settings(content_hash=True)
+watch_settings(ignore=['foo'])
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	// Settings from earlier calls are kept unless overridden.
+	state := MustState(result)
+	require.True(t, state.ContentHash)
+	require.Len(t, state.Ignores, 1)
+}
+
 func TestLoaded(t *testing.T) {
 	f := NewFixture(t)
 	f.File("foo/Tiltfile", `
@@ -31,6 +31,18 @@ import (
 const ClusterNameDefault = "default"
 const ClusterNameDocker = "docker"
 
+// Strategies for getting locally-built images onto a cluster.
+//
+// See ClusterStatus.ImageLoader.
+const (
+	// Push images to a registry that the cluster pulls from.
+	ImageLoaderRegistry = "registry"
+
+	// Stream images directly into the containerd image store of each of
+	// the cluster's nodes (e.g., KIND, k3d, or minikube with containerd).
+	ImageLoaderContainerd = "containerd"
+)
+
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -75,6 +87,14 @@ type ClusterSpec struct {
 	//
 	// +optional
 	DefaultRegistry *RegistryHosting `json:"defaultRegistry,omitempty" protobuf:"bytes,2,opt,name=defaultRegistry"`
+
+	// Buildkit connects to a standalone Buildkit daemon that builds the
+	// images for this cluster.
+	//
+	// If not specified, images are built with the Docker daemon.
+	//
+	// +optional
+	Buildkit *BuildkitClusterConnection `json:"buildkit,omitempty" protobuf:"bytes,3,opt,name=buildkit"`
 }
 
 // Connection spec for an existing cluster.
@@ -110,6 +130,25 @@ type DockerClusterConnection struct {
 	Host string `json:"host,omitempty" protobuf:"bytes,1,opt,name=host"`
 }
 
+// Connection spec for a standalone Buildkit daemon, which builds
+// images without a Docker daemon.
+type BuildkitClusterConnection struct {
+	// The address of the buildkitd socket, e.g.,
+	// unix:///run/buildkit/buildkitd.sock or tcp://127.0.0.1:1234.
+	//
+	// To use a buildkitd running inside the cluster, port-forward to it.
+	Host string `json:"host" protobuf:"bytes,1,opt,name=host"`
+
+	// If true, buildkitd stores images directly in the containerd instance that
+	// the cluster runs on, so they don't need to be pushed. This requires
+	// buildkitd to use the containerd worker with the k8s.io namespace.
+	//
+	// Otherwise, images are pushed to the cluster's registry.
+	//
+	// +optional
+	LoadToContainerd bool `json:"loadToContainerd,omitempty" protobuf:"varint,2,opt,name=loadToContainerd"`
+}
+
 var _ resource.Object = &Cluster{}
 var _ resourcestrategy.Validater = &Cluster{}
 
@@ -151,6 +190,10 @@ func (in *Cluster) Validate(ctx context.Context) field.ErrorList {
 		errors = append(errors,
 			in.Spec.DefaultRegistry.validateAsSubfield(ctx, field.NewPath(".spec.defaultRegistry"))...)
 	}
+	if in.Spec.Buildkit != nil && in.Spec.Buildkit.Host == "" {
+		errors = append(errors,
+			field.Required(field.NewPath(".spec.buildkit.host"), "buildkitd address is required"))
+	}
 	return errors
 }
 
@@ -203,6 +246,15 @@ type ClusterStatus struct {
 	//
 	// +optional
 	Version string `json:"version,omitempty" protobuf:"bytes,6,opt,name=version"`
+
+	// How Tilt gets the images it builds onto the cluster, when they're
+	// not built on the cluster's container runtime.
+	//
+	// One of "registry" or "containerd". Empty for clusters
+	// that aren't Kubernetes clusters.
+	//
+	// +optional
+	ImageLoader string `json:"imageLoader,omitempty" protobuf:"bytes,7,opt,name=imageLoader"`
 }
 
 // Cluster implements ObjectWithStatusSubResource interface.
@@ -0,0 +1,24 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestCluster_Validate_BuildkitHost(t *testing.T) {
+	cluster := &v1alpha1.Cluster{
+		Spec: v1alpha1.ClusterSpec{Buildkit: &v1alpha1.BuildkitClusterConnection{}},
+	}
+	errs := cluster.Validate(context.Background())
+	if assert.Len(t, errs, 1) {
+		require.EqualError(t, errs[0], ".spec.buildkit.host: Required value: buildkitd address is required")
+	}
+
+	cluster.Spec.Buildkit.Host = "tcp://buildkitd:1234"
+	assert.Empty(t, cluster.Validate(context.Background()))
+}
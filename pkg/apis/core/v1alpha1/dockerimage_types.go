@@ -130,16 +130,41 @@ type DockerImageSpec struct {
 
 	// Images to use as cache sources.
 	//
+	// Each entry is either an image reference, or a Buildkit cache source
+	// (e.g., type=registry,ref=gcr.io/foo/cache or type=local,src=path/to/dir).
+	//
 	// Equivalent to `--cache-from` in the Docker CLI.
 	CacheFrom []string `json:"cacheFrom,omitempty" protobuf:"bytes,9,rep,name=cacheFrom"`
 
+	// Buildkit cache destinations to export the build cache to
+	// (e.g., type=registry,ref=gcr.io/foo/cache,mode=max,
+	// type=local,dest=path/to/dir, or type=inline).
+	//
+	// Equivalent to `--cache-to` in the Docker Buildx CLI.
+	//
+	// +optional
+	CacheTo []string `json:"cacheTo,omitempty" protobuf:"bytes,17,rep,name=cacheTo"`
+
 	// Platform specifies architecture information for target image.
 	//
 	// https://docs.docker.com/desktop/multi-arch/
 	//
+	// If neither Platform nor Platforms is set, defaults to the architecture
+	// of the target cluster.
+	//
 	// Equivalent to `--platform` in the Docker CLI.
 	Platform string `json:"platform,omitempty" protobuf:"bytes,10,opt,name=platform"`
 
+	// Platforms to build a multi-platform image for, with a manifest list
+	// (e.g., linux/amd64 and linux/arm64).
+	//
+	// Cannot be combined with Platform. Requires Buildkit.
+	//
+	// Equivalent to `--platform` with multiple platforms in the Docker Buildx CLI.
+	//
+	// +optional
+	Platforms []string `json:"platforms,omitempty" protobuf:"bytes,18,rep,name=platforms"`
+
 	// By default, Tilt creates a new temporary image reference for each build.
 	// The user can also specify their own reference, to integrate with other tooling
 	// (like build IDs for Jenkins build pipelines)
@@ -328,4 +353,13 @@ type DockerImageStageStatus struct {
 	// Error message if the stage failed. If empty, the stage succeeded.
 	// +optional
 	Error string `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+
+	// How long the stage took, once it finished.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty" protobuf:"bytes,8,opt,name=duration"`
+
+	// The number of bytes that the stage transferred, if Buildkit reported it
+	// (e.g., the size of the build context, or of the base image layers pulled).
+	// +optional
+	TransferredBytes int64 `json:"transferredBytes,omitempty" protobuf:"varint,9,opt,name=transferredBytes"`
 }
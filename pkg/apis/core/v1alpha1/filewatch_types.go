@@ -66,6 +66,15 @@ type FileWatchSpec struct {
 	//
 	// +optional
 	DisableSource *DisableSource `json:"disableSource,omitempty" protobuf:"bytes,3,opt,name=disableSource"`
+
+	// ContentHash enables content-hash change detection.
+	//
+	// The watcher keeps a hash of each watched file, and drops events for files
+	// whose contents didn't actually change (e.g., a formatter that rewrites
+	// identical bytes, or a touch).
+	//
+	// +optional
+	ContentHash bool `json:"contentHash,omitempty" protobuf:"varint,4,opt,name=contentHash"`
 }
 
 // Describes sets of file paths that the FileWatch should ignore.
@@ -158,6 +167,10 @@ type FileWatchStatus struct {
 	// Details about whether/why this is disabled.
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
+	// SuppressedEventCount is the number of file changes that were dropped
+	// because the file contents didn't change. Only set in ContentHash mode.
+	// +optional
+	SuppressedEventCount int32 `json:"suppressedEventCount,omitempty" protobuf:"varint,6,opt,name=suppressedEventCount"`
 }
 
 type FileEvent struct {
@@ -168,6 +181,13 @@ type FileEvent struct {
 	Time metav1.MicroTime `json:"time" protobuf:"bytes,1,opt,name=time"`
 	// SeenFiles is a list of paths which changed (create, modify, or delete).
 	SeenFiles []string `json:"seenFiles" protobuf:"bytes,2,rep,name=seenFiles"`
+	// BranchSwitch is true if these files changed because git moved HEAD
+	// (e.g., a branch checkout or a rebase).
+	//
+	// Changes during a git operation are held until the operation finishes,
+	// then reported together as a single event.
+	// +optional
+	BranchSwitch bool `json:"branchSwitch,omitempty" protobuf:"varint,3,opt,name=branchSwitch"`
 }
 
 // FileWatch implements ObjectWithStatusSubResource interface.
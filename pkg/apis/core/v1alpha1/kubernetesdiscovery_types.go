@@ -154,6 +154,13 @@ type PodLogStreamTemplateSpec struct {
 	//
 	// +optional
 	IgnoreContainers []string `json:"ignoreContainers,omitempty" protobuf:"bytes,3,rep,name=ignoreContainers"`
+
+	// How to parse log lines into structured fields.
+	//
+	// If not set, log lines are not parsed.
+	//
+	// +optional
+	Format PodLogFormat `json:"format,omitempty" protobuf:"bytes,4,opt,name=format,casttype=PodLogFormat"`
 }
 
 func (in *KubernetesDiscovery) Default() {
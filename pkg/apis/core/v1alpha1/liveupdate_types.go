@@ -109,6 +109,17 @@ type LiveUpdateSpec struct {
 	//
 	// +optional
 	Restart LiveUpdateRestartStrategy `json:"restart,omitempty" protobuf:"bytes,7,opt,name=restart,casttype=LiveUpdateRestartStrategy"`
+
+	// Paths to copy from the container back to the local filesystem
+	// after the execs have run.
+	//
+	// Useful for files that are generated inside the container (like lockfiles
+	// or generated code) that you want to check in.
+	//
+	// File changes caused by copying these files don't trigger another update.
+	//
+	// +optional
+	SyncBacks []LiveUpdateSyncBack `json:"syncBacks,omitempty" protobuf:"bytes,10,rep,name=syncBacks"`
 }
 
 var _ resource.Object = &LiveUpdate{}
@@ -169,6 +180,16 @@ func (in *LiveUpdate) Validate(ctx context.Context) field.ErrorList {
 		}
 	}
 
+	for i, syncBack := range in.Spec.SyncBacks {
+		if !path.IsAbs(syncBack.ContainerPath) {
+			errors = append(errors,
+				field.Invalid(
+					field.NewPath("spec.syncBacks").Index(i),
+					syncBack.ContainerPath,
+					"sync back source is not absolute"))
+		}
+	}
+
 	selectorPath := field.NewPath("spec.selector")
 	kSelector := in.Spec.Selector.Kubernetes
 	dcSelector := in.Spec.Selector.DockerCompose
@@ -225,6 +246,29 @@ type LiveUpdateStatus struct {
 	//
 	// +optional
 	Failed *LiveUpdateStateFailed `json:"failed,omitempty" protobuf:"bytes,2,opt,name=failed"`
+
+	// Syncs that the live-updater doesn't need to copy files for, because a
+	// bind mount already makes the local files visible in the container.
+	//
+	// Only populated for Docker Compose services.
+	//
+	// +optional
+	MountedSyncs []LiveUpdateMountedSync `json:"mountedSyncs,omitempty" protobuf:"bytes,3,rep,name=mountedSyncs"`
+}
+
+// A sync that's satisfied by a bind mount.
+type LiveUpdateMountedSync struct {
+	// The local path of the sync, as specified in the LiveUpdateSync.
+	LocalPath string `json:"localPath" protobuf:"bytes,1,opt,name=localPath"`
+
+	// The path of the sync inside the container.
+	ContainerPath string `json:"containerPath" protobuf:"bytes,2,opt,name=containerPath"`
+
+	// The local directory that's bind-mounted into the container.
+	MountSource string `json:"mountSource" protobuf:"bytes,3,opt,name=mountSource"`
+
+	// The directory inside the container that the source is mounted at.
+	MountTarget string `json:"mountTarget" protobuf:"bytes,4,opt,name=mountTarget"`
 }
 
 // LiveUpdate implements ObjectWithStatusSubResource interface.
@@ -329,6 +373,28 @@ type LiveUpdateSync struct {
 
 	// An absolute path inside the container. Required.
 	ContainerPath string `json:"containerPath" protobuf:"bytes,2,opt,name=containerPath"`
+
+	// When true, large files that already exist in the container are updated
+	// by sending only the blocks that changed, rather than the whole file.
+	//
+	// Requires a shell, dd, and md5sum in the container. Falls back to
+	// copying the whole file if they aren't available.
+	//
+	// +optional
+	Delta bool `json:"delta,omitempty" protobuf:"varint,3,opt,name=delta"`
+}
+
+// Specifies a path in the container to copy back to the local filesystem.
+type LiveUpdateSyncBack struct {
+	// An absolute path inside the container. Required.
+	//
+	// May be a file or a directory.
+	ContainerPath string `json:"containerPath" protobuf:"bytes,1,opt,name=containerPath"`
+
+	// A relative path to local files. Required.
+	//
+	// Computed relative to the live-update BasePath.
+	LocalPath string `json:"localPath" protobuf:"bytes,2,opt,name=localPath"`
 }
 
 // Runs a remote command after files have been synced to the container.
@@ -102,8 +102,27 @@ type PodLogStreamSpec struct {
 	//
 	// +optional
 	Cluster string `json:"cluster" protobuf:"bytes,6,opt,name=cluster"`
+
+	// How to parse log lines into structured fields.
+	//
+	// If not set, log lines are not parsed.
+	//
+	// +optional
+	Format PodLogFormat `json:"format,omitempty" protobuf:"bytes,7,opt,name=format,casttype=PodLogFormat"`
 }
 
+type PodLogFormat string
+
+const (
+	// Lines that are JSON objects are parsed, and their top-level keys
+	// are promoted to log fields. Other lines are logged as-is.
+	PodLogFormatJSON PodLogFormat = "json"
+
+	// Lines of key=value pairs are parsed, and the keys
+	// are promoted to log fields. Other lines are logged as-is.
+	PodLogFormatLogfmt PodLogFormat = "logfmt"
+)
+
 var _ resource.Object = &PodLogStream{}
 var _ resourcestrategy.Validater = &PodLogStream{}
 var _ resourcerest.ShortNamesProvider = &PodLogStream{}
@@ -147,8 +166,18 @@ func (in *PodLogStream) Default() {
 }
 
 func (in *PodLogStream) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	var fieldErrors field.ErrorList
+	fieldErrors = append(fieldErrors, in.Spec.Format.validate(field.NewPath("spec", "format"))...)
+	return fieldErrors
+}
+
+func (f PodLogFormat) validate(path *field.Path) field.ErrorList {
+	if f == "" || f == PodLogFormatJSON || f == PodLogFormatLogfmt {
+		return nil
+	}
+	return field.ErrorList{
+		field.NotSupported(path, f, []string{string(PodLogFormatJSON), string(PodLogFormatLogfmt)}),
+	}
 }
 
 var _ resource.ObjectList = &PodLogStreamList{}
@@ -58,6 +58,12 @@ type SessionSpec struct {
 	TiltfilePath string `json:"tiltfilePath" protobuf:"bytes,1,opt,name=tiltfilePath"`
 	// ExitCondition defines the criteria for Tilt to exit.
 	ExitCondition ExitCondition `json:"exitCondition" protobuf:"bytes,2,opt,name=exitCondition,casttype=ExitCondition"`
+
+	// GateResources are the names of the resources whose status determines when
+	// the session exits with ExitConditionCIGated.
+	//
+	// +optional
+	GateResources []string `json:"gateResources,omitempty" protobuf:"bytes,3,rep,name=gateResources"`
 }
 
 type ExitCondition string
@@ -72,9 +78,15 @@ const (
 	//
 	// This is used by `tilt ci`.
 	ExitConditionCI ExitCondition = "ci"
+	// ExitConditionCIGated is like ExitConditionCI, but only considers the resources
+	// listed in GateResources. Failures of other resources are ignored, and
+	// other resources don't need to become ready.
+	//
+	// This is used by `tilt ci` when gate resources have been specified.
+	ExitConditionCIGated ExitCondition = "ci-gated"
 )
 
-var exitConditions = []ExitCondition{ExitConditionManual, ExitConditionCI}
+var exitConditions = []ExitCondition{ExitConditionManual, ExitConditionCI, ExitConditionCIGated}
 
 var _ resource.Object = &Session{}
 var _ resourcestrategy.Validater = &Session{}
@@ -134,6 +146,10 @@ func (in *Session) Validate(_ context.Context) field.ErrorList {
 			in.Spec.ExitCondition,
 			detailMsg.String()))
 	}
+	if in.Spec.ExitCondition == ExitConditionCIGated && len(in.Spec.GateResources) == 0 {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("gateResources"),
+			"must be specified with exitCondition "+string(ExitConditionCIGated)))
+	}
 	return fieldErrors
 }
 
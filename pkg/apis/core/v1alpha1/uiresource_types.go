@@ -209,6 +209,14 @@ type UIResourceStatus struct {
 	//
 	// +optional
 	Conditions []UIResourceCondition `json:"conditions,omitempty" protobuf:"bytes,18,rep,name=conditions"`
+
+	// Settings of this resource that were overridden outside the Tiltfile
+	// (e.g., in the user's tilt_overrides.json).
+	//
+	// Settings that aren't listed here come from the Tiltfile.
+	//
+	// +optional
+	Overrides []UIResourceOverride `json:"overrides,omitempty" protobuf:"bytes,19,rep,name=overrides"`
 }
 
 // UIResource implements ObjectWithStatusSubResource interface.
@@ -225,6 +233,20 @@ func (in UIResourceStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
 	parent.(*UIResource).Status = in
 }
 
+// UIResourceOverride describes a resource setting that didn't come from the Tiltfile.
+type UIResourceOverride struct {
+	// The name of the setting (e.g., "trigger_mode", "port_forwards").
+	Setting string `json:"setting" protobuf:"bytes,1,opt,name=setting"`
+
+	// The path of the file that the setting came from.
+	Source string `json:"source" protobuf:"bytes,2,opt,name=source"`
+
+	// A human-readable form of the overriding value.
+	//
+	// +optional
+	Value string `json:"value,omitempty" protobuf:"bytes,3,opt,name=value"`
+}
+
 // UIResourceLink represents a link assocatiated with a UIResource.
 type UIResourceLink struct {
 	// A URL to link to.
@@ -84,6 +84,16 @@ var (
 	ErrorLvl   = Level{id: 5, severity: 500}
 )
 
+// LevelFromProtoID is the inverse of Level.ToProtoID.
+func LevelFromProtoID(id int32) Level {
+	for _, l := range []Level{DebugLvl, VerboseLvl, InfoLvl, WarnLvl, ErrorLvl} {
+		if l.id == id {
+			return l
+		}
+	}
+	return NoneLvl
+}
+
 type contextKey struct{}
 
 var LoggerContextKey = contextKey{}
@@ -37,6 +37,13 @@ const (
 	// Building manifestA will mark imageB
 	// with changed dependencies.
 	BuildReasonFlagChangedDeps
+
+	// Files changed because git switched branches.
+	//
+	// A branch switch can change anything, so these changes aren't held for
+	// the LiveUpdate reconciler. They go through the same image build and
+	// deploy as any other build reason that isn't just changed files.
+	BuildReasonFlagBranchSwitch
 )
 
 func (r BuildReason) With(flag BuildReason) BuildReason {
@@ -76,6 +83,7 @@ var translations = map[BuildReason]string{
 	BuildReasonFlagTriggerUnknown:  "Unknown Trigger",
 	BuildReasonFlagTiltfileArgs:    "Tilt Args",
 	BuildReasonFlagChangedDeps:     "Dependency Updated",
+	BuildReasonFlagBranchSwitch:    "Branch Switch",
 }
 
 var triggerBuildReasons = []BuildReason{
@@ -94,6 +102,7 @@ var allBuildReasons = []BuildReason{
 	BuildReasonFlagChangedDeps,
 	BuildReasonFlagTriggerUnknown,
 	BuildReasonFlagTiltfileArgs,
+	BuildReasonFlagBranchSwitch,
 }
 
 func (r BuildReason) String() string {
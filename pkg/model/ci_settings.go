@@ -0,0 +1,9 @@
+package model
+
+// CISettings are Tiltfile settings that only apply to `tilt ci`.
+type CISettings struct {
+	// The resources that determine when `tilt ci` exits.
+	//
+	// If empty, `tilt ci` waits on all resources.
+	GateResources []ManifestName
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis"
@@ -26,6 +27,14 @@ type LocalTarget struct {
 
 	// Move this to CmdServerSpec when we move CmdServer to API
 	ServeCmdDisableSource *v1alpha1.DisableSource
+
+	// Run by `tilt down` to clean up any state left behind by the
+	// update cmd or serve cmd (e.g., `docker rm -f my-db`).
+	TeardownCmd Cmd
+
+	// How long `tilt down` waits for the TeardownCmd before giving up.
+	// If zero, a default is used.
+	TeardownTimeout time.Duration
 }
 
 var _ TargetSpec = LocalTarget{}
@@ -83,6 +92,12 @@ func (lt LocalTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) LocalTarget
 	return lt
 }
 
+func (lt LocalTarget) WithTeardownCmd(cmd Cmd, timeout time.Duration) LocalTarget {
+	lt.TeardownCmd = cmd
+	lt.TeardownTimeout = timeout
+	return lt
+}
+
 func (lt LocalTarget) ID() TargetID {
 	return TargetID{
 		Name: lt.Name,
@@ -101,6 +116,9 @@ func (lt LocalTarget) Validate() error {
 	if !lt.ServeCmd.Empty() && lt.ServeCmd.Dir == "" {
 		return fmt.Errorf("[Validate] LocalTarget serve_cmd missing workdir")
 	}
+	if !lt.TeardownCmd.Empty() && lt.TeardownCmd.Dir == "" {
+		return fmt.Errorf("[Validate] LocalTarget teardown_cmd missing workdir")
+	}
 	return nil
 }
 
@@ -0,0 +1,589 @@
+package logstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+const archiveFileExt = ".jsonl"
+
+// The prefix of each session's directory. The archive directory may be shared
+// with other files, so we only index and prune directories with this prefix.
+const archiveSessionPrefix = "tilt-session-"
+
+// The directory name for logs that don't belong to any manifest.
+// Manifest names can't contain parens (except for the Tiltfile), so this can't collide.
+const archiveGlobalDir = "(global)"
+
+type ArchiveOptions struct {
+	// Delete the oldest archived logs once the archive is bigger than this.
+	// Zero means no limit.
+	MaxBytes int64
+
+	// Delete archived logs older than this. Zero means no limit.
+	MaxAge time.Duration
+}
+
+// Archive is a rolling on-disk store of logs that the LogStore has truncated
+// from memory, so that clients can page back into them.
+//
+// The archive for each session lives in its own directory,
+// with one file per span:
+//
+// <root>/tilt-session-<session>/<manifest name>/<span id>.jsonl
+//
+// Retention applies to all the session directories under the root, so that
+// old sessions get cleaned up too. Other files under the root are left alone.
+type Archive struct {
+	root    string
+	session string
+	opts    ArchiveOptions
+
+	// Protects the files on disk, and our index of them.
+	mu sync.Mutex
+
+	// An index of the files in session directories, so that we don't need to
+	// walk the root each time we prune. Built when the archive is created, and
+	// updated as we write and delete files.
+	files map[string]archiveFile
+	total int64
+
+	// The position of the last segment written in this session. Positions
+	// order segments across spans in the order they were logged, so clients
+	// can page with them even when segments share a timestamp.
+	lastPos int64
+
+	// Batches of segments waiting to be written in the background,
+	// so that the LogStore doesn't wait on the disk.
+	queueMu   sync.Mutex
+	queueCond *sync.Cond
+	queue     []archiveBatch
+	writing   bool
+	failing   bool
+	closed    bool
+
+	// Closed when the background writer exits.
+	done chan struct{}
+}
+
+type archiveBatch struct {
+	segments []LogSegment
+	spans    map[SpanID]*Span
+	secrets  model.SecretSet
+}
+
+type archivedSegment struct {
+	Pos           int64         `json:"pos"`
+	Time          time.Time     `json:"time"`
+	Level         int32         `json:"level"`
+	Fields        logger.Fields `json:"fields,omitempty"`
+	Text          string        `json:"text"`
+	Anchor        bool          `json:"anchor,omitempty"`
+	ContinuesLine bool          `json:"continuesLine,omitempty"`
+}
+
+func NewArchive(root string, session string, opts ArchiveOptions) (*Archive, error) {
+	a := &Archive{
+		root:    root,
+		session: session,
+		opts:    opts,
+		files:   make(map[string]archiveFile),
+		done:    make(chan struct{}),
+	}
+	a.queueCond = sync.NewCond(&a.queueMu)
+	err := os.MkdirAll(a.sessionDir(), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("creating log archive: %v", err)
+	}
+
+	// Clean up after old sessions.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	err = a.index()
+	if err != nil {
+		return nil, fmt.Errorf("indexing log archive: %v", err)
+	}
+	err = a.prune(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("pruning log archive: %v", err)
+	}
+
+	go a.writeLoop()
+	return a, nil
+}
+
+func (a *Archive) sessionDir() string {
+	return filepath.Join(a.root, archiveSessionPrefix+url.PathEscape(a.session))
+}
+
+func (a *Archive) manifestDir(mn model.ManifestName) string {
+	name := archiveGlobalDir
+	if mn != "" {
+		name = url.PathEscape(string(mn))
+	}
+	return filepath.Join(a.sessionDir(), name)
+}
+
+// Enqueue schedules segments to be written to the archive in the background.
+//
+// The archive is best-effort, so write errors are logged rather than returned.
+func (a *Archive) Enqueue(segments []LogSegment, spans map[SpanID]*Span, secrets model.SecretSet) {
+	if len(segments) == 0 {
+		return
+	}
+
+	// Copy the spans, because the LogStore will keep modifying its map.
+	batchSpans := make(map[SpanID]*Span)
+	for _, seg := range segments {
+		if span, ok := spans[seg.SpanID]; ok {
+			spanCopy := *span
+			batchSpans[seg.SpanID] = &spanCopy
+		}
+	}
+
+	a.queueMu.Lock()
+	defer a.queueMu.Unlock()
+	if a.closed {
+		return
+	}
+	a.queue = append(a.queue, archiveBatch{segments: segments, spans: batchSpans, secrets: secrets})
+	a.queueCond.Broadcast()
+}
+
+// Flush waits until all the enqueued segments have been written.
+func (a *Archive) Flush() {
+	a.queueMu.Lock()
+	defer a.queueMu.Unlock()
+	for len(a.queue) > 0 || a.writing {
+		a.queueCond.Wait()
+	}
+}
+
+// Close writes any enqueued segments, then stops the background writer.
+// Segments enqueued after Close are dropped.
+func (a *Archive) Close() {
+	if a == nil {
+		return
+	}
+
+	a.queueMu.Lock()
+	a.closed = true
+	a.queueCond.Broadcast()
+	a.queueMu.Unlock()
+	<-a.done
+}
+
+func (a *Archive) writeLoop() {
+	defer close(a.done)
+	for {
+		a.queueMu.Lock()
+		for len(a.queue) == 0 && !a.closed {
+			a.queueCond.Wait()
+		}
+		if len(a.queue) == 0 {
+			// Closed, with everything written.
+			a.queueMu.Unlock()
+			return
+		}
+		batches := a.queue
+		a.queue = nil
+		a.writing = true
+		a.queueMu.Unlock()
+
+		for _, batch := range batches {
+			err := a.Write(batch.segments, batch.spans, batch.secrets)
+
+			// Only log the first error in a row, so that a full disk doesn't
+			// flood the logs (and trigger more archiving).
+			if err != nil && !a.failing {
+				log.Printf("Error writing log archive: %v", err)
+			}
+			a.failing = err != nil
+		}
+
+		a.queueMu.Lock()
+		a.writing = false
+		a.queueCond.Broadcast()
+		a.queueMu.Unlock()
+	}
+}
+
+// Write appends segments to the archive, after scrubbing the given secrets.
+//
+// Segments are appended to the file for their span, so they should be in the
+// order they were logged.
+func (a *Archive) Write(segments []LogSegment, spans map[SpanID]*Span, secrets model.SecretSet) error {
+	if len(segments) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	bySpan := make(map[SpanID][]positionedSegment)
+	var spanOrder []SpanID
+	for _, seg := range segments {
+		if _, ok := bySpan[seg.SpanID]; !ok {
+			spanOrder = append(spanOrder, seg.SpanID)
+		}
+		a.lastPos++
+		bySpan[seg.SpanID] = append(bySpan[seg.SpanID], positionedSegment{pos: a.lastPos, LogSegment: seg})
+	}
+
+	for _, spanID := range spanOrder {
+		var mn model.ManifestName
+		if span, ok := spans[spanID]; ok {
+			mn = span.ManifestName
+		}
+		err := a.writeSpan(mn, spanID, bySpan[spanID], secrets)
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.prune(time.Now())
+}
+
+type positionedSegment struct {
+	LogSegment
+	pos int64
+}
+
+func (a *Archive) writeSpan(mn model.ManifestName, spanID SpanID, segments []positionedSegment, secrets model.SecretSet) error {
+	dir := a.manifestDir(mn)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, url.PathEscape(string(spanID))+archiveFileExt)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+		a.updateIndex(path)
+	}()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	for _, seg := range segments {
+		err := encoder.Encode(archivedSegment{
+			Pos:           seg.pos,
+			Time:          seg.Time,
+			Level:         seg.Level.ToProtoID(),
+			Fields:        seg.Fields,
+			Text:          string(secrets.Scrub(seg.Text)),
+			Anchor:        seg.Anchor,
+			ContinuesLine: seg.ContinuesLine,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// ToLogList reads archived logs from the current session, oldest first.
+//
+// If mns is non-empty, only returns logs for those manifests.
+// If before is positive, only returns logs from before that position.
+// If limit is positive, only returns the latest `limit` segments.
+//
+// The positions of the returned segments are in [FromCheckpoint, ToCheckpoint),
+// so clients page backwards by passing FromCheckpoint as the next `before`.
+// An empty list has the interval [0, 0).
+func (a *Archive) ToLogList(mns model.ManifestNameSet, before int64, limit int) (*webview.LogList, error) {
+	a.Flush()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	manifestDirs, err := os.ReadDir(a.sessionDir())
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make(map[string]*webview.LogSpan)
+	var segments []*webview.LogSegment
+	var positions []int64
+	for _, manifestDir := range manifestDirs {
+		if !manifestDir.IsDir() {
+			continue
+		}
+
+		mn := model.ManifestName("")
+		if manifestDir.Name() != archiveGlobalDir {
+			name, err := url.PathUnescape(manifestDir.Name())
+			if err != nil {
+				continue
+			}
+			mn = model.ManifestName(name)
+		}
+		if len(mns) != 0 && !mns[mn] {
+			continue
+		}
+
+		spanFiles, err := os.ReadDir(filepath.Join(a.sessionDir(), manifestDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, spanFile := range spanFiles {
+			name := spanFile.Name()
+			if spanFile.IsDir() || !strings.HasSuffix(name, archiveFileExt) {
+				continue
+			}
+			spanID, err := url.PathUnescape(strings.TrimSuffix(name, archiveFileExt))
+			if err != nil {
+				continue
+			}
+
+			spanSegments, spanPositions, err := readArchivedSpan(filepath.Join(a.sessionDir(), manifestDir.Name(), name), spanID, before)
+			if err != nil {
+				return nil, err
+			}
+			if len(spanSegments) == 0 {
+				continue
+			}
+			spans[spanID] = &webview.LogSpan{ManifestName: string(mn)}
+			segments = append(segments, spanSegments...)
+			positions = append(positions, spanPositions...)
+		}
+	}
+
+	sort.Sort(byPosition{segments: segments, positions: positions})
+	if limit > 0 && len(segments) > limit {
+		start := pageStart(segments, len(segments)-limit)
+		segments = segments[start:]
+		positions = positions[start:]
+	}
+
+	result := &webview.LogList{
+		Spans:    spans,
+		Segments: segments,
+	}
+	if len(positions) > 0 {
+		result.FromCheckpoint = int32(positions[0])
+		result.ToCheckpoint = int32(positions[len(positions)-1] + 1)
+	}
+	return result, nil
+}
+
+// Sorts segments, and their positions alongside them, by position.
+type byPosition struct {
+	segments  []*webview.LogSegment
+	positions []int64
+}
+
+func (b byPosition) Len() int           { return len(b.segments) }
+func (b byPosition) Less(i, j int) bool { return b.positions[i] < b.positions[j] }
+func (b byPosition) Swap(i, j int) {
+	b.segments[i], b.segments[j] = b.segments[j], b.segments[i]
+	b.positions[i], b.positions[j] = b.positions[j], b.positions[i]
+}
+
+// Moves the start of a page back until it doesn't begin in the middle of a line,
+// so that clients paging backwards can put lines back together.
+func pageStart(segments []*webview.LogSegment, start int) int {
+	for {
+		// Find the spans whose first segment in the page continues a line.
+		seen := make(map[string]bool)
+		split := make(map[string]bool)
+		for _, seg := range segments[start:] {
+			if seen[seg.SpanId] {
+				continue
+			}
+			seen[seg.SpanId] = true
+			if seg.ContinuesLine {
+				split[seg.SpanId] = true
+			}
+		}
+
+		// Move back to the previous segment in one of those spans, if we have it.
+		prev := -1
+		for i := start - 1; i >= 0; i-- {
+			if split[segments[i].SpanId] {
+				prev = i
+				break
+			}
+		}
+		if prev == -1 {
+			return start
+		}
+		start = prev
+	}
+}
+
+func readArchivedSpan(path string, spanID string, before int64) ([]*webview.LogSegment, []int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var result []*webview.LogSegment
+	var positions []int64
+	decoder := json.NewDecoder(f)
+	for decoder.More() {
+		var seg archivedSegment
+		err := decoder.Decode(&seg)
+		if err != nil {
+			// The last write may have been interrupted. Keep what we have.
+			break
+		}
+		if before > 0 && seg.Pos >= before {
+			continue
+		}
+		positions = append(positions, seg.Pos)
+		result = append(result, &webview.LogSegment{
+			SpanId:        spanID,
+			Time:          timestamppb.New(seg.Time),
+			Text:          seg.Text,
+			Level:         webview.LogLevel(seg.Level),
+			Anchor:        seg.Anchor,
+			Fields:        seg.Fields,
+			ContinuesLine: seg.ContinuesLine,
+		})
+	}
+	return result, positions, nil
+}
+
+type archiveFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Builds the index of archived files in the session directories under the root.
+// Must be called while holding the lock.
+func (a *Archive) index() error {
+	entries, err := os.ReadDir(a.root)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), archiveSessionPrefix) {
+			continue
+		}
+
+		// Archived files are always <session>/<manifest>/<span>.jsonl.
+		matches, err := filepath.Glob(filepath.Join(a.root, entry.Name(), "*", "*"+archiveFileExt))
+		if err != nil {
+			return err
+		}
+		for _, path := range matches {
+			info, err := os.Lstat(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				continue
+			}
+			a.files[path] = archiveFile{path: path, size: info.Size(), modTime: info.ModTime()}
+			a.total += info.Size()
+		}
+	}
+	return nil
+}
+
+// Updates the index after writing a file. Must be called while holding the lock.
+func (a *Archive) updateIndex(path string) {
+	a.total -= a.files[path].size
+	delete(a.files, path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	a.files[path] = archiveFile{path: path, size: info.Size(), modTime: info.ModTime()}
+	a.total += info.Size()
+}
+
+// Deletes files that are too old, then the oldest files until the archive fits
+// in MaxBytes. Must be called while holding the lock.
+//
+// Only considers the files in the index, so files written by other Tilt
+// sessions since this one started get cleaned up the next time Tilt starts.
+func (a *Archive) prune(now time.Time) error {
+	if a.opts.MaxAge == 0 && a.opts.MaxBytes == 0 {
+		return nil
+	}
+
+	if !a.tooBig() && !a.hasTooOld(now) {
+		return nil
+	}
+
+	files := make([]archiveFile, 0, len(a.files))
+	for _, f := range a.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, f := range files {
+		if !a.isTooOld(f, now) && !a.tooBig() {
+			break
+		}
+		err := os.Remove(f.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(a.files, f.path)
+		a.total -= f.size
+		a.removeEmptyDirs(f.path)
+	}
+	return nil
+}
+
+// Removes the manifest and session directories of a deleted file,
+// if they're empty now (and aren't the current session's).
+func (a *Archive) removeEmptyDirs(path string) {
+	manifestDir := filepath.Dir(path)
+	sessionDir := filepath.Dir(manifestDir)
+	if sessionDir == a.sessionDir() {
+		return
+	}
+
+	// Remove fails on directories that aren't empty, which is what we want.
+	_ = os.Remove(manifestDir)
+	_ = os.Remove(sessionDir)
+}
+
+func (a *Archive) tooBig() bool {
+	return a.opts.MaxBytes != 0 && a.total > a.opts.MaxBytes
+}
+
+func (a *Archive) isTooOld(f archiveFile, now time.Time) bool {
+	return a.opts.MaxAge != 0 && now.Sub(f.modTime) > a.opts.MaxAge
+}
+
+func (a *Archive) hasTooOld(now time.Time) bool {
+	for _, f := range a.files {
+		if a.isTooOld(f, now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package logstore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+func TestArchiveTruncatedLogs(t *testing.T) {
+	a := newTestArchive(t, ArchiveOptions{})
+	l := NewLogStore()
+	l.maxLogLengthInBytes = 100
+	l.SetArchive(a)
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		l.Append(newTestLogEvent("fe", start.Add(time.Duration(i)*time.Second), fmt.Sprintf("line %d\n", i)), nil)
+	}
+	require.NotContains(t, l.SpanLog("fe"), "line 0\n")
+
+	list, err := a.ToLogList(nil, 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Segments)
+	assert.Equal(t, "line 0\n", list.Segments[0].Text)
+	assert.Equal(t, "fe", list.Spans["fe"].ManifestName)
+
+	// Together, the archive and the in-memory logs should have everything.
+	archived := ""
+	for _, seg := range list.Segments {
+		archived += seg.Text
+	}
+	assert.Equal(t, expectedLines(0, 20), archived+l.SpanLog("fe"))
+}
+
+func TestArchiveScrubsSecrets(t *testing.T) {
+	a := newTestArchive(t, ArchiveOptions{})
+	l := NewLogStore()
+	l.maxLogLengthInBytes = 50
+	l.SetArchive(a)
+
+	// The secret isn't known until after the first log, so it's only
+	// scrubbed from memory retroactively.
+	l.Append(newTestLogEvent("fe", time.Now(), "password: hunter2\n"), nil)
+	secrets := model.SecretSet{}
+	secrets.AddSecret("pw", "password", []byte("hunter2"))
+	l.ScrubSecretsStartingAt(secrets, 0)
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("fe", time.Now(), fmt.Sprintf("line %d\n", i)), secrets)
+	}
+
+	list, err := a.ToLogList(nil, 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Segments)
+	assert.Equal(t, "password: [redacted secret pw:password]\n", list.Segments[0].Text)
+}
+
+func TestArchiveFiltersAndPages(t *testing.T) {
+	a := newTestArchive(t, ArchiveOptions{})
+	start := time.Now()
+	spans := map[SpanID]*Span{
+		"fe": {ManifestName: "fe"},
+		"be": {ManifestName: "be"},
+	}
+	var segments []LogSegment
+	for i := 0; i < 10; i++ {
+		spanID := SpanID("fe")
+		if i%2 == 1 {
+			spanID = "be"
+		}
+		segments = append(segments, LogSegment{
+			SpanID: spanID,
+			Time:   start.Add(time.Duration(i) * time.Second),
+			Text:   []byte(fmt.Sprintf("line %d\n", i)),
+		})
+	}
+	require.NoError(t, a.Write(segments, spans, nil))
+
+	list, err := a.ToLogList(model.ManifestNameSet{"be": true}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1\n", "line 3\n", "line 5\n", "line 7\n", "line 9\n"}, segmentTexts(list.Segments))
+
+	list, err = a.ToLogList(nil, 7, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 3\n", "line 4\n", "line 5\n"}, segmentTexts(list.Segments))
+	assert.Equal(t, int32(4), list.FromCheckpoint)
+	assert.Equal(t, int32(7), list.ToCheckpoint)
+}
+
+// Segments from one append share a timestamp, so pages can't be split by time.
+func TestArchivePagesThroughSameTimestamp(t *testing.T) {
+	a := newTestArchive(t, ArchiveOptions{})
+	now := time.Now()
+	spans := map[SpanID]*Span{
+		"fe": {ManifestName: "fe"},
+		"be": {ManifestName: "be"},
+	}
+	var segments []LogSegment
+	for i := 0; i < 10; i++ {
+		spanID := SpanID("fe")
+		if i%3 == 0 {
+			spanID = "be"
+		}
+		segments = append(segments, LogSegment{SpanID: spanID, Time: now, Text: []byte(fmt.Sprintf("line %d\n", i))})
+	}
+	require.NoError(t, a.Write(segments, spans, nil))
+
+	// Page backwards, 3 segments at a time, until we run out.
+	var pages [][]string
+	before := int64(0)
+	for {
+		list, err := a.ToLogList(nil, before, 3)
+		require.NoError(t, err)
+		if len(list.Segments) == 0 {
+			assert.Equal(t, int32(0), list.FromCheckpoint)
+			break
+		}
+		pages = append([][]string{segmentTexts(list.Segments)}, pages...)
+		before = int64(list.FromCheckpoint)
+	}
+
+	var all []string
+	for _, page := range pages {
+		all = append(all, page...)
+	}
+	assert.Equal(t, strings.SplitAfter(expectedLines(0, 10), "\n")[:10], all)
+}
+
+func TestArchiveKeepsContinuesLine(t *testing.T) {
+	a := newTestArchive(t, ArchiveOptions{})
+	start := time.Now()
+	a.Enqueue([]LogSegment{
+		{SpanID: "fe", Time: start, Text: []byte("hello ")},
+		{SpanID: "fe", Time: start.Add(time.Second), Text: []byte("world\n"), ContinuesLine: true},
+	}, map[SpanID]*Span{"fe": {ManifestName: "fe"}}, nil)
+
+	list, err := a.ToLogList(nil, 2, 0)
+	require.NoError(t, err)
+	require.Len(t, list.Segments, 1)
+	assert.False(t, list.Segments[0].ContinuesLine)
+
+	// Pages don't start in the middle of a line.
+	list, err = a.ToLogList(nil, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello ", "world\n"}, segmentTexts(list.Segments))
+	assert.True(t, list.Segments[1].ContinuesLine)
+}
+
+func TestArchiveClose(t *testing.T) {
+	a := newTestArchive(t, ArchiveOptions{})
+	spans := map[SpanID]*Span{"fe": {ManifestName: "fe"}}
+	a.Enqueue([]LogSegment{{SpanID: "fe", Time: time.Now(), Text: []byte("before close\n")}}, spans, nil)
+
+	// Close writes what's enqueued, and stops the writer.
+	a.Close()
+	select {
+	case <-a.done:
+	default:
+		t.Fatal("writer still running after Close")
+	}
+
+	a.Enqueue([]LogSegment{{SpanID: "fe", Time: time.Now(), Text: []byte("after close\n")}}, spans, nil)
+	a.Close()
+
+	list, err := a.ToLogList(nil, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"before close\n"}, segmentTexts(list.Segments))
+
+	var nilArchive *Archive
+	nilArchive.Close()
+}
+
+func TestArchivePrunesBySize(t *testing.T) {
+	root := t.TempDir()
+	oldSession := filepath.Join(root, "tilt-session-old", "fe")
+	require.NoError(t, os.MkdirAll(oldSession, 0755))
+	oldFile := filepath.Join(oldSession, "fe.jsonl")
+	require.NoError(t, os.WriteFile(oldFile, []byte(strings.Repeat("x", 100)), 0644))
+	oldTime := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(oldFile, oldTime, oldTime))
+
+	a, err := NewArchive(root, "new", ArchiveOptions{MaxBytes: 150})
+	require.NoError(t, err)
+	defer a.Close()
+	assert.FileExists(t, oldFile)
+
+	err = a.Write([]LogSegment{{SpanID: "fe", Time: time.Now(), Text: []byte(strings.Repeat("y", 80))}},
+		map[SpanID]*Span{"fe": {ManifestName: "fe"}}, nil)
+	require.NoError(t, err)
+	assert.NoFileExists(t, oldFile)
+
+	list, err := a.ToLogList(nil, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, list.Segments, 1)
+}
+
+func TestArchivePrunesByAge(t *testing.T) {
+	root := t.TempDir()
+	oldSession := filepath.Join(root, "tilt-session-old", "fe")
+	require.NoError(t, os.MkdirAll(oldSession, 0755))
+	oldFile := filepath.Join(oldSession, "fe.jsonl")
+	require.NoError(t, os.WriteFile(oldFile, []byte("{}\n"), 0644))
+	oldTime := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(oldFile, oldTime, oldTime))
+
+	a, err := NewArchive(root, "new", ArchiveOptions{MaxAge: 24 * time.Hour})
+	require.NoError(t, err)
+	defer a.Close()
+	assert.NoFileExists(t, oldFile)
+	assert.NoDirExists(t, filepath.Dir(oldSession))
+}
+
+func TestArchiveOnlyPrunesSessionDirs(t *testing.T) {
+	root := t.TempDir()
+	oldTime := time.Now().Add(-48 * time.Hour)
+
+	// Files that Tilt didn't write, in a directory the user shares with the archive.
+	var others []string
+	for _, path := range []string{
+		filepath.Join(root, "notes.jsonl"),
+		filepath.Join(root, "other", "fe", "fe.jsonl"),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0644))
+		require.NoError(t, os.Chtimes(path, oldTime, oldTime))
+		others = append(others, path)
+	}
+
+	a, err := NewArchive(root, "new", ArchiveOptions{MaxAge: 24 * time.Hour, MaxBytes: 50})
+	require.NoError(t, err)
+	defer a.Close()
+	err = a.Write([]LogSegment{{SpanID: "fe", Time: time.Now(), Text: []byte(strings.Repeat("y", 80))}},
+		map[SpanID]*Span{"fe": {ManifestName: "fe"}}, nil)
+	require.NoError(t, err)
+
+	for _, path := range others {
+		assert.FileExists(t, path)
+	}
+}
+
+func newTestArchive(t *testing.T, opts ArchiveOptions) *Archive {
+	a, err := NewArchive(t.TempDir(), "session", opts)
+	require.NoError(t, err)
+	t.Cleanup(a.Close)
+	return a
+}
+
+func expectedLines(start, end int) string {
+	sb := strings.Builder{}
+	for i := start; i < end; i++ {
+		sb.WriteString(fmt.Sprintf("line %d\n", i))
+	}
+	return sb.String()
+}
+
+func segmentTexts(segments []*webview.LogSegment) []string {
+	var result []string
+	for _, seg := range segments {
+		result = append(result, seg.Text)
+	}
+	return result
+}
@@ -155,6 +155,13 @@ type LogStore struct {
 
 	// If the log is truncated, we need to adjust all checkpoints
 	checkpointOffset Checkpoint
+
+	// If set, logs truncated from memory are written to the archive.
+	archive *Archive
+
+	// The most recent secrets we've seen, so that we can scrub logs
+	// before they're archived.
+	secrets model.SecretSet
 }
 
 func NewLogStoreForTesting(msg string) *LogStore {
@@ -172,6 +179,15 @@ func NewLogStore() *LogStore {
 	}
 }
 
+// SetArchive sets an on-disk archive for logs that are truncated from memory.
+func (s *LogStore) SetArchive(a *Archive) {
+	s.archive = a
+}
+
+func (s *LogStore) Archive() *Archive {
+	return s.archive
+}
+
 func (s *LogStore) Checkpoint() Checkpoint {
 	return s.checkpointFromIndex(len(s.segments))
 }
@@ -234,6 +250,7 @@ func (s *LogStore) nextIndexMatchingManifests(index int, mns model.ManifestNameS
 }
 
 func (s *LogStore) ScrubSecretsStartingAt(secrets model.SecretSet, checkpoint Checkpoint) {
+	s.secrets = secrets
 	index := s.checkpointToIndex(checkpoint)
 	for i := index; i < len(s.segments); i++ {
 		s.segments[i].Text = secrets.Scrub(s.segments[i].Text)
@@ -257,6 +274,7 @@ func (s *LogStore) Append(le LogEvent, secrets model.SecretSet) {
 		s.spans[spanID] = span
 	}
 
+	s.secrets = secrets
 	msg := secrets.Scrub(le.Message())
 	added := segmentsFromBytes(spanID, le.Time(), le.Level(), le.Fields(), msg)
 	if len(added) == 0 {
@@ -790,6 +808,7 @@ func (s *LogStore) ensureMaxLength() {
 	// Lastly, go through all the segments, and truncate the manifests
 	// where we said we would.
 	newSegments := make([]LogSegment, 0, len(s.segments)/2)
+	var trimmedSegments []LogSegment
 	trimmedSegmentCount := 0
 	for i := len(s.segments) - 1; i >= 0; i-- {
 		segment := s.segments[i]
@@ -797,6 +816,9 @@ func (s *LogStore) ensureMaxLength() {
 		manifestWeightMap[mn].byteCount -= segment.Len()
 		if manifestWeightMap[mn].byteCount < 0 {
 			trimmedSegmentCount++
+			if s.archive != nil {
+				trimmedSegments = append(trimmedSegments, segment)
+			}
 			continue
 		}
 
@@ -804,6 +826,12 @@ func (s *LogStore) ensureMaxLength() {
 	}
 
 	reverseLogSegments(newSegments)
+	if s.archive != nil {
+		// Write to disk in the background, so that we don't hold up
+		// everyone waiting on the store.
+		reverseLogSegments(trimmedSegments)
+		s.archive.Enqueue(trimmedSegments, s.spans, s.secrets)
+	}
 	s.checkpointOffset += Checkpoint(trimmedSegmentCount)
 	s.segments = newSegments
 	s.recomputeDerivedValues()
@@ -63,6 +63,23 @@ type Manifest struct {
 	SourceTiltfile ManifestName
 
 	Labels map[string]string
+
+	// Settings that were overridden outside the Tiltfile.
+	//
+	// Only used to show users where a setting came from; the overrides
+	// have already been applied to the rest of the manifest.
+	Overrides []ManifestOverride
+}
+
+type ManifestOverride struct {
+	// The name of the setting, as it appears in the overrides file.
+	Setting string
+
+	// The path of the file that the setting came from.
+	Source string
+
+	// A human-readable form of the overriding value.
+	Value string
 }
 
 func (m Manifest) ID() TargetID {
@@ -263,6 +280,9 @@ func (m *Manifest) ClusterName() string {
 		return v1alpha1.ClusterNameDocker
 	}
 	if m.IsK8s() {
+		if cluster := m.K8sTarget().KubernetesApplySpec.Cluster; cluster != "" {
+			return cluster
+		}
 		return v1alpha1.ClusterNameDefault
 	}
 	return ""
@@ -597,7 +617,7 @@ var refSetAllowUnexported = cmp.AllowUnexported(container.RefSet{})
 var portForwardPathAllowUnexported = cmp.AllowUnexported(PortForward{})
 var ignoreCustomBuildDepsField = cmpopts.IgnoreFields(CustomBuild{}, "Deps")
 var ignoreLocalTargetDepsField = cmpopts.IgnoreFields(LocalTarget{}, "Deps")
-var ignoreDockerBuildCacheFrom = cmpopts.IgnoreFields(DockerBuild{}, "CacheFrom")
+var ignoreDockerBuildCache = cmpopts.IgnoreFields(DockerBuild{}, "CacheFrom", "CacheTo")
 var ignoreLabels = cmpopts.IgnoreFields(Manifest{}, "Labels")
 var ignoreDockerComposeProject = cmpopts.IgnoreFields(v1alpha1.DockerComposeServiceSpec{}, "Project")
 var ignoreRegistryFields = cmpopts.IgnoreFields(v1alpha1.RegistryHosting{}, "HostFromClusterNetwork", "Help")
@@ -640,9 +660,9 @@ func equalForBuildInvalidation(x, y interface{}) bool {
 		ignoreCustomBuildDepsField,
 		ignoreLocalTargetDepsField,
 
-		// DockerBuild.CacheFrom doesn't invalidate a build (b/c it affects HOW we build but
+		// DockerBuild.CacheFrom and CacheTo don't invalidate a build (b/c they affect HOW we build but
 		// shouldn't affect the result of the build), so don't compare these fields
-		ignoreDockerBuildCacheFrom,
+		ignoreDockerBuildCache,
 
 		// user-added labels don't invalidate a build
 		ignoreLabels,
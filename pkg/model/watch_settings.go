@@ -4,10 +4,13 @@ import "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 
 type WatchSettings struct {
 	Ignores []Dockerignore
+
+	// Drop file events where the file contents didn't change.
+	ContentHash bool
 }
 
 func (ws WatchSettings) Empty() bool {
-	return len(ws.Ignores) == 0
+	return len(ws.Ignores) == 0 && !ws.ContentHash
 }
 
 type Dockerignore struct {
@@ -31,6 +31,7 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildkitClusterConnection":         schema_pkg_apis_core_v1alpha1_BuildkitClusterConnection(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Cluster":                           schema_pkg_apis_core_v1alpha1_Cluster(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection":                 schema_pkg_apis_core_v1alpha1_ClusterConnection(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnectionStatus":           schema_pkg_apis_core_v1alpha1_ClusterConnectionStatus(ref),
@@ -134,12 +135,14 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec":                    schema_pkg_apis_core_v1alpha1_LiveUpdateExec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateKubernetesSelector":      schema_pkg_apis_core_v1alpha1_LiveUpdateKubernetesSelector(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateList":                    schema_pkg_apis_core_v1alpha1_LiveUpdateList(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateMountedSync":             schema_pkg_apis_core_v1alpha1_LiveUpdateMountedSync(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector":                schema_pkg_apis_core_v1alpha1_LiveUpdateSelector(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource":                  schema_pkg_apis_core_v1alpha1_LiveUpdateSource(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSpec":                    schema_pkg_apis_core_v1alpha1_LiveUpdateSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed":             schema_pkg_apis_core_v1alpha1_LiveUpdateStateFailed(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStatus":                  schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync":                    schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSyncBack":                schema_pkg_apis_core_v1alpha1_LiveUpdateSyncBack(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ObjectSelector":                    schema_pkg_apis_core_v1alpha1_ObjectSelector(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Pod":                               schema_pkg_apis_core_v1alpha1_Pod(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.PodCondition":                      schema_pkg_apis_core_v1alpha1_PodCondition(ref),
@@ -205,6 +208,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLink":                    schema_pkg_apis_core_v1alpha1_UIResourceLink(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceList":                    schema_pkg_apis_core_v1alpha1_UIResourceList(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLocal":                   schema_pkg_apis_core_v1alpha1_UIResourceLocal(ref),
+		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceOverride":                schema_pkg_apis_core_v1alpha1_UIResourceOverride(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceSpec":                    schema_pkg_apis_core_v1alpha1_UIResourceSpec(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceStateWaiting":            schema_pkg_apis_core_v1alpha1_UIResourceStateWaiting(ref),
 		"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceStateWaitingOnRef":       schema_pkg_apis_core_v1alpha1_UIResourceStateWaitingOnRef(ref),
@@ -272,6 +276,35 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_BuildkitClusterConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Connection spec for a standalone Buildkit daemon, which builds images without a Docker daemon.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The address of the buildkitd socket, e.g., unix:///run/buildkit/buildkitd.sock or tcp://127.0.0.1:1234.\n\nTo use a buildkitd running inside the cluster, port-forward to it.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"loadToContainerd": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, buildkitd stores images directly in the containerd instance that the cluster runs on, so they don't need to be pushed. This requires buildkitd to use the containerd worker with the k8s.io namespace.\n\nOtherwise, images are pushed to the cluster's registry.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"host"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_Cluster(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -435,11 +468,17 @@ func schema_pkg_apis_core_v1alpha1_ClusterSpec(ref common.ReferenceCallback) com
 							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"),
 						},
 					},
+					"buildkit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Buildkit connects to a standalone Buildkit daemon that builds the images for this cluster.\n\nIf not specified, images are built with the Docker daemon.",
+							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildkitClusterConnection"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.BuildkitClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.ClusterConnection", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.RegistryHosting"},
 	}
 }
 
@@ -489,6 +528,13 @@ func schema_pkg_apis_core_v1alpha1_ClusterStatus(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"imageLoader": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How Tilt gets the images it builds onto the cluster, when they're not built on the cluster's container runtime.\n\nOne of \"registry\" or \"containerd\". Empty for clusters that aren't Kubernetes clusters.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -2411,7 +2457,22 @@ func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback)
 					},
 					"cacheFrom": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Images to use as cache sources.\n\nEquivalent to `--cache-from` in the Docker CLI.",
+							Description: "Images to use as cache sources.\n\nEach entry is either an image reference, or a Buildkit cache source (e.g., type=registry,ref=gcr.io/foo/cache or type=local,src=path/to/dir).\n\nEquivalent to `--cache-from` in the Docker CLI.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"cacheTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Buildkit cache destinations to export the build cache to (e.g., type=registry,ref=gcr.io/foo/cache,mode=max, type=local,dest=path/to/dir, or type=inline).\n\nEquivalent to `--cache-to` in the Docker Buildx CLI.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -2426,11 +2487,26 @@ func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback)
 					},
 					"platform": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Platform specifies architecture information for target image.\n\nhttps://docs.docker.com/desktop/multi-arch/\n\nEquivalent to `--platform` in the Docker CLI.",
+							Description: "Platform specifies architecture information for target image.\n\nhttps://docs.docker.com/desktop/multi-arch/\n\nIf neither Platform nor Platforms is set, defaults to the architecture of the target cluster.\n\nEquivalent to `--platform` in the Docker CLI.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"platforms": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Platforms to build a multi-platform image for, with a manifest list (e.g., linux/amd64 and linux/arm64).\n\nCannot be combined with Platform. Requires Buildkit.\n\nEquivalent to `--platform` with multiple platforms in the Docker Buildx CLI.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 					"extraTags": {
 						SchemaProps: spec.SchemaProps{
 							Description: "By default, Tilt creates a new temporary image reference for each build. The user can also specify their own reference, to integrate with other tooling (like build IDs for Jenkins build pipelines)\n\nEquivalent to the docker build --tag flag.",
@@ -2525,12 +2601,25 @@ func schema_pkg_apis_core_v1alpha1_DockerImageStageStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the stage took, once it finished.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"transferredBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of bytes that the stage transferred, if Buildkit reported it (e.g., the size of the build context, or of the base image layers pulled).",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 				},
 				Required: []string{"name"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
@@ -3106,6 +3195,13 @@ func schema_pkg_apis_core_v1alpha1_FileEvent(ref common.ReferenceCallback) commo
 							},
 						},
 					},
+					"branchSwitch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BranchSwitch is true if these files changed because git moved HEAD (e.g., a branch checkout or a rebase).\n\nChanges during a git operation are held until the operation finishes, then reported together as a single event.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"time", "seenFiles"},
 			},
@@ -3253,6 +3349,13 @@ func schema_pkg_apis_core_v1alpha1_FileWatchSpec(ref common.ReferenceCallback) c
 							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableSource"),
 						},
 					},
+					"contentHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ContentHash enables content-hash change detection.\n\nThe watcher keeps a hash of each watched file, and drops events for files whose contents didn't actually change (e.g., a formatter that rewrites identical bytes, or a touch).",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"watchedPaths"},
 			},
@@ -3310,6 +3413,13 @@ func schema_pkg_apis_core_v1alpha1_FileWatchStatus(ref common.ReferenceCallback)
 							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableStatus"),
 						},
 					},
+					"suppressedEventCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SuppressedEventCount is the number of file changes that were dropped because the file contents didn't change. Only set in ContentHash mode.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
@@ -4930,6 +5040,52 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateList(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_LiveUpdateMountedSync(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "A sync that's satisfied by a bind mount.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"localPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The local path of the sync, as specified in the LiveUpdateSync.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"containerPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The path of the sync inside the container.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mountSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The local directory that's bind-mounted into the container.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"mountTarget": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The directory inside the container that the source is mounted at.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"localPath", "containerPath", "mountSource", "mountTarget"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_LiveUpdateSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5070,12 +5226,26 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"syncBacks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Paths to copy from the container back to the local filesystem after the execs have run.\n\nUseful for files that are generated inside the container (like lockfiles or generated code) that you want to check in.\n\nFile changes caused by copying these files don't trigger another update.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSyncBack"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"basePath", "selector"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateExec", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSelector", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSource", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSync", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateSyncBack"},
 	}
 }
 
@@ -5142,11 +5312,25 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref common.ReferenceCallback
 							Ref:         ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"),
 						},
 					},
+					"mountedSyncs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Syncs that the live-updater doesn't need to copy files for, because a bind mount already makes the local files visible in the container.\n\nOnly populated for Docker Compose services.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateMountedSync"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateContainerStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateMountedSync", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.LiveUpdateStateFailed"},
 	}
 }
 
@@ -5173,6 +5357,13 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"delta": {
+						SchemaProps: spec.SchemaProps{
+							Description: "When true, large files that already exist in the container are updated by sending only the blocks that changed, rather than the whole file.\n\nRequires a shell, dd, and md5sum in the container. Falls back to copying the whole file if they aren't available.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"localPath", "containerPath"},
 			},
@@ -5180,6 +5371,36 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSyncBack(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Specifies a path in the container to copy back to the local filesystem.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"containerPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "An absolute path inside the container. Required.\n\nMay be a file or a directory.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"localPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A relative path to local files. Required.\n\nComputed relative to the live-update BasePath.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"containerPath", "localPath"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_ObjectSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5583,6 +5804,13 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamSpec(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to parse log lines into structured fields.\n\nIf not set, log lines are not parsed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -5670,6 +5898,13 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamTemplateSpec(ref common.Reference
 							},
 						},
 					},
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to parse log lines into structured fields.\n\nIf not set, log lines are not parsed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -6211,6 +6446,21 @@ func schema_pkg_apis_core_v1alpha1_SessionSpec(ref common.ReferenceCallback) com
 							Format:      "",
 						},
 					},
+					"gateResources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GateResources are the names of the resources whose status determines when the session exits with ExitConditionCIGated.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"tiltfilePath", "exitCondition"},
 			},
@@ -8003,6 +8253,43 @@ func schema_pkg_apis_core_v1alpha1_UIResourceLocal(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_UIResourceOverride(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UIResourceOverride describes a resource setting that didn't come from the Tiltfile.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"setting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the setting (e.g., \"trigger_mode\", \"port_forwards\").",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The path of the file that the setting came from.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human-readable form of the overriding value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"setting", "source"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_UIResourceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -8246,11 +8533,25 @@ func schema_pkg_apis_core_v1alpha1_UIResourceStatus(ref common.ReferenceCallback
 							},
 						},
 					},
+					"overrides": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Settings of this resource that were overridden outside the Tiltfile (e.g., in the user's tilt_overrides.json).\n\nSettings that aren't listed here come from the Tiltfile.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceOverride"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableResourceStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBuildRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBuildTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceCondition", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceKubernetes", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLink", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLocal", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceStateWaiting", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceTargetSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
+			"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.DisableResourceStatus", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBuildRunning", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIBuildTerminated", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceCondition", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceKubernetes", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLink", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceLocal", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceOverride", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceStateWaiting", "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.UIResourceTargetSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
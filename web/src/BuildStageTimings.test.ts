@@ -0,0 +1,58 @@
+import {
+  contextTransferSize,
+  DockerImage,
+  formatBytes,
+  slowestStages,
+} from "./BuildStageTimings"
+
+function image(...stages: any[]): DockerImage {
+  return {
+    metadata: { name: "fe", annotations: { "tilt.dev/resource": "fe" } },
+    status: { stageStatuses: stages },
+  }
+}
+
+describe("BuildStageTimings", () => {
+  const images = [
+    image(
+      {
+        name: "[internal] load build context",
+        startedAt: "2022-05-18T19:58:39.000000Z",
+        finishedAt: "2022-05-18T19:58:40.000000Z",
+        transferredBytes: 2500000,
+      },
+      {
+        name: "[1/3] FROM docker.io/library/busybox",
+        cached: true,
+        startedAt: "2022-05-18T19:58:39.000000Z",
+        finishedAt: "2022-05-18T19:58:39.100000Z",
+      },
+      {
+        name: "[3/3] RUN npm install",
+        startedAt: "2022-05-18T19:58:40.000000Z",
+        finishedAt: "2022-05-18T19:59:10.000000Z",
+      },
+      {
+        name: "[2/3] ADD . .",
+        startedAt: "2022-05-18T19:58:40.000000Z",
+      }
+    ),
+  ]
+
+  it("sorts finished Dockerfile steps slowest first", () => {
+    expect(slowestStages(images)).toEqual([
+      { name: "[3/3] RUN npm install", cached: false, ms: 30000 },
+      { name: "[1/3] FROM docker.io/library/busybox", cached: true, ms: 100 },
+    ])
+  })
+
+  it("adds up the context transfer size", () => {
+    expect(contextTransferSize(images)).toEqual(2500000)
+    expect(contextTransferSize([image()])).toEqual(0)
+  })
+
+  it("formats bytes", () => {
+    expect(formatBytes(362)).toEqual("362B")
+    expect(formatBytes(2500000)).toEqual("2.5MB")
+  })
+})
@@ -0,0 +1,174 @@
+import moment from "moment"
+import React, { useEffect, useState } from "react"
+import styled from "styled-components"
+import { annotations } from "./annotations"
+import { Color, Font, FontSize, SizeUnit } from "./style-helpers"
+import { formatBuildDuration, timeDiff } from "./time"
+import { tiltApiList } from "./tiltApi"
+import { UIResource } from "./types"
+
+// DockerImage objects aren't part of the webview, so we
+// fetch them from the API server directly.
+export type DockerImageStageStatus = {
+  name: string
+  cached?: boolean
+  startedAt?: string
+  finishedAt?: string
+  error?: string
+  transferredBytes?: number
+}
+
+export type DockerImage = {
+  metadata?: Proto.v1ObjectMeta
+  status?: {
+    stageStatuses?: DockerImageStageStatus[]
+  }
+}
+
+export type StageTiming = {
+  name: string
+  cached: boolean
+  ms: number
+}
+
+const contextStageName = "[internal] load build context"
+const internalPrefix = "[internal]"
+const maxStagesShown = 3
+
+// Returns the finished Dockerfile steps, slowest first.
+export function slowestStages(images: DockerImage[]): StageTiming[] {
+  let result: StageTiming[] = []
+  images.forEach((image) => {
+    let stages = image.status?.stageStatuses || []
+    stages.forEach((stage) => {
+      if (!stage.startedAt || !stage.finishedAt) {
+        return
+      }
+      if (stage.name.startsWith(internalPrefix)) {
+        return
+      }
+      result.push({
+        name: stage.name,
+        cached: !!stage.cached,
+        ms: timeDiff(stage.startedAt, stage.finishedAt).asMilliseconds(),
+      })
+    })
+  })
+  result.sort((a, b) => b.ms - a.ms)
+  return result
+}
+
+// Returns the total size of the build contexts sent to Buildkit.
+export function contextTransferSize(images: DockerImage[]): number {
+  let total = 0
+  images.forEach((image) => {
+    let stages = image.status?.stageStatuses || []
+    stages.forEach((stage) => {
+      if (stage.name === contextStageName) {
+        total += stage.transferredBytes || 0
+      }
+    })
+  })
+  return total
+}
+
+export function formatBytes(n: number): string {
+  let units = ["B", "KB", "MB", "GB"]
+  let i = 0
+  while (n >= 1000 && i < units.length - 1) {
+    n /= 1000
+    i++
+  }
+  return i === 0 ? `${n}${units[i]}` : `${n.toFixed(1)}${units[i]}`
+}
+
+let BuildStageTimingsRoot = styled.div`
+  display: flex;
+  flex-wrap: wrap;
+  align-items: center;
+  background-color: ${Color.gray10};
+  border-bottom: 1px solid ${Color.gray40};
+  color: ${Color.gray70};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  padding: ${SizeUnit(0.125)} ${SizeUnit(0.5)};
+`
+
+let Label = styled.span`
+  color: ${Color.gray60};
+  margin-right: ${SizeUnit(0.25)};
+`
+
+let Stage = styled.span`
+  margin-right: ${SizeUnit(0.5)};
+
+  &.isCached {
+    color: ${Color.gray60};
+  }
+`
+
+type BuildStageTimingsProps = {
+  resource?: UIResource
+}
+
+// Shows which Dockerfile steps took the longest in the most recent
+// image builds of a resource.
+export default function BuildStageTimings(props: BuildStageTimingsProps) {
+  let manifestName = props.resource?.metadata?.name || ""
+  let lastBuild = props.resource?.status?.buildHistory?.[0]
+  let lastFinishTime = lastBuild?.finishTime || ""
+  let [images, setImages] = useState<DockerImage[]>([])
+
+  useEffect(() => {
+    if (!manifestName) {
+      setImages([])
+      return
+    }
+
+    let cancelled = false
+    tiltApiList<DockerImage>("dockerimages")
+      .then((list) => {
+        if (cancelled) {
+          return
+        }
+        setImages(
+          list.filter(
+            (image) => annotations(image)["tilt.dev/resource"] === manifestName
+          )
+        )
+      })
+      .catch(() => {
+        // The timings are a nice-to-have, so don't show an error.
+        if (!cancelled) {
+          setImages([])
+        }
+      })
+    return () => {
+      cancelled = true
+    }
+  }, [manifestName, lastFinishTime])
+
+  let stages = slowestStages(images)
+  if (stages.length === 0) {
+    return null
+  }
+
+  let contextSize = contextTransferSize(images)
+  return (
+    <BuildStageTimingsRoot aria-label="Build step timings">
+      <Label>Slowest build steps:</Label>
+      {stages.slice(0, maxStagesShown).map((stage, i) => (
+        <Stage key={i} className={stage.cached ? "isCached" : ""}>
+          {stage.name} ({formatBuildDuration(moment.duration(stage.ms))}
+          {stage.cached ? ", cached" : ""})
+        </Stage>
+      ))}
+      {contextSize > 0 ? (
+        <Stage>
+          <Label>Context:</Label>
+          {formatBytes(contextSize)}
+        </Stage>
+      ) : null}
+    </BuildStageTimingsRoot>
+  )
+}
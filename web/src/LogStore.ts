@@ -617,6 +617,7 @@ class LogStore implements LogAlertIndex {
           manifestName: span.manifestName,
           buildEvent: storedLine.fields?.buildEvent,
           spanId: spanId,
+          fields: storedLine.fields ?? undefined,
           storedLineIndex: i,
         }
 
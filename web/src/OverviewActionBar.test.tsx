@@ -190,6 +190,9 @@ describe("OverviewActionBar", () => {
   })
 
   describe("term filter input", () => {
+    const termInput = () =>
+      screen.getByRole("textbox", { name: /filter resource logs/i })
+
     it("renders with no initial value if there is no existing term filter", () => {
       customRender(<FullBar />, { history })
 
@@ -216,7 +219,7 @@ describe("OverviewActionBar", () => {
 
       customRender(<FullBar />, { history })
 
-      userEvent.type(screen.getByRole("textbox"), "docker")
+      userEvent.type(termInput(), "docker")
 
       jest.advanceTimersByTime(FILTER_INPUT_DEBOUNCE)
 
@@ -228,14 +231,14 @@ describe("OverviewActionBar", () => {
 
       customRender(<FullBar />, { history })
 
-      userEvent.type(screen.getByRole("textbox"), "doc")
+      userEvent.type(termInput(), "doc")
 
       jest.advanceTimersByTime(FILTER_INPUT_DEBOUNCE / 2)
 
       // The debouncing time hasn't passed yet, so we don't expect to see any changes
       expect(history.location.search.toString()).toEqual("")
 
-      userEvent.type(screen.getByRole("textbox"), "ker")
+      userEvent.type(termInput(), "ker")
 
       // The debouncing time hasn't passed yet, so we don't expect to see any changes
       expect(history.location.search.toString()).toEqual("")
@@ -254,7 +257,7 @@ describe("OverviewActionBar", () => {
 
       customRender(<FullBar />, { history })
 
-      userEvent.type(screen.getByRole("textbox"), "help")
+      userEvent.type(termInput(), "help")
 
       jest.advanceTimersByTime(FILTER_INPUT_DEBOUNCE)
 
@@ -264,6 +267,52 @@ describe("OverviewActionBar", () => {
     })
   })
 
+  describe("fields filter input", () => {
+    const fieldsInput = () =>
+      screen.getByRole("textbox", { name: /structured fields/i })
+
+    it("renders with an initial value if there is an existing fields filter", () => {
+      history.push({
+        pathname: "/",
+        search: createLogSearch("", {
+          fields: { level: "error", trace_id: "abc" },
+        }).toString(),
+      })
+
+      customRender(<FullBar />, { history })
+
+      expect(fieldsInput()).toHaveValue("level=error trace_id=abc")
+    })
+
+    it("changes the global fields filter state when its value changes", () => {
+      jest.useFakeTimers()
+
+      history.push({ pathname: "/", search: "term=help" })
+
+      customRender(<FullBar />, { history })
+
+      userEvent.type(fieldsInput(), "level=error")
+
+      jest.advanceTimersByTime(FILTER_INPUT_DEBOUNCE)
+
+      expect(history.location.search.toString()).toEqual(
+        "?term=help&field=level%3Derror"
+      )
+    })
+
+    it("doesn't update the filter state while a pair is incomplete", () => {
+      jest.useFakeTimers()
+
+      customRender(<FullBar />, { history })
+
+      userEvent.type(fieldsInput(), "level")
+
+      jest.advanceTimersByTime(FILTER_INPUT_DEBOUNCE)
+
+      expect(history.location.search.toString()).toEqual("")
+    })
+  })
+
   describe("createLogSearch", () => {
     let currentSearch: URLSearchParams
     beforeEach(() => (currentSearch = new URLSearchParams()))
@@ -25,11 +25,14 @@ import { displayURL } from "./links"
 import LogActions from "./LogActions"
 import {
   EMPTY_TERM,
+  fieldsInput,
+  FilterFields,
   FilterLevel,
   FilterSet,
   FilterSource,
   FilterTerm,
   isErrorTerm,
+  parseFieldsInput,
   TermState,
 } from "./logfilters"
 import { useLogStore } from "./LogStore"
@@ -327,7 +330,13 @@ export function createLogSearch(
     level,
     source,
     term,
-  }: { level?: FilterLevel; source?: FilterSource; term?: string }
+    fields,
+  }: {
+    level?: FilterLevel
+    source?: FilterSource
+    term?: string
+    fields?: FilterFields
+  }
 ) {
   // Start with the existing search params
   const newSearch = new URLSearchParams(currentSearch)
@@ -356,6 +365,13 @@ export function createLogSearch(
     }
   }
 
+  if (fields !== undefined) {
+    newSearch.delete("field")
+    Object.entries(fields).forEach(([key, value]) => {
+      newSearch.append("field", `${key}=${value}`)
+    })
+  }
+
   return newSearch
 }
 
@@ -581,6 +597,90 @@ export function FilterTermField({ termFromUrl }: { termFromUrl: FilterTerm }) {
   )
 }
 
+export const FILTER_FIELDS_FIELD_ID = "FilterFieldsTextInput"
+
+// Filters logs by their structured fields, e.g., `level=error`.
+// Like the term field, the input is debounced before it updates the url.
+export function FilterFieldsField({
+  fieldsFromUrl,
+}: {
+  fieldsFromUrl: FilterFields | undefined
+}) {
+  const location = useLocation()
+  const history = useHistory()
+
+  const [input, setInput] = useState(fieldsInput(fieldsFromUrl))
+
+  // If the location changes, reset the value of the input field based on url
+  useEffect(() => {
+    setInput(fieldsInput(fieldsFromUrl))
+  }, [location.pathname])
+
+  const fields = parseFieldsInput(input)
+
+  const setFields = (value: string, withDebounceDelay = true) => {
+    setInput(value)
+
+    const parsed = parseFieldsInput(value)
+    if (!parsed) {
+      return
+    }
+
+    const search = createLogSearch(location.search, { fields: parsed })
+    if (withDebounceDelay) {
+      debounceFilterLogs(history, search.toString())
+    } else {
+      history.push({ search: search.toString() })
+    }
+  }
+
+  const onChange = (
+    event: ChangeEvent<HTMLInputElement | HTMLTextAreaElement>
+  ) => {
+    setFields(event.target.value ?? "")
+  }
+
+  const inputProps: InputProps = {}
+  if (input) {
+    inputProps.endAdornment = (
+      <InputAdornment position="end">
+        <ClearFilterTermTextButton
+          analyticsName="ui.web.clearFilterFields"
+          onClick={() => setFields("", false)}
+        >
+          <SrOnly>Clear field filter</SrOnly>
+          <CloseSvg fill={Color.grayLightest} role="presentation" />
+        </ClearFilterTermTextButton>
+      </InputAdornment>
+    )
+  }
+
+  return (
+    <>
+      <FilterTermTextField
+        error={!fields}
+        id={FILTER_FIELDS_FIELD_ID}
+        helperText={
+          !fields ? (
+            <FilterTermFieldError error="Fields should be key=value pairs" />
+          ) : (
+            ""
+          )
+        }
+        InputProps={inputProps}
+        onChange={onChange}
+        placeholder="Filter by field=value"
+        value={input}
+        variant="outlined"
+        analyticsName="ui.web.filterFields"
+      />
+      <SrOnly component="label" htmlFor={FILTER_FIELDS_FIELD_ID}>
+        Filter logs by structured fields, like level=error
+      </SrOnly>
+    </>
+  )
+}
+
 type CopyButtonProps = {
   podId: string
 }
@@ -808,6 +908,12 @@ export default function OverviewActionBar(props: OverviewActionBarProps) {
     bottomRow.push(
       <FilterTermField key="filterTermField" termFromUrl={filterSet.term} />
     )
+    bottomRow.push(
+      <FilterFieldsField
+        key="filterFieldsField"
+        fieldsFromUrl={filterSet.fields}
+      />
+    )
     bottomRow.push(
       <LogActions
         key="logActions"
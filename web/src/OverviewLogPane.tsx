@@ -9,6 +9,11 @@ import {
   FilterSource,
   TermState,
 } from "./logfilters"
+import {
+  archivedLogLines,
+  archivePageSize,
+  fetchArchivedLogs,
+} from "./logarchive"
 import "./LogLine.scss"
 import "./LogPane.scss"
 import LogStore, {
@@ -217,6 +222,16 @@ export class OverviewLogComponent extends Component<OverviewLogComponentProps> {
   // N lines before the error. So we keep track of the last N lines for each span.
   private prologuesBySpanId: { [key: string]: LogLine[] } = {}
 
+  // When the user scrolls to the top, we page back through the logs that
+  // Tilt has truncated from memory, if it's archiving them.
+  //
+  // archiveBefore is the archive position of the oldest log we've rendered.
+  // archiveRequest is bumped on reset, so that we drop responses for old renders.
+  private archiveBefore: number = 0
+  private archiveLoading: boolean = false
+  private archiveDone: boolean = false
+  private archiveRequest: number = 0
+
   constructor(props: OverviewLogComponentProps) {
     super(props)
 
@@ -316,6 +331,10 @@ export class OverviewLogComponent extends Component<OverviewLogComponentProps> {
       return
     }
 
+    if (scrollTop === 0 && scrollTop < oldScrollTop) {
+      this.maybeLoadArchivedLogs()
+    }
+
     // If we're scrolled horizontally, cancel the autoscroll.
     if (rootEl.scrollLeft > 0) {
       if (this.autoscroll) {
@@ -404,6 +423,11 @@ export class OverviewLogComponent extends Component<OverviewLogComponentProps> {
     this.logCheckpoint = 0
     this.scrollTop = -1
 
+    this.archiveBefore = 0
+    this.archiveLoading = false
+    this.archiveDone = false
+    this.archiveRequest++
+
     if (this.renderBufferRafId) {
       this.props.raf.cancelAnimationFrame(this.renderBufferRafId)
       this.renderBufferRafId = 0
@@ -438,6 +462,14 @@ export class OverviewLogComponent extends Component<OverviewLogComponentProps> {
     return true
   }
 
+  // If we have a fields filter on, check if this line has all the fields.
+  matchesFieldsFilter(line: LogLine): boolean {
+    let fields = this.props.filterSet.fields ?? {}
+    return Object.entries(fields).every(
+      ([key, value]) => line.fields?.[key] === value
+    )
+  }
+
   // Check if this line matches the current filter.
   matchesFilter(line: LogLine): boolean {
     if (line.buildEvent) {
@@ -457,7 +489,11 @@ export class OverviewLogComponent extends Component<OverviewLogComponentProps> {
       return false
     }
 
-    return this.matchesLevelFilter(line) && this.matchesTermFilter(line)
+    return (
+      this.matchesLevelFilter(line) &&
+      this.matchesTermFilter(line) &&
+      this.matchesFieldsFilter(line)
+    )
   }
 
   // Index this line so that we can display prologues to errors.
@@ -524,6 +560,74 @@ export class OverviewLogComponent extends Component<OverviewLogComponentProps> {
     this.maybeScheduleRender()
   }
 
+  // Fetch the next page of archived logs, and render them at the top of the pane.
+  maybeLoadArchivedLogs() {
+    if (
+      this.archiveLoading ||
+      this.archiveDone ||
+      this.backwardBuffer.length > 0 ||
+      this.props.pathBuilder.isSnapshot()
+    ) {
+      return
+    }
+
+    this.archiveLoading = true
+    let request = this.archiveRequest
+    fetchArchivedLogs(
+      this.props.manifestName,
+      this.archiveBefore,
+      archivePageSize
+    )
+      .then((logList) => {
+        if (request !== this.archiveRequest) {
+          return
+        }
+        this.archiveLoading = false
+
+        let segments = logList?.segments ?? []
+        if (!logList || segments.length === 0) {
+          this.archiveDone = true
+          return
+        }
+        this.archiveBefore = logList.fromCheckpoint ?? 0
+        if (!this.archiveBefore) {
+          this.archiveDone = true
+        }
+
+        let lines = archivedLogLines(logList).filter((line) =>
+          this.matchesFilter(line)
+        )
+        this.renderArchivedLines(lines)
+      })
+      .catch((err) => {
+        if (request !== this.archiveRequest) {
+          return
+        }
+        console.error(err)
+        this.archiveLoading = false
+        this.archiveDone = true
+      })
+  }
+
+  // Archived lines are older than anything in the LogStore, so they go at the
+  // top of the pane, without moving the lines that the user is looking at.
+  renderArchivedLines(lines: LogLine[]) {
+    let root = this.rootRef.current
+    if (!root || lines.length === 0) {
+      return
+    }
+
+    let showManifestName = !this.props.manifestName
+    let fragment = document.createDocumentFragment()
+    lines.forEach((line) => {
+      fragment.appendChild(newLineEl(line, showManifestName, []))
+    })
+
+    let oldScrollHeight = root.scrollHeight
+    root.insertBefore(fragment, root.firstChild)
+    root.scrollTop += root.scrollHeight - oldScrollHeight
+  }
+
   // Schedule a render job if there's not one already scheduled.
   maybeScheduleRender() {
     if (this.renderBufferRafId) return
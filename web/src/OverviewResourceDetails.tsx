@@ -2,6 +2,7 @@ import React from "react"
 import styled from "styled-components"
 import { Alert } from "./alerts"
 import { ButtonSet } from "./ApiButton"
+import BuildStageTimings from "./BuildStageTimings"
 import { useFilterSet } from "./logfilters"
 import OverviewActionBar from "./OverviewActionBar"
 import OverviewLogPane from "./OverviewLogPane"
@@ -48,6 +49,7 @@ export default function OverviewResourceDetails(
         alerts={alerts}
         buttons={buttons}
       />
+      {notFound ? null : <BuildStageTimings resource={resource} />}
       {notFound ? (
         <NotFound>No resource '{name}'</NotFound>
       ) : (
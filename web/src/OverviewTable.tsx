@@ -433,12 +433,23 @@ function uiResourceToCell(
     podId: res.k8sResourceInfo?.podName ?? "",
     endpoints: res.endpointLinks ?? [],
     mode: res.triggerMode ?? TriggerMode.TriggerModeAuto,
+    triggerModeSource: overrideSource(res, "trigger_mode", "auto_init"),
+    portForwardsSource: overrideSource(res, "port_forwards"),
+    labelsSource: overrideSource(res, "labels"),
+    disabledSource: overrideSource(res, "disabled"),
     buttons: buttons,
     analyticsTags: analyticsTags,
     selectable,
   }
 }
 
+// If any of the settings were overridden outside the Tiltfile,
+// the file they came from.
+function overrideSource(res: UIResourceStatus, ...settings: string[]): string {
+  let override = res.overrides?.find((o) => settings.includes(o.setting ?? ""))
+  return override?.source ?? ""
+}
+
 function resourceTypeLabel(r: UIResource): string {
   let res = (r.status || {}) as UIResourceStatus
   let name = r.metadata?.name
@@ -63,6 +63,11 @@ export type RowValues = {
   podId: string
   endpoints: UILink[]
   mode: TriggerMode
+  // The files that overrode the Tiltfile's settings, if any.
+  triggerModeSource: string
+  portForwardsSource: string
+  labelsSource: string
+  disabledSource: string
   buttons: ButtonSet
   analyticsTags: Tags
   selectable: boolean
@@ -394,6 +399,18 @@ export function TableBuildButtonColumn({ row }: CellProps<RowValues>) {
   )
 }
 
+// Explains which of the resource's settings were overridden outside the Tiltfile.
+function nameTitleText(row: RowValues): string | undefined {
+  let notes: string[] = []
+  if (row.disabledSource) {
+    notes.push(`Disabled state set in ${row.disabledSource}`)
+  }
+  if (row.labelsSource) {
+    notes.push(`Labels set in ${row.labelsSource}`)
+  }
+  return notes.length ? notes.join("\n") : undefined
+}
+
 export function TableNameColumn({ row }: CellProps<RowValues>) {
   let nav = useResourceNav()
   let hasError =
@@ -405,6 +422,7 @@ export function TableNameColumn({ row }: CellProps<RowValues>) {
     <Name
       className={`${errorClass} ${disabledClass}`}
       onClick={(e) => nav.openResource(row.values.name)}
+      title={nameTitleText(row.original)}
     >
       {row.values.name}
     </Name>
@@ -504,7 +522,10 @@ export function TableEndpointColumn({ row }: CellProps<RowValues>) {
     return null
   }
 
+  let source = row.original.portForwardsSource
   let endpoints = row.original.endpoints.map((ep: any) => {
+    let text = ep.name || displayURL(ep)
+    let title = source ? `${text} (port-forwards set in ${source})` : text
     return (
       <Endpoint
         onClick={() =>
@@ -516,9 +537,7 @@ export function TableEndpointColumn({ row }: CellProps<RowValues>) {
         key={ep.url}
       >
         <StyledLinkSvg />
-        <DetailText title={ep.name || displayURL(ep)}>
-          {ep.name || displayURL(ep)}
-        </DetailText>
+        <DetailText title={title}>{text}</DetailText>
       </Endpoint>
     )
   })
@@ -534,6 +553,7 @@ export function TableTriggerModeColumn({ row }: CellProps<RowValues>) {
     <OverviewTableTriggerModeToggle
       resourceName={row.values.name}
       triggerMode={row.values.mode}
+      triggerModeSource={row.original.triggerModeSource}
     />
   )
 }
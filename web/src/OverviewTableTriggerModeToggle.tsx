@@ -43,6 +43,8 @@ let StyledTriggerModeToggle = styled(InstrumentedButton)`
 type TriggerModeToggleProps = {
   resourceName: string
   triggerMode: TriggerMode
+  // The file that overrode the Tiltfile's trigger mode, if any.
+  triggerModeSource?: string
 }
 
 export const ToggleTriggerModeTooltip = {
@@ -50,12 +52,14 @@ export const ToggleTriggerModeTooltip = {
   isAuto: "Auto: File changes trigger update",
 }
 
-const titleText = (isManual: boolean): string => {
-  if (isManual) {
-    return ToggleTriggerModeTooltip.isManual
-  } else {
-    return ToggleTriggerModeTooltip.isAuto
+const titleText = (isManual: boolean, source?: string): string => {
+  let text = isManual
+    ? ToggleTriggerModeTooltip.isManual
+    : ToggleTriggerModeTooltip.isAuto
+  if (source) {
+    text += ` (set in ${source})`
   }
+  return text
 }
 
 export function toggleTriggerMode(name: string, mode: TriggerMode) {
@@ -93,7 +97,7 @@ export default function OverviewTableTriggerModeToggle(
     <StyledTriggerModeToggle
       className={isManualTriggerMode ? "is-manual" : ""}
       onClick={onClick}
-      title={titleText(isManualTriggerMode)}
+      title={titleText(isManualTriggerMode, props.triggerModeSource)}
       analyticsName="ui.web.toggleTriggerMode"
       analyticsTags={{ toMode: desiredMode.toString() }}
     >
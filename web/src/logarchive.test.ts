@@ -0,0 +1,48 @@
+import { archivedLogLines } from "./logarchive"
+
+describe("archivedLogLines", () => {
+  it("joins segments that continue a line", () => {
+    let lines = archivedLogLines({
+      spans: {
+        fe: { manifestName: "fe" },
+        be: { manifestName: "be" },
+      },
+      segments: [
+        { spanId: "fe", text: "hello " },
+        { spanId: "be", text: "backend\n" },
+        { spanId: "fe", text: "world\n", continuesLine: true },
+        { spanId: "fe", text: "goodbye\n" },
+      ],
+    })
+
+    expect(lines.map((line) => line.text)).toEqual([
+      "hello world",
+      "backend",
+      "goodbye",
+    ])
+    expect(lines.map((line) => line.manifestName)).toEqual(["fe", "be", "fe"])
+    expect(lines.every((line) => line.storedLineIndex === -1)).toBe(true)
+  })
+
+  it("starts a new line when the line it continues isn't in the page", () => {
+    let lines = archivedLogLines({
+      spans: { fe: { manifestName: "fe" } },
+      segments: [{ spanId: "fe", text: "world\n", continuesLine: true }],
+    })
+
+    expect(lines.map((line) => line.text)).toEqual(["world"])
+  })
+
+  it("starts a new line when the level changes", () => {
+    let lines = archivedLogLines({
+      spans: { fe: { manifestName: "fe" } },
+      segments: [
+        { spanId: "fe", text: "hello ", level: "INFO" },
+        { spanId: "fe", text: "oops\n", level: "ERROR", continuesLine: true },
+      ],
+    })
+
+    expect(lines.map((line) => line.text)).toEqual(["hello ", "oops"])
+    expect(lines.map((line) => line.level)).toEqual(["INFO", "ERROR"])
+  })
+})
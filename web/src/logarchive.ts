@@ -0,0 +1,97 @@
+// Helpers for paging through logs that Tilt has truncated from memory
+// and written to the on-disk log archive (tilt up --log-archive).
+
+import { LogLine } from "./types"
+
+// The number of archived segments to fetch at a time.
+export const archivePageSize = 500
+
+// Fetches the page of archived logs just before `before`. The page holds
+// the newest `limit` segments, oldest first.
+//
+// `before` is the position of the oldest archived segment we've seen
+// (the fromCheckpoint of the last page), or 0 for the newest page.
+//
+// Returns null if the log archive isn't enabled.
+export async function fetchArchivedLogs(
+  manifestName: string,
+  before: number,
+  limit: number
+): Promise<Proto.webviewLogList | null> {
+  let params = new URLSearchParams()
+  if (manifestName) {
+    params.append("manifest", manifestName)
+  }
+  if (before > 0) {
+    params.append("before", String(before))
+  }
+  params.append("limit", String(limit))
+
+  const resp = await fetch(`/api/logs/archive?${params.toString()}`, {
+    method: "GET",
+    headers: {
+      Accept: "application/json",
+    },
+  })
+  if (resp.status === 404) {
+    return null
+  }
+  if (resp.status !== 200) {
+    const body = await resp.text()
+    throw `error fetching archived logs: ${body}`
+  }
+  return (await resp.json()) as Proto.webviewLogList
+}
+
+// Folds archived segments into lines.
+//
+// Archived lines aren't in the LogStore, so they all have a storedLineIndex of -1.
+export function archivedLogLines(logList: Proto.webviewLogList): LogLine[] {
+  let spans = (logList.spans ?? {}) as {
+    [key: string]: Proto.webviewLogSpan
+  }
+  let lines: LogLine[] = []
+
+  // The index of the last line for each span, if that line hasn't ended yet.
+  let openLines: { [key: string]: number } = {}
+
+  for (let seg of logList.segments ?? []) {
+    let spanId = seg.spanId ?? ""
+    let text = seg.text ?? ""
+    let level = seg.level ?? "INFO"
+    let open = openLines[spanId]
+    if (
+      seg.continuesLine &&
+      open !== undefined &&
+      lines[open].level === level
+    ) {
+      lines[open].text += text
+    } else {
+      let fields = seg.fields as { [key: string]: string } | undefined
+      lines.push({
+        text: text,
+        level: level,
+        manifestName: spans[spanId]?.manifestName ?? "",
+        buildEvent: fields?.buildEvent,
+        spanId: spanId,
+        fields: fields,
+        storedLineIndex: -1,
+      })
+      open = lines.length - 1
+    }
+
+    if (text[text.length - 1] === "\n") {
+      delete openLines[spanId]
+    } else {
+      openLines[spanId] = open
+    }
+  }
+
+  // strip off the newlines
+  lines.forEach((line) => {
+    if (line.text[line.text.length - 1] === "\n") {
+      line.text = line.text.substring(0, line.text.length - 1)
+    }
+  })
+  return lines
+}
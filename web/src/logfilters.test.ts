@@ -2,6 +2,7 @@ import { Location } from "history"
 import {
   EMPTY_FILTER_TERM,
   filterSetFromLocation,
+  parseFieldsInput,
   parseTermInput,
   TermState,
 } from "./logfilters"
@@ -17,6 +18,32 @@ enum TestStrings {
 
 describe("Log filters", () => {
   describe("state generation", () => {
+    describe("for fields filter", () => {
+      it("parses repeated field params", () => {
+        const location = {
+          search: "field=level%3Derror&field=trace_id%3Dabc",
+        } as Location
+        expect(filterSetFromLocation(location).fields).toEqual({
+          level: "error",
+          trace_id: "abc",
+        })
+      })
+
+      it("is unset if no fields are present", () => {
+        const location = { search: "" } as Location
+        expect(filterSetFromLocation(location).fields).toBeUndefined()
+      })
+
+      it("rejects pairs without a key", () => {
+        expect(parseFieldsInput("level=error  msg=a=b")).toEqual({
+          level: "error",
+          msg: "a=b",
+        })
+        expect(parseFieldsInput("error")).toBeNull()
+        expect(parseFieldsInput("=error")).toBeNull()
+      })
+    })
+
     describe("for term filter", () => {
       it("gets set with an empty state if no term is present", () => {
         const emptyTermLocation = { search: "term=" } as Location
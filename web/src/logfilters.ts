@@ -46,10 +46,14 @@ export type FilterTerm = {
   input: string // Unmodified string input
 } & (EmptyTerm | ParsedTerm | ErrorTerm)
 
+// Structured log fields that a line must have, e.g., {level: "error"}.
+export type FilterFields = { [key: string]: string }
+
 export type FilterSet = {
   level: FilterLevel
   source: FilterSource
   term: FilterTerm
+  fields?: FilterFields
 }
 
 export const EMPTY_TERM = ""
@@ -106,6 +110,30 @@ export function createFilterTermState(input: string): FilterTerm {
   }
 }
 
+// Parses space-separated key=value pairs, e.g., "level=error trace_id=abc".
+// Returns null if any of the pairs is malformed.
+export function parseFieldsInput(input: string): FilterFields | null {
+  let fields: FilterFields = {}
+  for (let pair of input.split(/\s+/)) {
+    if (!pair) {
+      continue
+    }
+    let index = pair.indexOf("=")
+    if (index <= 0) {
+      return null
+    }
+    fields[pair.slice(0, index)] = pair.slice(index + 1)
+  }
+  return fields
+}
+
+// Formats fields as space-separated key=value pairs.
+export function fieldsInput(fields: FilterFields | undefined): string {
+  return Object.entries(fields ?? {})
+    .map(([key, value]) => `${key}=${value}`)
+    .join(" ")
+}
+
 // Infers filter set from the history React hook.
 export function useFilterSet(): FilterSet {
   return filterSetFromLocation(useLocation())
@@ -116,6 +144,9 @@ export function useFilterSet(): FilterSet {
 // /r/(all)/overview?level=error&source=build&term=docker
 // will only show errors from the build, not from the pod,
 // and that include the string `docker`.
+//
+// /r/(all)/overview?field=level%3Derror
+// will only show lines whose structured `level` field is `error`.
 export function filterSetFromLocation(l: Location): FilterSet {
   let params = new URLSearchParams(l.search)
   let filters: FilterSet = {
@@ -146,6 +177,11 @@ export function filterSetFromLocation(l: Location): FilterSet {
     filters.term = createFilterTermState(input)
   }
 
+  const fields = parseFieldsInput(params.getAll("field").join(" "))
+  if (fields && Object.keys(fields).length) {
+    filters.fields = fields
+  }
+
   return filters
 }
 
@@ -154,5 +190,6 @@ export function filterSetsEqual(a: FilterSet, b: FilterSet): boolean {
   const levelEqual = a.level === b.level
   // Filter terms are case-insensitive, so we can ignore casing when comparing terms
   const termEqual = a.term.input.toLowerCase() === b.term.input.toLowerCase()
-  return sourceEqual && levelEqual && termEqual
+  const fieldsEqual = fieldsInput(a.fields) === fieldsInput(b.fields)
+  return sourceEqual && levelEqual && termEqual && fieldsEqual
 }
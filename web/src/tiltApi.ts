@@ -29,3 +29,19 @@ export async function tiltApiPut<T extends { metadata?: Proto.v1ObjectMeta }>(
     throw `error updating object in api: ${body}`
   }
 }
+
+export async function tiltApiList<T>(kindPlural: string): Promise<T[]> {
+  const url = `/proxy/apis/tilt.dev/v1alpha1/${kindPlural}`
+  const resp = await fetch(url, {
+    method: "GET",
+    headers: {
+      Accept: "application/json",
+    },
+  })
+  if (resp && resp.status !== 200) {
+    const body = await resp.text()
+    throw `error listing objects in api: ${body}`
+  }
+  const list = await resp.json()
+  return (list?.items ?? []) as T[]
+}
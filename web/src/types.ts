@@ -113,6 +113,9 @@ export type LogLine = {
   buildEvent?: string
   spanId: string
 
+  // Structured fields parsed from the log line, if any.
+  fields?: { [key: string]: string }
+
   // The index of this line in the LogStore StoredLine list.
   storedLineIndex: number
 }
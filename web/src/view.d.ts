@@ -110,6 +110,12 @@ declare namespace Proto {
      * Used for experimenting with new types of log metadata.
      */
     fields?: object;
+    /**
+     * Whether this segment continues the line from the previous segment in
+     * the same span. Only set on segments from the log archive, where the
+     * previous segment may have been pruned.
+     */
+    continuesLine?: boolean;
   }
   export interface webviewLogList {
     spans?: object;
@@ -498,6 +504,15 @@ declare namespace Proto {
      * +optional
      */
     conditions?: v1alpha1UIResourceCondition[];
+    /**
+     * Settings of this resource that were overridden outside the Tiltfile
+     * (e.g., in the user's tilt_overrides.json).
+     *
+     * Settings that aren't listed here come from the Tiltfile.
+     *
+     * +optional
+     */
+    overrides?: v1alpha1UIResourceOverride[];
   }
   export interface v1alpha1UIResourceStateWaitingOnRef {
     /**
@@ -545,6 +560,22 @@ declare namespace Proto {
      */
     isTest?: boolean;
   }
+  export interface v1alpha1UIResourceOverride {
+    /**
+     * The name of the setting (e.g., "trigger_mode", "port_forwards").
+     */
+    setting?: string;
+    /**
+     * The path of the file that the setting came from.
+     */
+    source?: string;
+    /**
+     * A human-readable form of the overriding value.
+     *
+     * +optional
+     */
+    value?: string;
+  }
   export interface v1alpha1UIResourceLink {
     url?: string;
     name?: string;
@@ -839,6 +870,25 @@ declare namespace Proto {
      */
     host?: string;
   }
+  export interface v1alpha1BuildkitClusterConnection {
+    /**
+     * The address of the buildkitd socket, e.g.,
+     * unix:///run/buildkit/buildkitd.sock or tcp://127.0.0.1:1234.
+     *
+     * To use a buildkitd running inside the cluster, port-forward to it.
+     */
+    host: string;
+    /**
+     * If true, buildkitd stores images directly in the containerd instance that
+     * the cluster runs on, so they don't need to be pushed. This requires
+     * buildkitd to use the containerd worker with the k8s.io namespace.
+     *
+     * Otherwise, images are pushed to the cluster's registry.
+     *
+     * +optional
+     */
+    loadToContainerd?: boolean;
+  }
   export interface v1alpha1DisableSource {
     /**
      * Disabled by single ConfigMap value.
@@ -921,6 +971,16 @@ declare namespace Proto {
      * +optional
      */
     version?: string;
+    /**
+     * How Tilt gets the images it builds onto the cluster, when they're
+     * not built on the cluster's container runtime.
+     *
+     * One of "registry" or "containerd". Empty for clusters
+     * that aren't Kubernetes clusters.
+     *
+     * +optional
+     */
+    imageLoader?: string;
   }
   export interface v1alpha1ClusterSpec {
     /**
@@ -939,6 +999,15 @@ declare namespace Proto {
      * +optional
      */
     defaultRegistry?: v1alpha1RegistryHosting;
+    /**
+     * Buildkit connects to a standalone Buildkit daemon that builds the
+     * images for this cluster.
+     *
+     * If not specified, images are built with the Docker daemon.
+     *
+     * +optional
+     */
+    buildkit?: v1alpha1BuildkitClusterConnection;
   }
   export interface v1alpha1ClusterConnectionStatus {
     /**